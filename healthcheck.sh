@@ -18,4 +18,8 @@ if [ -z "${X_AUTHELIA_HEALTHCHECK_PORT}" ]; then
   X_AUTHELIA_HEALTHCHECK_PORT=9091
 fi
 
-wget --quiet --no-check-certificate --tries=1 --spider "${X_AUTHELIA_HEALTHCHECK_SCHEME}://${X_AUTHELIA_HEALTHCHECK_HOST}:${X_AUTHELIA_HEALTHCHECK_PORT}${X_AUTHELIA_HEALTHCHECK_PATH}/api/health" || exit 1
+if [ -z "${X_AUTHELIA_HEALTHCHECK_ENDPOINT}" ]; then
+  X_AUTHELIA_HEALTHCHECK_ENDPOINT=/api/health
+fi
+
+wget --quiet --no-check-certificate --tries=1 --spider "${X_AUTHELIA_HEALTHCHECK_SCHEME}://${X_AUTHELIA_HEALTHCHECK_HOST}:${X_AUTHELIA_HEALTHCHECK_PORT}${X_AUTHELIA_HEALTHCHECK_PATH}${X_AUTHELIA_HEALTHCHECK_ENDPOINT}" || exit 1
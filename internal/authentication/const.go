@@ -27,14 +27,27 @@ const (
 	ldapPlaceholderUsername          = "{username}"
 )
 
+const ldapLogRedacted = "<redacted>"
+
+const schemeLDAPS = "ldaps"
+
+// ldapResultReferralTag is the tag of the referral element of an LDAPResult as per RFC4511 section 4.1.9.
+const ldapResultReferralTag = 3
+
 // CryptAlgo the crypt representation of an algorithm used in the prefix of the hash.
 type CryptAlgo string
 
 const (
 	// HashingAlgorithmArgon2id Argon2id hash identifier.
 	HashingAlgorithmArgon2id CryptAlgo = argon2id
+	// HashingAlgorithmArgon2i Argon2i hash identifier, only supported for verifying existing hashes.
+	HashingAlgorithmArgon2i CryptAlgo = argon2i
+	// HashingAlgorithmArgon2d Argon2d hash identifier, recognized but not supported.
+	HashingAlgorithmArgon2d CryptAlgo = argon2d
 	// HashingAlgorithmSHA512 SHA512 hash identifier.
 	HashingAlgorithmSHA512 CryptAlgo = "6"
+	// HashingAlgorithmScrypt Scrypt hash identifier.
+	HashingAlgorithmScrypt CryptAlgo = scrypt
 )
 
 // These are the default values from the upstream crypt module we use them to for GetInt
@@ -47,6 +60,13 @@ const (
 	HashingDefaultSHA512Iterations    = 5000
 )
 
+// These are the bounds of the scrypt parameters, the cost parameter is stored as the base 2 logarithm of N.
+const (
+	HashingScryptMinimumCost      = 1
+	HashingScryptMaximumCost      = 30
+	HashingScryptMinimumKeyLength = 16
+)
+
 // HashingPossibleSaltCharacters represents valid hashing runes.
 var HashingPossibleSaltCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+/"
 
@@ -54,7 +74,10 @@ var HashingPossibleSaltCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQ
 var ErrUserNotFound = errors.New("user not found")
 
 const argon2id = "argon2id"
+const argon2i = "argon2i"
+const argon2d = "argon2d"
 const sha512 = "sha512"
+const scrypt = "scrypt"
 
 const testPassword = "my;secure*password"
 
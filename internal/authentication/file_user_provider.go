@@ -179,10 +179,18 @@ func (p *FileUserProvider) UpdatePassword(username string, newPassword string) e
 		return err
 	}
 
-	hash, err := HashPassword(
-		newPassword, "", algorithm, p.configuration.Password.Iterations,
-		p.configuration.Password.Memory*1024, p.configuration.Password.Parallelism,
-		p.configuration.Password.KeyLength, p.configuration.Password.SaltLength)
+	var hash string
+
+	if algorithm == HashingAlgorithmScrypt {
+		hash, err = HashPasswordScrypt(
+			newPassword, "", p.configuration.Password.Iterations, p.configuration.Password.BlockSize,
+			p.configuration.Password.Parallelism, p.configuration.Password.KeyLength, p.configuration.Password.SaltLength)
+	} else {
+		hash, err = HashPassword(
+			newPassword, "", algorithm, p.configuration.Password.Iterations,
+			p.configuration.Password.Memory*1024, p.configuration.Password.Parallelism,
+			p.configuration.Password.KeyLength, p.configuration.Password.SaltLength)
+	}
 
 	if err != nil {
 		return err
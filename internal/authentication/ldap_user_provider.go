@@ -3,8 +3,10 @@ package authentication
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"strings"
 
 	"github.com/go-ldap/ldap/v3"
@@ -84,13 +86,17 @@ func newLDAPUserProvider(configuration schema.LDAPAuthenticationBackendConfigura
 }
 
 func (p *LDAPUserProvider) connect(userDN string, password string) (LDAPConnection, error) {
-	conn, err := p.connectionFactory.DialURL(p.configuration.URL, p.dialOpts...)
+	return p.connectCustom(p.configuration.URL, p.configuration.StartTLS, p.tlsConfig, p.dialOpts, userDN, password)
+}
+
+func (p *LDAPUserProvider) connectCustom(address string, startTLS bool, tlsConfig *tls.Config, dialOpts []ldap.DialOpt, userDN, password string) (LDAPConnection, error) {
+	conn, err := p.connectionFactory.DialURL(address, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	if p.configuration.StartTLS {
-		if err := conn.StartTLS(p.tlsConfig); err != nil {
+	if startTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
 			return nil, err
 		}
 	}
@@ -102,6 +108,170 @@ func (p *LDAPUserProvider) connect(userDN string, password string) (LDAPConnecti
 	return conn, nil
 }
 
+// search performs a search request and when the 'follow_referrals' option is enabled follows the referrals returned by
+// the server, either instead of a result or as search result references, for at most 'referral_hops' servers deep.
+func (p *LDAPUserProvider) search(conn LDAPConnection, searchRequest *ldap.SearchRequest) (searchResult *ldap.SearchResult, err error) {
+	return p.searchWithReferrals(conn, searchRequest, p.configuration.ReferralHops)
+}
+
+func (p *LDAPUserProvider) searchWithReferrals(conn LDAPConnection, searchRequest *ldap.SearchRequest, hops int) (searchResult *ldap.SearchResult, err error) {
+	if searchResult, err = conn.Search(searchRequest); err != nil {
+		referral, ok := p.getReferral(err)
+		if !ok || hops <= 0 {
+			return nil, err
+		}
+
+		p.log.Debugf("Following referral %s for search request with filter %s", referral, searchRequest.Filter)
+
+		return p.searchReferral(referral, searchRequest, hops-1)
+	}
+
+	if !p.configuration.FollowReferrals || len(searchResult.Referrals) == 0 {
+		return searchResult, nil
+	}
+
+	for _, referral := range searchResult.Referrals {
+		if hops <= 0 {
+			p.log.Debugf("Not following referral %s for search request with filter %s as the maximum number of referral hops was reached", referral, searchRequest.Filter)
+
+			continue
+		}
+
+		p.log.Debugf("Following search result reference %s for search request with filter %s", referral, searchRequest.Filter)
+
+		var result *ldap.SearchResult
+
+		if result, err = p.searchReferral(referral, searchRequest, hops-1); err != nil {
+			p.log.WithError(err).Warnf("Error occurred following search result reference %s", referral)
+
+			continue
+		}
+
+		searchResult.Entries = append(searchResult.Entries, result.Entries...)
+	}
+
+	return searchResult, nil
+}
+
+func (p *LDAPUserProvider) searchReferral(referral string, searchRequest *ldap.SearchRequest, hops int) (searchResult *ldap.SearchResult, err error) {
+	conn, baseDN, err := p.connectReferral(referral)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	request := *searchRequest
+
+	if baseDN != "" {
+		request.BaseDN = baseDN
+	}
+
+	return p.searchWithReferrals(conn, &request, hops)
+}
+
+// modify performs a modify operation using fn and when the 'follow_referrals' option is enabled performs it again on
+// the server the referral returned by the server refers to, for at most 'referral_hops' servers deep.
+func (p *LDAPUserProvider) modify(conn LDAPConnection, fn func(conn LDAPConnection) error) (err error) {
+	return p.modifyWithReferrals(conn, fn, p.configuration.ReferralHops)
+}
+
+func (p *LDAPUserProvider) modifyWithReferrals(conn LDAPConnection, fn func(conn LDAPConnection) error, hops int) (err error) {
+	if err = fn(conn); err == nil {
+		return nil
+	}
+
+	referral, ok := p.getReferral(err)
+	if !ok || hops <= 0 {
+		return err
+	}
+
+	p.log.Debugf("Following referral %s for modify request", referral)
+
+	if conn, _, err = p.connectReferral(referral); err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return p.modifyWithReferrals(conn, fn, hops-1)
+}
+
+// connectReferral connects and binds to the server the referral refers to with the configured user. The referral is
+// rejected when its host isn't one of the 'referral_hosts' or its connection is less secure than the configured one, so
+// the password of the user is never sent in plain text or to an unexpected server. The certificate of the server is
+// verified against the host of the referral rather than the configured server name.
+func (p *LDAPUserProvider) connectReferral(referral string) (conn LDAPConnection, baseDN string, err error) {
+	address, baseDN, err := ldapParseReferral(referral)
+	if err != nil {
+		return nil, "", err
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, "", fmt.Errorf("error occurred parsing referral %s: %w", referral, err)
+	}
+
+	if len(p.configuration.ReferralHosts) != 0 && !utils.IsStringInSliceFold(u.Hostname(), p.configuration.ReferralHosts) {
+		return nil, "", fmt.Errorf("error occurred following referral %s: the host '%s' is not one of the referral hosts", referral, u.Hostname())
+	}
+
+	if p.isSecure() && u.Scheme != schemeLDAPS && !p.configuration.StartTLS {
+		return nil, "", fmt.Errorf("error occurred following referral %s: the connection would not be secured with TLS unlike the connection to the configured server", referral)
+	}
+
+	tlsConfig := p.newReferralTLSConfig(u.Hostname())
+
+	dialOpts := []ldap.DialOpt{
+		ldap.DialWithDialer(&net.Dialer{Timeout: p.configuration.Timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	}
+
+	// The StartTLS operation is only performed on referrals with the ldap scheme as the ldaps scheme is already secured.
+	startTLS := p.configuration.StartTLS && u.Scheme != schemeLDAPS
+
+	if conn, err = p.connectCustom(address, startTLS, tlsConfig, dialOpts, p.configuration.User, p.configuration.Password); err != nil {
+		return nil, "", fmt.Errorf("error occurred connecting to referral %s: %w", referral, err)
+	}
+
+	return conn, baseDN, nil
+}
+
+// isSecure returns true if the connection to the configured server is secured with TLS.
+func (p *LDAPUserProvider) isSecure() bool {
+	return p.configuration.StartTLS || strings.HasPrefix(p.configuration.URL, schemeLDAPS+"://")
+}
+
+// newReferralTLSConfig returns the TLS configuration used to connect to the server with the given host name referred to
+// by a referral.
+func (p *LDAPUserProvider) newReferralTLSConfig(host string) (tlsConfig *tls.Config) {
+	tlsConfig = p.tlsConfig.Clone()
+	tlsConfig.ServerName = host
+
+	return tlsConfig
+}
+
+// getReferral returns the referral from an error returned by the server when the 'follow_referrals' option is enabled.
+func (p *LDAPUserProvider) getReferral(err error) (referral string, ok bool) {
+	if !p.configuration.FollowReferrals {
+		return "", false
+	}
+
+	return ldapGetReferral(err)
+}
+
+// CheckConnection checks the LDAP server can be reached and the configured user can bind to it.
+func (p *LDAPUserProvider) CheckConnection() (err error) {
+	conn, err := p.connect(p.configuration.User, p.configuration.Password)
+	if err != nil {
+		return err
+	}
+
+	conn.Close()
+
+	return nil
+}
+
 // CheckUserPassword checks if provided password matches for the given user.
 func (p *LDAPUserProvider) CheckUserPassword(inputUsername string, password string) (bool, error) {
 	conn, err := p.connect(p.configuration.User, p.configuration.Password)
@@ -162,7 +332,7 @@ func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername str
 		1, 0, false, userFilter, p.usersAttributes, nil,
 	)
 
-	sr, err := conn.Search(searchRequest)
+	sr, err := p.search(conn, searchRequest)
 	if err != nil {
 		return nil, fmt.Errorf("cannot find user DN of user '%s'. Cause: %w", inputUsername, err)
 	}
@@ -175,6 +345,10 @@ func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername str
 		return nil, fmt.Errorf("multiple users %s found", inputUsername)
 	}
 
+	if p.log.IsLevelEnabled(logrus.TraceLevel) {
+		p.log.Tracef("Retrieved user entry %s with attributes: %s", sr.Entries[0].DN, ldapEntryAttributesLogString(sr.Entries[0], p.configuration.LogAllowedAttributes))
+	}
+
 	userProfile := ldapUserProfile{
 		DN: sr.Entries[0].DN,
 	}
@@ -205,6 +379,57 @@ func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername str
 	return &userProfile, nil
 }
 
+// ldapEntryAttributesLogString returns a representation of the attributes of an entry suitable for logging. The values
+// of attributes which are not in the allowed list are redacted so sensitive values are not written to the logs.
+func ldapEntryAttributesLogString(entry *ldap.Entry, allowed []string) string {
+	attributes := make([]string, len(entry.Attributes))
+
+	for i, attr := range entry.Attributes {
+		if utils.IsStringInSliceFold(attr.Name, allowed) {
+			attributes[i] = fmt.Sprintf("%s=[%s]", attr.Name, strings.Join(attr.Values, ", "))
+		} else {
+			attributes[i] = fmt.Sprintf("%s=[%s]", attr.Name, ldapLogRedacted)
+		}
+	}
+
+	return strings.Join(attributes, " ")
+}
+
+// ldapGetReferral returns the first referral URL from an error with the referral result code.
+func ldapGetReferral(err error) (referral string, ok bool) {
+	var e *ldap.Error
+
+	if !errors.As(err, &e) || e.ResultCode != ldap.LDAPResultReferral || e.Packet == nil || len(e.Packet.Children) < 2 {
+		return "", false
+	}
+
+	// The referral is the optional context specific element with the tag 3 of the LDAPResult.
+	for _, child := range e.Packet.Children[1].Children {
+		if child.Tag == ldapResultReferralTag && len(child.Children) != 0 {
+			referral, ok = child.Children[0].Value.(string)
+
+			return referral, ok
+		}
+	}
+
+	return "", false
+}
+
+// ldapParseReferral returns the address of the server and the base DN from an LDAP URL referral.
+func ldapParseReferral(referral string) (address, baseDN string, err error) {
+	u, err := url.Parse(referral)
+	if err != nil {
+		return "", "", fmt.Errorf("error occurred parsing referral %s: %w", referral, err)
+	}
+
+	switch u.Scheme {
+	case "ldap", "ldaps":
+		return fmt.Sprintf("%s://%s", u.Scheme, u.Host), strings.TrimPrefix(u.Path, "/"), nil
+	default:
+		return "", "", fmt.Errorf("error occurred parsing referral %s: the scheme '%s' is not supported", referral, u.Scheme)
+	}
+}
+
 func (p *LDAPUserProvider) resolveGroupsFilter(inputUsername string, profile *ldapUserProfile) (filter string, err error) { //nolint:unparam
 	filter = p.configuration.GroupsFilter
 
@@ -252,7 +477,7 @@ func (p *LDAPUserProvider) GetDetails(inputUsername string) (*UserDetails, error
 		0, 0, false, groupsFilter, p.groupsAttributes, nil,
 	)
 
-	sr, err := conn.Search(searchGroupRequest)
+	sr, err := p.search(conn, searchGroupRequest)
 
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve groups of user '%s'. Cause: %w", inputUsername, err)
@@ -300,7 +525,11 @@ func (p *LDAPUserProvider) UpdatePassword(inputUsername string, newPassword stri
 			newPassword,
 		)
 
-		_, err = conn.PasswordModify(modifyRequest)
+		err = p.modify(conn, func(conn LDAPConnection) (err error) {
+			_, err = conn.PasswordModify(modifyRequest)
+
+			return err
+		})
 	case p.configuration.Implementation == schema.LDAPImplementationActiveDirectory:
 		modifyRequest := ldap.NewModifyRequest(profile.DN, nil)
 		utf16 := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
@@ -309,12 +538,16 @@ func (p *LDAPUserProvider) UpdatePassword(inputUsername string, newPassword stri
 		pwdEncoded, _ := utf16.NewEncoder().String(fmt.Sprintf("\"%s\"", newPassword))
 		modifyRequest.Replace("unicodePwd", []string{pwdEncoded})
 
-		err = conn.Modify(modifyRequest)
+		err = p.modify(conn, func(conn LDAPConnection) error {
+			return conn.Modify(modifyRequest)
+		})
 	default:
 		modifyRequest := ldap.NewModifyRequest(profile.DN, nil)
 		modifyRequest.Replace("userPassword", []string{newPassword})
 
-		err = conn.Modify(modifyRequest)
+		err = p.modify(conn, func(conn LDAPConnection) error {
+			return conn.Modify(modifyRequest)
+		})
 	}
 
 	if err != nil {
@@ -1,10 +1,12 @@
 package authentication
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"testing"
 
+	ber "github.com/go-asn1-ber/asn1-ber"
 	"github.com/go-ldap/ldap/v3"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -107,6 +109,20 @@ func TestEscapeSpecialCharsFromUserInput(t *testing.T) {
 	assert.Equal(t, "test\\,\\5c\\28abc\\29", ldapClient.ldapEscape("test,\\(abc)"))
 }
 
+func TestShouldRedactLDAPEntryAttributesInLogs(t *testing.T) {
+	entry := &ldap.Entry{
+		DN: "uid=john,dc=example,dc=com",
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "uid", Values: []string{"john"}},
+			{Name: "mail", Values: []string{"john@example.com", "j@example.com"}},
+			{Name: "telephoneNumber", Values: []string{"555-0100"}},
+		},
+	}
+
+	assert.Equal(t, "uid=[<redacted>] mail=[<redacted>] telephoneNumber=[<redacted>]", ldapEntryAttributesLogString(entry, nil))
+	assert.Equal(t, "uid=[john] mail=[john@example.com, j@example.com] telephoneNumber=[<redacted>]", ldapEntryAttributesLogString(entry, []string{"UID", "mail"}))
+}
+
 func TestEscapeSpecialCharsInGroupsFilter(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -136,6 +152,44 @@ func TestEscapeSpecialCharsInGroupsFilter(t *testing.T) {
 	assert.Equal(t, "(|(member=cn=john \\28external\\29,dc=example,dc=com)(uid=john)(uid=john\\#\\=\\28abc\\,def\\29))", filter)
 }
 
+func TestShouldEscapeFilterInjectionAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:               "ldaps://127.0.0.1:389",
+			UsernameAttribute: "uid",
+			UsersFilter:       "(&({username_attribute}={input})(objectClass=person))",
+			GroupsFilter:      "(&(uid={input})(objectClass=groupOfNames))",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	testCases := []struct {
+		input, users, groups string
+	}{
+		{"*", "(&(uid=\\2a)(objectClass=person))", "(&(uid=\\2a)(objectClass=groupOfNames))"},
+		{"john*", "(&(uid=john\\2a)(objectClass=person))", "(&(uid=john\\2a)(objectClass=groupOfNames))"},
+		{"*)(uid=*", "(&(uid=\\2a\\29\\28uid\\=\\2a)(objectClass=person))", "(&(uid=\\2a\\29\\28uid\\=\\2a)(objectClass=groupOfNames))"},
+		{"admin)(|(objectClass=*", "(&(uid=admin\\29\\28|\\28objectClass\\=\\2a)(objectClass=person))", "(&(uid=admin\\29\\28|\\28objectClass\\=\\2a)(objectClass=groupOfNames))"},
+		{"john\\2a", "(&(uid=john\\5c2a)(objectClass=person))", "(&(uid=john\\5c2a)(objectClass=groupOfNames))"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.users, ldapClient.resolveUsersFilter(tc.input))
+
+			filter, err := ldapClient.resolveGroupsFilter(tc.input, &ldapUserProfile{Username: "john"})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.groups, filter)
+		})
+	}
+}
+
 type ExtendedSearchRequestMatcher struct {
 	filter       string
 	baseDN       string
@@ -311,6 +365,45 @@ func TestShouldReturnCheckServerConnectError(t *testing.T) {
 	assert.False(t, ldapClient.supportExtensionPasswdModify)
 }
 
+func TestShouldCheckConnection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:      "ldap://127.0.0.1:389",
+			User:     "cn=admin,dc=example,dc=com",
+			Password: "password",
+			BaseDN:   "dc=example,dc=com",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	gomock.InOrder(
+		mockFactory.EXPECT().
+			DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+			Return(mockConn, nil),
+		mockConn.EXPECT().
+			Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+			Return(errors.New("invalid credentials")),
+		mockFactory.EXPECT().
+			DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+			Return(mockConn, nil),
+		mockConn.EXPECT().
+			Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+			Return(nil),
+		mockConn.EXPECT().
+			Close(),
+	)
+
+	assert.EqualError(t, ldapClient.CheckConnection(), "invalid credentials")
+	assert.NoError(t, ldapClient.CheckConnection())
+}
+
 func TestShouldReturnCheckServerSearchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1344,3 +1437,460 @@ func TestShouldReturnLDAPSAlreadySecuredWhenStartTLSAttempted(t *testing.T) {
 	_, err := ldapClient.GetDetails("john")
 	assert.EqualError(t, err, "LDAP Result Code 200 \"Network Error\": ldap: already encrypted")
 }
+
+func createReferralError(resultCode int, referral string) error {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, int64(1), "MessageID"))
+
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationModifyResponse, nil, "Modify Response")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), "Result Code"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "referral", "Diagnostic Message"))
+
+	referrals := ber.Encode(ber.ClassContext, ber.TypeConstructed, ldapResultReferralTag, nil, "Referral")
+	referrals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, referral, "URI"))
+
+	response.AppendChild(referrals)
+	packet.AppendChild(response)
+
+	return ldap.GetLDAPError(packet)
+}
+
+func TestShouldGetReferralFromError(t *testing.T) {
+	referral, ok := ldapGetReferral(createReferralError(ldap.LDAPResultReferral, "ldap://ldap2.example.com/dc=example,dc=com"))
+	assert.True(t, ok)
+	assert.Equal(t, "ldap://ldap2.example.com/dc=example,dc=com", referral)
+
+	_, ok = ldapGetReferral(createReferralError(ldap.LDAPResultOperationsError, "ldap://ldap2.example.com"))
+	assert.False(t, ok)
+
+	_, ok = ldapGetReferral(errors.New("not an ldap error"))
+	assert.False(t, ok)
+}
+
+func TestShouldParseReferral(t *testing.T) {
+	testCases := []struct {
+		name, referral, address, baseDN, err string
+	}{
+		{"ShouldParseHostOnly", "ldap://ldap2.example.com", "ldap://ldap2.example.com", "", ""},
+		{"ShouldParseHostAndPort", "ldaps://ldap2.example.com:636", "ldaps://ldap2.example.com:636", "", ""},
+		{"ShouldParseBaseDN", "ldap://ldap2.example.com/ou=users,dc=example,dc=com??sub", "ldap://ldap2.example.com", "ou=users,dc=example,dc=com", ""},
+		{"ShouldParseEscapedBaseDN", "ldap://ldap2.example.com/ou=sales%20team,dc=example,dc=com", "ldap://ldap2.example.com", "ou=sales team,dc=example,dc=com", ""},
+		{"ShouldErrorOnInvalidScheme", "http://ldap2.example.com", "", "", "error occurred parsing referral http://ldap2.example.com: the scheme 'http' is not supported"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			address, baseDN, err := ldapParseReferral(tc.referral)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.address, address)
+				assert.Equal(t, tc.baseDN, baseDN)
+			}
+		})
+	}
+}
+
+func TestShouldFollowSearchResultReferencesWhenEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+	mockReferralConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+			FollowReferrals:      true,
+			ReferralHops:         1,
+		},
+		false,
+		nil,
+		mockFactory)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	connBind := mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Referrals: []string{"ldap://ldap2.example.com/ou=users,dc=child,dc=example,dc=com"},
+		}, nil)
+
+	dialReferral := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://ldap2.example.com"), gomock.Any()).
+		Return(mockReferralConn, nil)
+
+	referralBind := mockReferralConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	referralSearchProfile := mockReferralConn.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			assert.Equal(t, "ou=users,dc=child,dc=example,dc=com", searchRequest.BaseDN)
+
+			return &ldap.SearchResult{
+				Entries: []*ldap.Entry{
+					{
+						DN: "uid=john,ou=users,dc=child,dc=example,dc=com",
+						Attributes: []*ldap.EntryAttribute{
+							{
+								Name:   "uid",
+								Values: []string{"john"},
+							},
+						},
+					},
+				},
+				Referrals: []string{"ldap://ldap3.example.com"},
+			}, nil
+		})
+
+	referralClose := mockReferralConn.EXPECT().Close()
+
+	searchGroups := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(createSearchResultWithAttributeValues("group1"), nil)
+
+	connClose := mockConn.EXPECT().Close()
+
+	gomock.InOrder(dialURL, connBind, searchProfile, dialReferral, referralBind, referralSearchProfile, referralClose, searchGroups, connClose)
+
+	details, err := ldapClient.GetDetails("john")
+	require.NoError(t, err)
+
+	assert.Equal(t, "john", details.Username)
+	assert.Equal(t, []string{"group1"}, details.Groups)
+}
+
+func TestShouldNotFollowSearchResultReferencesWhenDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+			ReferralHops:         5,
+		},
+		false,
+		nil,
+		mockFactory)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	connBind := mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Referrals: []string{"ldap://ldap2.example.com/ou=users,dc=child,dc=example,dc=com"},
+		}, nil)
+
+	connClose := mockConn.EXPECT().Close()
+
+	gomock.InOrder(dialURL, connBind, searchProfile, connClose)
+
+	_, err := ldapClient.GetDetails("john")
+	assert.Equal(t, ErrUserNotFound, err)
+}
+
+func TestShouldFollowModifyReferralWhenEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+	mockReferralConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			Implementation:       "custom",
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "uid=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+			FollowReferrals:      true,
+			ReferralHops:         1,
+		},
+		false,
+		nil,
+		mockFactory)
+
+	modifyRequest := ldap.NewModifyRequest("uid=test,dc=example,dc=com", nil)
+	modifyRequest.Replace("userPassword", []string{"password"})
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	connBind := mockConn.EXPECT().
+		Bind(gomock.Eq("uid=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "uid=test,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "uid",
+							Values: []string{"John"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	modify := mockConn.EXPECT().
+		Modify(modifyRequest).
+		Return(createReferralError(ldap.LDAPResultReferral, "ldap://ldap2.example.com"))
+
+	dialReferral := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://ldap2.example.com"), gomock.Any()).
+		Return(mockReferralConn, nil)
+
+	referralBind := mockReferralConn.EXPECT().
+		Bind(gomock.Eq("uid=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	referralModify := mockReferralConn.EXPECT().
+		Modify(modifyRequest).
+		Return(nil)
+
+	referralClose := mockReferralConn.EXPECT().Close()
+
+	connClose := mockConn.EXPECT().Close()
+
+	gomock.InOrder(dialURL, connBind, searchProfile, modify, dialReferral, referralBind, referralModify, referralClose, connClose)
+
+	err := ldapClient.UpdatePassword("john", "password")
+	require.NoError(t, err)
+}
+
+func TestShouldNotFollowModifyReferralWhenHopsExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+	mockReferralConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			Implementation:       "custom",
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "uid=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+			FollowReferrals:      true,
+			ReferralHops:         1,
+		},
+		false,
+		nil,
+		mockFactory)
+
+	modifyRequest := ldap.NewModifyRequest("uid=test,dc=example,dc=com", nil)
+	modifyRequest.Replace("userPassword", []string{"password"})
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	connBind := mockConn.EXPECT().
+		Bind(gomock.Eq("uid=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	searchProfile := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "uid=test,dc=example,dc=com",
+				},
+			},
+		}, nil)
+
+	modify := mockConn.EXPECT().
+		Modify(modifyRequest).
+		Return(createReferralError(ldap.LDAPResultReferral, "ldap://ldap2.example.com"))
+
+	dialReferral := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://ldap2.example.com"), gomock.Any()).
+		Return(mockReferralConn, nil)
+
+	referralBind := mockReferralConn.EXPECT().
+		Bind(gomock.Eq("uid=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	referralModify := mockReferralConn.EXPECT().
+		Modify(modifyRequest).
+		Return(createReferralError(ldap.LDAPResultReferral, "ldap://ldap3.example.com"))
+
+	referralClose := mockReferralConn.EXPECT().Close()
+
+	connClose := mockConn.EXPECT().Close()
+
+	gomock.InOrder(dialURL, connBind, searchProfile, modify, dialReferral, referralBind, referralModify, referralClose, connClose)
+
+	err := ldapClient.UpdatePassword("john", "password")
+	assert.EqualError(t, err, "unable to update password. Cause: LDAP Result Code 10 \"Referral\": referral")
+}
+
+func TestShouldNotFollowInsecureOrUnexpectedReferrals(t *testing.T) {
+	testCases := []struct {
+		name, url, referral, err string
+		startTLS                 bool
+		hosts                    []string
+	}{
+		{"ShouldRejectPlainTextReferralFromLDAPS", "ldaps://ldap.example.com:636", "ldap://ldap2.example.com", "error occurred following referral ldap://ldap2.example.com: the connection would not be secured with TLS unlike the connection to the configured server", false, nil},
+		{"ShouldRejectHostNotInReferralHosts", "ldaps://ldap.example.com:636", "ldaps://evil.example.com", "error occurred following referral ldaps://evil.example.com: the host 'evil.example.com' is not one of the referral hosts", false, []string{"ldap2.example.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPConnectionFactory(ctrl)
+
+			ldapClient := newLDAPUserProvider(
+				schema.LDAPAuthenticationBackendConfiguration{
+					URL:             tc.url,
+					StartTLS:        tc.startTLS,
+					User:            "cn=admin,dc=example,dc=com",
+					Password:        "password",
+					FollowReferrals: true,
+					ReferralHops:    1,
+					ReferralHosts:   tc.hosts,
+				},
+				false,
+				nil,
+				mockFactory)
+
+			conn, _, err := ldapClient.connectReferral(tc.referral)
+			assert.Nil(t, conn)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestShouldFollowSecureReferrals(t *testing.T) {
+	testCases := []struct {
+		name, url, referral, address string
+		startTLS, expectStartTLS     bool
+	}{
+		{"ShouldFollowLDAPSReferralFromLDAPS", "ldaps://ldap.example.com:636", "ldaps://ldap2.example.com/dc=child,dc=example,dc=com", "ldaps://ldap2.example.com", false, false},
+		{"ShouldFollowPlainTextReferralWithStartTLS", "ldap://ldap.example.com:389", "ldap://ldap2.example.com/dc=child,dc=example,dc=com", "ldap://ldap2.example.com", true, true},
+		{"ShouldFollowLDAPSReferralWithoutStartTLS", "ldap://ldap.example.com:389", "ldaps://ldap2.example.com/dc=child,dc=example,dc=com", "ldaps://ldap2.example.com", true, false},
+		{"ShouldFollowPlainTextReferralFromPlainText", "ldap://ldap.example.com:389", "ldap://ldap2.example.com/dc=child,dc=example,dc=com", "ldap://ldap2.example.com", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPConnectionFactory(ctrl)
+			mockReferralConn := NewMockLDAPConnection(ctrl)
+
+			ldapClient := newLDAPUserProvider(
+				schema.LDAPAuthenticationBackendConfiguration{
+					URL:             tc.url,
+					StartTLS:        tc.startTLS,
+					User:            "cn=admin,dc=example,dc=com",
+					Password:        "password",
+					FollowReferrals: true,
+					ReferralHops:    1,
+					ReferralHosts:   []string{"LDAP2.example.com"},
+					TLS:             &schema.TLSConfig{ServerName: "ldap.example.com"},
+				},
+				false,
+				nil,
+				mockFactory)
+
+			mockFactory.EXPECT().
+				DialURL(gomock.Eq(tc.address), gomock.Any()).
+				Return(mockReferralConn, nil)
+
+			if tc.expectStartTLS {
+				mockReferralConn.EXPECT().
+					StartTLS(gomock.Any()).
+					DoAndReturn(func(config *tls.Config) error {
+						assert.Equal(t, "ldap2.example.com", config.ServerName)
+
+						return nil
+					})
+			}
+
+			mockReferralConn.EXPECT().
+				Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+				Return(nil)
+
+			conn, baseDN, err := ldapClient.connectReferral(tc.referral)
+			require.NoError(t, err)
+			assert.Equal(t, mockReferralConn, conn)
+			assert.Equal(t, "dc=child,dc=example,dc=com", baseDN)
+		})
+	}
+}
+
+func TestShouldUseReferralHostAsServerName(t *testing.T) {
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL: "ldaps://ldap.example.com:636",
+			TLS: &schema.TLSConfig{ServerName: "ldap.example.com", MinimumVersion: "TLS1.3"},
+		},
+		false,
+		nil,
+		nil)
+
+	tlsConfig := ldapClient.newReferralTLSConfig("ldap2.example.com")
+
+	assert.Equal(t, "ldap2.example.com", tlsConfig.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	assert.Equal(t, "ldap.example.com", ldapClient.tlsConfig.ServerName)
+}
@@ -8,12 +8,15 @@ import (
 	"strings"
 
 	"github.com/simia-tech/crypt"
+	xscrypt "golang.org/x/crypto/scrypt"
 
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // PasswordHash represents all characteristics of a password hash.
-// Authelia only supports salted SHA512 or salted argon2id method, i.e., $6$ mode or $argon2id$ mode.
+// Authelia only supports salted SHA512, salted argon2id, or salted scrypt methods, i.e., $6$ mode, $argon2id$ mode, or
+// $scrypt$ mode. Salted argon2i hashes, i.e., $argon2i$ mode, are also supported for verifying existing hashes. The
+// Iterations of a scrypt hash are the base 2 logarithm of the cost parameter N.
 type PasswordHash struct {
 	Algorithm   CryptAlgo
 	Iterations  int
@@ -22,6 +25,7 @@ type PasswordHash struct {
 	KeyLength   int
 	Memory      int
 	Parallelism int
+	BlockSize   int
 }
 
 // ConfigAlgoToCryptoAlgo returns a CryptAlgo and nil error if valid, otherwise it returns argon2id and an error.
@@ -31,8 +35,10 @@ func ConfigAlgoToCryptoAlgo(fromConfig string) (CryptAlgo, error) {
 		return HashingAlgorithmArgon2id, nil
 	case sha512:
 		return HashingAlgorithmSHA512, nil
+	case scrypt:
+		return HashingAlgorithmScrypt, nil
 	default:
-		return HashingAlgorithmArgon2id, errors.New("Invalid algorithm in configuration. It should be `argon2id` or `sha512`")
+		return HashingAlgorithmArgon2id, errors.New("Invalid algorithm in configuration. It should be `argon2id`, `sha512`, or `scrypt`")
 	}
 }
 
@@ -65,42 +71,78 @@ func ParseHash(hash string) (passwordHash *PasswordHash, err error) {
 			return nil, fmt.Errorf("SHA512 iterations is not numeric (%s)", parameters["rounds"])
 		}
 	case HashingAlgorithmArgon2id:
-		_, err = crypt.Base64Encoding.DecodeString(h.Salt)
-		if err != nil {
-			return nil, errors.New("Salt contains invalid base64 characters")
+		if err = parseArgon2Hash(h, code, "Argon2id", parameters, hash); err != nil {
+			return nil, err
+		}
+	case HashingAlgorithmArgon2i:
+		if err = parseArgon2Hash(h, code, "Argon2i", parameters, hash); err != nil {
+			return nil, err
 		}
+	case HashingAlgorithmArgon2d:
+		return nil, fmt.Errorf("Authelia does not support verifying argon2d ($argon2d$) hashes, the password must be reset or rehashed using argon2id (%s)", hash)
+	case HashingAlgorithmScrypt:
+		if err = parseScryptHash(h, parameters); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Authelia only supports salted SHA512 hashing ($6$), salted argon2id ($argon2id$), salted argon2i ($argon2i$), and salted scrypt ($scrypt$), not $%s$", code)
+	}
+
+	return h, nil
+}
 
-		version := parameters.GetInt("v", 0)
-		if version < 19 {
-			if version == 0 {
-				return nil, fmt.Errorf("Argon2id version parameter not found (%s)", hash)
-			}
+// parseArgon2Hash parses the parameters of an argon2 hash, the name is the variant name used in the errors.
+func parseArgon2Hash(h *PasswordHash, code CryptAlgo, name string, parameters crypt.Parameter, hash string) (err error) {
+	if _, err = crypt.Base64Encoding.DecodeString(h.Salt); err != nil {
+		return errors.New("Salt contains invalid base64 characters")
+	}
 
-			return nil, fmt.Errorf("Argon2id versions less than v19 are not supported (hash is version %d)", version)
-		} else if version > 19 {
-			return nil, fmt.Errorf("Argon2id versions greater than v19 are not supported (hash is version %d)", version)
+	version := parameters.GetInt("v", 0)
+	if version < 19 {
+		if version == 0 {
+			return fmt.Errorf("%s version parameter not found (%s)", name, hash)
 		}
 
-		h.Algorithm = HashingAlgorithmArgon2id
-		h.Memory = parameters.GetInt("m", HashingDefaultArgon2idMemory)
-		h.Iterations = parameters.GetInt("t", HashingDefaultArgon2idTime)
-		h.Parallelism = parameters.GetInt("p", HashingDefaultArgon2idParallelism)
-		h.KeyLength = parameters.GetInt("k", HashingDefaultArgon2idKeyLength)
+		return fmt.Errorf("%s versions less than v19 are not supported (hash is version %d)", name, version)
+	} else if version > 19 {
+		return fmt.Errorf("%s versions greater than v19 are not supported (hash is version %d)", name, version)
+	}
 
-		decodedKey, err := crypt.Base64Encoding.DecodeString(h.Key)
+	h.Algorithm = code
+	h.Memory = parameters.GetInt("m", HashingDefaultArgon2idMemory)
+	h.Iterations = parameters.GetInt("t", HashingDefaultArgon2idTime)
+	h.Parallelism = parameters.GetInt("p", HashingDefaultArgon2idParallelism)
+	h.KeyLength = parameters.GetInt("k", HashingDefaultArgon2idKeyLength)
 
-		if err != nil {
-			return nil, errors.New("Hash key contains invalid base64 characters")
-		}
+	decodedKey, err := crypt.Base64Encoding.DecodeString(h.Key)
+	if err != nil {
+		return errors.New("Hash key contains invalid base64 characters")
+	}
 
-		if len(decodedKey) != h.KeyLength {
-			return nil, fmt.Errorf("Argon2id key length parameter (%d) does not match the actual key length (%d)", h.KeyLength, len(decodedKey))
-		}
-	default:
-		return nil, fmt.Errorf("Authelia only supports salted SHA512 hashing ($6$) and salted argon2id ($argon2id$), not $%s$", code)
+	if len(decodedKey) != h.KeyLength {
+		return fmt.Errorf("%s key length parameter (%d) does not match the actual key length (%d)", name, h.KeyLength, len(decodedKey))
 	}
 
-	return h, nil
+	return nil
+}
+
+func parseScryptHash(h *PasswordHash, parameters crypt.Parameter) (err error) {
+	if _, err = crypt.Base64Encoding.DecodeString(h.Salt); err != nil {
+		return errors.New("Salt contains invalid base64 characters")
+	}
+
+	decodedKey, err := crypt.Base64Encoding.DecodeString(h.Key)
+	if err != nil {
+		return errors.New("Hash key contains invalid base64 characters")
+	}
+
+	h.Algorithm = HashingAlgorithmScrypt
+	h.Iterations = parameters.GetInt("ln", 0)
+	h.BlockSize = parameters.GetInt("r", 0)
+	h.Parallelism = parameters.GetInt("p", 0)
+	h.KeyLength = len(decodedKey)
+
+	return validateScryptSettings(h.Iterations, h.BlockSize, h.Parallelism, h.KeyLength)
 }
 
 // HashPassword generate a salt and hash the password with the salt and a constant number of rounds.
@@ -137,6 +179,34 @@ func HashPassword(password, salt string, algorithm CryptAlgo, iterations, memory
 	return hash, nil
 }
 
+// HashPasswordScrypt generates a salt if one isn't provided and hashes the password with scrypt. The cost is the base 2
+// logarithm of the scrypt N parameter.
+func HashPasswordScrypt(password, salt string, cost, blockSize, parallelism, keyLength, saltLength int) (hash string, err error) {
+	if err = validateScryptSettings(cost, blockSize, parallelism, keyLength); err != nil {
+		return "", err
+	}
+
+	if err = validateSalt(salt, saltLength); err != nil {
+		return "", err
+	}
+
+	if salt == "" {
+		salt = crypt.Base64Encoding.EncodeToString(utils.RandomBytes(saltLength, HashingPossibleSaltCharacters, true))
+	}
+
+	decodedSalt, err := crypt.Base64Encoding.DecodeString(salt)
+	if err != nil {
+		return "", fmt.Errorf("Salt input of %s is invalid, only base64 strings are valid for input", salt)
+	}
+
+	key, err := xscrypt.Key([]byte(password), decodedSalt, 1<<cost, blockSize, parallelism, keyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("$%s$ln=%d,r=%d,p=%d$%s$%s", HashingAlgorithmScrypt, cost, blockSize, parallelism, salt, crypt.Base64Encoding.EncodeToString(key)), nil
+}
+
 // CheckPassword check a password against a hash.
 func CheckPassword(password, hash string) (ok bool, err error) {
 	expectedHash, err := ParseHash(hash)
@@ -144,7 +214,18 @@ func CheckPassword(password, hash string) (ok bool, err error) {
 		return false, err
 	}
 
-	passwordHashString, err := HashPassword(password, expectedHash.Salt, expectedHash.Algorithm, expectedHash.Iterations, expectedHash.Memory, expectedHash.Parallelism, expectedHash.KeyLength, len(expectedHash.Salt))
+	var passwordHashString string
+
+	switch expectedHash.Algorithm {
+	case HashingAlgorithmScrypt:
+		passwordHashString, err = HashPasswordScrypt(password, expectedHash.Salt, expectedHash.Iterations, expectedHash.BlockSize, expectedHash.Parallelism, expectedHash.KeyLength, len(expectedHash.Salt))
+	case HashingAlgorithmArgon2i:
+		// Argon2i hashes can only be verified, so they're not hashed with HashPassword which is used for generation.
+		passwordHashString, err = crypt.Crypt(password, getCryptSettings(expectedHash.Salt, expectedHash.Algorithm, expectedHash.Iterations, expectedHash.Memory, expectedHash.Parallelism, expectedHash.KeyLength))
+	default:
+		passwordHashString, err = HashPassword(password, expectedHash.Salt, expectedHash.Algorithm, expectedHash.Iterations, expectedHash.Memory, expectedHash.Parallelism, expectedHash.KeyLength, len(expectedHash.Salt))
+	}
+
 	if err != nil {
 		return false, err
 	}
@@ -161,6 +242,8 @@ func getCryptSettings(salt string, algorithm CryptAlgo, iterations, memory, para
 	switch algorithm {
 	case HashingAlgorithmArgon2id:
 		settings, _ = crypt.Argon2idSettings(memory, iterations, parallelism, keyLength, salt)
+	case HashingAlgorithmArgon2i:
+		settings, _ = crypt.Argon2iSettings(memory, iterations, parallelism, keyLength, salt)
 	case HashingAlgorithmSHA512:
 		settings = fmt.Sprintf("$6$rounds=%d$%s", iterations, salt)
 	default:
@@ -218,3 +301,32 @@ func validateArgon2idSettings(memory, parallelism, iterations, keyLength int) er
 	// Caution: Increasing any of the values in the above block has a high chance in old passwords that cannot be verified.
 	return nil
 }
+
+// validateScryptSettings checks the scrypt settings are valid.
+func validateScryptSettings(cost, blockSize, parallelism, keyLength int) error {
+	if cost < HashingScryptMinimumCost || cost > HashingScryptMaximumCost {
+		return fmt.Errorf("Cost (scrypt) input of %d is invalid, it must be between %d and %d", cost, HashingScryptMinimumCost, HashingScryptMaximumCost)
+	}
+
+	if blockSize < 1 {
+		return fmt.Errorf("Block size (scrypt) input of %d is invalid, it must be 1 or higher", blockSize)
+	}
+
+	if parallelism < 1 {
+		return fmt.Errorf("Parallelism (scrypt) input of %d is invalid, it must be 1 or higher", parallelism)
+	}
+
+	if uint64(blockSize)*uint64(parallelism) >= 1<<30 {
+		return fmt.Errorf("Block size (scrypt) input of %d multiplied by the parallelism input of %d is invalid, it must be less than 2^30", blockSize, parallelism)
+	}
+
+	if cost >= 16*blockSize {
+		return fmt.Errorf("Cost (scrypt) input of %d is invalid with a block size input of %d, it must be less than %d (block size * 16)", cost, blockSize, 16*blockSize)
+	}
+
+	if keyLength < HashingScryptMinimumKeyLength {
+		return fmt.Errorf("Key length (scrypt) input of %d is invalid, it must be %d or higher", keyLength, HashingScryptMinimumKeyLength)
+	}
+
+	return nil
+}
@@ -237,6 +237,51 @@ func TestShouldCheckArgon2idPassword(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestShouldCheckArgon2iPassword(t *testing.T) {
+	hashes := []string{
+		"$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$IMit9qkFULCMA/ViizL57cnTLOa5DiVM9eMwpAvPwr4",
+		"$argon2i$v=19$m=65536,t=3,p=1,k=64$bG9uZ3NhbHRsb25nc2FsdA$7DnJ2B7gxZDMEk+HVNDpIuTtOxDkwDaA0IhvuiBn9oeBTXpqBPxP9iro2cPiFongTwoFHHpVrqiL8JvMXrb63Q",
+	}
+
+	for _, hash := range hashes {
+		t.Run(hash, func(t *testing.T) {
+			ok, err := CheckPassword("password", hash)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = CheckPassword("wrong", hash)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestShouldParseArgon2iHash(t *testing.T) {
+	passwordHash, err := ParseHash("$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$IMit9qkFULCMA/ViizL57cnTLOa5DiVM9eMwpAvPwr4")
+	assert.NoError(t, err)
+	assert.Equal(t, HashingAlgorithmArgon2i, passwordHash.Algorithm)
+	assert.Equal(t, 2, passwordHash.Iterations)
+	assert.Equal(t, 4, passwordHash.Parallelism)
+	assert.Equal(t, 32, passwordHash.KeyLength)
+	assert.Equal(t, 65536, passwordHash.Memory)
+}
+
+func TestShouldNotHashArgon2iPassword(t *testing.T) {
+	hash, err := HashPassword("password", "c29tZXNhbHQ", HashingAlgorithmArgon2i, 2, 65536, 4, 32, 8)
+
+	assert.EqualError(t, err, "Hashing algorithm input of 'argon2i' is invalid, only values of argon2id and 6 are supported")
+	assert.Equal(t, "", hash)
+}
+
+func TestShouldNotCheckArgon2dPassword(t *testing.T) {
+	hash := "$argon2d$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$2+JCoQtY/2x5F0VB9pEVP3xBNguWP1T25Ui0PtZuk8o"
+
+	ok, err := CheckPassword("password", hash)
+
+	assert.EqualError(t, err, fmt.Sprintf("Authelia does not support verifying argon2d ($argon2d$) hashes, the password must be reset or rehashed using argon2id (%s)", hash))
+	assert.False(t, ok)
+}
+
 func TestCannotParseSHA512Hash(t *testing.T) {
 	ok, err := CheckPassword("password", "$6$roSnSL3fEVkK0yHFQ.oFFAd8D4OhPAy18K5U61Z2eBhxQXExGU/eknXlY1")
 
@@ -251,13 +296,67 @@ func TestCannotParseArgon2idHash(t *testing.T) {
 	assert.False(t, ok)
 }
 
-func TestOnlySupportSHA512AndArgon2id(t *testing.T) {
+func TestOnlySupportSHA512Argon2idAndScrypt(t *testing.T) {
 	ok, err := CheckPassword("password", "$8$rounds=50000$aFr56HjK3DrB8t3S$zhPQiS85cgBlNhUKKE6n/AHMlpqrvYSnSL3fEVkK0yHFQ.oFFAd8D4OhPAy18K5U61Z2eBhxQXExGU/eknXlY1")
 
-	assert.EqualError(t, err, "Authelia only supports salted SHA512 hashing ($6$) and salted argon2id ($argon2id$), not $8$")
+	assert.EqualError(t, err, "Authelia only supports salted SHA512 hashing ($6$), salted argon2id ($argon2id$), salted argon2i ($argon2i$), and salted scrypt ($scrypt$), not $8$")
 	assert.False(t, ok)
 }
 
+func TestShouldCheckScryptPassword(t *testing.T) {
+	hash := "$scrypt$ln=14,r=8,p=1$YUZyNTZIakszRHJCOHQzUw$uyba8Wq+CJnXsJVZP5f6aP3GQAU4GcXZtJbh5GCfrIY"
+
+	ok, err := CheckPassword("password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = CheckPassword("wrongpassword", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestShouldHashScryptPassword(t *testing.T) {
+	hash, err := HashPasswordScrypt("password", "", 10, 8, 1, 32, 16)
+	require.NoError(t, err)
+
+	passwordHash, err := ParseHash(hash)
+	require.NoError(t, err)
+
+	assert.Equal(t, HashingAlgorithmScrypt, passwordHash.Algorithm)
+	assert.Equal(t, 10, passwordHash.Iterations)
+	assert.Equal(t, 8, passwordHash.BlockSize)
+	assert.Equal(t, 1, passwordHash.Parallelism)
+	assert.Equal(t, 32, passwordHash.KeyLength)
+
+	ok, err := CheckPassword("password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestShouldNotHashScryptPasswordWithInvalidSettings(t *testing.T) {
+	testCases := []struct {
+		name                                    string
+		cost, blockSize, parallelism, keyLength int
+		err                                     string
+	}{
+		{"ShouldErrOnLowCost", 0, 8, 1, 32, "Cost (scrypt) input of 0 is invalid, it must be between 1 and 30"},
+		{"ShouldErrOnHighCost", 31, 8, 1, 32, "Cost (scrypt) input of 31 is invalid, it must be between 1 and 30"},
+		{"ShouldErrOnLowBlockSize", 10, 0, 1, 32, "Block size (scrypt) input of 0 is invalid, it must be 1 or higher"},
+		{"ShouldErrOnLowParallelism", 10, 8, 0, 32, "Parallelism (scrypt) input of 0 is invalid, it must be 1 or higher"},
+		{"ShouldErrOnCostTooHighForBlockSize", 16, 1, 1, 32, "Cost (scrypt) input of 16 is invalid with a block size input of 1, it must be less than 16 (block size * 16)"},
+		{"ShouldErrOnLowKeyLength", 10, 8, 1, 8, "Key length (scrypt) input of 8 is invalid, it must be 16 or higher"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := HashPasswordScrypt("password", "", tc.cost, tc.blockSize, tc.parallelism, tc.keyLength, 16)
+
+			assert.EqualError(t, err, tc.err)
+			assert.Equal(t, "", hash)
+		})
+	}
+}
+
 func TestCannotFindNumberOfRounds(t *testing.T) {
 	hash := "$6$rounds50000$aFr56HjK3DrB8t3S$zhPQiS85cgBlNhUKKE6n/AHMlpqrvYSnSL3fEVkK0yHFQ.oFFAd8D4OhPAy18K5U61Z2eBhxQXExGU/eknXlY1"
 	ok, err := CheckPassword("password", hash)
@@ -0,0 +1,99 @@
+package authorization
+
+import (
+	"regexp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// AccessControlQuery represents an ACL query criteria, it matches when all of its rules match.
+type AccessControlQuery struct {
+	Rules []AccessControlQueryRule
+}
+
+// IsMatch returns true if all of the rules of the ACL query match the object.
+func (acq AccessControlQuery) IsMatch(object Object) (match bool) {
+	for _, rule := range acq.Rules {
+		if !rule.IsMatch(object) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AccessControlQueryRule represents an ACL query rule which matches a single query parameter.
+type AccessControlQueryRule struct {
+	Operator string
+	Key      string
+	Value    string
+	Pattern  *regexp.Regexp
+}
+
+// IsMatch returns true if the query parameter of the object matches the rule. When the parameter is given more than
+// once every value must satisfy the rule so a duplicate parameter can't be used to match a more permissive rule.
+func (acqr AccessControlQueryRule) IsMatch(object Object) (match bool) {
+	values, present := object.Query[acqr.Key]
+
+	switch acqr.Operator {
+	case operatorPresent:
+		return present
+	case operatorAbsent:
+		return !present
+	case operatorEqual:
+		return present && isEveryQueryValue(values, func(value string) bool { return value == acqr.Value })
+	case operatorNotEqual:
+		return isEveryQueryValue(values, func(value string) bool { return value != acqr.Value })
+	case operatorPattern:
+		return present && acqr.Pattern != nil && isEveryQueryValue(values, acqr.Pattern.MatchString)
+	case operatorNotPattern:
+		return acqr.Pattern != nil && isEveryQueryValue(values, func(value string) bool { return !acqr.Pattern.MatchString(value) })
+	default:
+		return false
+	}
+}
+
+func isEveryQueryValue(values []string, fn func(value string) bool) bool {
+	for _, value := range values {
+		if !fn(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func schemaQueryToACL(schemaQuery [][]schema.ACLQueryRule) (query []AccessControlQuery) {
+	for _, schemaRules := range schemaQuery {
+		acq := AccessControlQuery{}
+
+		for _, schemaRule := range schemaRules {
+			rule := AccessControlQueryRule{
+				Operator: schemaRule.Operator,
+				Key:      schemaRule.Key,
+				Value:    schemaRule.Value,
+			}
+
+			if rule.Operator == "" {
+				if rule.Value == "" {
+					rule.Operator = operatorPresent
+				} else {
+					rule.Operator = operatorEqual
+				}
+			}
+
+			if rule.Operator == operatorPattern || rule.Operator == operatorNotPattern {
+				// Invalid patterns are reported by the configuration validation, the rule never matches if one is used.
+				rule.Pattern, _ = regexp.Compile(rule.Value)
+			}
+
+			acq.Rules = append(acq.Rules, rule)
+		}
+
+		if len(acq.Rules) != 0 {
+			query = append(query, acq)
+		}
+	}
+
+	return query
+}
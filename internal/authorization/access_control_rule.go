@@ -21,29 +21,52 @@ func NewAccessControlRules(config schema.AccessControlConfiguration) (rules []*A
 // NewAccessControlRule parses a schema ACL and generates an internal ACL.
 func NewAccessControlRule(pos int, rule schema.ACLRule, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) *AccessControlRule {
 	return &AccessControlRule{
-		Position:  pos,
-		Domains:   schemaDomainsToACL(rule.Domains, rule.DomainsRegex),
-		Resources: schemaResourcesToACL(rule.Resources),
-		Methods:   schemaMethodsToACL(rule.Methods),
-		Networks:  schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
-		Subjects:  schemaSubjectsToACL(rule.Subjects),
-		Policy:    PolicyToLevel(rule.Policy),
+		Position:            pos,
+		Domains:             schemaDomainsToACL(rule.Domains, rule.DomainsRegex),
+		Resources:           schemaResourcesToACL(rule.Resources),
+		Methods:             schemaMethodsToACL(rule.Methods),
+		Query:               schemaQueryToACL(rule.Query),
+		Time:                schemaTimeToACL(rule.Time),
+		Networks:            schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
+		Subjects:            schemaSubjectsToACL(rule.Subjects),
+		Policy:              PolicyToLevel(rule.Policy),
+		BasicAuth:           rule.BasicAuth,
+		SecondFactorMethods: schemaSecondFactorMethodsToACL(rule.SecondFactorMethods),
 	}
 }
 
 // AccessControlRule controls and represents an ACL internally.
 type AccessControlRule struct {
-	Position  int
-	Domains   []SubjectObjectMatcher
-	Resources []AccessControlResource
-	Methods   []string
-	Networks  []*net.IPNet
-	Subjects  []AccessControlSubjects
-	Policy    Level
+	Position            int
+	Domains             []SubjectObjectMatcher
+	Resources           []AccessControlResource
+	Methods             []string
+	Query               []AccessControlQuery
+	Time                []AccessControlTime
+	Networks            []*net.IPNet
+	Subjects            []AccessControlSubjects
+	Policy              Level
+	BasicAuth           bool
+	SecondFactorMethods []string
 }
 
 // IsMatch returns true if all elements of an AccessControlRule match the object and subject.
 func (acr *AccessControlRule) IsMatch(subject Subject, object Object) (match bool) {
+	return acr.isMatch(subject, object, false)
+}
+
+// IsMatchInvalidQuery returns true if the rule has query criteria, the query of the object is invalid, and all of the
+// other elements of the AccessControlRule match the object and subject. The query criteria can't be evaluated reliably
+// for these objects so they must not fall through to the subsequent rules.
+func (acr *AccessControlRule) IsMatchInvalidQuery(subject Subject, object Object) (match bool) {
+	if len(acr.Query) == 0 || !object.QueryInvalid {
+		return false
+	}
+
+	return acr.isMatch(subject, object, true)
+}
+
+func (acr *AccessControlRule) isMatch(subject Subject, object Object, skipQuery bool) (match bool) {
 	if !isMatchForDomains(subject, object, acr) {
 		return false
 	}
@@ -56,6 +79,14 @@ func (acr *AccessControlRule) IsMatch(subject Subject, object Object) (match boo
 		return false
 	}
 
+	if !skipQuery && !isMatchForQuery(object, acr) {
+		return false
+	}
+
+	if !isMatchForTime(object, acr) {
+		return false
+	}
+
 	if !isMatchForNetworks(subject, acr) {
 		return false
 	}
@@ -64,9 +95,51 @@ func (acr *AccessControlRule) IsMatch(subject Subject, object Object) (match boo
 		return false
 	}
 
+	// Rules restricted to HTTP Basic Authentication never match requests authenticated via a session.
+	if acr.BasicAuth && !subject.BasicAuth {
+		return false
+	}
+
 	return true
 }
 
+// Criteria returns the names of the criteria configured for this rule, all of which must match for the rule to match.
+func (acr *AccessControlRule) Criteria() (criteria []string) {
+	if len(acr.Domains) != 0 {
+		criteria = append(criteria, "domain")
+	}
+
+	if len(acr.Resources) != 0 {
+		criteria = append(criteria, "resources")
+	}
+
+	if len(acr.Methods) != 0 {
+		criteria = append(criteria, "methods")
+	}
+
+	if len(acr.Query) != 0 {
+		criteria = append(criteria, "query")
+	}
+
+	if len(acr.Time) != 0 {
+		criteria = append(criteria, "time")
+	}
+
+	if len(acr.Networks) != 0 {
+		criteria = append(criteria, "networks")
+	}
+
+	if len(acr.Subjects) != 0 {
+		criteria = append(criteria, "subject")
+	}
+
+	if acr.BasicAuth {
+		criteria = append(criteria, "basic_auth")
+	}
+
+	return criteria
+}
+
 func isMatchForDomains(subject Subject, object Object, acl *AccessControlRule) (match bool) {
 	// If there are no domains in this rule then the domain condition is a match.
 	if len(acl.Domains) == 0 {
@@ -108,6 +181,43 @@ func isMatchForMethods(object Object, acl *AccessControlRule) (match bool) {
 	return utils.IsStringInSlice(object.Method, acl.Methods)
 }
 
+func isMatchForQuery(object Object, acl *AccessControlRule) (match bool) {
+	// If there is no query in this rule then the query condition is a match.
+	if len(acl.Query) == 0 {
+		return true
+	}
+
+	// The query of the object can't be evaluated reliably so it never matches a query condition.
+	if object.QueryInvalid {
+		return false
+	}
+
+	// Iterate over the queries until we find a match (return true) or until we exit the loop (return false).
+	for _, query := range acl.Query {
+		if query.IsMatch(object) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMatchForTime(object Object, acl *AccessControlRule) (match bool) {
+	// If there are no times in this rule then the time condition is a match.
+	if len(acl.Time) == 0 {
+		return true
+	}
+
+	// Iterate over the times until we find a match (return true) or until we exit the loop (return false).
+	for _, t := range acl.Time {
+		if t.IsMatch(object.Time) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func isMatchForNetworks(subject Subject, acl *AccessControlRule) (match bool) {
 	// If there are no networks in this rule then the network condition is a match.
 	if len(acl.Networks) == 0 {
@@ -150,3 +260,47 @@ func isExactMatchForSubjects(subject Subject, acl *AccessControlRule) (match boo
 
 	return false
 }
+
+// NewAccessControlTrustedNetworks converts the trusted networks of a schema.AccessControlConfiguration into a
+// net.IPNet slice.
+func NewAccessControlTrustedNetworks(config schema.AccessControlConfiguration) (networks []*net.IPNet) {
+	for _, network := range config.TrustedNetworks {
+		if cidr, err := ParseNetwork(network); err == nil {
+			networks = append(networks, cidr)
+		}
+	}
+
+	return networks
+}
+
+// NewAccessControlDomainDefaultPolicies converts a schema.AccessControlConfiguration into an
+// AccessControlDomainDefaultPolicy slice.
+func NewAccessControlDomainDefaultPolicies(config schema.AccessControlConfiguration) (policies []*AccessControlDomainDefaultPolicy) {
+	for i, schemaPolicy := range config.DomainDefaultPolicies {
+		policies = append(policies, &AccessControlDomainDefaultPolicy{
+			Position: i + 1,
+			Domains:  schemaDomainsToACL(schemaPolicy.Domains, nil),
+			Policy:   PolicyToLevel(schemaPolicy.Policy),
+		})
+	}
+
+	return policies
+}
+
+// AccessControlDomainDefaultPolicy represents the default policy applied to the matching domains when no rule matches.
+type AccessControlDomainDefaultPolicy struct {
+	Position int
+	Domains  []SubjectObjectMatcher
+	Policy   Level
+}
+
+// IsMatch returns true if any of the domains of the AccessControlDomainDefaultPolicy match the object and subject.
+func (p *AccessControlDomainDefaultPolicy) IsMatch(subject Subject, object Object) (match bool) {
+	for _, domain := range p.Domains {
+		if domain.IsMatch(subject, object) {
+			return true
+		}
+	}
+
+	return false
+}
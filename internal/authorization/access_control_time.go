@@ -0,0 +1,139 @@
+package authorization
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewAccessControlTime parses a time condition of an ACL rule. The condition has the format '[days] [HH:MM-HH:MM]'
+// where days is a comma separated list of week days or week day ranges such as 'mon-fri,sun', and the time window
+// includes the start but excludes the end which may be '24:00'. Either part may be omitted but not both.
+func NewAccessControlTime(value string) (act AccessControlTime, err error) {
+	fields := strings.Fields(strings.ToLower(value))
+
+	switch len(fields) {
+	case 1:
+		if strings.Contains(fields[0], ":") {
+			act.Weekdays = weekdaysAll
+			act.Start, act.End, err = parseTimeWindow(fields[0])
+		} else {
+			act.Weekdays, err = parseWeekdays(fields[0])
+			act.End = minutesPerDay
+		}
+	case 2:
+		if act.Weekdays, err = parseWeekdays(fields[0]); err != nil {
+			return act, err
+		}
+
+		act.Start, act.End, err = parseTimeWindow(fields[1])
+	default:
+		return act, fmt.Errorf("must have the format '[days] [HH:MM-HH:MM]'")
+	}
+
+	return act, err
+}
+
+// AccessControlTime represents an ACL time condition which matches the week days and time window it describes.
+type AccessControlTime struct {
+	Weekdays uint8
+	Start    int
+	End      int
+}
+
+// IsMatch returns true if the time is on one of the week days and within the time window of the condition.
+func (act AccessControlTime) IsMatch(t time.Time) (match bool) {
+	if act.Weekdays&(1<<uint(t.Weekday())) == 0 {
+		return false
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+
+	return minutes >= act.Start && minutes < act.End
+}
+
+func parseWeekdays(value string) (weekdays uint8, err error) {
+	for _, item := range strings.Split(value, ",") {
+		start, end := item, item
+
+		if i := strings.Index(item, "-"); i != -1 {
+			start, end = item[:i], item[i+1:]
+		}
+
+		first, ok := weekdayNames[start]
+		if !ok {
+			return 0, fmt.Errorf("the week day '%s' is not one of '%s'", start, strings.Join(weekdayNamesOrdered, "', '"))
+		}
+
+		last, ok := weekdayNames[end]
+		if !ok {
+			return 0, fmt.Errorf("the week day '%s' is not one of '%s'", end, strings.Join(weekdayNamesOrdered, "', '"))
+		}
+
+		// Ranges such as 'fri-mon' wrap around the end of the week.
+		for day := first; ; day = (day + 1) % 7 {
+			weekdays |= 1 << uint(day)
+
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return weekdays, nil
+}
+
+func parseTimeWindow(value string) (start, end int, err error) {
+	i := strings.Index(value, "-")
+	if i == -1 {
+		return 0, 0, fmt.Errorf("the time window '%s' must have the format 'HH:MM-HH:MM'", value)
+	}
+
+	if start, err = parseTimeOfDay(value[:i]); err != nil {
+		return 0, 0, err
+	}
+
+	if end, err = parseTimeOfDay(value[i+1:]); err != nil {
+		return 0, 0, err
+	}
+
+	if end <= start {
+		return 0, 0, fmt.Errorf("the time window '%s' must end after it starts, windows which span midnight must be split in two", value)
+	}
+
+	return start, end, nil
+}
+
+func parseTimeOfDay(value string) (minutes int, err error) {
+	parts := strings.Split(value, ":")
+
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("the time '%s' must have the format 'HH:MM'", value)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 24 {
+		return 0, fmt.Errorf("the time '%s' must have hours between 00 and 24", value)
+	}
+
+	mins, err := strconv.Atoi(parts[1])
+	if err != nil || mins < 0 || mins > 59 || (hours == 24 && mins != 0) {
+		return 0, fmt.Errorf("the time '%s' must have minutes between 00 and 59 and can't be after 24:00", value)
+	}
+
+	return hours*60 + mins, nil
+}
+
+func schemaTimeToACL(schemaTime []string) (times []AccessControlTime) {
+	for _, value := range schemaTime {
+		act, err := NewAccessControlTime(value)
+		if err != nil {
+			continue
+		}
+
+		times = append(times, act)
+	}
+
+	return times
+}
@@ -0,0 +1,86 @@
+package authorization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccessControlTime(t *testing.T) {
+	weekdays := func(days ...time.Weekday) (mask uint8) {
+		for _, day := range days {
+			mask |= 1 << uint(day)
+		}
+
+		return mask
+	}
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected AccessControlTime
+		err      string
+	}{
+		{"ShouldParseDaysAndWindow", "mon-fri 08:00-18:00", AccessControlTime{Weekdays: weekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday), Start: 480, End: 1080}, ""},
+		{"ShouldParseDaysOnly", "sat,sun", AccessControlTime{Weekdays: weekdays(time.Saturday, time.Sunday), Start: 0, End: 1440}, ""},
+		{"ShouldParseWindowOnly", "09:30-17:15", AccessControlTime{Weekdays: weekdaysAll, Start: 570, End: 1035}, ""},
+		{"ShouldParseEndOfDay", "sun 22:00-24:00", AccessControlTime{Weekdays: weekdays(time.Sunday), Start: 1320, End: 1440}, ""},
+		{"ShouldParseWrappingDayRange", "fri-mon", AccessControlTime{Weekdays: weekdays(time.Friday, time.Saturday, time.Sunday, time.Monday), Start: 0, End: 1440}, ""},
+		{"ShouldParseMixedDaysAndRanges", "mon,wed-thu,sat", AccessControlTime{Weekdays: weekdays(time.Monday, time.Wednesday, time.Thursday, time.Saturday), Start: 0, End: 1440}, ""},
+		{"ShouldParseCaseInsensitiveWithExtraSpaces", "  MON-Fri   08:00-18:00 ", AccessControlTime{Weekdays: weekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday), Start: 480, End: 1080}, ""},
+		{"ShouldErrorEmpty", "", AccessControlTime{}, "must have the format '[days] [HH:MM-HH:MM]'"},
+		{"ShouldErrorTooManyFields", "mon 08:00-12:00 13:00-17:00", AccessControlTime{}, "must have the format '[days] [HH:MM-HH:MM]'"},
+		{"ShouldErrorInvalidDay", "monday", AccessControlTime{}, "the week day 'monday' is not one of 'mon', 'tue', 'wed', 'thu', 'fri', 'sat', 'sun'"},
+		{"ShouldErrorInvalidRangeEnd", "mon-friday 08:00-18:00", AccessControlTime{}, "the week day 'friday' is not one of 'mon', 'tue', 'wed', 'thu', 'fri', 'sat', 'sun'"},
+		{"ShouldErrorEmptyDayInList", "mon,,fri", AccessControlTime{}, "the week day '' is not one of 'mon', 'tue', 'wed', 'thu', 'fri', 'sat', 'sun'"},
+		{"ShouldErrorWindowWithoutEnd", "mon 08:00", AccessControlTime{}, "the time window '08:00' must have the format 'HH:MM-HH:MM'"},
+		{"ShouldErrorWindowBeforeDays", "08:00-18:00 mon", AccessControlTime{}, "the week day '08:00' is not one of 'mon', 'tue', 'wed', 'thu', 'fri', 'sat', 'sun'"},
+		{"ShouldErrorSingleDigitHour", "8:00-18:00", AccessControlTime{}, "the time '8:00' must have the format 'HH:MM'"},
+		{"ShouldErrorHoursOutOfRange", "08:00-25:00", AccessControlTime{}, "the time '25:00' must have hours between 00 and 24"},
+		{"ShouldErrorMinutesOutOfRange", "08:60-18:00", AccessControlTime{}, "the time '08:60' must have minutes between 00 and 59 and can't be after 24:00"},
+		{"ShouldErrorAfterEndOfDay", "08:00-24:30", AccessControlTime{}, "the time '24:30' must have minutes between 00 and 59 and can't be after 24:00"},
+		{"ShouldErrorNotNumeric", "ab:00-18:00", AccessControlTime{}, "the time 'ab:00' must have hours between 00 and 24"},
+		{"ShouldErrorEndBeforeStart", "22:00-06:00", AccessControlTime{}, "the time window '22:00-06:00' must end after it starts, windows which span midnight must be split in two"},
+		{"ShouldErrorEmptyWindow", "08:00-08:00", AccessControlTime{}, "the time window '08:00-08:00' must end after it starts, windows which span midnight must be split in two"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := NewAccessControlTime(tc.value)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, act)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestAccessControlTime_IsMatch(t *testing.T) {
+	act, err := NewAccessControlTime("mon-fri 08:00-18:00")
+	require.NoError(t, err)
+
+	// 2021-11-01 is a Monday.
+	testCases := []struct {
+		name     string
+		time     time.Time
+		expected bool
+	}{
+		{"ShouldMatchStart", time.Date(2021, 11, 1, 8, 0, 0, 0, time.UTC), true},
+		{"ShouldMatchWithinWindow", time.Date(2021, 11, 5, 12, 30, 0, 0, time.UTC), true},
+		{"ShouldMatchLastMinute", time.Date(2021, 11, 1, 17, 59, 59, 0, time.UTC), true},
+		{"ShouldNotMatchEnd", time.Date(2021, 11, 1, 18, 0, 0, 0, time.UTC), false},
+		{"ShouldNotMatchBeforeStart", time.Date(2021, 11, 1, 7, 59, 0, 0, time.UTC), false},
+		{"ShouldNotMatchWeekend", time.Date(2021, 11, 6, 12, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, act.IsMatch(tc.time))
+		})
+	}
+}
@@ -1,23 +1,32 @@
 package authorization
 
 import (
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 )
 
 // Authorizer the component in charge of checking whether a user can access a given resource.
 type Authorizer struct {
-	defaultPolicy Level
-	rules         []*AccessControlRule
-	configuration *schema.Configuration
+	defaultPolicy         Level
+	domainDefaultPolicies []*AccessControlDomainDefaultPolicy
+	rules                 []*AccessControlRule
+	trustedNetworks       []*net.IPNet
+	configuration         *schema.Configuration
 }
 
 // NewAuthorizer create an instance of authorizer with a given access control configuration.
 func NewAuthorizer(configuration *schema.Configuration) *Authorizer {
 	return &Authorizer{
-		defaultPolicy: PolicyToLevel(configuration.AccessControl.DefaultPolicy),
-		rules:         NewAccessControlRules(configuration.AccessControl),
-		configuration: configuration,
+		defaultPolicy:         PolicyToLevel(configuration.AccessControl.DefaultPolicy),
+		domainDefaultPolicies: NewAccessControlDomainDefaultPolicies(configuration.AccessControl),
+		rules:                 NewAccessControlRules(configuration.AccessControl),
+		trustedNetworks:       NewAccessControlTrustedNetworks(configuration.AccessControl),
+		configuration:         configuration,
 	}
 }
 
@@ -27,6 +36,12 @@ func (p Authorizer) IsSecondFactorEnabled() bool {
 		return true
 	}
 
+	for _, policy := range p.domainDefaultPolicies {
+		if policy.Policy == TwoFactor {
+			return true
+		}
+	}
+
 	for _, rule := range p.rules {
 		if rule.Policy == TwoFactor {
 			return true
@@ -44,27 +59,85 @@ func (p Authorizer) IsSecondFactorEnabled() bool {
 	return false
 }
 
-// GetRequiredLevel retrieve the required level of authorization to access the object.
+// GetRequiredLevel retrieve the required level of authorization to access the object. The two_factor level is
+// downgraded to one_factor when the subject is within one of the trusted networks.
 func (p Authorizer) GetRequiredLevel(subject Subject, object Object) Level {
+	level, _ := p.GetRequiredLevelAndSecondFactorMethods(subject, object)
+
+	return level
+}
+
+// GetRequiredLevelAndSecondFactorMethods retrieve the required level of authorization to access the object and the
+// second factor methods the subject must have authenticated with. The methods are only returned for the two_factor
+// level and an empty list means any second factor method is accepted.
+func (p Authorizer) GetRequiredLevelAndSecondFactorMethods(subject Subject, object Object) (level Level, methods []string) {
+	level, methods = p.getRequiredLevel(subject, object)
+
+	if level != TwoFactor {
+		return level, nil
+	}
+
+	if p.isTrustedNetwork(subject.IP) {
+		logging.Logger().Debugf("Subject %s is within a trusted network so the policy for object %s is downgraded from '%s' to '%s'.",
+			subject.String(), object.String(), twoFactor, oneFactor)
+
+		return OneFactor, nil
+	}
+
+	return level, methods
+}
+
+func (p Authorizer) isTrustedNetwork(ip net.IP) bool {
+	for _, network := range p.trustedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Authorizer) getRequiredLevel(subject Subject, object Object) (level Level, methods []string) {
 	logger := logging.Logger()
 
 	logger.Debugf("Check authorization of subject %s and object %s (method %s).",
 		subject.String(), object.String(), object.Method)
 
 	for _, rule := range p.rules {
+		if rule.IsMatchInvalidQuery(subject, object) {
+			logger.Warnf("ACL rule #%d has query criteria and the query of object %s (Method %s) can't be parsed "+
+				"unambiguously, applying policy '%s'", rule.Position, object.String(), object.Method, deny)
+
+			return Denied, nil
+		}
+
 		if rule.IsMatch(subject, object) {
 			logger.Tracef(traceFmtACLHitMiss, "HIT", rule.Position, subject.String(), object.String(), object.Method)
 
-			return rule.Policy
+			if logger.IsLevelEnabled(logrus.DebugLevel) {
+				logger.Debugf(debugFmtACLMatch, rule.Position, subject.String(), object.String(), object.Method,
+					strings.Join(rule.Criteria(), "', '"), LevelToPolicy(rule.Policy))
+			}
+
+			return rule.Policy, rule.SecondFactorMethods
 		}
 
 		logger.Tracef(traceFmtACLHitMiss, "MISS", rule.Position, subject.String(), object.String(), object.Method)
 	}
 
+	for _, policy := range p.domainDefaultPolicies {
+		if policy.IsMatch(subject, object) {
+			logger.Debugf("No matching rule for subject %s and url %s... Applying domain default policy #%d.",
+				subject.String(), object.String(), policy.Position)
+
+			return policy.Policy, nil
+		}
+	}
+
 	logger.Debugf("No matching rule for subject %s and url %s... Applying default policy.",
 		subject.String(), object.String())
 
-	return p.defaultPolicy
+	return p.defaultPolicy, nil
 }
 
 // GetRuleMatchResults iterates through the rules and produces a list of RuleMatchResult provided a subject and object.
@@ -81,6 +154,8 @@ func (p Authorizer) GetRuleMatchResults(subject Subject, object Object) (results
 			MatchDomain:        isMatchForDomains(subject, object, rule),
 			MatchResources:     isMatchForResources(object, rule),
 			MatchMethods:       isMatchForMethods(object, rule),
+			MatchQuery:         isMatchForQuery(object, rule),
+			MatchTime:          isMatchForTime(object, rule),
 			MatchNetworks:      isMatchForNetworks(subject, rule),
 			MatchSubjects:      isMatchForSubjects(subject, rule),
 			MatchSubjectsExact: isExactMatchForSubjects(subject, rule),
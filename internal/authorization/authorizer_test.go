@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,7 +35,7 @@ func NewAuthorizerTester(config schema.AccessControlConfiguration) *AuthorizerTe
 func (s *AuthorizerTester) CheckAuthorizations(t *testing.T, subject Subject, requestURI, method string, expectedLevel Level) {
 	targetURL, _ := url.ParseRequestURI(requestURI)
 
-	object := NewObject(targetURL, method)
+	object := NewObject(targetURL, method, time.Now())
 
 	level := s.GetRequiredLevel(subject, object)
 
@@ -44,7 +45,7 @@ func (s *AuthorizerTester) CheckAuthorizations(t *testing.T, subject Subject, re
 func (s *AuthorizerTester) GetRuleMatchResults(subject Subject, requestURI, method string) (results []RuleMatchResult) {
 	targetURL, _ := url.ParseRequestURI(requestURI)
 
-	object := NewObject(targetURL, method)
+	object := NewObject(targetURL, method, time.Now())
 
 	return s.Authorizer.GetRuleMatchResults(subject, object)
 }
@@ -67,6 +68,16 @@ func (b *AuthorizerTesterBuilder) WithRule(rule schema.ACLRule) *AuthorizerTeste
 	return b
 }
 
+func (b *AuthorizerTesterBuilder) WithDomainDefaultPolicy(policy schema.ACLDomainDefaultPolicy) *AuthorizerTesterBuilder {
+	b.config.DomainDefaultPolicies = append(b.config.DomainDefaultPolicies, policy)
+	return b
+}
+
+func (b *AuthorizerTesterBuilder) WithTrustedNetworks(networks ...string) *AuthorizerTesterBuilder {
+	b.config.TrustedNetworks = append(b.config.TrustedNetworks, networks...)
+	return b
+}
+
 func (b *AuthorizerTesterBuilder) Build() *AuthorizerTester {
 	return NewAuthorizerTester(b.config)
 }
@@ -129,6 +140,32 @@ func (s *AuthorizerSuite) TestShouldCheckDefaultDeniedConfig() {
 	tester.CheckAuthorizations(s.T(), UserWithoutGroups, "https://public.example.com/elsewhere", "GET", Denied)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckDomainDefaultPolicies() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithDomainDefaultPolicy(schema.ACLDomainDefaultPolicy{
+			Domains: []string{"internal.example.com"},
+			Policy:  oneFactor,
+		}).
+		WithDomainDefaultPolicy(schema.ACLDomainDefaultPolicy{
+			Domains: []string{"*.external.example.com", "external.example.com"},
+			Policy:  twoFactor,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"public.external.example.com"},
+			Policy:  bypass,
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), UserWithGroups, "https://internal.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), UserWithGroups, "https://external.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), UserWithGroups, "https://app.external.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), UserWithGroups, "https://public.external.example.com/", "GET", Bypass)
+	tester.CheckAuthorizations(s.T(), UserWithGroups, "https://other.example.com/", "GET", Denied)
+
+	s.Assert().True(tester.IsSecondFactorEnabled())
+}
+
 func (s *AuthorizerSuite) TestShouldCheckMultiDomainRule() {
 	tester := NewAuthorizerBuilder().
 		WithDefaultPolicy(deny).
@@ -357,6 +394,88 @@ func (s *AuthorizerSuite) TestShouldCheckUserMatching() {
 	tester.CheckAuthorizations(s.T(), Bob, "https://protected.example.com/", "GET", Denied)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckBasicAuthMatching() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains:   []string{"protected.example.com"},
+			Policy:    oneFactor,
+			Subjects:  [][]string{{"user:john"}},
+			BasicAuth: true,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"protected.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	johnBasicAuth := John
+	johnBasicAuth.BasicAuth = true
+
+	bobBasicAuth := Bob
+	bobBasicAuth.BasicAuth = true
+
+	tester.CheckAuthorizations(s.T(), johnBasicAuth, "https://protected.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://protected.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), bobBasicAuth, "https://protected.example.com/", "GET", TwoFactor)
+}
+
+func (s *AuthorizerSuite) TestShouldDowngradeTwoFactorForTrustedNetworks() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(twoFactor).
+		WithTrustedNetworks("10.0.0.0/24", "fec0::1").
+		WithRule(schema.ACLRule{
+			Domains: []string{"protected.example.com"},
+			Policy:  twoFactor,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"denied.example.com"},
+			Policy:  deny,
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), John, "https://protected.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), Sam, "https://protected.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), Sally, "https://protected.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://protected.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://denied.example.com/", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://other.example.com/", "GET", OneFactor)
+}
+
+func (s *AuthorizerSuite) TestShouldReturnSecondFactorMethods() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(twoFactor).
+		WithTrustedNetworks("10.0.0.0/24").
+		WithRule(schema.ACLRule{
+			Domains:             []string{"webauthn.example.com"},
+			Policy:              twoFactor,
+			SecondFactorMethods: []string{"WebAuthn"},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"protected.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	targetURL, _ := url.ParseRequestURI("https://webauthn.example.com/")
+
+	level, methods := tester.GetRequiredLevelAndSecondFactorMethods(Sally, NewObject(targetURL, "GET", time.Now()))
+	s.Assert().Equal(TwoFactor, level)
+	s.Assert().Equal([]string{SecondFactorMethodWebauthn}, methods)
+
+	level, methods = tester.GetRequiredLevelAndSecondFactorMethods(John, NewObject(targetURL, "GET", time.Now()))
+	s.Assert().Equal(OneFactor, level)
+	s.Assert().Nil(methods)
+
+	targetURL, _ = url.ParseRequestURI("https://protected.example.com/")
+
+	level, methods = tester.GetRequiredLevelAndSecondFactorMethods(Sally, NewObject(targetURL, "GET", time.Now()))
+	s.Assert().Equal(TwoFactor, level)
+	s.Assert().Nil(methods)
+
+	tester.CheckAuthorizations(s.T(), Sally, "https://webauthn.example.com/", "GET", TwoFactor)
+}
+
 func (s *AuthorizerSuite) TestShouldCheckGroupMatching() {
 	tester := NewAuthorizerBuilder().
 		WithDefaultPolicy(deny).
@@ -544,6 +663,138 @@ func (s *AuthorizerSuite) TestShouldMatchAnyDomainIfBlank() {
 	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://one.domain-two.com", "POST", Denied)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckTimeMatching() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains: []string{"admin.example.com"},
+			Policy:  twoFactor,
+			Time:    []string{"mon-fri 08:00-18:00", "sat 09:00-12:00"},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"admin.example.com"},
+			Policy:  oneFactor,
+			Time:    []string{"sun"},
+		}).
+		Build()
+
+	targetURL, _ := url.ParseRequestURI("https://admin.example.com/")
+
+	object := NewObject(targetURL, "GET", time.Now())
+
+	// 2021-11-01 is a Monday.
+	object.Time = time.Date(2021, 11, 1, 9, 0, 0, 0, time.Local)
+	s.Assert().Equal(TwoFactor, tester.GetRequiredLevel(John, object))
+
+	object.Time = time.Date(2021, 11, 1, 19, 0, 0, 0, time.Local)
+	s.Assert().Equal(Denied, tester.GetRequiredLevel(John, object))
+
+	object.Time = time.Date(2021, 11, 6, 11, 59, 0, 0, time.Local)
+	s.Assert().Equal(TwoFactor, tester.GetRequiredLevel(John, object))
+
+	object.Time = time.Date(2021, 11, 6, 12, 0, 0, 0, time.Local)
+	s.Assert().Equal(Denied, tester.GetRequiredLevel(John, object))
+
+	object.Time = time.Date(2021, 11, 7, 23, 0, 0, 0, time.Local)
+	s.Assert().Equal(OneFactor, tester.GetRequiredLevel(John, object))
+
+	results := tester.Authorizer.GetRuleMatchResults(John, object)
+
+	s.Require().Len(results, 2)
+	s.Assert().False(results[0].MatchTime)
+	s.Assert().False(results[0].IsMatch())
+	s.Assert().True(results[1].MatchTime)
+	s.Assert().True(results[1].IsMatch())
+}
+
+func (s *AuthorizerSuite) TestShouldCheckQueryMatching() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains: []string{"query.example.com"},
+			Policy:  twoFactor,
+			Query: [][]schema.ACLQueryRule{
+				{
+					{Key: "type", Value: "admin"},
+				},
+			},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"query.example.com"},
+			Policy:  bypass,
+			Query: [][]schema.ACLQueryRule{
+				{
+					{Operator: operatorEqual, Key: "type", Value: "public"},
+					{Operator: operatorAbsent, Key: "debug"},
+				},
+				{
+					{Operator: operatorPattern, Key: "token", Value: "^(abc123|xyz789)$"},
+				},
+			},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"query.example.com"},
+			Policy:  oneFactor,
+			Query: [][]schema.ACLQueryRule{
+				{
+					{Key: "preview"},
+					{Operator: operatorNotEqual, Key: "type", Value: "internal"},
+					{Operator: operatorNotPattern, Key: "id", Value: "^[0-9]+$"},
+				},
+			},
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?type=admin", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?type=public", "GET", Bypass)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?type=public&debug", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?type=public&type=admin", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?type=Public", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?token=abc123", "GET", Bypass)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?token=abc1234", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?preview", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?preview&type=internal", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?preview&id=abc", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?preview&id=123", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download", "GET", Denied)
+}
+
+func (s *AuthorizerSuite) TestShouldDenyQueryMatchingWithInvalidQuery() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(bypass).
+		WithRule(schema.ACLRule{
+			Domains: []string{"query.example.com"},
+			Policy:  twoFactor,
+			Query: [][]schema.ACLQueryRule{
+				{
+					{Operator: operatorEqual, Key: "type", Value: "admin"},
+				},
+			},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"query.example.com"},
+			Policy:  oneFactor,
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?type=admin", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?x=1;type=admin", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://query.example.com/download?type=%zz", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://other.example.com/download?x=1;type=admin", "GET", Bypass)
+
+	targetURL, _ := url.ParseRequestURI("https://query.example.com/download?x=1;type=admin")
+
+	object := NewObject(targetURL, "GET", time.Now())
+
+	s.Assert().True(object.QueryInvalid)
+
+	results := tester.Authorizer.GetRuleMatchResults(John, object)
+
+	s.Require().Len(results, 2)
+	s.Assert().False(results[0].MatchQuery)
+	s.Assert().False(results[0].IsMatch())
+}
+
 func (s *AuthorizerSuite) TestShouldMatchResourceWithSubjectRules() {
 	createSliceRegexRule := func(t *testing.T, rules []string) []regexp.Regexp {
 		result, err := stringSliceToRegexpSlice(rules)
@@ -717,6 +968,35 @@ func TestNewAuthorizer(t *testing.T) {
 	assert.Equal(t, "admins", group.Name)
 }
 
+func TestAccessControlRuleCriteria(t *testing.T) {
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Rules: []schema.ACLRule{
+				{
+					Domains: []string{"example.com"},
+					Policy:  twoFactor,
+				},
+				{
+					Domains:   []string{"example.com"},
+					Policy:    oneFactor,
+					Methods:   []string{"GET"},
+					Query:     [][]schema.ACLQueryRule{{{Key: "type"}}},
+					Time:      []string{"mon-fri"},
+					Networks:  []string{"10.0.0.0/8"},
+					Subjects:  [][]string{{"user:admin"}},
+					BasicAuth: true,
+				},
+			},
+		},
+	}
+
+	authorizer := NewAuthorizer(config)
+
+	assert.Equal(t, []string{"domain"}, authorizer.rules[0].Criteria())
+	assert.Equal(t, []string{"domain", "methods", "query", "time", "networks", "subject", "basic_auth"}, authorizer.rules[1].Criteria())
+}
+
 func TestAuthorizerIsSecondFactorEnabledRuleWithNoOIDC(t *testing.T) {
 	config := &schema.Configuration{
 		AccessControl: schema.AccessControlConfiguration{
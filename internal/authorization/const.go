@@ -1,5 +1,9 @@
 package authorization
 
+import (
+	"time"
+)
+
 // Level is the type representing an authorization level.
 type Level int
 
@@ -26,6 +30,43 @@ const (
 	deny      = "deny"
 )
 
+// Second factor methods which can be required by an access control rule.
+const (
+	SecondFactorMethodTOTP     = "totp"
+	SecondFactorMethodWebauthn = "webauthn"
+	SecondFactorMethodDuo      = "duo"
+)
+
+const (
+	operatorEqual      = "equal"
+	operatorNotEqual   = "not equal"
+	operatorPresent    = "present"
+	operatorAbsent     = "absent"
+	operatorPattern    = "pattern"
+	operatorNotPattern = "not pattern"
+)
+
+const (
+	minutesPerDay = 24 * 60
+
+	// weekdaysAll is the bit mask of all week days where the bit of each day is its time.Weekday.
+	weekdaysAll uint8 = 1<<7 - 1
+)
+
+var (
+	weekdayNames = map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	}
+
+	weekdayNamesOrdered = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+)
+
 const (
 	subexpNameUser  = "User"
 	subexpNameGroup = "Group"
@@ -36,4 +77,7 @@ var (
 	IdentitySubexpNames = []string{subexpNameUser, subexpNameGroup}
 )
 
-const traceFmtACLHitMiss = "ACL %s Position %d for subject %s and object %s (Method %s)"
+const (
+	traceFmtACLHitMiss = "ACL %s Position %d for subject %s and object %s (Method %s)"
+	debugFmtACLMatch   = "ACL rule #%d matched subject %s and object %s (Method %s) on criteria '%s', applying policy '%s'"
+)
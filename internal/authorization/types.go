@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // SubjectMatcher is a matcher that takes a subject.
@@ -20,9 +21,10 @@ type SubjectObjectMatcher interface {
 
 // Subject represents the identity of a user for the purposes of ACL matching.
 type Subject struct {
-	Username string
-	Groups   []string
-	IP       net.IP
+	Username  string
+	Groups    []string
+	IP        net.IP
+	BasicAuth bool
 }
 
 // String returns a string representation of the Subject.
@@ -41,6 +43,15 @@ type Object struct {
 	Domain string
 	Path   string
 	Method string
+	Query  url.Values
+
+	// Time is the time of the request, the time criteria of the rules are evaluated against it in the local time zone
+	// of the server.
+	Time time.Time
+
+	// QueryInvalid is true when the raw query of the URL couldn't be parsed unambiguously, in which case the Query
+	// only contains the parts which could be parsed.
+	QueryInvalid bool
 }
 
 // String is a string representation of the Object.
@@ -48,17 +59,24 @@ func (o Object) String() string {
 	return fmt.Sprintf("%s://%s%s", o.Scheme, o.Domain, o.Path)
 }
 
-// NewObjectRaw creates a new Object type from a URL and a method header.
-func NewObjectRaw(targetURL *url.URL, method []byte) (object Object) {
-	return NewObject(targetURL, string(method))
+// NewObjectRaw creates a new Object type from a URL, a method header, and the time of the request.
+func NewObjectRaw(targetURL *url.URL, method []byte, now time.Time) (object Object) {
+	return NewObject(targetURL, string(method), now)
 }
 
-// NewObject creates a new Object type from a URL and a method header.
-func NewObject(targetURL *url.URL, method string) (object Object) {
+// NewObject creates a new Object type from a URL, a method header, and the time of the request. The query is marked as
+// invalid when it fails to parse or contains a semicolon, as parsers disagree on whether the semicolon separates query
+// parameters.
+func NewObject(targetURL *url.URL, method string, now time.Time) (object Object) {
+	query, err := url.ParseQuery(targetURL.RawQuery)
+
 	object = Object{
-		Scheme: targetURL.Scheme,
-		Domain: targetURL.Hostname(),
-		Method: method,
+		Scheme:       targetURL.Scheme,
+		Domain:       targetURL.Hostname(),
+		Method:       method,
+		Query:        query,
+		Time:         now,
+		QueryInvalid: err != nil || strings.Contains(targetURL.RawQuery, ";"),
 	}
 
 	if targetURL.RawQuery == "" {
@@ -79,6 +97,8 @@ type RuleMatchResult struct {
 	MatchDomain        bool
 	MatchResources     bool
 	MatchMethods       bool
+	MatchQuery         bool
+	MatchTime          bool
 	MatchNetworks      bool
 	MatchSubjects      bool
 	MatchSubjectsExact bool
@@ -86,10 +106,10 @@ type RuleMatchResult struct {
 
 // IsMatch returns true if all the criteria matched.
 func (r RuleMatchResult) IsMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchQuery && r.MatchTime && r.MatchNetworks && r.MatchSubjectsExact
 }
 
 // IsPotentialMatch returns true if the rule is potentially a match.
 func (r RuleMatchResult) IsPotentialMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjects && !r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchQuery && r.MatchTime && r.MatchNetworks && r.MatchSubjects && !r.MatchSubjectsExact
 }
@@ -3,6 +3,7 @@ package authorization
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,8 +14,11 @@ func TestShouldAppendQueryParamToURL(t *testing.T) {
 
 	require.NoError(t, err)
 
-	object := NewObject(targetURL, "GET")
+	now := time.Date(2021, 11, 1, 9, 0, 0, 0, time.Local)
 
+	object := NewObject(targetURL, "GET", now)
+
+	assert.Equal(t, now, object.Time)
 	assert.Equal(t, "domain.example.com", object.Domain)
 	assert.Equal(t, "GET", object.Method)
 	assert.Equal(t, "/api?type=none", object.Path)
@@ -26,7 +30,7 @@ func TestShouldCreateNewObjectFromRaw(t *testing.T) {
 
 	require.NoError(t, err)
 
-	object := NewObjectRaw(targetURL, []byte("GET"))
+	object := NewObjectRaw(targetURL, []byte("GET"), time.Now())
 
 	assert.Equal(t, "domain.example.com", object.Domain)
 	assert.Equal(t, "GET", object.Method)
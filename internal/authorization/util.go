@@ -98,13 +98,21 @@ func schemaMethodsToACL(methodRules []string) (methods []string) {
 	return methods
 }
 
+func schemaSecondFactorMethodsToACL(methodRules []string) (methods []string) {
+	for _, method := range methodRules {
+		methods = append(methods, strings.ToLower(method))
+	}
+
+	return methods
+}
+
 func schemaNetworksToACL(networkRules []string, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) (networks []*net.IPNet) {
 	for _, network := range networkRules {
 		if _, ok := networksMap[network]; !ok {
 			if _, ok := networksCacheMap[network]; ok {
 				networks = append(networks, networksCacheMap[network])
 			} else {
-				cidr, err := parseNetwork(network)
+				cidr, err := ParseNetwork(network)
 				if err == nil {
 					networks = append(networks, cidr)
 					networksCacheMap[cidr.String()] = cidr
@@ -132,7 +140,7 @@ func parseSchemaNetworks(schemaNetworks []schema.ACLNetwork) (networksMap map[st
 		var networks []*net.IPNet
 
 		for _, networkRule := range aclNetwork.Networks {
-			cidr, err := parseNetwork(networkRule)
+			cidr, err := ParseNetwork(networkRule)
 			if err == nil {
 				networks = append(networks, cidr)
 				networksCacheMap[cidr.String()] = cidr
@@ -151,7 +159,8 @@ func parseSchemaNetworks(schemaNetworks []schema.ACLNetwork) (networksMap map[st
 	return networksMap, networksCacheMap
 }
 
-func parseNetwork(networkRule string) (cidr *net.IPNet, err error) {
+// ParseNetwork parses an IP or CIDR network rule into a *net.IPNet, single IP addresses are treated as a /32 or /128.
+func ParseNetwork(networkRule string) (cidr *net.IPNet, err error) {
 	if !strings.Contains(networkRule, "/") {
 		ip := net.ParseIP(networkRule)
 		if ip.To4() != nil {
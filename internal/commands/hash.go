@@ -22,11 +22,13 @@ func NewHashPasswordCmd() (cmd *cobra.Command) {
 	}
 
 	cmd.Flags().BoolP("sha512", "z", false, fmt.Sprintf("use sha512 as the algorithm (changes iterations to %d, change with -i)", schema.DefaultPasswordSHA512Configuration.Iterations))
+	cmd.Flags().Bool("scrypt", false, fmt.Sprintf("use scrypt as the algorithm (changes iterations to %d, the base 2 logarithm of the cost N, change with -i)", schema.DefaultPasswordScryptConfiguration.Iterations))
 	cmd.Flags().IntP("iterations", "i", schema.DefaultPasswordConfiguration.Iterations, "set the number of hashing iterations")
 	cmd.Flags().StringP("salt", "s", "", "set the salt string")
 	cmd.Flags().IntP("memory", "m", schema.DefaultPasswordConfiguration.Memory, "[argon2id] set the amount of memory param (in MB)")
-	cmd.Flags().IntP("parallelism", "p", schema.DefaultPasswordConfiguration.Parallelism, "[argon2id] set the parallelism param")
-	cmd.Flags().IntP("key-length", "k", schema.DefaultPasswordConfiguration.KeyLength, "[argon2id] set the key length param")
+	cmd.Flags().IntP("parallelism", "p", schema.DefaultPasswordConfiguration.Parallelism, "[argon2id/scrypt] set the parallelism param")
+	cmd.Flags().IntP("block-size", "r", schema.DefaultPasswordScryptConfiguration.BlockSize, "[scrypt] set the block size param")
+	cmd.Flags().IntP("key-length", "k", schema.DefaultPasswordConfiguration.KeyLength, "[argon2id/scrypt] set the key length param")
 	cmd.Flags().IntP("salt-length", "l", schema.DefaultPasswordConfiguration.SaltLength, "set the auto-generated salt length")
 	cmd.Flags().StringSliceP("config", "c", []string{}, "Configuration files")
 
@@ -37,12 +39,14 @@ func cmdHashPasswordRun(cmd *cobra.Command, args []string) {
 	logger := logging.Logger()
 
 	sha512, _ := cmd.Flags().GetBool("sha512")
+	scrypt, _ := cmd.Flags().GetBool("scrypt")
 	iterations, _ := cmd.Flags().GetInt("iterations")
 	salt, _ := cmd.Flags().GetString("salt")
 	keyLength, _ := cmd.Flags().GetInt("key-length")
 	saltLength, _ := cmd.Flags().GetInt("salt-length")
 	memory, _ := cmd.Flags().GetInt("memory")
 	parallelism, _ := cmd.Flags().GetInt("parallelism")
+	blockSize, _ := cmd.Flags().GetInt("block-size")
 	configs, _ := cmd.Flags().GetStringSlice("config")
 
 	if len(configs) > 0 {
@@ -55,34 +59,46 @@ func cmdHashPasswordRun(cmd *cobra.Command, args []string) {
 
 		if config.AuthenticationBackend.File != nil && config.AuthenticationBackend.File.Password != nil {
 			sha512 = config.AuthenticationBackend.File.Password.Algorithm == "sha512"
+			scrypt = config.AuthenticationBackend.File.Password.Algorithm == "scrypt"
 			iterations = config.AuthenticationBackend.File.Password.Iterations
 			keyLength = config.AuthenticationBackend.File.Password.KeyLength
 			saltLength = config.AuthenticationBackend.File.Password.SaltLength
 			memory = config.AuthenticationBackend.File.Password.Memory
 			parallelism = config.AuthenticationBackend.File.Password.Parallelism
+			blockSize = config.AuthenticationBackend.File.Password.BlockSize
 		}
 	}
 
 	var (
-		hash      string
-		algorithm authentication.CryptAlgo
+		hash string
+		err  error
 	)
 
-	if sha512 {
+	if salt != "" {
+		salt = crypt.Base64Encoding.EncodeToString([]byte(salt))
+	}
+
+	switch {
+	case scrypt:
 		if iterations == schema.DefaultPasswordConfiguration.Iterations {
-			iterations = schema.DefaultPasswordSHA512Configuration.Iterations
+			iterations = schema.DefaultPasswordScryptConfiguration.Iterations
 		}
 
-		algorithm = authentication.HashingAlgorithmSHA512
-	} else {
-		algorithm = authentication.HashingAlgorithmArgon2id
-	}
+		if len(configs) == 0 && !cmd.Flags().Changed("parallelism") {
+			parallelism = schema.DefaultPasswordScryptConfiguration.Parallelism
+		}
 
-	if salt != "" {
-		salt = crypt.Base64Encoding.EncodeToString([]byte(salt))
+		hash, err = authentication.HashPasswordScrypt(args[0], salt, iterations, blockSize, parallelism, keyLength, saltLength)
+	case sha512:
+		if iterations == schema.DefaultPasswordConfiguration.Iterations {
+			iterations = schema.DefaultPasswordSHA512Configuration.Iterations
+		}
+
+		hash, err = authentication.HashPassword(args[0], salt, authentication.HashingAlgorithmSHA512, iterations, memory*1024, parallelism, keyLength, saltLength)
+	default:
+		hash, err = authentication.HashPassword(args[0], salt, authentication.HashingAlgorithmArgon2id, iterations, memory*1024, parallelism, keyLength, saltLength)
 	}
 
-	hash, err := authentication.HashPassword(args[0], salt, algorithm, iterations, memory*1024, parallelism, keyLength, saltLength)
 	if err != nil {
 		logging.Logger().Fatalf("Error occurred during hashing: %v\n", err)
 	}
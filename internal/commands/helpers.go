@@ -29,7 +29,7 @@ func getStorageProvider() (provider storage.Provider) {
 
 func getProviders() (providers middlewares.Providers, warnings []error, errors []error) {
 	// TODO: Adjust this so the CertPool can be used like a provider.
-	autheliaCertPool, warnings, errors := utils.NewX509CertPool(config.CertificatesDirectory)
+	autheliaCertPool, warnings, errors := utils.NewX509CertPool(config.CertificatesDirectory, config.TLS.TrustedCA)
 	if len(warnings) != 0 || len(errors) != 0 {
 		return providers, warnings, errors
 	}
@@ -69,6 +69,10 @@ func getProviders() (providers middlewares.Providers, warnings []error, errors [
 		errors = append(errors, err)
 	}
 
+	if oidcProvider.Store != nil && config.AuthenticationBackend.PasswordReset.RevokeSessions {
+		oidcProvider.Store.EnableUserRequestTracking()
+	}
+
 	totpProvider := totp.NewTimeBasedProvider(config.TOTP)
 
 	passwordPolicyProvider := middlewares.NewPasswordPolicyProvider(config.PasswordPolicy)
@@ -12,6 +12,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/server"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -77,6 +78,10 @@ func cmdRootRun(_ *cobra.Command, _ []string) {
 
 	doStartupChecks(config, &providers)
 
+	if config.Notifier.PasswordReset.OnFailure == schema.NotifierOnFailureQueue {
+		go notification.NewQueueWorker(providers.Notifier, providers.StorageProvider, config.Notifier.PasswordReset.QueueInterval).Run()
+	}
+
 	server.Start(*config, providers)
 }
 
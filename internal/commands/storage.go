@@ -210,6 +210,7 @@ func newStorageMigrateUpCmd() (cmd *cobra.Command) {
 	}
 
 	cmd.Flags().IntP("target", "t", 0, "sets the version to migrate to, by default this is the latest version")
+	cmd.Flags().Bool("dry-run", false, "logs the SQL of the migrations instead of applying them")
 
 	return cmd
 }
@@ -225,6 +226,7 @@ func newStorageMigrateDownCmd() (cmd *cobra.Command) {
 	cmd.Flags().IntP("target", "t", 0, "sets the version to migrate to")
 	cmd.Flags().Bool("pre1", false, "sets pre1 as the version to migrate to")
 	cmd.Flags().Bool("destroy-data", false, "confirms you want to destroy data with this migration")
+	cmd.Flags().Bool("dry-run", false, "logs the SQL of the migrations instead of applying them")
 
 	return cmd
 }
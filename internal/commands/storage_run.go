@@ -67,6 +67,7 @@ func storagePersistentPreRunE(cmd *cobra.Command, _ []string) (err error) {
 		"digits":    "totp.digits",
 		"algorithm": "totp.algorithm",
 		"issuer":    "totp.issuer",
+		"dry-run":   "storage.migrate.dry_run",
 	}
 
 	sources = append(sources, configuration.NewEnvironmentSource(configuration.DefaultEnvPrefix, configuration.DefaultEnvDelimiter))
@@ -510,9 +511,9 @@ func newStorageMigrationRunE(up bool) func(cmd *cobra.Command, args []string) (e
 		case up:
 			switch cmd.Flags().Changed("target") {
 			case true:
-				return provider.SchemaMigrate(ctx, true, target)
+				err = provider.SchemaMigrate(ctx, true, target)
 			default:
-				return provider.SchemaMigrate(ctx, true, storage.SchemaLatest)
+				err = provider.SchemaMigrate(ctx, true, storage.SchemaLatest)
 			}
 		default:
 			if pre1, err = cmd.Flags().GetBool("pre1"); err != nil {
@@ -523,17 +524,25 @@ func newStorageMigrationRunE(up bool) func(cmd *cobra.Command, args []string) (e
 				return errors.New("must set target")
 			}
 
-			if err = storageMigrateDownConfirmDestroy(cmd); err != nil {
-				return err
+			if !config.Storage.Migrate.DryRun {
+				if err = storageMigrateDownConfirmDestroy(cmd); err != nil {
+					return err
+				}
 			}
 
 			switch {
 			case pre1:
-				return provider.SchemaMigrate(ctx, false, -1)
+				err = provider.SchemaMigrate(ctx, false, -1)
 			default:
-				return provider.SchemaMigrate(ctx, false, target)
+				err = provider.SchemaMigrate(ctx, false, target)
 			}
 		}
+
+		if errors.Is(err, storage.ErrSchemaMigrateDryRun) {
+			return nil
+		}
+
+		return err
 	}
 }
 
@@ -6,9 +6,17 @@ import (
 
 // AccessControlConfiguration represents the configuration related to ACLs.
 type AccessControlConfiguration struct {
-	DefaultPolicy string       `koanf:"default_policy"`
-	Networks      []ACLNetwork `koanf:"networks"`
-	Rules         []ACLRule    `koanf:"rules"`
+	DefaultPolicy         string                   `koanf:"default_policy"`
+	DomainDefaultPolicies []ACLDomainDefaultPolicy `koanf:"domain_default_policies"`
+	Networks              []ACLNetwork             `koanf:"networks"`
+	TrustedNetworks       []string                 `koanf:"trusted_networks"`
+	Rules                 []ACLRule                `koanf:"rules"`
+}
+
+// ACLDomainDefaultPolicy represents the default policy applied to a list of domains when no rule matches.
+type ACLDomainDefaultPolicy struct {
+	Domains []string `koanf:"domain"`
+	Policy  string   `koanf:"policy"`
 }
 
 // ACLNetwork represents one ACL network group entry.
@@ -19,13 +27,24 @@ type ACLNetwork struct {
 
 // ACLRule represents one ACL rule entry.
 type ACLRule struct {
-	Domains      []string        `koanf:"domain"`
-	DomainsRegex []regexp.Regexp `koanf:"domain_regex"`
-	Policy       string          `koanf:"policy"`
-	Subjects     [][]string      `koanf:"subject"`
-	Networks     []string        `koanf:"networks"`
-	Resources    []regexp.Regexp `koanf:"resources"`
-	Methods      []string        `koanf:"methods"`
+	Domains             []string         `koanf:"domain"`
+	DomainsRegex        []regexp.Regexp  `koanf:"domain_regex"`
+	Policy              string           `koanf:"policy"`
+	Subjects            [][]string       `koanf:"subject"`
+	Networks            []string         `koanf:"networks"`
+	Resources           []regexp.Regexp  `koanf:"resources"`
+	Methods             []string         `koanf:"methods"`
+	Query               [][]ACLQueryRule `koanf:"query"`
+	Time                []string         `koanf:"time"`
+	BasicAuth           bool             `koanf:"basic_auth"`
+	SecondFactorMethods []string         `koanf:"methods_2fa"`
+}
+
+// ACLQueryRule represents one ACL query criteria which matches a query parameter of the request.
+type ACLQueryRule struct {
+	Operator string `koanf:"operator"`
+	Key      string `koanf:"key"`
+	Value    string `koanf:"value"`
 }
 
 // DefaultACLNetwork represents the default configuration related to access control network group configuration.
@@ -13,6 +13,10 @@ type LDAPAuthenticationBackendConfiguration struct {
 	StartTLS       bool          `koanf:"start_tls"`
 	TLS            *TLSConfig    `koanf:"tls"`
 
+	FollowReferrals bool     `koanf:"follow_referrals"`
+	ReferralHops    int      `koanf:"referral_hops"`
+	ReferralHosts   []string `koanf:"referral_hosts"`
+
 	BaseDN string `koanf:"base_dn"`
 
 	AdditionalUsersDN string `koanf:"additional_users_dn"`
@@ -26,6 +30,8 @@ type LDAPAuthenticationBackendConfiguration struct {
 	MailAttribute        string `koanf:"mail_attribute"`
 	DisplayNameAttribute string `koanf:"display_name_attribute"`
 
+	LogAllowedAttributes []string `koanf:"log_allowed_attributes"`
+
 	User     string `koanf:"user"`
 	Password string `koanf:"password"`
 }
@@ -44,6 +50,7 @@ type PasswordConfiguration struct {
 	Algorithm   string `koanf:"algorithm"`
 	Memory      int    `koanf:"memory"`
 	Parallelism int    `koanf:"parallelism"`
+	BlockSize   int    `koanf:"block_size"`
 }
 
 // AuthenticationBackendConfiguration represents the configuration related to the authentication backend.
@@ -53,13 +60,24 @@ type AuthenticationBackendConfiguration struct {
 
 	PasswordReset PasswordResetAuthenticationBackendConfiguration `koanf:"password_reset"`
 
-	DisableResetPassword bool   `koanf:"disable_reset_password"`
-	RefreshInterval      string `koanf:"refresh_interval"`
+	DisableResetPassword  bool          `koanf:"disable_reset_password"`
+	RefreshInterval       string        `koanf:"refresh_interval"`
+	VerifyTimeout         time.Duration `koanf:"verify_timeout"`
+	MaxConcurrentRequests int           `koanf:"max_concurrent_requests"`
+
+	VerifyResponseHeaders []VerifyResponseHeaderConfiguration `koanf:"verify_response_headers"`
+}
+
+// VerifyResponseHeaderConfiguration represents a header added to the verify endpoint response for authorized users.
+type VerifyResponseHeaderConfiguration struct {
+	Name   string `koanf:"name"`
+	Source string `koanf:"source"`
 }
 
 // PasswordResetAuthenticationBackendConfiguration represents the configuration related to password reset functionality.
 type PasswordResetAuthenticationBackendConfiguration struct {
-	CustomURL url.URL `koanf:"custom_url"`
+	CustomURL      url.URL `koanf:"custom_url"`
+	RevokeSessions bool    `koanf:"revoke_sessions"`
 }
 
 // DefaultPasswordConfiguration represents the default configuration related to Argon2id hashing.
@@ -89,6 +107,16 @@ var DefaultPasswordSHA512Configuration = PasswordConfiguration{
 	Algorithm:  "sha512",
 }
 
+// DefaultPasswordScryptConfiguration represents the default configuration related to Scrypt hashing.
+var DefaultPasswordScryptConfiguration = PasswordConfiguration{
+	Iterations:  16,
+	KeyLength:   32,
+	SaltLength:  16,
+	Algorithm:   "scrypt",
+	Parallelism: 1,
+	BlockSize:   8,
+}
+
 // DefaultLDAPAuthenticationBackendConfiguration represents the default LDAP config.
 var DefaultLDAPAuthenticationBackendConfiguration = LDAPAuthenticationBackendConfiguration{
 	Implementation:       LDAPImplementationCustom,
@@ -97,6 +125,7 @@ var DefaultLDAPAuthenticationBackendConfiguration = LDAPAuthenticationBackendCon
 	DisplayNameAttribute: "displayName",
 	GroupNameAttribute:   "cn",
 	Timeout:              time.Second * 5,
+	ReferralHops:         5,
 	TLS: &TLSConfig{
 		MinimumVersion: "TLS1.2",
 	},
@@ -6,7 +6,9 @@ type Configuration struct {
 	CertificatesDirectory string `koanf:"certificates_directory"`
 	JWTSecret             string `koanf:"jwt_secret"`
 	DefaultRedirectionURL string `koanf:"default_redirection_url"`
+	RememberLast2FAMethod bool   `koanf:"remember_last_2fa_method"`
 
+	TLS                   GlobalTLSConfiguration             `koanf:"tls"`
 	Log                   LogConfiguration                   `koanf:"log"`
 	IdentityProviders     IdentityProvidersConfiguration     `koanf:"identity_providers"`
 	AuthenticationBackend AuthenticationBackendConfiguration `koanf:"authentication_backend"`
@@ -21,4 +23,7 @@ type Configuration struct {
 	Server                ServerConfiguration                `koanf:"server"`
 	Webauthn              WebauthnConfiguration              `koanf:"webauthn"`
 	PasswordPolicy        PasswordPolicyConfiguration        `koanf:"password_policy"`
+	TrustedDevice         TrustedDeviceConfiguration         `koanf:"trusted_device"`
+
+	ConfigurationValidation ConfigurationValidationConfiguration `koanf:"configuration"`
 }
@@ -0,0 +1,11 @@
+package schema
+
+// ConfigurationValidationConfiguration represents the configuration of how the configuration itself is validated.
+type ConfigurationValidationConfiguration struct {
+	Strict bool `koanf:"strict"`
+}
+
+// DefaultConfigurationValidationConfiguration represents the default values of the ConfigurationValidationConfiguration.
+var DefaultConfigurationValidationConfiguration = ConfigurationValidationConfiguration{
+	Strict: false,
+}
@@ -28,6 +28,31 @@ const (
 	LDAPImplementationActiveDirectory = "activedirectory"
 )
 
+const (
+	// NotifierOnFailureFail is the on_failure value which fails the request when the notification can't be sent.
+	NotifierOnFailureFail = "fail"
+
+	// NotifierOnFailureQueue is the on_failure value which queues the notification when it can't be sent.
+	NotifierOnFailureQueue = "queue"
+)
+
+const (
+	// VerifyResponseHeaderSourceUsername is the verify response header source for the username of the user.
+	VerifyResponseHeaderSourceUsername = "username"
+
+	// VerifyResponseHeaderSourceDisplayName is the verify response header source for the display name of the user.
+	VerifyResponseHeaderSourceDisplayName = "display_name"
+
+	// VerifyResponseHeaderSourceEmail is the verify response header source for the primary email address of the user.
+	VerifyResponseHeaderSourceEmail = "email"
+
+	// VerifyResponseHeaderSourceEmails is the verify response header source for all email addresses of the user.
+	VerifyResponseHeaderSourceEmails = "emails"
+
+	// VerifyResponseHeaderSourceGroups is the verify response header source for the groups of the user.
+	VerifyResponseHeaderSourceGroups = "groups"
+)
+
 // TOTP Algorithm.
 const (
 	TOTPAlgorithmSHA1   = "SHA1"
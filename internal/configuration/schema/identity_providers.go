@@ -13,20 +13,50 @@ type OpenIDConnectConfiguration struct {
 	HMACSecret       string `koanf:"hmac_secret"`
 	IssuerPrivateKey string `koanf:"issuer_private_key"`
 
+	IssuerPrivateKeys []OpenIDConnectIssuerPrivateKeyConfiguration `koanf:"issuer_private_keys"`
+
 	AccessTokenLifespan   time.Duration `koanf:"access_token_lifespan"`
 	AuthorizeCodeLifespan time.Duration `koanf:"authorize_code_lifespan"`
 	IDTokenLifespan       time.Duration `koanf:"id_token_lifespan"`
 	RefreshTokenLifespan  time.Duration `koanf:"refresh_token_lifespan"`
 
 	EnableClientDebugMessages bool `koanf:"enable_client_debug_messages"`
+	EnableConsentRegulation   bool `koanf:"enable_consent_regulation"`
 	MinimumParameterEntropy   int  `koanf:"minimum_parameter_entropy"`
+	MaximumDebugBodyLength    int  `koanf:"maximum_debug_body_length"`
+
+	ConsentRateLimit OpenIDConnectClientRateLimitConfiguration `koanf:"consent_rate_limit"`
+
+	MaximumRequestedScopes    int `koanf:"maximum_requested_scopes"`
+	MaximumRequestedAudiences int `koanf:"maximum_requested_audiences"`
+
+	TokenEndpointMaximumBodySize int                                       `koanf:"token_endpoint_maximum_body_size"`
+	TokenEndpointRateLimit       OpenIDConnectClientRateLimitConfiguration `koanf:"token_endpoint_rate_limit"`
+	TokenEndpointTrustedProxies  []string                                  `koanf:"token_endpoint_trusted_proxies"`
 
 	EnforcePKCE              string `koanf:"enforce_pkce"`
 	EnablePKCEPlainChallenge bool   `koanf:"enable_pkce_plain_challenge"`
 
+	EnforceIntrospectionAudience bool `koanf:"enforce_introspection_audience"`
+	EnforceHTTPSRedirectURIs     bool `koanf:"enforce_https_redirect_uris"`
+
+	DisableImplicitFlow bool `koanf:"disable_implicit_flow"`
+
+	AllowedResponseTypes []string `koanf:"allowed_response_types"`
+
+	PreferredUsernameClaim string `koanf:"preferred_username_claim"`
+
+	ConsentAuditLogPath string `koanf:"consent_audit_log_path"`
+
 	Clients []OpenIDConnectClientConfiguration `koanf:"clients"`
 }
 
+// OpenIDConnectIssuerPrivateKeyConfiguration configuration for an additional OpenID Connect issuer private key.
+type OpenIDConnectIssuerPrivateKeyConfiguration struct {
+	KeyID string `koanf:"key_id"`
+	Key   string `koanf:"key"`
+}
+
 // OpenIDConnectClientConfiguration configuration for an OpenID Connect client.
 type OpenIDConnectClientConfiguration struct {
 	ID          string `koanf:"id"`
@@ -34,6 +64,9 @@ type OpenIDConnectClientConfiguration struct {
 	Secret      string `koanf:"secret"`
 	Public      bool   `koanf:"public"`
 
+	SecretNext      string `koanf:"secret_next"`
+	SecretExpiresAt string `koanf:"secret_expires_at"`
+
 	Policy string `koanf:"authorization_policy"`
 
 	Audience      []string `koanf:"audience"`
@@ -44,6 +77,31 @@ type OpenIDConnectClientConfiguration struct {
 	ResponseModes []string `koanf:"response_modes"`
 
 	UserinfoSigningAlgorithm string `koanf:"userinfo_signing_algorithm"`
+	SigningKeyID             string `koanf:"signing_key_id"`
+
+	TokenEndpointRateLimit OpenIDConnectClientRateLimitConfiguration `koanf:"token_endpoint_rate_limit"`
+
+	AccessTokenLifespan   time.Duration `koanf:"access_token_lifespan"`
+	AuthorizeCodeLifespan time.Duration `koanf:"authorize_code_lifespan"`
+	IDTokenLifespan       time.Duration `koanf:"id_token_lifespan"`
+	RefreshTokenLifespan  time.Duration `koanf:"refresh_token_lifespan"`
+
+	RefreshTokenAbsoluteLifetime time.Duration `koanf:"refresh_token_absolute_lifetime"`
+
+	RefreshSessionActivity bool `koanf:"refresh_session_activity"`
+
+	RequireState bool `koanf:"require_state"`
+
+	AllowedNetworks []string `koanf:"allowed_networks"`
+
+	Claims map[string]string `koanf:"claims"`
+}
+
+// OpenIDConnectClientRateLimitConfiguration configuration for the rate limit of an OpenID Connect client or of the
+// remote IP's of an OpenID Connect endpoint.
+type OpenIDConnectClientRateLimitConfiguration struct {
+	RequestsPerSecond float64 `koanf:"requests_per_second"`
+	Burst             int     `koanf:"burst"`
 }
 
 // DefaultOpenIDConnectConfiguration contains defaults for OIDC.
@@ -53,6 +111,13 @@ var DefaultOpenIDConnectConfiguration = OpenIDConnectConfiguration{
 	IDTokenLifespan:       time.Hour,
 	RefreshTokenLifespan:  time.Minute * 90,
 	EnforcePKCE:           "public_clients_only",
+
+	PreferredUsernameClaim: "username",
+
+	MaximumRequestedScopes:    20,
+	MaximumRequestedAudiences: 20,
+
+	MaximumDebugBodyLength: 1024,
 }
 
 // DefaultOpenIDConnectClientConfiguration contains defaults for OIDC Clients.
@@ -6,10 +6,16 @@ type LogConfiguration struct {
 	Format     string `koanf:"format"`
 	FilePath   string `koanf:"file_path"`
 	KeepStdout bool   `koanf:"keep_stdout"`
+
+	RedactedFields []string `koanf:"redacted_fields"`
 }
 
 // DefaultLoggingConfiguration is the default logging configuration.
 var DefaultLoggingConfiguration = LogConfiguration{
 	Level:  "info",
 	Format: "text",
+	RedactedFields: []string{
+		"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+		"code", "access_token", "id_token", "refresh_token", "token", "client_secret", "password",
+	},
 }
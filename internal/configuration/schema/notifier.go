@@ -24,6 +24,24 @@ type SMTPNotifierConfiguration struct {
 	DisableRequireTLS   bool          `koanf:"disable_require_tls"`
 	DisableHTMLEmails   bool          `koanf:"disable_html_emails"`
 	TLS                 *TLSConfig    `koanf:"tls"`
+
+	Auth *SMTPNotifierAuthConfiguration `koanf:"auth"`
+
+	Fallback *SMTPNotifierConfiguration `koanf:"fallback"`
+}
+
+// SMTPNotifierAuthConfiguration represents the configuration of the alternative SMTP authentication mechanisms.
+type SMTPNotifierAuthConfiguration struct {
+	XOAUTH2 *SMTPNotifierXOAUTH2Configuration `koanf:"xoauth2"`
+}
+
+// SMTPNotifierXOAUTH2Configuration represents the configuration of the OAuth 2.0 client used to obtain the access token
+// for the XOAUTH2 SMTP authentication mechanism with the client credentials grant.
+type SMTPNotifierXOAUTH2Configuration struct {
+	TokenEndpoint string   `koanf:"token_endpoint"`
+	ClientID      string   `koanf:"client_id"`
+	ClientSecret  string   `koanf:"client_secret"`
+	Scopes        []string `koanf:"scopes"`
 }
 
 // NotifierConfiguration represents the configuration of the notifier to use when sending notifications to users.
@@ -32,6 +50,30 @@ type NotifierConfiguration struct {
 	FileSystem          *FileSystemNotifierConfiguration `koanf:"filesystem"`
 	SMTP                *SMTPNotifierConfiguration       `koanf:"smtp"`
 	TemplatePath        string                           `koanf:"template_path"`
+
+	Notifications NotifierNotificationsConfiguration `koanf:"notifications"`
+	PasswordReset NotifierPasswordResetConfiguration `koanf:"password_reset"`
+}
+
+// NotifierNotificationsConfiguration represents the configuration of the optional notifications sent to users.
+type NotifierNotificationsConfiguration struct {
+	DeviceChange bool `koanf:"device_change"`
+}
+
+// NotifierPasswordResetConfiguration represents the configuration of how the password reset notification is sent
+// when the notifier fails.
+type NotifierPasswordResetConfiguration struct {
+	Retries       int           `koanf:"retries"`
+	RetryBackoff  time.Duration `koanf:"retry_backoff"`
+	OnFailure     string        `koanf:"on_failure"`
+	QueueInterval time.Duration `koanf:"queue_interval"`
+}
+
+// DefaultNotifierPasswordResetConfiguration represents the default configuration of the password reset notification.
+var DefaultNotifierPasswordResetConfiguration = NotifierPasswordResetConfiguration{
+	RetryBackoff:  time.Second,
+	OnFailure:     NotifierOnFailureFail,
+	QueueInterval: time.Second * 30,
 }
 
 // DefaultSMTPNotifierConfiguration represents default configuration parameters for the SMTP notifier.
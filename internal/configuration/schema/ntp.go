@@ -9,6 +9,7 @@ type NTPConfiguration struct {
 	Address             string        `koanf:"address"`
 	Version             int           `koanf:"version"`
 	MaximumDesync       time.Duration `koanf:"max_desync"`
+	Timeout             time.Duration `koanf:"timeout"`
 	DisableStartupCheck bool          `koanf:"disable_startup_check"`
 	DisableFailure      bool          `koanf:"disable_failure"`
 }
@@ -18,4 +19,5 @@ var DefaultNTPConfiguration = NTPConfiguration{
 	Address:       "time.cloudflare.com:123",
 	Version:       4,
 	MaximumDesync: time.Second * 3,
+	Timeout:       time.Second * 3,
 }
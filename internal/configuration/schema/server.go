@@ -1,25 +1,37 @@
 package schema
 
+import (
+	"net/url"
+)
+
 // ServerConfiguration represents the configuration of the http server.
 type ServerConfiguration struct {
-	Host               string `koanf:"host"`
-	Port               int    `koanf:"port"`
-	Path               string `koanf:"path"`
-	AssetPath          string `koanf:"asset_path"`
-	ReadBufferSize     int    `koanf:"read_buffer_size"`
-	WriteBufferSize    int    `koanf:"write_buffer_size"`
-	EnablePprof        bool   `koanf:"enable_pprof"`
-	EnableExpvars      bool   `koanf:"enable_expvars"`
-	DisableHealthcheck bool   `koanf:"disable_healthcheck"`
+	Host               string  `koanf:"host"`
+	Port               int     `koanf:"port"`
+	Path               string  `koanf:"path"`
+	ExternalURL        url.URL `koanf:"external_url"`
+	AssetPath          string  `koanf:"asset_path"`
+	FallbackLocale     string  `koanf:"fallback_locale"`
+	ReadBufferSize     int     `koanf:"read_buffer_size"`
+	WriteBufferSize    int     `koanf:"write_buffer_size"`
+	EnablePprof        bool    `koanf:"enable_pprof"`
+	EnableExpvars      bool    `koanf:"enable_expvars"`
+	DisableHealthcheck bool    `koanf:"disable_healthcheck"`
 
 	TLS     ServerTLSConfiguration     `koanf:"tls"`
 	Headers ServerHeadersConfiguration `koanf:"headers"`
+	Debug   ServerDebugConfiguration   `koanf:"debug"`
+
+	HealthCheck ServerHealthCheckConfiguration `koanf:"health_check"`
+
+	ErrorPages ServerErrorPagesConfiguration `koanf:"error_pages"`
 }
 
 // ServerTLSConfiguration represents the configuration of the http servers TLS options.
 type ServerTLSConfiguration struct {
-	Certificate string `koanf:"certificate"`
-	Key         string `koanf:"key"`
+	Certificate  string `koanf:"certificate"`
+	Key          string `koanf:"key"`
+	RedirectPort int    `koanf:"redirect_port"`
 }
 
 // ServerHeadersConfiguration represents the customization of the http server headers.
@@ -27,10 +39,30 @@ type ServerHeadersConfiguration struct {
 	CSPTemplate string `koanf:"csp_template"`
 }
 
+// ServerDebugConfiguration represents the configuration of the debug endpoints listener.
+type ServerDebugConfiguration struct {
+	Address string `koanf:"address"`
+}
+
+// ServerHealthCheckConfiguration represents the configuration of the health check endpoint.
+type ServerHealthCheckConfiguration struct {
+	Path string `koanf:"path"`
+}
+
+// ServerErrorPagesConfiguration represents the configuration of the custom error page templates.
+type ServerErrorPagesConfiguration struct {
+	ClientError string `koanf:"client_error"`
+	ServerError string `koanf:"server_error"`
+}
+
 // DefaultServerConfiguration represents the default values of the ServerConfiguration.
 var DefaultServerConfiguration = ServerConfiguration{
 	Host:            "0.0.0.0",
 	Port:            9091,
 	ReadBufferSize:  4096,
 	WriteBufferSize: 4096,
+	FallbackLocale:  "en",
+	HealthCheck: ServerHealthCheckConfiguration{
+		Path: "/api/health",
+	},
 }
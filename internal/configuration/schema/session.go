@@ -26,10 +26,12 @@ type RedisSessionConfiguration struct {
 	Port                     int                                 `koanf:"port"`
 	Username                 string                              `koanf:"username"`
 	Password                 string                              `koanf:"password"`
+	CredentialsFile          string                              `koanf:"credentials_file"`
 	DatabaseIndex            int                                 `koanf:"database_index"`
 	MaximumActiveConnections int                                 `koanf:"maximum_active_connections"`
 	MinimumIdleConnections   int                                 `koanf:"minimum_idle_connections"`
 	TLS                      *TLSConfig                          `koanf:"tls"`
+	RequireTLS               bool                                `koanf:"require_tls"`
 	HighAvailability         *RedisHighAvailabilityConfiguration `koanf:"high_availability"`
 }
 
@@ -37,11 +39,16 @@ type RedisSessionConfiguration struct {
 type SessionConfiguration struct {
 	Name               string        `koanf:"name"`
 	Domain             string        `koanf:"domain"`
+	Domains            []string      `koanf:"domains"`
 	SameSite           string        `koanf:"same_site"`
+	SameSiteOIDC       string        `koanf:"same_site_oidc"`
 	Secret             string        `koanf:"secret"`
 	Expiration         time.Duration `koanf:"expiration"`
 	Inactivity         time.Duration `koanf:"inactivity"`
 	RememberMeDuration time.Duration `koanf:"remember_me_duration"`
+	MaximumLifetime    time.Duration `koanf:"maximum_lifetime"`
+
+	RememberMeRequireTwoFactor bool `koanf:"remember_me_require_two_factor"`
 
 	Redis *RedisSessionConfiguration `koanf:"redis"`
 }
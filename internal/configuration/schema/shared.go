@@ -6,3 +6,8 @@ type TLSConfig struct {
 	SkipVerify     bool   `koanf:"skip_verify"`
 	ServerName     string `koanf:"server_name"`
 }
+
+// GlobalTLSConfiguration represents the TLS configuration shared by all of the outbound TLS connections.
+type GlobalTLSConfiguration struct {
+	TrustedCA string `koanf:"trusted_ca"`
+}
@@ -15,6 +15,10 @@ type SQLStorageConfiguration struct {
 	Username string        `koanf:"username"`
 	Password string        `koanf:"password"`
 	Timeout  time.Duration `koanf:"timeout"`
+
+	MaxOpenConnections    int           `koanf:"max_open_connections"`
+	MaxIdleConnections    int           `koanf:"max_idle_connections"`
+	ConnectionMaxLifetime time.Duration `koanf:"connection_max_lifetime"`
 }
 
 // MySQLStorageConfiguration represents the configuration of a MySQL database.
@@ -48,11 +52,21 @@ type StorageConfiguration struct {
 	PostgreSQL *PostgreSQLStorageConfiguration `koanf:"postgres"`
 
 	EncryptionKey string `koanf:"encryption_key"`
+
+	Migrate StorageMigrateConfiguration `koanf:"migrate"`
+}
+
+// StorageMigrateConfiguration represents the configuration of the storage schema migrations.
+type StorageMigrateConfiguration struct {
+	DryRun bool `koanf:"dry_run"`
 }
 
 // DefaultSQLStorageConfiguration represents the default SQL configuration.
 var DefaultSQLStorageConfiguration = SQLStorageConfiguration{
-	Timeout: 5 * time.Second,
+	Timeout:               5 * time.Second,
+	MaxOpenConnections:    10,
+	MaxIdleConnections:    2,
+	ConnectionMaxLifetime: time.Hour,
 }
 
 // DefaultPostgreSQLStorageConfiguration represents the default PostgreSQL configuration.
@@ -0,0 +1,17 @@
+package schema
+
+import (
+	"time"
+)
+
+// TrustedDeviceConfiguration represents the configuration related to trusted devices.
+type TrustedDeviceConfiguration struct {
+	Lifespan   time.Duration `koanf:"lifespan"`
+	CookieName string        `koanf:"cookie_name"`
+	BindToIP   bool          `koanf:"bind_to_ip"`
+}
+
+// DefaultTrustedDeviceConfiguration represents default configuration parameters for trusted devices.
+var DefaultTrustedDeviceConfiguration = TrustedDeviceConfiguration{
+	CookieName: "authelia_trusted_device",
+}
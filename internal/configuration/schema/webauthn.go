@@ -15,6 +15,8 @@ type WebauthnConfiguration struct {
 	UserVerification     protocol.UserVerificationRequirement `koanf:"user_verification"`
 
 	Timeout time.Duration `koanf:"timeout"`
+
+	AllowedAAGUIDs []string `koanf:"allowed_aaguids"`
 }
 
 // DefaultWebauthnConfiguration describes the default values for the WebauthnConfiguration.
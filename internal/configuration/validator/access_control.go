@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/authorization"
@@ -51,6 +52,14 @@ func ruleDescriptor(position int, rule schema.ACLRule) string {
 	return fmt.Sprintf("#%d (domain '%s')", position, strings.Join(rule.Domains, ","))
 }
 
+func domainDefaultPolicyDescriptor(position int, policy schema.ACLDomainDefaultPolicy) string {
+	if len(policy.Domains) == 0 {
+		return fmt.Sprintf("#%d", position)
+	}
+
+	return fmt.Sprintf("#%d (domain '%s')", position, strings.Join(policy.Domains, ","))
+}
+
 // ValidateAccessControl validates access control configuration.
 func ValidateAccessControl(config *schema.Configuration, validator *schema.StructValidator) {
 	if config.AccessControl.DefaultPolicy == "" {
@@ -61,6 +70,16 @@ func ValidateAccessControl(config *schema.Configuration, validator *schema.Struc
 		validator.Push(fmt.Errorf(errFmtAccessControlDefaultPolicyValue, strings.Join(validACLRulePolicies, "', '"), config.AccessControl.DefaultPolicy))
 	}
 
+	for i, policy := range config.AccessControl.DomainDefaultPolicies {
+		if len(policy.Domains) == 0 {
+			validator.Push(fmt.Errorf(errFmtAccessControlDomainDefaultPolicyNoDomains, i+1))
+		}
+
+		if !IsPolicyValid(policy.Policy) {
+			validator.Push(fmt.Errorf(errFmtAccessControlDomainDefaultPolicyValue, domainDefaultPolicyDescriptor(i+1, policy), strings.Join(validACLRulePolicies, "', '"), policy.Policy))
+		}
+	}
+
 	if config.AccessControl.Networks != nil {
 		for _, n := range config.AccessControl.Networks {
 			for _, networks := range n.Networks {
@@ -70,18 +89,30 @@ func ValidateAccessControl(config *schema.Configuration, validator *schema.Struc
 			}
 		}
 	}
+
+	for _, network := range config.AccessControl.TrustedNetworks {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtAccessControlTrustedNetworkInvalid, network))
+		}
+	}
 }
 
 // ValidateRules validates an ACL Rule configuration.
 func ValidateRules(config *schema.Configuration, validator *schema.StructValidator) {
 	if config.AccessControl.Rules == nil || len(config.AccessControl.Rules) == 0 {
+		for i, policy := range config.AccessControl.DomainDefaultPolicies {
+			if policy.Policy != policyOneFactor && policy.Policy != policyTwoFactor {
+				validator.Push(fmt.Errorf(errFmtAccessControlDomainDefaultPolicyWithoutRules, domainDefaultPolicyDescriptor(i+1, policy), policy.Policy))
+			}
+		}
+
 		if config.AccessControl.DefaultPolicy != policyOneFactor && config.AccessControl.DefaultPolicy != policyTwoFactor {
 			validator.Push(fmt.Errorf(errFmtAccessControlDefaultPolicyWithoutRules, config.AccessControl.DefaultPolicy))
 
 			return
 		}
 
-		validator.PushWarning(fmt.Errorf(errFmtAccessControlWarnNoRulesDefaultPolicy, config.AccessControl.DefaultPolicy))
+		validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtAccessControlWarnNoRulesDefaultPolicy, config.AccessControl.DefaultPolicy)))
 
 		return
 	}
@@ -103,9 +134,29 @@ func ValidateRules(config *schema.Configuration, validator *schema.StructValidat
 
 		validateMethods(rulePosition, rule, validator)
 
+		validateQuery(rulePosition, rule, validator)
+
+		validateTime(rulePosition, rule, validator)
+
+		validateSecondFactorMethods(rulePosition, rule, validator)
+
 		if rule.Policy == policyBypass {
 			validateBypass(rulePosition, rule, validator)
 		}
+
+		if rule.BasicAuth {
+			validateBasicAuth(rulePosition, rule, validator)
+		}
+	}
+}
+
+func validateBasicAuth(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	if rule.Policy != policyOneFactor {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleBasicAuthPolicyInvalid, ruleDescriptor(rulePosition, rule), rule.Policy))
+	}
+
+	if len(rule.Subjects) == 0 {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleBasicAuthNoSubjects, ruleDescriptor(rulePosition, rule)))
 	}
 }
 
@@ -149,3 +200,66 @@ func validateMethods(rulePosition int, rule schema.ACLRule, validator *schema.St
 		}
 	}
 }
+
+func validateSecondFactorMethods(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	if len(rule.SecondFactorMethods) == 0 {
+		return
+	}
+
+	if rule.Policy != policyTwoFactor {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleSecondFactorMethodsPolicyInvalid, ruleDescriptor(rulePosition, rule), rule.Policy))
+	}
+
+	for _, method := range rule.SecondFactorMethods {
+		if !utils.IsStringInSliceFold(method, validACLRuleSecondFactorMethods) {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleSecondFactorMethodInvalid, ruleDescriptor(rulePosition, rule), method, strings.Join(validACLRuleSecondFactorMethods, "', '")))
+		}
+	}
+}
+
+func validateTime(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	for _, value := range rule.Time {
+		if _, err := authorization.NewAccessControlTime(value); err != nil {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleTimeInvalid, ruleDescriptor(rulePosition, rule), value, err))
+		}
+	}
+}
+
+func validateQuery(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	for i := range rule.Query {
+		for j := range rule.Query[i] {
+			query := &rule.Query[i][j]
+
+			if query.Key == "" {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryKeyMissing, ruleDescriptor(rulePosition, rule)))
+
+				continue
+			}
+
+			if query.Operator == "" {
+				if query.Value == "" {
+					query.Operator = operatorPresent
+				} else {
+					query.Operator = operatorEqual
+				}
+			}
+
+			switch query.Operator {
+			case operatorPresent, operatorAbsent:
+				if query.Value != "" {
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryValueUnexpected, ruleDescriptor(rulePosition, rule), query.Operator, query.Key))
+				}
+			case operatorEqual, operatorNotEqual:
+				break
+			case operatorPattern, operatorNotPattern:
+				if query.Value == "" {
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryValueMissing, ruleDescriptor(rulePosition, rule), query.Operator, query.Key))
+				} else if _, err := regexp.Compile(query.Value); err != nil {
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryPatternInvalid, ruleDescriptor(rulePosition, rule), query.Key, err))
+				}
+			default:
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleQueryOperatorInvalid, ruleDescriptor(rulePosition, rule), query.Operator, strings.Join(validACLQueryOperators, "', '")))
+			}
+		}
+	}
+}
@@ -72,6 +72,30 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidDefaultPolicy() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: option 'default_policy' must be one of 'bypass', 'one_factor', 'two_factor', 'deny' but it is configured as 'invalid'")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidDomainDefaultPolicy() {
+	suite.config.AccessControl.DomainDefaultPolicies = []schema.ACLDomainDefaultPolicy{
+		{
+			Domains: []string{"internal.example.com"},
+			Policy:  policyOneFactor,
+		},
+		{
+			Domains: []string{"external.example.com"},
+			Policy:  testInvalidPolicy,
+		},
+		{
+			Policy: policyTwoFactor,
+		},
+	}
+
+	ValidateAccessControl(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: domain default policy #2 (domain 'external.example.com'): option 'policy' must be one of 'bypass', 'one_factor', 'two_factor', 'deny' but it is configured as 'invalid'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: domain default policy #3 is invalid: must have the option 'domain' configured")
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorInvalidNetworkGroupNetwork() {
 	suite.config.AccessControl.Networks = []schema.ACLNetwork{
 		{
@@ -88,6 +112,17 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidNetworkGroupNetwork() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: networks: network group 'internal' is invalid: the network 'abc.def.ghi.jkl' is not a valid IP or CIDR notation")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidTrustedNetwork() {
+	suite.config.AccessControl.TrustedNetworks = []string{"10.0.0.0/8", "192.168.1.1", "internal"}
+
+	ValidateAccessControl(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: option 'trusted_networks' must only contain valid IP addresses or CIDR notation networks but it contains 'internal'")
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorWithNoRulesDefined() {
 	suite.config.AccessControl.Rules = []schema.ACLRule{}
 
@@ -99,6 +134,29 @@ func (suite *AccessControl) TestShouldRaiseErrorWithNoRulesDefined() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: 'default_policy' option 'deny' is invalid: when no rules are specified it must be 'two_factor' or 'one_factor'")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorWithNoRulesDefinedAndDomainDefaultPolicyDeny() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{}
+
+	suite.config.AccessControl.DefaultPolicy = policyTwoFactor
+	suite.config.AccessControl.DomainDefaultPolicies = []schema.ACLDomainDefaultPolicy{
+		{
+			Domains: []string{"internal.example.com"},
+			Policy:  policyOneFactor,
+		},
+		{
+			Domains: []string{"*.example.com"},
+			Policy:  policyDeny,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Errors(), 1)
+	suite.Require().Len(suite.validator.Warnings(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: domain default policy #2 (domain '*.example.com'): option 'policy' with value 'deny' is invalid: when no rules are specified it must be 'two_factor' or 'one_factor'")
+}
+
 func (suite *AccessControl) TestShouldRaiseWarningWithNoRulesDefined() {
 	suite.config.AccessControl.Rules = []schema.ACLRule{}
 
@@ -181,6 +239,78 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidMethod() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'methods' option 'HOP' is invalid: must be one of 'GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE', 'TRACE', 'CONNECT', 'OPTIONS', 'COPY', 'LOCK', 'MKCOL', 'MOVE', 'PROPFIND', 'PROPPATCH', 'UNLOCK'")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidQuery() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "bypass",
+			Query: [][]schema.ACLQueryRule{
+				{
+					{Value: "admin"},
+					{Operator: "contains", Key: "type", Value: "admin"},
+				},
+				{
+					{Operator: "absent", Key: "debug", Value: "true"},
+					{Operator: "pattern", Key: "id"},
+					{Operator: "not pattern", Key: "token", Value: "^(abc"},
+				},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 5)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'query' option must have the 'key' option configured for every rule")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): 'query' option 'operator' with value 'contains' is invalid: must be one of 'equal', 'not equal', 'present', 'absent', 'pattern', 'not pattern'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): 'query' option 'value' must not be configured when the 'operator' is 'absent' but it is configured for the key 'debug'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): 'query' option 'value' must be configured when the 'operator' is 'pattern' but it is not configured for the key 'id'")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "access control: rule #1 (domain 'public.example.com'): 'query' option 'value' for the key 'token' is not a valid regular expression: error parsing regexp: missing closing ): `^(abc`")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidTime() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "two_factor",
+			Time:    []string{"mon-fri 08:00-18:00", "monday", "22:00-06:00"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'time' option 'monday' is invalid: the week day 'monday' is not one of 'mon', 'tue', 'wed', 'thu', 'fri', 'sat', 'sun'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): 'time' option '22:00-06:00' is invalid: the time window '22:00-06:00' must end after it starts, windows which span midnight must be split in two")
+}
+
+func (suite *AccessControl) TestShouldSetDefaultQueryOperator() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "bypass",
+			Query: [][]schema.ACLQueryRule{
+				{
+					{Key: "type", Value: "public"},
+					{Key: "preview"},
+				},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal("equal", suite.config.AccessControl.Rules[0].Query[0][0].Operator)
+	suite.Assert().Equal("present", suite.config.AccessControl.Rules[0].Query[0][1].Operator)
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorInvalidSubject() {
 	domains := []string{"public.example.com"}
 	subjects := [][]string{{"invalid"}}
@@ -201,6 +331,73 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidSubject() {
 	suite.Assert().EqualError(suite.validator.Errors()[1], fmt.Sprintf(errAccessControlRuleBypassPolicyInvalidWithSubjects, ruleDescriptor(1, suite.config.AccessControl.Rules[0])))
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidBasicAuth() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:   []string{"api.example.com"},
+			Policy:    "two_factor",
+			BasicAuth: true,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'api.example.com'): 'basic_auth' option is only supported when the 'policy' option is 'one_factor' but it is configured as 'two_factor'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'api.example.com'): 'basic_auth' option requires the 'subject' option to be configured so only the intended clients are permitted to authenticate with a single factor")
+}
+
+func (suite *AccessControl) TestShouldValidateBasicAuth() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:   []string{"api.example.com"},
+			Policy:    "one_factor",
+			Subjects:  [][]string{{"user:ci"}},
+			BasicAuth: true,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidSecondFactorMethods() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:             []string{"api.example.com"},
+			Policy:              "one_factor",
+			SecondFactorMethods: []string{"webauthn", "sms"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'api.example.com'): 'methods_2fa' option is only supported when the 'policy' option is 'two_factor' but it is configured as 'one_factor'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'api.example.com'): 'methods_2fa' option 'sms' is invalid: must be one of 'totp', 'webauthn', 'duo'")
+}
+
+func (suite *AccessControl) TestShouldValidateSecondFactorMethods() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:             []string{"admin.example.com"},
+			Policy:              "two_factor",
+			SecondFactorMethods: []string{"webauthn", "Duo"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
 func TestAccessControl(t *testing.T) {
 	suite.Run(t, new(AccessControl))
 }
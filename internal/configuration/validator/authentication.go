@@ -34,6 +34,16 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackendConfigura
 		}
 	}
 
+	if config.VerifyTimeout < 0 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendVerifyTimeout, config.VerifyTimeout))
+	}
+
+	if config.MaxConcurrentRequests < 0 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendMaxConcurrentRequests, config.MaxConcurrentRequests))
+	}
+
+	validateVerifyResponseHeaders(config, validator)
+
 	if config.PasswordReset.CustomURL.String() != "" {
 		switch config.PasswordReset.CustomURL.Scheme {
 		case schemeHTTP, schemeHTTPS:
@@ -44,6 +54,30 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackendConfigura
 	}
 }
 
+// validateVerifyResponseHeaders validates the headers added to the verify endpoint response.
+func validateVerifyResponseHeaders(config *schema.AuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	names := make([]string, 0, len(config.VerifyResponseHeaders))
+
+	for i, header := range config.VerifyResponseHeaders {
+		switch {
+		case header.Name == "":
+			validator.Push(fmt.Errorf(errFmtAuthBackendVerifyResponseHeaderNameRequired, i+1))
+		case !reHeaderName.MatchString(header.Name):
+			validator.Push(fmt.Errorf(errFmtAuthBackendVerifyResponseHeaderNameInvalid, header.Name))
+		case utils.IsStringInSliceFold(header.Name, reservedVerifyResponseHeaders):
+			validator.Push(fmt.Errorf(errFmtAuthBackendVerifyResponseHeaderNameReserved, header.Name))
+		case utils.IsStringInSliceFold(header.Name, names):
+			validator.Push(fmt.Errorf(errFmtAuthBackendVerifyResponseHeaderNameDuplicate, header.Name))
+		default:
+			names = append(names, header.Name)
+		}
+
+		if !utils.IsStringInSlice(header.Source, validVerifyResponseHeaderSources) {
+			validator.Push(fmt.Errorf(errFmtAuthBackendVerifyResponseHeaderSource, header.Name, strings.Join(validVerifyResponseHeaderSources, "', '"), header.Source))
+		}
+	}
+}
+
 // validateFileAuthenticationBackend validates and updates the file authentication backend configuration.
 func validateFileAuthenticationBackend(config *schema.FileAuthenticationBackendConfiguration, validator *schema.StructValidator) {
 	if config.Path == "" {
@@ -69,11 +103,16 @@ func validateFileAuthenticationBackend(config *schema.FileAuthenticationBackendC
 			validateFileAuthenticationBackendArgon2id(config, validator)
 		case hashSHA512:
 			validateFileAuthenticationBackendSHA512(config)
+		case hashScrypt:
+			validateFileAuthenticationBackendScrypt(config, validator)
+		case hashArgon2i:
+			validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordUnsupportedAlg, config.Password.Algorithm))
 		default:
 			validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordUnknownAlg, config.Password.Algorithm))
 		}
 
-		if config.Password.Iterations < 1 {
+		// The scrypt iterations are validated with the other scrypt bounds.
+		if config.Password.Algorithm != hashScrypt && config.Password.Iterations < 1 {
 			validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordInvalidIterations, config.Password.Iterations))
 		}
 	}
@@ -85,6 +124,42 @@ func validateFileAuthenticationBackendSHA512(config *schema.FileAuthenticationBa
 		config.Password.Iterations = schema.DefaultPasswordSHA512Configuration.Iterations
 	}
 }
+
+func validateFileAuthenticationBackendScrypt(config *schema.FileAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	// Iterations (cost, the base 2 logarithm of N).
+	switch {
+	case config.Password.Iterations == 0:
+		config.Password.Iterations = schema.DefaultPasswordScryptConfiguration.Iterations
+	case config.Password.Iterations < scryptMinimumCost, config.Password.Iterations > scryptMaximumCost:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordScryptInvalidIterations, scryptMinimumCost, scryptMaximumCost, config.Password.Iterations))
+	}
+
+	// Block Size (r).
+	if config.Password.BlockSize == 0 {
+		config.Password.BlockSize = schema.DefaultPasswordScryptConfiguration.BlockSize
+	} else if config.Password.BlockSize < 1 {
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordScryptInvalidOption, "block_size", 1, config.Password.BlockSize))
+	}
+
+	// Parallelism (p).
+	if config.Password.Parallelism == 0 {
+		config.Password.Parallelism = schema.DefaultPasswordScryptConfiguration.Parallelism
+	} else if config.Password.Parallelism < 1 {
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordScryptInvalidOption, "parallelism", 1, config.Password.Parallelism))
+	}
+
+	if config.Password.BlockSize > 0 && config.Password.Iterations >= config.Password.BlockSize*16 {
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordScryptInvalidIterationsBlockSize, config.Password.BlockSize, config.Password.BlockSize*16, config.Password.Iterations))
+	}
+
+	// Key Length.
+	if config.Password.KeyLength == 0 {
+		config.Password.KeyLength = schema.DefaultPasswordScryptConfiguration.KeyLength
+	} else if config.Password.KeyLength < scryptMinimumKeyLength {
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordScryptInvalidOption, "key_length", scryptMinimumKeyLength, config.Password.KeyLength))
+	}
+}
+
 func validateFileAuthenticationBackendArgon2id(config *schema.FileAuthenticationBackendConfiguration, validator *schema.StructValidator) {
 	// Iterations (time).
 	if config.Password.Iterations == 0 {
@@ -122,6 +197,12 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 		config.Implementation = schema.DefaultLDAPAuthenticationBackendConfiguration.Implementation
 	}
 
+	if config.ReferralHops == 0 {
+		config.ReferralHops = schema.DefaultLDAPAuthenticationBackendConfiguration.ReferralHops
+	} else if config.ReferralHops < 0 {
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendReferralHops, config.ReferralHops))
+	}
+
 	if config.TLS == nil {
 		config.TLS = schema.DefaultLDAPAuthenticationBackendConfiguration.TLS
 	}
@@ -138,7 +219,13 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 	case schema.LDAPImplementationCustom:
 		setDefaultImplementationCustomLDAPAuthenticationBackend(config)
 	case schema.LDAPImplementationActiveDirectory:
+		if config.GroupNameAttribute == "" && config.GroupsFilter != "" {
+			validator.PushWarning(fmt.Errorf(errFmtLDAPAuthBackendActiveDirectoryGroupNameAttribute,
+				schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration.GroupNameAttribute))
+		}
+
 		setDefaultImplementationActiveDirectoryLDAPAuthenticationBackend(config)
+		validateLDAPAuthenticationBackendActiveDirectoryUsersFilter(config, validator)
 	default:
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendImplementation, config.Implementation, strings.Join([]string{schema.LDAPImplementationCustom, schema.LDAPImplementationActiveDirectory}, "', '")))
 	}
@@ -155,6 +242,9 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendFilterReplacedPlaceholders, "groups_filter", "{1}", "{username}"))
 	}
 
+	validateLDAPAuthenticationBackendFilterPlaceholders("users_filter", config.UsersFilter, validator)
+	validateLDAPAuthenticationBackendFilterPlaceholders("groups_filter", config.GroupsFilter, validator)
+
 	if config.URL == "" {
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendMissingOption, "url"))
 	} else {
@@ -164,6 +254,48 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 	validateLDAPRequiredParameters(config, validator)
 }
 
+func validateLDAPAuthenticationBackendActiveDirectoryUsersFilter(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	filter := strings.ToLower(strings.NewReplacer(
+		"{username_attribute}", config.UsernameAttribute,
+		"{mail_attribute}", config.MailAttribute,
+		"{display_name_attribute}", config.DisplayNameAttribute,
+	).Replace(config.UsersFilter))
+
+	if !strings.Contains(filter, "samaccountname=") && !strings.Contains(filter, "userprincipalname=") {
+		validator.PushWarning(fmt.Errorf(errFmtLDAPAuthBackendActiveDirectoryUsersFilter, config.UsersFilter))
+	}
+}
+
+// validateLDAPAuthenticationBackendFilterPlaceholders warns about the placeholders replaced with user controlled values
+// which are not the value of an attribute comparison, i.e. '(&{input}(objectClass=person))' or '({input}=john)'. The
+// values are escaped when they are substituted but outside of a comparison they can still alter the filter.
+func validateLDAPAuthenticationBackendFilterPlaceholders(name, filter string, validator *schema.StructValidator) {
+	// Filters without enclosing parenthesis are already reported as errors.
+	if !strings.HasPrefix(filter, "(") || !strings.HasSuffix(filter, ")") {
+		return
+	}
+
+	for _, placeholder := range []string{ldapPlaceholderInput, ldapPlaceholderUsername, ldapPlaceholderDistinguishedName} {
+		for i := strings.Index(filter, placeholder); i != -1; {
+			start := strings.LastIndexAny(filter[:i], "()")
+			end := strings.IndexAny(filter[i+len(placeholder):], "()")
+
+			if start == -1 || filter[start] != '(' || !strings.Contains(filter[start:i], "=") ||
+				end == -1 || filter[i+len(placeholder)+end] != ')' {
+				validator.PushWarning(fmt.Errorf(errFmtLDAPAuthBackendFilterPlaceholderNotInComparison, name, placeholder, placeholder))
+
+				break
+			}
+
+			if next := strings.Index(filter[i+len(placeholder):], placeholder); next == -1 {
+				i = -1
+			} else {
+				i += len(placeholder) + next
+			}
+		}
+	}
+}
+
 func validateLDAPAuthenticationBackendURL(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
 	var (
 		parsedURL *url.URL
@@ -126,6 +126,73 @@ func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultConfigurationWh
 	suite.Assert().Equal(schema.DefaultPasswordSHA512Configuration.Memory, suite.config.File.Password.Memory)
 	suite.Assert().Equal(schema.DefaultPasswordSHA512Configuration.Parallelism, suite.config.File.Password.Parallelism)
 }
+
+func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultConfigurationWhenOnlyScryptSet() {
+	suite.config.File.Password = &schema.PasswordConfiguration{}
+	suite.config.File.Password.Algorithm = "scrypt"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultPasswordScryptConfiguration.KeyLength, suite.config.File.Password.KeyLength)
+	suite.Assert().Equal(schema.DefaultPasswordScryptConfiguration.Iterations, suite.config.File.Password.Iterations)
+	suite.Assert().Equal(schema.DefaultPasswordScryptConfiguration.SaltLength, suite.config.File.Password.SaltLength)
+	suite.Assert().Equal(schema.DefaultPasswordScryptConfiguration.Algorithm, suite.config.File.Password.Algorithm)
+	suite.Assert().Equal(schema.DefaultPasswordScryptConfiguration.Parallelism, suite.config.File.Password.Parallelism)
+	suite.Assert().Equal(schema.DefaultPasswordScryptConfiguration.BlockSize, suite.config.File.Password.BlockSize)
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorsWhenScryptParametersOutOfBounds() {
+	suite.config.File.Password = &schema.PasswordConfiguration{
+		Algorithm:   "scrypt",
+		Iterations:  31,
+		BlockSize:   -1,
+		Parallelism: -1,
+		KeyLength:   8,
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'iterations' must be between 1 and 30 when using algorithm 'scrypt' but it is configured as '31'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: file: password: option 'block_size' must be 1 or more when using algorithm 'scrypt' but it is configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "authentication_backend: file: password: option 'parallelism' must be 1 or more when using algorithm 'scrypt' but it is configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "authentication_backend: file: password: option 'key_length' must be 16 or more when using algorithm 'scrypt' but it is configured as '8'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenScryptIterationsNegative() {
+	suite.config.File.Password = &schema.PasswordConfiguration{
+		Algorithm:  "scrypt",
+		Iterations: -1,
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'iterations' must be between 1 and 30 when using algorithm 'scrypt' but it is configured as '-1'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenScryptIterationsTooHighForBlockSize() {
+	suite.config.File.Password = &schema.PasswordConfiguration{
+		Algorithm:  "scrypt",
+		Iterations: 16,
+		BlockSize:  1,
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'iterations' must be less than block_size multiplied by 16 when using algorithm 'scrypt' with block_size 1 it should be less than 16 but it is configured as '16'")
+}
+
 func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenKeyLengthTooLow() {
 	suite.config.File.Password.KeyLength = 1
 
@@ -156,7 +223,18 @@ func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenBadAlgorith
 	suite.Assert().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 1)
 
-	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'algorithm' must be either 'argon2id' or 'sha512' but it is configured as 'bogus'")
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'algorithm' must be one of 'argon2id', 'sha512', or 'scrypt' but it is configured as 'bogus'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenVerificationOnlyAlgorithmDefined() {
+	suite.config.File.Password.Algorithm = "argon2i"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'algorithm' must be one of 'argon2id', 'sha512', or 'scrypt' but it is configured as 'argon2i' which is only supported when verifying existing password hashes and can't be used to generate them")
 }
 
 func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenIterationsTooLow() {
@@ -378,6 +456,85 @@ func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseOnBadRefreshInterval
 	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: option 'refresh_interval' is configured to 'blah' but it must be either a duration notation or one of 'disable', or 'always': could not parse 'blah' as a duration")
 }
 
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseOnNegativeVerifyTimeout() {
+	suite.config.VerifyTimeout = -time.Second
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: option 'verify_timeout' is configured to '-1s' but it must be greater than or equal to 0")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseOnNegativeMaxConcurrentRequests() {
+	suite.config.MaxConcurrentRequests = -1
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: option 'max_concurrent_requests' is configured to '-1' but it must be greater than or equal to 0")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultReferralHops() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().False(suite.config.LDAP.FollowReferrals)
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendConfiguration.ReferralHops, suite.config.LDAP.ReferralHops)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseOnNegativeReferralHops() {
+	suite.config.LDAP.FollowReferrals = true
+	suite.config.LDAP.ReferralHops = -1
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: option 'referral_hops' is configured to '-1' but it must be greater than or equal to 0")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldNotRaiseOnValidVerifyResponseHeaders() {
+	suite.config.VerifyResponseHeaders = []schema.VerifyResponseHeaderConfiguration{
+		{Name: "X-Forwarded-Preferred-Username", Source: schema.VerifyResponseHeaderSourceUsername},
+		{Name: "X-Forwarded-Email", Source: schema.VerifyResponseHeaderSourceEmail},
+		{Name: "X-Forwarded-Groups", Source: schema.VerifyResponseHeaderSourceGroups},
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseOnInvalidVerifyResponseHeaders() {
+	suite.config.VerifyResponseHeaders = []schema.VerifyResponseHeaderConfiguration{
+		{Source: schema.VerifyResponseHeaderSourceUsername},
+		{Name: "X Forwarded Email", Source: schema.VerifyResponseHeaderSourceEmail},
+		{Name: "remote-user", Source: schema.VerifyResponseHeaderSourceUsername},
+		{Name: "X-Forwarded-Groups", Source: schema.VerifyResponseHeaderSourceGroups},
+		{Name: "x-forwarded-groups", Source: schema.VerifyResponseHeaderSourceGroups},
+		{Name: "X-Forwarded-Phone", Source: "phone_number"},
+	}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 5)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: verify_response_headers: header #1: option 'name' is required")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: verify_response_headers: header 'X Forwarded Email': option 'name' must only contain the characters allowed in a HTTP header name")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "authentication_backend: verify_response_headers: header 'remote-user': option 'name' must not be one of the headers which are always set by the verify endpoint")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "authentication_backend: verify_response_headers: header 'x-forwarded-groups': option 'name' must be unique but it's configured more than once")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "authentication_backend: verify_response_headers: header 'X-Forwarded-Phone': option 'source' must be one of 'username', 'display_name', 'email', 'emails', 'groups' but it's configured as 'phone_number'")
+}
+
 func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultImplementation() {
 	ValidateAuthenticationBackend(&suite.config, suite.validator)
 
@@ -482,6 +639,40 @@ func (suite *LDAPAuthenticationBackendSuite) TestShouldHelpDetectNoInputPlacehol
 	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: option 'users_filter' must contain the placeholder '{input}' but it is required")
 }
 
+func (suite *LDAPAuthenticationBackendSuite) TestShouldWarnWhenFilterPlaceholderIsNotInComparison() {
+	testCases := []struct {
+		name, usersFilter, groupsFilter string
+		expected                        []string
+	}{
+		{"ShouldNotWarnOnComparisons", "(&(|({username_attribute}={input})(mail={input}*))(objectClass=person))", "(|(member={dn})(memberUid={username})(cn=*{input}*))", nil},
+		{"ShouldWarnOnBareInput", "(&({username_attribute}={input}){input})", "(member={dn})", []string{
+			"authentication_backend: ldap: option 'users_filter' has the placeholder '{input}' outside of an attribute comparison such as '(attribute={input})' which may allow the value to change the meaning of the filter",
+		}},
+		{"ShouldWarnOnAttributeName", "({username_attribute}={input})", "(&({username}=member)(objectClass=group)({dn}))", []string{
+			"authentication_backend: ldap: option 'groups_filter' has the placeholder '{username}' outside of an attribute comparison such as '(attribute={username})' which may allow the value to change the meaning of the filter",
+			"authentication_backend: ldap: option 'groups_filter' has the placeholder '{dn}' outside of an attribute comparison such as '(attribute={dn})' which may allow the value to change the meaning of the filter",
+		}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+
+			suite.config.LDAP.UsersFilter = tc.usersFilter
+			suite.config.LDAP.GroupsFilter = tc.groupsFilter
+
+			ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+			suite.Assert().Len(suite.validator.Errors(), 0)
+			suite.Require().Len(suite.validator.Warnings(), len(tc.expected))
+
+			for i, expected := range tc.expected {
+				suite.Assert().EqualError(suite.validator.Warnings()[i], expected)
+			}
+		})
+	}
+}
+
 func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultTLSMinimumVersion() {
 	suite.config.LDAP.TLS = &schema.TLSConfig{MinimumVersion: ""}
 
@@ -609,6 +800,40 @@ func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldRaiseErrorOnIn
 	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: option 'url' could not be parsed: parse \"ldap://dc1:abc\": invalid port \":abc\" after host")
 }
 
+func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldWarnWhenUsersFilterDoesNotMatchActiveDirectoryAttributes() {
+	suite.config.LDAP.UsersFilter = "(&({username_attribute}={input})(objectClass=person))"
+	suite.config.LDAP.UsernameAttribute = "uid"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+	suite.Require().Len(suite.validator.Warnings(), 1)
+
+	suite.Assert().EqualError(suite.validator.Warnings()[0], "authentication_backend: ldap: option 'users_filter' should match users by the 'sAMAccountName' or 'userPrincipalName' attribute when using the 'activedirectory' implementation but it is configured as '(&({username_attribute}={input})(objectClass=person))'")
+}
+
+func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldNotWarnWhenUsersFilterMatchesUserPrincipalName() {
+	suite.config.LDAP.UsersFilter = "(&(userPrincipalName={input})({username_attribute}={input})(objectClass=person))"
+	suite.config.LDAP.UsernameAttribute = "uid"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+}
+
+func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldWarnWhenGroupsFilterConfiguredWithoutGroupNameAttribute() {
+	suite.config.LDAP.GroupsFilter = "(&(member={dn})(objectClass=group))"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+	suite.Require().Len(suite.validator.Warnings(), 1)
+
+	suite.Assert().EqualError(suite.validator.Warnings()[0], "authentication_backend: ldap: option 'group_name_attribute' is not configured while option 'groups_filter' is so the default 'cn' is used which may not match the groups returned by the filter")
+	suite.Assert().Equal("cn", suite.config.LDAP.GroupNameAttribute)
+}
+
 func TestActiveDirectoryAuthenticationBackend(t *testing.T) {
 	suite.Run(t, new(ActiveDirectoryAuthenticationBackendSuite))
 }
@@ -1,8 +1,12 @@
 package validator
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -14,12 +18,12 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 	var err error
 
 	if config.CertificatesDirectory != "" {
-		var info os.FileInfo
+		validateCertificatesDirectory(config.CertificatesDirectory, validator)
+	}
 
-		if info, err = os.Stat(config.CertificatesDirectory); err != nil {
-			validator.Push(fmt.Errorf("the location 'certificates_directory' could not be inspected: %w", err))
-		} else if !info.IsDir() {
-			validator.Push(fmt.Errorf("the location 'certificates_directory' refers to '%s' is not a directory", config.CertificatesDirectory))
+	if config.TLS.TrustedCA != "" {
+		if err = validateCertificateFile(config.TLS.TrustedCA); err != nil {
+			validator.Push(fmt.Errorf(errFmtTLSTrustedCAInvalid, config.TLS.TrustedCA, err))
 		}
 	}
 
@@ -61,5 +65,116 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 
 	ValidateNTP(config, validator)
 
+	ValidateTrustedDevice(config, validator)
+
 	ValidatePasswordPolicy(&config.PasswordPolicy, validator)
+
+	ValidateSecurity(config, validator)
+
+	promoteStrictWarnings(config, validator)
+}
+
+// strictWarning is a warning which is promoted to an error when the 'configuration.strict' option is enabled. The set of
+// these warnings is explicit: they are only the warnings pushed using newStrictWarning.
+type strictWarning struct {
+	error
+}
+
+func (w strictWarning) Unwrap() error {
+	return w.error
+}
+
+func newStrictWarning(err error) error {
+	return strictWarning{err}
+}
+
+// promoteStrictWarnings moves the strict warnings to the errors when the 'configuration.strict' option is enabled.
+func promoteStrictWarnings(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.ConfigurationValidation.Strict {
+		return
+	}
+
+	errs, warnings := validator.Errors(), validator.Warnings()
+
+	validator.Clear()
+
+	for _, err := range errs {
+		validator.Push(err)
+	}
+
+	for _, warning := range warnings {
+		if errors.As(warning, &strictWarning{}) {
+			validator.Push(fmt.Errorf(errFmtConfigurationStrictWarning, warning))
+		} else {
+			validator.PushWarning(warning)
+		}
+	}
+}
+
+func validateCertificatesDirectory(directory string, validator *schema.StructValidator) {
+	info, err := os.Stat(directory)
+	if err != nil {
+		validator.Push(fmt.Errorf("the location 'certificates_directory' could not be inspected: %w", err))
+
+		return
+	}
+
+	if !info.IsDir() {
+		validator.Push(fmt.Errorf("the location 'certificates_directory' refers to '%s' is not a directory", directory))
+
+		return
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		validator.Push(fmt.Errorf("the location 'certificates_directory' could not be read: %w", err))
+
+		return
+	}
+
+	for _, entry := range entries {
+		nameLower := strings.ToLower(entry.Name())
+
+		if entry.IsDir() || !(strings.HasSuffix(nameLower, ".cer") || strings.HasSuffix(nameLower, ".crt") || strings.HasSuffix(nameLower, ".pem")) {
+			continue
+		}
+
+		if err = validateCertificateFile(filepath.Join(directory, entry.Name())); err != nil {
+			validator.Push(fmt.Errorf("the location 'certificates_directory' contains the file '%s' which is not a valid certificate: %w", entry.Name(), err))
+		}
+	}
+}
+
+func validateCertificateFile(path string) (err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var (
+		block        *pem.Block
+		certificates int
+	)
+
+	for {
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("the file contains a PEM block of type '%s' but only 'CERTIFICATE' blocks are permitted", block.Type)
+		}
+
+		if _, err = x509.ParseCertificate(block.Bytes); err != nil {
+			return err
+		}
+
+		certificates++
+	}
+
+	if certificates == 0 {
+		return errors.New("the file does not contain any PEM encoded certificates")
+	}
+
+	return nil
 }
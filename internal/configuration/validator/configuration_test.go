@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -88,6 +90,20 @@ func TestShouldRaiseErrorWithUndefinedJWTSecretKey(t *testing.T) {
 	assert.EqualError(t, validator.Warnings()[0], "access control: no rules have been specified so the 'default_policy' of 'two_factor' is going to be applied to all requests")
 }
 
+func TestShouldPromoteWarningsToErrorsInStrictMode(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.ConfigurationValidation.Strict = true
+	config.Server.Debug.Address = "localhost:9092"
+
+	ValidateConfiguration(&config, validator)
+	require.Len(t, validator.Errors(), 1)
+	require.Len(t, validator.Warnings(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "access control: no rules have been specified so the 'default_policy' of 'two_factor' is going to be applied to all requests: this warning is reported as an error because the 'configuration.strict' option is enabled")
+	assert.EqualError(t, validator.Warnings()[0], "server: debug: option 'address' is configured but neither option 'enable_pprof' or 'enable_expvars' are enabled so it has no effect")
+}
+
 func TestShouldRaiseErrorWithBadDefaultRedirectionURL(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultConfig()
@@ -148,7 +164,12 @@ func TestShouldRaiseErrorOnInvalidCertificatesDirectory(t *testing.T) {
 func TestShouldNotRaiseErrorOnValidCertificatesDirectory(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultConfig()
-	config.CertificatesDirectory = "../../suites/common/ssl"
+	config.CertificatesDirectory = t.TempDir()
+
+	data, err := os.ReadFile("../../suites/common/ssl/cert.pem")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(config.CertificatesDirectory, "cert.pem"), data, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(config.CertificatesDirectory, "README.txt"), []byte("not a certificate"), 0600))
 
 	ValidateConfiguration(&config, validator)
 
@@ -157,3 +178,54 @@ func TestShouldNotRaiseErrorOnValidCertificatesDirectory(t *testing.T) {
 
 	assert.EqualError(t, validator.Warnings()[0], "access control: no rules have been specified so the 'default_policy' of 'two_factor' is going to be applied to all requests")
 }
+
+func TestShouldRaiseErrorOnInvalidCertificatesInCertificatesDirectory(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.CertificatesDirectory = "../../suites/common/ssl"
+
+	ValidateConfiguration(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "the location 'certificates_directory' contains the file 'key.pem' which is not a valid certificate: the file contains a PEM block of type 'PRIVATE KEY' but only 'CERTIFICATE' blocks are permitted")
+
+	validator = schema.NewStructValidator()
+	config.CertificatesDirectory = t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(config.CertificatesDirectory, "empty.crt"), []byte("not a certificate"), 0600))
+
+	ValidateConfiguration(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "the location 'certificates_directory' contains the file 'empty.crt' which is not a valid certificate: the file does not contain any PEM encoded certificates")
+}
+
+func TestShouldValidateTrustedCA(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.TLS.TrustedCA = "../../suites/common/ssl/cert.pem"
+
+	ValidateConfiguration(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+
+	validator = schema.NewStructValidator()
+	config.TLS.TrustedCA = "../../suites/common/ssl/key.pem"
+
+	ValidateConfiguration(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "tls: option 'trusted_ca' refers to '../../suites/common/ssl/key.pem' which is not a valid certificate authority bundle: the file contains a PEM block of type 'PRIVATE KEY' but only 'CERTIFICATE' blocks are permitted")
+
+	validator = schema.NewStructValidator()
+	config.TLS.TrustedCA = "../../suites/common/ssl/not-a-file.pem"
+
+	ValidateConfiguration(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "tls: option 'trusted_ca' refers to '../../suites/common/ssl/not-a-file.pem' which is not a valid certificate authority bundle: open ../../suites/common/ssl/not-a-file.pem: no such file or directory")
+}
@@ -2,15 +2,19 @@ package validator
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/go-webauthn/webauthn/protocol"
 
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
 const (
 	loopback           = "127.0.0.1"
 	oauth2InstalledApp = "urn:ietf:wg:oauth:2.0:oob"
+	dev                = "dev"
 )
 
 // Policy constants.
@@ -21,10 +25,36 @@ const (
 	policyDeny      = "deny"
 )
 
+// Access control query operator constants.
+const (
+	operatorEqual      = "equal"
+	operatorNotEqual   = "not equal"
+	operatorPresent    = "present"
+	operatorAbsent     = "absent"
+	operatorPattern    = "pattern"
+	operatorNotPattern = "not pattern"
+)
+
 // Hashing constants.
 const (
 	hashArgon2id = "argon2id"
 	hashSHA512   = "sha512"
+	hashScrypt   = "scrypt"
+	hashArgon2i  = "argon2i"
+)
+
+// Scrypt hashing bounds, these must match the bounds in the authentication package.
+const (
+	scryptMinimumCost      = 1
+	scryptMaximumCost      = 30
+	scryptMinimumKeyLength = 16
+)
+
+// Password reset notification retry bounds. The retries delay the response to the password reset request so both the
+// number of retries and the total delay are bounded.
+const (
+	notifierPasswordResetMaximumRetries    = 3
+	notifierPasswordResetMaximumRetryDelay = time.Second * 10
 )
 
 // Scheme constants.
@@ -35,10 +65,17 @@ const (
 	schemeHTTPS = "https"
 )
 
+// LDAP filter placeholders which are replaced with user controlled values.
+const (
+	ldapPlaceholderInput             = "{input}"
+	ldapPlaceholderUsername          = "{username}"
+	ldapPlaceholderDistinguishedName = "{dn}"
+)
+
 // Test constants.
 const (
 	testInvalidPolicy = "invalid"
-	testJWTSecret     = "a_secret"
+	testJWTSecret     = "a_secret_which_is_long_enough"
 	testLDAPBaseDN    = "base_dn"
 	testLDAPPassword  = "password"
 	testLDAPURL       = "ldap://ldap"
@@ -49,6 +86,11 @@ const (
 	testEncryptionKey = "a_not_so_secure_encryption_key"
 )
 
+// TLS Error constants.
+const (
+	errFmtTLSTrustedCAInvalid = "tls: option 'trusted_ca' refers to '%s' which is not a valid certificate authority bundle: %w"
+)
+
 // Notifier Error constants.
 const (
 	errFmtNotifierMultipleConfigured = "notifier: please ensure only one of the 'smtp' or 'filesystem' notifier is configured"
@@ -59,6 +101,15 @@ const (
 	errFmtNotifierTemplateLoad                    = "notifier: error loading template '%s': %w"
 	errFmtNotifierFileSystemFileNameNotConfigured = "notifier: filesystem: option 'filename' is required "
 	errFmtNotifierSMTPNotConfigured               = "notifier: smtp: option '%s' is required"
+	errFmtNotifierSMTPIdentifierInvalid           = "notifier: smtp: option '%s' must be a valid hostname or address literal but it is configured as '%s'"
+	errFmtNotifierSMTPXOAUTH2NotConfigured        = "notifier: smtp: option '%s' is required when the 'auth.xoauth2' option is configured"
+	errFmtNotifierSMTPXOAUTH2TokenEndpointInvalid = "notifier: smtp: option '%s' must be an absolute URL with the https scheme but it is configured as '%s'"
+	errFmtNotifierSMTPXOAUTH2Fallback             = "notifier: smtp: option '%s' is required when the 'auth.xoauth2' option is configured: falling back to authentication with the 'username' and 'password' options"
+	errFmtNotifierPasswordResetRetries            = "notifier: password_reset: option 'retries' must be between 0 and %d but it is configured as '%d'"
+	errFmtNotifierPasswordResetOnFailure          = "notifier: password_reset: option 'on_failure' must be one of '%s' but it is configured as '%s'"
+	errFmtNotifierPasswordResetDuration           = "notifier: password_reset: option '%s' must be more than 0 but it is configured as '%s'"
+	errFmtNotifierPasswordResetRetryDelay         = "notifier: password_reset: options 'retries' and 'retry_backoff' must " +
+		"not delay the response by more than %s but they are configured to delay it by up to '%s'"
 )
 
 // Authentication Backend Error constants.
@@ -69,6 +120,20 @@ const (
 		"backend is configured"
 	errFmtAuthBackendRefreshInterval = "authentication_backend: option 'refresh_interval' is configured to '%s' but " +
 		"it must be either a duration notation or one of 'disable', or 'always': %w"
+	errFmtAuthBackendVerifyTimeout = "authentication_backend: option 'verify_timeout' is configured to '%s' but " +
+		"it must be greater than or equal to 0"
+	errFmtAuthBackendMaxConcurrentRequests = "authentication_backend: option 'max_concurrent_requests' is configured to '%d' but " +
+		"it must be greater than or equal to 0"
+	errFmtAuthBackendVerifyResponseHeaderNameRequired = "authentication_backend: verify_response_headers: header #%d: " +
+		"option 'name' is required"
+	errFmtAuthBackendVerifyResponseHeaderNameInvalid = "authentication_backend: verify_response_headers: header '%s': " +
+		"option 'name' must only contain the characters allowed in a HTTP header name"
+	errFmtAuthBackendVerifyResponseHeaderNameReserved = "authentication_backend: verify_response_headers: header '%s': " +
+		"option 'name' must not be one of the headers which are always set by the verify endpoint"
+	errFmtAuthBackendVerifyResponseHeaderNameDuplicate = "authentication_backend: verify_response_headers: header '%s': " +
+		"option 'name' must be unique but it's configured more than once"
+	errFmtAuthBackendVerifyResponseHeaderSource = "authentication_backend: verify_response_headers: header '%s': " +
+		"option 'source' must be one of '%s' but it's configured as '%s'"
 	errFmtAuthBackendPasswordResetCustomURLScheme = "authentication_backend: password_reset: option 'custom_url' is" +
 		" configured to '%s' which has the scheme '%s' but the scheme must be either 'http' or 'https'"
 
@@ -76,13 +141,23 @@ const (
 	errFmtFileAuthBackendPasswordSaltLength = "authentication_backend: file: password: option 'salt_length' " +
 		"must be 2 or more but it is configured a '%d'"
 	errFmtFileAuthBackendPasswordUnknownAlg = "authentication_backend: file: password: option 'algorithm' " +
-		"must be either 'argon2id' or 'sha512' but it is configured as '%s'"
+		"must be one of 'argon2id', 'sha512', or 'scrypt' but it is configured as '%s'"
+	errFmtFileAuthBackendPasswordUnsupportedAlg = "authentication_backend: file: password: option 'algorithm' " +
+		"must be one of 'argon2id', 'sha512', or 'scrypt' but it is configured as '%s' which is only supported " +
+		"when verifying existing password hashes and can't be used to generate them"
 	errFmtFileAuthBackendPasswordInvalidIterations = "authentication_backend: file: password: option " +
 		"'iterations' must be 1 or more but it is configured as '%d'"
 	errFmtFileAuthBackendPasswordArgon2idInvalidKeyLength = "authentication_backend: file: password: option " +
 		"'key_length' must be 16 or more when using algorithm 'argon2id' but it is configured as '%d'"
 	errFmtFileAuthBackendPasswordArgon2idInvalidParallelism = "authentication_backend: file: password: option " +
 		"'parallelism' must be 1 or more when using algorithm 'argon2id' but it is configured as '%d'"
+	errFmtFileAuthBackendPasswordScryptInvalidIterations = "authentication_backend: file: password: option " +
+		"'iterations' must be between %d and %d when using algorithm 'scrypt' but it is configured as '%d'"
+	errFmtFileAuthBackendPasswordScryptInvalidOption = "authentication_backend: file: password: option " +
+		"'%s' must be %d or more when using algorithm 'scrypt' but it is configured as '%d'"
+	errFmtFileAuthBackendPasswordScryptInvalidIterationsBlockSize = "authentication_backend: file: password: " +
+		"option 'iterations' must be less than block_size multiplied by 16 when using algorithm 'scrypt' " +
+		"with block_size %d it should be less than %d but it is configured as '%d'"
 	errFmtFileAuthBackendPasswordArgon2idInvalidMemory = "authentication_backend: file: password: option 'memory' " +
 		"must at least be parallelism multiplied by 8 when using algorithm 'argon2id' " +
 		"with parallelism %d it should be at least %d but it is configured as '%d'"
@@ -94,6 +169,8 @@ const (
 		"is configured as '%s' but must be one of the following values: '%s'"
 	errFmtLDAPAuthBackendFilterReplacedPlaceholders = "authentication_backend: ldap: option " +
 		"'%s' has an invalid placeholder: '%s' has been removed, please use '%s' instead"
+	errFmtLDAPAuthBackendReferralHops = "authentication_backend: ldap: option 'referral_hops' " +
+		"is configured to '%d' but it must be greater than or equal to 0"
 	errFmtLDAPAuthBackendURLNotParsable = "authentication_backend: ldap: option " +
 		"'url' could not be parsed: %w"
 	errFmtLDAPAuthBackendURLInvalidScheme = "authentication_backend: ldap: option " +
@@ -102,6 +179,15 @@ const (
 		"'%s' must contain enclosing parenthesis: '%s' should probably be '(%s)'"
 	errFmtLDAPAuthBackendFilterMissingPlaceholder = "authentication_backend: ldap: option " +
 		"'%s' must contain the placeholder '{%s}' but it is required"
+	errFmtLDAPAuthBackendFilterPlaceholderNotInComparison = "authentication_backend: ldap: option " +
+		"'%s' has the placeholder '%s' outside of an attribute comparison such as '(attribute=%s)' which may allow " +
+		"the value to change the meaning of the filter"
+	errFmtLDAPAuthBackendActiveDirectoryUsersFilter = "authentication_backend: ldap: option " +
+		"'users_filter' should match users by the 'sAMAccountName' or 'userPrincipalName' attribute when using " +
+		"the 'activedirectory' implementation but it is configured as '%s'"
+	errFmtLDAPAuthBackendActiveDirectoryGroupNameAttribute = "authentication_backend: ldap: option " +
+		"'group_name_attribute' is not configured while option 'groups_filter' is so the default '%s' is used " +
+		"which may not match the groups returned by the filter"
 )
 
 // TOTP Error constants.
@@ -109,6 +195,7 @@ const (
 	errFmtTOTPInvalidAlgorithm = "totp: option 'algorithm' must be one of '%s' but it is configured as '%s'"
 	errFmtTOTPInvalidPeriod    = "totp: option 'period' option must be 15 or more but it is configured as '%d'"
 	errFmtTOTPInvalidDigits    = "totp: option 'digits' must be 6 or 8 but it is configured as '%d'"
+	errFmtTOTPInvalidSkew      = "totp: option 'skew' must be between 0 and 2 but it is configured as '%d'"
 )
 
 // Storage Error constants.
@@ -119,6 +206,8 @@ const (
 	errFmtStorageUserPassMustBeProvided      = "storage: %s: option 'username' and 'password' are required" //nolint: gosec
 	errFmtStorageOptionMustBeProvided        = "storage: %s: option '%s' is required"
 	errFmtStoragePostgreSQLInvalidSSLMode    = "storage: postgres: ssl: option 'mode' must be one of '%s' but it is configured as '%s'"
+	errFmtStorageConnectionPoolOption        = "storage: %s: option '%s' must be more than 0 (the default is '%v') but it is configured as '%v'"
+	errFmtStorageMaxIdleConnections          = "storage: %s: option 'max_idle_connections' must not be more than the 'max_open_connections' value of '%d' but it is configured as '%d'"
 )
 
 // OpenID Error constants.
@@ -127,9 +216,43 @@ const (
 		"more clients configured"
 	errFmtOIDCNoPrivateKey = "identity_providers: oidc: option 'issuer_private_key' is required"
 
+	errFmtOIDCIssuerPrivateKeysNoKeyID = "identity_providers: oidc: issuer_private_keys: key #%d: option " +
+		"'key_id' is required"
+	errFmtOIDCIssuerPrivateKeysInvalidKeyID = "identity_providers: oidc: issuer_private_keys: key #%d: option " +
+		"'key_id' must only contain alphanumeric characters, hyphens, and underscores but it is configured as '%s'"
+	errFmtOIDCIssuerPrivateKeysDuplicateKeyID = "identity_providers: oidc: issuer_private_keys: key #%d: option " +
+		"'key_id' must be unique but '%s' is configured more than once"
+	errFmtOIDCIssuerPrivateKeysNoKey = "identity_providers: oidc: issuer_private_keys: key #%d: option " +
+		"'key' is required"
+
 	errFmtOIDCEnforcePKCEInvalidValue = "identity_providers: oidc: option 'enforce_pkce' must be 'never', " +
 		"'public_clients_only' or 'always', but it is configured as '%s'"
 
+	errFmtOIDCMaximumRequested       = "identity_providers: oidc: option 'maximum_requested_%s' must be above 0 but it is configured as '%d'"
+	errFmtOIDCMaximumDebugBodyLength = "identity_providers: oidc: option 'maximum_debug_body_length' must be above 0 but it is configured as '%d'"
+
+	errFmtOIDCTokenEndpointMaximumBodySize = "identity_providers: oidc: option 'token_endpoint_maximum_body_size' " +
+		"must not be negative but it is configured as '%d'"
+	errFmtOIDCTokenEndpointTrustedProxyInvalid = "identity_providers: oidc: option 'token_endpoint_trusted_proxies' " +
+		"must only contain IP addresses or networks in CIDR notation but it contains '%s'"
+	errFmtOIDCInvalidRateLimitValue = "identity_providers: oidc: %s: " +
+		"option '%s' must not be negative but it is configured as '%v'"
+	errFmtOIDCInvalidRateLimitBurst = "identity_providers: oidc: %s: " +
+		"option 'burst' must only be configured when option 'requests_per_second' is configured"
+
+	errFmtOIDCAllowedResponseTypesInvalidValue = "identity_providers: oidc: option 'allowed_response_types' must only " +
+		"have the values '%s' but one option is configured as '%s'"
+
+	errFmtOIDCConsentAuditLogPathUnknown = "identity_providers: oidc: option 'consent_audit_log_path' refers to the " +
+		"file '%s' which could not be checked: %w"
+	errFmtOIDCConsentAuditLogPathIsDirectory = "identity_providers: oidc: option 'consent_audit_log_path' refers to " +
+		"'%s' which is a directory but it must be a file"
+	errFmtOIDCConsentAuditLogPathNoDirectory = "identity_providers: oidc: option 'consent_audit_log_path' refers to the " +
+		"file '%s' but its directory '%s' does not exist"
+
+	errFmtOIDCPreferredUsernameClaimInvalidValue = "identity_providers: oidc: option 'preferred_username_claim' must be one of " +
+		"'%s' but it is configured as '%s'"
+
 	errFmtOIDCClientsDuplicateID = "identity_providers: oidc: one or more clients have the same id but all client" +
 		"id's must be unique"
 	errFmtOIDCClientsWithEmptyID = "identity_providers: oidc: one or more clients have been configured with " +
@@ -138,8 +261,22 @@ const (
 	errFmtOIDCClientInvalidSecret       = "identity_providers: oidc: client '%s': option 'secret' is required"
 	errFmtOIDCClientPublicInvalidSecret = "identity_providers: oidc: client '%s': option 'secret' is " +
 		"required to be empty when option 'public' is true"
+	errFmtOIDCClientPublicInvalidSecretNext = "identity_providers: oidc: client '%s': option 'secret_next' is " +
+		"required to be empty when option 'public' is true"
+	errFmtOIDCClientSecretNextWithoutSecret = "identity_providers: oidc: client '%s': option 'secret_next' " +
+		"must only be configured when option 'secret' is configured"
+	errFmtOIDCClientInvalidSecretExpiresAt = "identity_providers: oidc: client '%s': option 'secret_expires_at' " +
+		"must be a RFC3339 timestamp such as '2006-01-02T15:04:05Z' but it is configured as '%s'"
+	errFmtOIDCClientSecretExpired = "identity_providers: oidc: client '%s': option 'secret' expired at '%s' and " +
+		"should be rotated using the option 'secret_next'"
 	errFmtOIDCClientRedirectURI = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
 		"invalid value: redirect uri '%s' must have a scheme of 'http' or 'https' but '%s' is configured"
+	errFmtOIDCClientRedirectURIInsecure = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
+		"invalid value: redirect uri '%s' must have the scheme 'https' unless it's a loopback address when option " +
+		"'enforce_https_redirect_uris' is enabled but it has the scheme 'http'"
+	errFmtOIDCClientRedirectURILoopbackPort = "identity_providers: oidc: client '%s': option 'redirect_uris' has the " +
+		"redirect uri '%s' which uses the loopback host '%s' but the port of a redirect uri is only matched " +
+		"dynamically for the loopback addresses '127.0.0.1' and '[::1]', native apps should use one of them instead"
 	errFmtOIDCClientRedirectURICantBeParsed = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
 		"invalid value: redirect uri '%s' could not be parsed: %v"
 	errFmtOIDCClientRedirectURIPublic = "identity_providers: oidc: client '%s': option 'redirect_uris' has the" +
@@ -153,6 +290,25 @@ const (
 		"'%s' but one option is configured as '%s'"
 	errFmtOIDCClientInvalidUserinfoAlgorithm = "identity_providers: oidc: client '%s': option " +
 		"'userinfo_signing_algorithm' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidSigningKeyID = "identity_providers: oidc: client '%s': option 'signing_key_id' " +
+		"must be the 'key_id' of one of the 'issuer_private_keys' but it is configured as '%s'"
+	errFmtOIDCClientInvalidRateLimitValue = "identity_providers: oidc: client '%s': token_endpoint_rate_limit: " +
+		"option '%s' must not be negative but it is configured as '%v'"
+	errFmtOIDCClientInvalidRateLimitBurst = "identity_providers: oidc: client '%s': token_endpoint_rate_limit: " +
+		"option 'burst' must only be configured when option 'requests_per_second' is configured"
+	errFmtOIDCClientInvalidLifespan = "identity_providers: oidc: client '%s': option '%s' must be a positive duration " +
+		"but it is configured as '%s'"
+	errFmtOIDCClientInvalidRefreshTokenAbsoluteLifetime = "identity_providers: oidc: client '%s': option " +
+		"'refresh_token_absolute_lifetime' must be greater than or equal to the option 'refresh_token_lifespan' " +
+		"value of '%s' but it is configured as '%s'"
+	errFmtOIDCClientImplicitFlowDisabled = "identity_providers: oidc: client '%s': option '%s' must not contain " +
+		"'%s' when option 'disable_implicit_flow' is enabled as it's used by the implicit or hybrid flows"
+	errFmtOIDCClientResponseTypeNotAllowed = "identity_providers: oidc: client '%s': option 'response_types' " +
+		"must only have the values allowed by the option 'allowed_response_types' but one option is configured as '%s'"
+	errFmtOIDCClientInvalidAllowedNetwork = "identity_providers: oidc: client '%s': option 'allowed_networks' has " +
+		"an invalid value: the network '%s' must be a valid IP address or CIDR notation"
+	errFmtOIDCClientInvalidClaim = "identity_providers: oidc: client '%s': option 'claims' must not contain the " +
+		"reserved claim '%s'"
 	errFmtOIDCServerInsecureParameterEntropy = "openid connect provider: SECURITY ISSUE - minimum parameter entropy is " +
 		"configured to an unsafe value, it should be above 8 but it's configured to %d"
 )
@@ -161,6 +317,8 @@ const (
 const (
 	errFmtWebauthnConveyancePreference = "webauthn: option 'attestation_conveyance_preference' must be one of '%s' but it is configured as '%s'"
 	errFmtWebauthnUserVerification     = "webauthn: option 'user_verification' must be one of 'discouraged', 'preferred', 'required' but it is configured as '%s'"
+	errFmtWebauthnAllowedAAGUID        = "webauthn: option 'allowed_aaguids' must only contain valid UUIDs but it has the value '%s': %w"
+	errFmtWebauthnAllowedAAGUIDNone    = "webauthn: option 'allowed_aaguids' is configured but option 'attestation_conveyance_preference' is configured as 'none' so authenticators will not provide an AAGUID and registration will always fail"
 )
 
 // Access Control error constants.
@@ -169,8 +327,16 @@ const (
 		"configured as '%s'"
 	errFmtAccessControlDefaultPolicyWithoutRules = "access control: 'default_policy' option '%s' is invalid: when " +
 		"no rules are specified it must be 'two_factor' or 'one_factor'"
+	errFmtAccessControlDomainDefaultPolicyNoDomains = "access control: domain default policy #%d is invalid: must have " +
+		"the option 'domain' configured"
+	errFmtAccessControlDomainDefaultPolicyValue = "access control: domain default policy %s: option 'policy' must be " +
+		"one of '%s' but it is configured as '%s'"
+	errFmtAccessControlDomainDefaultPolicyWithoutRules = "access control: domain default policy %s: option 'policy' " +
+		"with value '%s' is invalid: when no rules are specified it must be 'two_factor' or 'one_factor'"
 	errFmtAccessControlNetworkGroupIPCIDRInvalid = "access control: networks: network group '%s' is invalid: the " +
 		"network '%s' is not a valid IP or CIDR notation"
+	errFmtAccessControlTrustedNetworkInvalid = "access control: option 'trusted_networks' must only contain valid " +
+		"IP addresses or CIDR notation networks but it contains '%s'"
 	errFmtAccessControlWarnNoRulesDefaultPolicy = "access control: no rules have been specified so the " +
 		"'default_policy' of '%s' is going to be applied to all requests"
 	errFmtAccessControlRuleNoDomains = "access control: rule %s: rule is invalid: must have the option " +
@@ -189,6 +355,26 @@ const (
 		"invalid: must start with 'user:' or 'group:'"
 	errFmtAccessControlRuleMethodInvalid = "access control: rule %s: 'methods' option '%s' is " +
 		"invalid: must be one of '%s'"
+	errFmtAccessControlRuleSecondFactorMethodInvalid = "access control: rule %s: 'methods_2fa' option '%s' is " +
+		"invalid: must be one of '%s'"
+	errFmtAccessControlRuleSecondFactorMethodsPolicyInvalid = "access control: rule %s: 'methods_2fa' option is only " +
+		"supported when the 'policy' option is 'two_factor' but it is configured as '%s'"
+	errFmtAccessControlRuleQueryKeyMissing = "access control: rule %s: 'query' option must have the 'key' option " +
+		"configured for every rule"
+	errFmtAccessControlRuleQueryOperatorInvalid = "access control: rule %s: 'query' option 'operator' with value '%s' " +
+		"is invalid: must be one of '%s'"
+	errFmtAccessControlRuleQueryValueUnexpected = "access control: rule %s: 'query' option 'value' must not be " +
+		"configured when the 'operator' is '%s' but it is configured for the key '%s'"
+	errFmtAccessControlRuleQueryValueMissing = "access control: rule %s: 'query' option 'value' must be configured " +
+		"when the 'operator' is '%s' but it is not configured for the key '%s'"
+	errFmtAccessControlRuleQueryPatternInvalid = "access control: rule %s: 'query' option 'value' for the key '%s' " +
+		"is not a valid regular expression: %w"
+	errFmtAccessControlRuleTimeInvalid = "access control: rule %s: 'time' option '%s' is " +
+		"invalid: %w"
+	errFmtAccessControlRuleBasicAuthPolicyInvalid = "access control: rule %s: 'basic_auth' option is only " +
+		"supported when the 'policy' option is 'one_factor' but it is configured as '%s'"
+	errFmtAccessControlRuleBasicAuthNoSubjects = "access control: rule %s: 'basic_auth' option requires the " +
+		"'subject' option to be configured so only the intended clients are permitted to authenticate with a single factor"
 )
 
 // Theme Error constants.
@@ -199,18 +385,39 @@ const (
 // NTP Error constants.
 const (
 	errFmtNTPVersion = "ntp: option 'version' must be either 3 or 4 but it is configured as '%d'"
+	errFmtNTPTimeout = "ntp: option 'timeout' must be a positive duration but it is configured as '%s'"
+)
+
+// Trusted Device Error constants.
+const (
+	errFmtTrustedDeviceLifespan   = "trusted_device: option 'lifespan' must not be negative but it is configured as '%s'"
+	errFmtTrustedDeviceCookieName = "trusted_device: option 'cookie_name' must not be the same as the session name but " +
+		"it is configured as '%s'"
 )
 
 // Session error constants.
 const (
 	errFmtSessionOptionRequired           = "session: option '%s' is required"
 	errFmtSessionDomainMustBeRoot         = "session: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '%s'"
+	errFmtSessionDomainsMustBeRoot        = "session: option 'domains' must only contain the domains you wish to protect not wildcard domains but one is configured as '%s'"
 	errFmtSessionSameSite                 = "session: option 'same_site' must be one of '%s' but is configured as '%s'"
+	errFmtSessionSameSiteOIDC             = "session: option 'same_site_oidc' must be one of '%s' but is configured as '%s'"
+	errFmtSessionMaximumLifetime          = "session: option 'maximum_lifetime' must be 0 (disabled) or a positive duration but it is configured as '%s'"
+	errSessionRememberMeRequireTwoFactor  = "session: option 'remember_me_require_two_factor' has no effect because option 'remember_me_duration' is configured as '-1' (disabled)"
 	errFmtSessionSecretRequired           = "session: option 'secret' is required when using the '%s' provider"
 	errFmtSessionRedisPortRange           = "session: redis: option 'port' must be between 1 and 65535 but is configured as '%d'"
 	errFmtSessionRedisHostRequired        = "session: redis: option 'host' is required"
 	errFmtSessionRedisHostOrNodesRequired = "session: redis: option 'host' or the 'high_availability' option 'nodes' is required"
 
+	errFmtSessionRedisHostAndNodesConfigured = "session: redis: option 'host' must not be configured when the 'high_availability' option 'nodes' is configured but it's configured as '%s'"
+
+	errSessionRedisCredentialsFileWithCredentials = "session: redis: option 'credentials_file' must not be configured with the 'username' or 'password' options"
+	errFmtSessionRedisCredentialsFileNotExist     = "session: redis: option 'credentials_file' refers to the file '%s' which does not exist"
+	errFmtSessionRedisCredentialsFileUnknownError = "session: redis: option 'credentials_file' refers to the file '%s' which could not be opened: %w"
+
+	errFmtSessionRedisTLSRequired      = "session: redis: option 'tls' must be configured when the option 'require_tls' is enabled but the host '%s' is not a loopback address or unix socket"
+	errFmtSessionRedisTLSNotConfigured = "session: redis: option 'tls' is not configured and the host '%s' is not a loopback address or unix socket, the session data will be sent to redis unencrypted"
+
 	errFmtSessionRedisSentinelMissingName     = "session: redis: high_availability: option 'sentinel_name' is required"
 	errFmtSessionRedisSentinelNodeHostMissing = "session: redis: high_availability: option 'nodes': option 'host' is required for each node but one or more nodes are missing this"
 )
@@ -225,9 +432,41 @@ const (
 	errFmtServerTLSCert = "server: tls: option 'key' must also be accompanied by option 'certificate'"
 	errFmtServerTLSKey  = "server: tls: option 'certificate' must also be accompanied by option 'key'"
 
+	errFmtServerTLSRedirectPort      = "server: tls: option 'redirect_port' must be between 1 and 65535 but it is configured as '%d'"
+	errFmtServerTLSRedirectPortInUse = "server: tls: option 'redirect_port' must not be the same as option 'port' which is configured as '%d'"
+	errFmtServerTLSRedirectPortNoTLS = "server: tls: option 'redirect_port' is configured but options 'certificate' and 'key' are not so it has no effect"
+	errFmtServerTLSRedirectPortDebug = "server: tls: option 'redirect_port' must not be the same as the port of option 'address' in section 'debug' which is configured as '%s'"
+	errFmtServerTLSRedirectPortURL   = "server: tls: option 'redirect_port' requires option 'external_url' to be configured with the 'https' scheme as it's used as the redirect location but it's configured as '%s'"
+
 	errFmtServerPathNoForwardSlashes = "server: option 'path' must not contain any forward slashes"
 	errFmtServerPathAlphaNum         = "server: option 'path' must only contain alpha numeric characters"
 	errFmtServerBufferSize           = "server: option '%s_buffer_size' must be above 0 but it is configured as '%d'"
+	errFmtServerExternalURL          = "server: option 'external_url' must be an absolute URL with the 'http' or 'https' scheme but it is configured as '%s'"
+	errFmtServerExternalURLDomain    = "server: option 'external_url' has the host '%s' which is not the same as or a subdomain of any of the session domains '%s' so the session cookie will never be set"
+	errFmtServerFallbackLocale       = "server: option 'fallback_locale' must be a locale such as 'en' or 'en-us' but it is configured as '%s'"
+
+	errFmtServerDebugAddress      = "server: debug: option 'address' must be a host and port such as 'localhost:9092' but it is configured as '%s': %w"
+	errFmtServerDebugAddressPort  = "server: debug: option 'address' must have a port between 1 and 65535 but it is configured as '%s'"
+	errFmtServerDebugAddressNoUse = "server: debug: option 'address' is configured but neither option 'enable_pprof' or 'enable_expvars' are enabled so it has no effect"
+	errFmtServerDebugAddressInUse = "server: debug: option 'address' must not be the same as the main server address '%s'"
+
+	errFmtServerHealthCheckPath = "server: health_check: option 'path' must be an absolute path starting with a forward slash " +
+		"and must not contain whitespace, query, fragment, or parameter characters but it is configured as '%s'"
+
+	errFmtServerErrorPagesNotExist = "server: error_pages: option '%s' must be the path of an existing file but it is configured as '%s'"
+	errFmtServerErrorPagesUnknown  = "server: error_pages: option '%s' with value '%s' could not be verified due to a file system error: %w"
+)
+
+// Security Error constants.
+const (
+	errFmtSecurityWeakSecret   = "security: SECURITY ISSUE - %s is configured with a well known value which must be changed"
+	errFmtSecuritySecretReused = "security: SECURITY ISSUE - %s is configured with the same value as %s but each secret should be unique"
+	errFmtSecurityShortSecret  = "security: SECURITY ISSUE - %s is configured with a value shorter than %d characters which is easily guessed"
+)
+
+// Configuration Validation Error constants.
+const (
+	errFmtConfigurationStrictWarning = "%w: this warning is reported as an error because the 'configuration.strict' option is enabled"
 )
 
 // Error constants.
@@ -257,6 +496,16 @@ var validThemeNames = []string{"light", "dark", "grey", "auto"}
 
 var validSessionSameSiteValues = []string{"none", "lax", "strict"}
 
+// securitySecretMinimumLength is the length below which a secret is considered weak, the same as the minimum length of
+// the storage encryption key.
+const securitySecretMinimumLength = 20
+
+// weakSecrets are well known secret values such as those from the configuration template and documentation examples.
+var weakSecrets = []string{
+	"secret", "password", "changeme", "insecure_secret", "a_very_important_secret", "v3ry_important_s3cr3t",
+	"this_is_a_secret_abc123abc123abc", "you_must_generate_a_random_string_of_more_than_twenty_chars_and_configure_this",
+}
+
 var validLoLevels = []string{"trace", "debug", "info", "warn", "error"}
 
 var validWebauthnConveyancePreferences = []string{string(protocol.PreferNoAttestation), string(protocol.PreferIndirectAttestation), string(protocol.PreferDirectAttestation)}
@@ -269,20 +518,64 @@ var validACLHTTPMethodVerbs = append(validRFC7231HTTPMethodVerbs, validRFC4918HT
 
 var validACLRulePolicies = []string{policyBypass, policyOneFactor, policyTwoFactor, policyDeny}
 
+var validACLRuleSecondFactorMethods = []string{authorization.SecondFactorMethodTOTP, authorization.SecondFactorMethodWebauthn, authorization.SecondFactorMethodDuo}
+
+var validVerifyResponseHeaderSources = []string{
+	schema.VerifyResponseHeaderSourceUsername,
+	schema.VerifyResponseHeaderSourceDisplayName,
+	schema.VerifyResponseHeaderSourceEmail,
+	schema.VerifyResponseHeaderSourceEmails,
+	schema.VerifyResponseHeaderSourceGroups,
+}
+
+// reservedVerifyResponseHeaders are the headers which are always set by the verify endpoint for authorized users.
+var reservedVerifyResponseHeaders = []string{"Remote-User", "Remote-Groups", "Remote-Name", "Remote-Email"}
+
+var validACLQueryOperators = []string{operatorEqual, operatorNotEqual, operatorPresent, operatorAbsent, operatorPattern, operatorNotPattern}
+
 var validOIDCScopes = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeProfile, oidc.ScopeGroups, "offline_access"}
-var validOIDCGrantTypes = []string{"implicit", "refresh_token", "authorization_code", "password", "client_credentials"}
+
+const (
+	oidcGrantTypeImplicit   = "implicit"
+	oidcResponseTypeToken   = "token"
+	oidcResponseTypeIDToken = "id_token"
+)
+
+var validOIDCGrantTypes = []string{oidcGrantTypeImplicit, "refresh_token", "authorization_code", "password", "client_credentials"}
+var validOIDCResponseTypes = []string{"code", "token", "id_token", "code token", "code id_token", "token id_token", "code token id_token", "none"}
 var validOIDCResponseModes = []string{"form_post", "query", "fragment"}
 var validOIDCUserinfoAlgorithms = []string{"none", "RS256"}
+var validOIDCPreferredUsernameClaims = []string{"username", "display_name", "email"}
+
+// reservedOIDCClaims are the claims which are set by the OpenID Connect provider and can't be configured as static claims.
+var reservedOIDCClaims = []string{
+	"iss", "sub", "aud", "exp", "iat", "nbf", "jti", "auth_time", "nonce", "acr", "amr", "azp", "at_hash", "c_hash",
+	"rat", "scope", "scp", "client_id",
+	oidc.ClaimGroups, oidc.ClaimDisplayName, oidc.ClaimPreferredUsername, oidc.ClaimEmail, oidc.ClaimEmailVerified,
+	oidc.ClaimEmailAlts,
+}
 
 var reKeyReplacer = regexp.MustCompile(`\[\d+]`)
 
+var reLocale = regexp.MustCompile(`^[a-z]{1,3}(-[a-z0-9-]+)?$`)
+
+// reHostname matches a hostname made of one or more labels as per RFC1123.
+var reHostname = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// reHeaderName matches the token characters which are allowed in a HTTP header name as per RFC7230.
+var reHeaderName = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+var reOIDCKeyID = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 // ValidKeys is a list of valid keys that are not secret names. For the sake of consistency please place any secret in
 // the secret names map and reuse it in relevant sections.
 var ValidKeys = []string{
 	// Root Keys.
 	"certificates_directory",
+	"tls.trusted_ca",
 	"theme",
 	"default_redirection_url",
+	"remember_last_2fa_method",
 	"jwt_secret",
 
 	// Log keys.
@@ -290,6 +583,7 @@ var ValidKeys = []string{
 	"log.format",
 	"log.file_path",
 	"log.keep_stdout",
+	"log.redacted_fields",
 
 	// Server Keys.
 	"server.host",
@@ -297,13 +591,20 @@ var ValidKeys = []string{
 	"server.read_buffer_size",
 	"server.write_buffer_size",
 	"server.path",
+	"server.external_url",
 	"server.asset_path",
+	"server.fallback_locale",
 	"server.enable_pprof",
 	"server.enable_expvars",
 	"server.disable_healthcheck",
 	"server.tls.key",
 	"server.tls.certificate",
+	"server.tls.redirect_port",
 	"server.headers.csp_template",
+	"server.debug.address",
+	"server.health_check.path",
+	"server.error_pages.client_error",
+	"server.error_pages.server_error",
 
 	// TOTP Keys.
 	"totp.disable",
@@ -313,12 +614,18 @@ var ValidKeys = []string{
 	"totp.period",
 	"totp.skew",
 
+	// Security Keys.
+
+	// Configuration Validation Keys.
+	"configuration.strict",
+
 	// Webauthn Keys.
 	"webauthn.disable",
 	"webauthn.display_name",
 	"webauthn.attestation_conveyance_preference",
 	"webauthn.user_verification",
 	"webauthn.timeout",
+	"webauthn.allowed_aaguids",
 
 	// DUO API Keys.
 	"duo_api.hostname",
@@ -328,10 +635,14 @@ var ValidKeys = []string{
 
 	// Access Control Keys.
 	"access_control.default_policy",
+	"access_control.domain_default_policies",
+	"access_control.domain_default_policies[].domain",
+	"access_control.domain_default_policies[].policy",
 	"access_control.networks",
 	"access_control.networks[].name",
 	"access_control.networks[].networks",
 	"access_control.rules",
+	"access_control.trusted_networks",
 	"access_control.rules[].domain",
 	"access_control.rules[].domain_regex",
 	"access_control.rules[].methods",
@@ -339,27 +650,37 @@ var ValidKeys = []string{
 	"access_control.rules[].subject",
 	"access_control.rules[].policy",
 	"access_control.rules[].resources",
+	"access_control.rules[].query",
+	"access_control.rules[].time",
+	"access_control.rules[].basic_auth",
+	"access_control.rules[].methods_2fa",
 
 	// Session Keys.
 	"session.name",
 	"session.domain",
+	"session.domains",
 	"session.secret",
 	"session.same_site",
+	"session.same_site_oidc",
 	"session.expiration",
 	"session.inactivity",
 	"session.remember_me_duration",
+	"session.remember_me_require_two_factor",
+	"session.maximum_lifetime",
 
 	// Redis Session Keys.
 	"session.redis.host",
 	"session.redis.port",
 	"session.redis.username",
 	"session.redis.password",
+	"session.redis.credentials_file",
 	"session.redis.database_index",
 	"session.redis.maximum_active_connections",
 	"session.redis.minimum_idle_connections",
 	"session.redis.tls.minimum_version",
 	"session.redis.tls.skip_verify",
 	"session.redis.tls.server_name",
+	"session.redis.require_tls",
 	"session.redis.high_availability.sentinel_name",
 	"session.redis.high_availability.sentinel_username",
 	"session.redis.high_availability.sentinel_password",
@@ -371,6 +692,7 @@ var ValidKeys = []string{
 
 	// Storage Keys.
 	"storage.encryption_key",
+	"storage.migrate.dry_run",
 
 	// Local Storage Keys.
 	"storage.local.path",
@@ -382,6 +704,9 @@ var ValidKeys = []string{
 	"storage.mysql.username",
 	"storage.mysql.password",
 	"storage.mysql.timeout",
+	"storage.mysql.max_open_connections",
+	"storage.mysql.max_idle_connections",
+	"storage.mysql.connection_max_lifetime",
 
 	// PostgreSQL Storage Keys.
 	"storage.postgres.host",
@@ -390,6 +715,9 @@ var ValidKeys = []string{
 	"storage.postgres.username",
 	"storage.postgres.password",
 	"storage.postgres.timeout",
+	"storage.postgres.max_open_connections",
+	"storage.postgres.max_idle_connections",
+	"storage.postgres.connection_max_lifetime",
 	"storage.postgres.schema",
 	"storage.postgres.ssl.mode",
 	"storage.postgres.ssl.root_certificate",
@@ -417,7 +745,34 @@ var ValidKeys = []string{
 	"notifier.smtp.tls.minimum_version",
 	"notifier.smtp.tls.skip_verify",
 	"notifier.smtp.tls.server_name",
+	"notifier.smtp.auth.xoauth2.token_endpoint",
+	"notifier.smtp.auth.xoauth2.client_id",
+	"notifier.smtp.auth.xoauth2.client_secret",
+	"notifier.smtp.auth.xoauth2.scopes",
+	"notifier.smtp.fallback.host",
+	"notifier.smtp.fallback.port",
+	"notifier.smtp.fallback.timeout",
+	"notifier.smtp.fallback.username",
+	"notifier.smtp.fallback.password",
+	"notifier.smtp.fallback.identifier",
+	"notifier.smtp.fallback.sender",
+	"notifier.smtp.fallback.subject",
+	"notifier.smtp.fallback.startup_check_address",
+	"notifier.smtp.fallback.disable_require_tls",
+	"notifier.smtp.fallback.disable_html_emails",
+	"notifier.smtp.fallback.tls.minimum_version",
+	"notifier.smtp.fallback.tls.skip_verify",
+	"notifier.smtp.fallback.tls.server_name",
+	"notifier.smtp.fallback.auth.xoauth2.token_endpoint",
+	"notifier.smtp.fallback.auth.xoauth2.client_id",
+	"notifier.smtp.fallback.auth.xoauth2.client_secret",
+	"notifier.smtp.fallback.auth.xoauth2.scopes",
 	"notifier.template_path",
+	"notifier.notifications.device_change",
+	"notifier.password_reset.retries",
+	"notifier.password_reset.retry_backoff",
+	"notifier.password_reset.on_failure",
+	"notifier.password_reset.queue_interval",
 
 	// Regulation Keys.
 	"regulation.max_retries",
@@ -427,7 +782,13 @@ var ValidKeys = []string{
 	// Authentication Backend Keys.
 	"authentication_backend.disable_reset_password",
 	"authentication_backend.password_reset.custom_url",
+	"authentication_backend.password_reset.revoke_sessions",
 	"authentication_backend.refresh_interval",
+	"authentication_backend.verify_timeout",
+	"authentication_backend.max_concurrent_requests",
+	"authentication_backend.verify_response_headers",
+	"authentication_backend.verify_response_headers[].name",
+	"authentication_backend.verify_response_headers[].source",
 
 	// LDAP Authentication Backend Keys.
 	"authentication_backend.ldap.implementation",
@@ -442,9 +803,13 @@ var ValidKeys = []string{
 	"authentication_backend.ldap.group_name_attribute",
 	"authentication_backend.ldap.mail_attribute",
 	"authentication_backend.ldap.display_name_attribute",
+	"authentication_backend.ldap.log_allowed_attributes",
 	"authentication_backend.ldap.user",
 	"authentication_backend.ldap.password",
 	"authentication_backend.ldap.start_tls",
+	"authentication_backend.ldap.follow_referrals",
+	"authentication_backend.ldap.referral_hops",
+	"authentication_backend.ldap.referral_hosts",
 	"authentication_backend.ldap.tls.minimum_version",
 	"authentication_backend.ldap.tls.skip_verify",
 	"authentication_backend.ldap.tls.server_name",
@@ -457,23 +822,45 @@ var ValidKeys = []string{
 	"authentication_backend.file.password.salt_length",
 	"authentication_backend.file.password.memory",
 	"authentication_backend.file.password.parallelism",
+	"authentication_backend.file.password.block_size",
 
 	// Identity Provider Keys.
 	"identity_providers.oidc.hmac_secret",
 	"identity_providers.oidc.issuer_private_key",
+	"identity_providers.oidc.issuer_private_keys",
+	"identity_providers.oidc.issuer_private_keys[].key_id",
+	"identity_providers.oidc.issuer_private_keys[].key",
 	"identity_providers.oidc.id_token_lifespan",
 	"identity_providers.oidc.access_token_lifespan",
 	"identity_providers.oidc.refresh_token_lifespan",
 	"identity_providers.oidc.authorize_code_lifespan",
 	"identity_providers.oidc.enforce_pkce",
 	"identity_providers.oidc.enable_pkce_plain_challenge",
+	"identity_providers.oidc.enforce_introspection_audience",
+	"identity_providers.oidc.enforce_https_redirect_uris",
+	"identity_providers.oidc.disable_implicit_flow",
+	"identity_providers.oidc.allowed_response_types",
+	"identity_providers.oidc.consent_audit_log_path",
 	"identity_providers.oidc.enable_client_debug_messages",
+	"identity_providers.oidc.maximum_debug_body_length",
+	"identity_providers.oidc.enable_consent_regulation",
 	"identity_providers.oidc.minimum_parameter_entropy",
+	"identity_providers.oidc.preferred_username_claim",
+	"identity_providers.oidc.maximum_requested_scopes",
+	"identity_providers.oidc.maximum_requested_audiences",
+	"identity_providers.oidc.token_endpoint_maximum_body_size",
+	"identity_providers.oidc.token_endpoint_rate_limit.requests_per_second",
+	"identity_providers.oidc.token_endpoint_rate_limit.burst",
+	"identity_providers.oidc.token_endpoint_trusted_proxies",
+	"identity_providers.oidc.consent_rate_limit.requests_per_second",
+	"identity_providers.oidc.consent_rate_limit.burst",
 	"identity_providers.oidc.clients",
 	"identity_providers.oidc.clients[].id",
 	"identity_providers.oidc.clients[].description",
 	"identity_providers.oidc.clients[].public",
 	"identity_providers.oidc.clients[].secret",
+	"identity_providers.oidc.clients[].secret_next",
+	"identity_providers.oidc.clients[].secret_expires_at",
 	"identity_providers.oidc.clients[].redirect_uris",
 	"identity_providers.oidc.clients[].authorization_policy",
 	"identity_providers.oidc.clients[].scopes",
@@ -482,14 +869,32 @@ var ValidKeys = []string{
 	"identity_providers.oidc.clients[].response_types",
 	"identity_providers.oidc.clients[].response_modes",
 	"identity_providers.oidc.clients[].userinfo_signing_algorithm",
+	"identity_providers.oidc.clients[].signing_key_id",
+	"identity_providers.oidc.clients[].token_endpoint_rate_limit.requests_per_second",
+	"identity_providers.oidc.clients[].token_endpoint_rate_limit.burst",
+	"identity_providers.oidc.clients[].claims",
+	"identity_providers.oidc.clients[].access_token_lifespan",
+	"identity_providers.oidc.clients[].authorize_code_lifespan",
+	"identity_providers.oidc.clients[].id_token_lifespan",
+	"identity_providers.oidc.clients[].refresh_token_lifespan",
+	"identity_providers.oidc.clients[].refresh_token_absolute_lifetime",
+	"identity_providers.oidc.clients[].refresh_session_activity",
+	"identity_providers.oidc.clients[].require_state",
+	"identity_providers.oidc.clients[].allowed_networks",
 
 	// NTP keys.
 	"ntp.address",
 	"ntp.version",
 	"ntp.max_desync",
+	"ntp.timeout",
 	"ntp.disable_startup_check",
 	"ntp.disable_failure",
 
+	// Trusted Device keys.
+	"trusted_device.lifespan",
+	"trusted_device.cookie_name",
+	"trusted_device.bind_to_ip",
+
 	// Password Policy keys.
 	"password_policy.standard.enabled",
 	"password_policy.standard.min_length",
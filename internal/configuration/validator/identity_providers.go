@@ -2,7 +2,12 @@ package validator
 
 import (
 	"fmt"
+	"math"
+	"net"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,6 +26,8 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, validator *schema.S
 			validator.Push(fmt.Errorf(errFmtOIDCNoPrivateKey))
 		}
 
+		validateOIDCIssuerPrivateKeys(config, validator)
+
 		if config.AccessTokenLifespan == time.Duration(0) {
 			config.AccessTokenLifespan = schema.DefaultOpenIDConnectConfiguration.AccessTokenLifespan
 		}
@@ -38,7 +45,7 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, validator *schema.S
 		}
 
 		if config.MinimumParameterEntropy != 0 && config.MinimumParameterEntropy < 8 {
-			validator.PushWarning(fmt.Errorf(errFmtOIDCServerInsecureParameterEntropy, config.MinimumParameterEntropy))
+			validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtOIDCServerInsecureParameterEntropy, config.MinimumParameterEntropy)))
 		}
 
 		if config.EnforcePKCE == "" {
@@ -49,6 +56,44 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, validator *schema.S
 			validator.Push(fmt.Errorf(errFmtOIDCEnforcePKCEInvalidValue, config.EnforcePKCE))
 		}
 
+		if config.MaximumRequestedScopes == 0 {
+			config.MaximumRequestedScopes = schema.DefaultOpenIDConnectConfiguration.MaximumRequestedScopes
+		} else if config.MaximumRequestedScopes < 0 {
+			validator.Push(fmt.Errorf(errFmtOIDCMaximumRequested, "scopes", config.MaximumRequestedScopes))
+		}
+
+		if config.MaximumRequestedAudiences == 0 {
+			config.MaximumRequestedAudiences = schema.DefaultOpenIDConnectConfiguration.MaximumRequestedAudiences
+		} else if config.MaximumRequestedAudiences < 0 {
+			validator.Push(fmt.Errorf(errFmtOIDCMaximumRequested, "audiences", config.MaximumRequestedAudiences))
+		}
+
+		if config.MaximumDebugBodyLength == 0 {
+			config.MaximumDebugBodyLength = schema.DefaultOpenIDConnectConfiguration.MaximumDebugBodyLength
+		} else if config.MaximumDebugBodyLength < 0 {
+			validator.Push(fmt.Errorf(errFmtOIDCMaximumDebugBodyLength, config.MaximumDebugBodyLength))
+		}
+
+		validateOIDCTokenEndpoint(config, validator)
+		validateOIDCRateLimit("consent_rate_limit", &config.ConsentRateLimit, validator)
+
+		switch {
+		case config.PreferredUsernameClaim == "":
+			config.PreferredUsernameClaim = schema.DefaultOpenIDConnectConfiguration.PreferredUsernameClaim
+		case !utils.IsStringInSlice(config.PreferredUsernameClaim, validOIDCPreferredUsernameClaims):
+			validator.Push(fmt.Errorf(errFmtOIDCPreferredUsernameClaimInvalidValue, strings.Join(validOIDCPreferredUsernameClaims, "', '"), config.PreferredUsernameClaim))
+		}
+
+		for _, responseType := range config.AllowedResponseTypes {
+			if !isOIDCResponseTypeInSlice(responseType, validOIDCResponseTypes) {
+				validator.Push(fmt.Errorf(errFmtOIDCAllowedResponseTypesInvalidValue, strings.Join(validOIDCResponseTypes, "', '"), responseType))
+			}
+		}
+
+		if config.ConsentAuditLogPath != "" {
+			validateOIDCConsentAuditLogPath(config.ConsentAuditLogPath, validator)
+		}
+
 		validateOIDCClients(config, validator)
 
 		if len(config.Clients) == 0 {
@@ -57,6 +102,79 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, validator *schema.S
 	}
 }
 
+func validateOIDCIssuerPrivateKeys(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	var keyIDs []string
+
+	for i, key := range config.IssuerPrivateKeys {
+		switch {
+		case key.KeyID == "":
+			validator.Push(fmt.Errorf(errFmtOIDCIssuerPrivateKeysNoKeyID, i+1))
+		case !reOIDCKeyID.MatchString(key.KeyID):
+			validator.Push(fmt.Errorf(errFmtOIDCIssuerPrivateKeysInvalidKeyID, i+1, key.KeyID))
+		case utils.IsStringInSlice(key.KeyID, keyIDs):
+			validator.Push(fmt.Errorf(errFmtOIDCIssuerPrivateKeysDuplicateKeyID, i+1, key.KeyID))
+		default:
+			keyIDs = append(keyIDs, key.KeyID)
+		}
+
+		if key.Key == "" {
+			validator.Push(fmt.Errorf(errFmtOIDCIssuerPrivateKeysNoKey, i+1))
+		}
+	}
+}
+
+func validateOIDCTokenEndpoint(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	if config.TokenEndpointMaximumBodySize < 0 {
+		validator.Push(fmt.Errorf(errFmtOIDCTokenEndpointMaximumBodySize, config.TokenEndpointMaximumBodySize))
+	}
+
+	validateOIDCRateLimit("token_endpoint_rate_limit", &config.TokenEndpointRateLimit, validator)
+
+	for _, network := range config.TokenEndpointTrustedProxies {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtOIDCTokenEndpointTrustedProxyInvalid, network))
+		}
+	}
+}
+
+func validateOIDCRateLimit(name string, limit *schema.OpenIDConnectClientRateLimitConfiguration, validator *schema.StructValidator) {
+	switch {
+	case limit.RequestsPerSecond < 0:
+		validator.Push(fmt.Errorf(errFmtOIDCInvalidRateLimitValue, name, "requests_per_second", limit.RequestsPerSecond))
+	case limit.Burst < 0:
+		validator.Push(fmt.Errorf(errFmtOIDCInvalidRateLimitValue, name, "burst", limit.Burst))
+	case limit.RequestsPerSecond == 0 && limit.Burst != 0:
+		validator.Push(fmt.Errorf(errFmtOIDCInvalidRateLimitBurst, name))
+	case limit.RequestsPerSecond > 0 && limit.Burst == 0:
+		limit.Burst = int(math.Ceil(limit.RequestsPerSecond))
+	}
+}
+
+// validateOIDCConsentAuditLogPath checks the consent audit log path refers to a file or a file which doesn't exist yet in
+// an existing directory. The file isn't created by the validation as it's created when the provider is started.
+func validateOIDCConsentAuditLogPath(path string, validator *schema.StructValidator) {
+	info, err := os.Stat(path)
+
+	switch {
+	case err == nil:
+		if info.IsDir() {
+			validator.Push(fmt.Errorf(errFmtOIDCConsentAuditLogPathIsDirectory, path))
+		}
+
+		return
+	case !os.IsNotExist(err):
+		validator.Push(fmt.Errorf(errFmtOIDCConsentAuditLogPathUnknown, path, err))
+
+		return
+	}
+
+	directory := filepath.Dir(path)
+
+	if info, err = os.Stat(directory); err != nil || !info.IsDir() {
+		validator.Push(fmt.Errorf(errFmtOIDCConsentAuditLogPathNoDirectory, path, directory))
+	}
+}
+
 func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
 	invalidID, duplicateIDs := false, false
 
@@ -80,12 +198,22 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *s
 			if client.Secret != "" {
 				validator.Push(fmt.Errorf(errFmtOIDCClientPublicInvalidSecret, client.ID))
 			}
+
+			if client.SecretNext != "" {
+				validator.Push(fmt.Errorf(errFmtOIDCClientPublicInvalidSecretNext, client.ID))
+			}
 		} else {
 			if client.Secret == "" {
 				validator.Push(fmt.Errorf(errFmtOIDCClientInvalidSecret, client.ID))
+
+				if client.SecretNext != "" {
+					validator.Push(fmt.Errorf(errFmtOIDCClientSecretNextWithoutSecret, client.ID))
+				}
 			}
 		}
 
+		validateOIDCClientSecretExpiresAt(client, time.Now(), validator)
+
 		if client.Policy == "" {
 			config.Clients[c].Policy = schema.DefaultOpenIDConnectClientConfiguration.Policy
 		} else if client.Policy != policyOneFactor && client.Policy != policyTwoFactor {
@@ -97,8 +225,18 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *s
 		validateOIDCClientResponseTypes(c, config, validator)
 		validateOIDCClientResponseModes(c, config, validator)
 		validateOIDDClientUserinfoAlgorithm(c, config, validator)
+		validateOIDCClientSigningKeyID(client, config.IssuerPrivateKeys, validator)
+		validateOIDCClientTokenEndpointRateLimit(c, config, validator)
+		validateOIDCClientLifespans(client, validator)
+		validateOIDCClientRefreshTokenAbsoluteLifetime(client, config.RefreshTokenLifespan, validator)
+		validateOIDCClientClaims(client, validator)
+		validateOIDCClientAllowedNetworks(client, validator)
+
+		if config.DisableImplicitFlow {
+			validateOIDCClientImplicitFlowDisabled(config.Clients[c], validator)
+		}
 
-		validateOIDCClientRedirectURIs(client, validator)
+		validateOIDCClientRedirectURIs(client, config.EnforceHTTPSRedirectURIs, validator)
 	}
 
 	if invalidID {
@@ -110,6 +248,100 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *s
 	}
 }
 
+func validateOIDCClientSecretExpiresAt(client schema.OpenIDConnectClientConfiguration, now time.Time, validator *schema.StructValidator) {
+	if client.SecretExpiresAt == "" {
+		return
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, client.SecretExpiresAt)
+	if err != nil {
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidSecretExpiresAt, client.ID, client.SecretExpiresAt))
+
+		return
+	}
+
+	if !client.Public && now.After(expiresAt) {
+		validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtOIDCClientSecretExpired, client.ID, client.SecretExpiresAt)))
+	}
+}
+
+func validateOIDCClientImplicitFlowDisabled(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
+	if utils.IsStringInSlice(oidcGrantTypeImplicit, client.GrantTypes) {
+		validator.Push(fmt.Errorf(errFmtOIDCClientImplicitFlowDisabled, client.ID, "grant_types", oidcGrantTypeImplicit))
+	}
+
+	for _, responseType := range client.ResponseTypes {
+		if utils.IsStringSliceContainsAny([]string{oidcResponseTypeToken, oidcResponseTypeIDToken}, strings.Fields(responseType)) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientImplicitFlowDisabled, client.ID, "response_types", responseType))
+		}
+	}
+}
+
+func validateOIDCClientClaims(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
+	claims := make([]string, 0, len(client.Claims))
+
+	for claim := range client.Claims {
+		claims = append(claims, claim)
+	}
+
+	sort.Strings(claims)
+
+	for _, claim := range claims {
+		if utils.IsStringInSlice(claim, reservedOIDCClaims) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidClaim, client.ID, claim))
+		}
+	}
+}
+
+func validateOIDCClientAllowedNetworks(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
+	for _, network := range client.AllowedNetworks {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidAllowedNetwork, client.ID, network))
+		}
+	}
+}
+
+func validateOIDCClientLifespans(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
+	for _, lifespan := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"access_token_lifespan", client.AccessTokenLifespan},
+		{"authorize_code_lifespan", client.AuthorizeCodeLifespan},
+		{"id_token_lifespan", client.IDTokenLifespan},
+		{"refresh_token_lifespan", client.RefreshTokenLifespan},
+	} {
+		if lifespan.value < 0 {
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidLifespan, client.ID, lifespan.name, lifespan.value))
+		}
+	}
+}
+
+func validateOIDCClientRefreshTokenAbsoluteLifetime(client schema.OpenIDConnectClientConfiguration, lifespan time.Duration, validator *schema.StructValidator) {
+	if client.RefreshTokenLifespan > 0 {
+		lifespan = client.RefreshTokenLifespan
+	}
+
+	if client.RefreshTokenAbsoluteLifetime != 0 && client.RefreshTokenAbsoluteLifetime < lifespan {
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidRefreshTokenAbsoluteLifetime, client.ID, lifespan, client.RefreshTokenAbsoluteLifetime))
+	}
+}
+
+func validateOIDCClientTokenEndpointRateLimit(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	limit := &configuration.Clients[c].TokenEndpointRateLimit
+
+	switch {
+	case limit.RequestsPerSecond < 0:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidRateLimitValue, configuration.Clients[c].ID, "requests_per_second", limit.RequestsPerSecond))
+	case limit.Burst < 0:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidRateLimitValue, configuration.Clients[c].ID, "burst", limit.Burst))
+	case limit.RequestsPerSecond == 0 && limit.Burst != 0:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidRateLimitBurst, configuration.Clients[c].ID))
+	case limit.RequestsPerSecond > 0 && limit.Burst == 0:
+		limit.Burst = int(math.Ceil(limit.RequestsPerSecond))
+	}
+}
+
 func validateOIDCClientScopes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
 	if len(configuration.Clients[c].Scopes) == 0 {
 		configuration.Clients[c].Scopes = schema.DefaultOpenIDConnectClientConfiguration.Scopes
@@ -144,11 +376,38 @@ func validateOIDCClientGrantTypes(c int, configuration *schema.OpenIDConnectConf
 	}
 }
 
-func validateOIDCClientResponseTypes(c int, configuration *schema.OpenIDConnectConfiguration, _ *schema.StructValidator) {
+func validateOIDCClientResponseTypes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
 	if len(configuration.Clients[c].ResponseTypes) == 0 {
 		configuration.Clients[c].ResponseTypes = schema.DefaultOpenIDConnectClientConfiguration.ResponseTypes
+	}
+
+	if len(configuration.AllowedResponseTypes) == 0 {
 		return
 	}
+
+	for _, responseType := range configuration.Clients[c].ResponseTypes {
+		if !isOIDCResponseTypeInSlice(responseType, configuration.AllowedResponseTypes) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientResponseTypeNotAllowed, configuration.Clients[c].ID, responseType))
+		}
+	}
+}
+
+// isOIDCResponseTypeInSlice returns true if the response type is in the slice regardless of the order of the individual
+// space separated values of each response type.
+func isOIDCResponseTypeInSlice(responseType string, responseTypes []string) bool {
+	needle := strings.Fields(responseType)
+	sort.Strings(needle)
+
+	for _, value := range responseTypes {
+		fields := strings.Fields(value)
+		sort.Strings(fields)
+
+		if strings.Join(needle, " ") == strings.Join(fields, " ") {
+			return true
+		}
+	}
+
+	return false
 }
 
 func validateOIDCClientResponseModes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
@@ -175,7 +434,21 @@ func validateOIDDClientUserinfoAlgorithm(c int, configuration *schema.OpenIDConn
 	}
 }
 
-func validateOIDCClientRedirectURIs(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
+func validateOIDCClientSigningKeyID(client schema.OpenIDConnectClientConfiguration, keys []schema.OpenIDConnectIssuerPrivateKeyConfiguration, validator *schema.StructValidator) {
+	if client.SigningKeyID == "" {
+		return
+	}
+
+	for _, key := range keys {
+		if key.KeyID == client.SigningKeyID {
+			return
+		}
+	}
+
+	validator.Push(fmt.Errorf(errFmtOIDCClientInvalidSigningKeyID, client.ID, client.SigningKeyID))
+}
+
+func validateOIDCClientRedirectURIs(client schema.OpenIDConnectClientConfiguration, enforceHTTPS bool, validator *schema.StructValidator) {
 	for _, redirectURI := range client.RedirectURIs {
 		if redirectURI == oauth2InstalledApp {
 			if client.Public {
@@ -200,6 +473,35 @@ func validateOIDCClientRedirectURIs(client schema.OpenIDConnectClientConfigurati
 
 		if !client.Public && parsedURL.Scheme != schemeHTTPS && parsedURL.Scheme != schemeHTTP {
 			validator.Push(fmt.Errorf(errFmtOIDCClientRedirectURI, client.ID, redirectURI, parsedURL.Scheme))
+
+			continue
+		}
+
+		if enforceHTTPS && !client.Public && parsedURL.Scheme == schemeHTTP && !isLoopbackHost(parsedURL.Hostname()) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientRedirectURIInsecure, client.ID, redirectURI))
+		}
+
+		// Native apps listen on a dynamic port which is only matched for the loopback IP literals, other loopback hosts
+		// such as localhost only ever match the exact redirect uri.
+		if client.Public && parsedURL.Scheme == schemeHTTP && isLoopbackHost(parsedURL.Hostname()) && !isLoopbackIPLiteral(parsedURL.Hostname()) {
+			validator.PushWarning(fmt.Errorf(errFmtOIDCClientRedirectURILoopbackPort, client.ID, redirectURI, parsedURL.Hostname()))
 		}
 	}
 }
+
+// isLoopbackIPLiteral returns true if the host is one of the loopback IP literals for which the port of the redirect uri
+// is matched dynamically as per RFC8252 section 7.3.
+func isLoopbackIPLiteral(host string) bool {
+	return host == "127.0.0.1" || host == "::1"
+}
+
+// isLoopbackHost returns true if the host is localhost or a loopback IP address.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
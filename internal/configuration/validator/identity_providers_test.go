@@ -3,6 +3,8 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -47,6 +49,62 @@ func TestShouldRaiseErrorWhenOIDCPKCEEnforceValueInvalid(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[1], errFmtOIDCNoClientsConfigured)
 }
 
+func TestShouldRaiseErrorWhenOIDCPreferredUsernameClaimInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:             "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:       "key-material",
+			PreferredUsernameClaim: "uid",
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'preferred_username_claim' must be one of 'username', 'display_name', 'email' but it is configured as 'uid'")
+	assert.EqualError(t, validator.Errors()[1], errFmtOIDCNoClientsConfigured)
+}
+
+func TestShouldRaiseErrorWhenOIDCMaximumRequestedNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:                "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:          "key-material",
+			MaximumRequestedScopes:    -1,
+			MaximumRequestedAudiences: -2,
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'maximum_requested_scopes' must be above 0 but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: option 'maximum_requested_audiences' must be above 0 but it is configured as '-2'")
+	assert.EqualError(t, validator.Errors()[2], errFmtOIDCNoClientsConfigured)
+}
+
+func TestShouldRaiseErrorWhenOIDCMaximumDebugBodyLengthNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:             "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:       "key-material",
+			MaximumDebugBodyLength: -1,
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'maximum_debug_body_length' must be above 0 but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[1], errFmtOIDCNoClientsConfigured)
+}
+
 func TestShouldRaiseErrorWhenOIDCServerIssuerPrivateKeyPathInvalid(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
@@ -272,6 +330,477 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadUserinfoAlg(t *testing.T
 	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'userinfo_signing_algorithm' must be one of 'none, RS256' but it is configured as 'rs256'")
 }
 
+func TestShouldRaiseErrorWhenOIDCIssuerPrivateKeysInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKeyConfiguration{
+				{KeyID: "legacy", Key: "key-material"},
+				{KeyID: "", Key: "key-material"},
+				{KeyID: "bad key", Key: "key-material"},
+				{KeyID: "legacy", Key: ""},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "good_id",
+					Secret:       "good_secret",
+					Policy:       "two_factor",
+					SigningKeyID: "legacy",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: issuer_private_keys: key #2: option 'key_id' is required")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: issuer_private_keys: key #3: option 'key_id' must only contain alphanumeric characters, hyphens, and underscores but it is configured as 'bad key'")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: issuer_private_keys: key #4: option 'key_id' must be unique but 'legacy' is configured more than once")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: issuer_private_keys: key #4: option 'key' is required")
+}
+
+func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadSigningKeyID(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKeyConfiguration{
+				{KeyID: "legacy", Key: "key-material"},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "good_id",
+					Secret:       "good_secret",
+					Policy:       "two_factor",
+					SigningKeyID: "missing",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'signing_key_id' must be the 'key_id' of one of the 'issuer_private_keys' but it is configured as 'missing'")
+}
+
+func TestShouldValidateOIDCTokenEndpoint(t *testing.T) {
+	testCases := []struct {
+		name     string
+		size     int
+		have     schema.OpenIDConnectClientRateLimitConfiguration
+		expected schema.OpenIDConnectClientRateLimitConfiguration
+		err      string
+	}{
+		{"ShouldAllowDisabled", 0, schema.OpenIDConnectClientRateLimitConfiguration{}, schema.OpenIDConnectClientRateLimitConfiguration{}, ""},
+		{"ShouldSetDefaultBurst", 4096, schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 0.5}, schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 0.5, Burst: 1}, ""},
+		{"ShouldRaiseErrorOnNegativeSize", -1, schema.OpenIDConnectClientRateLimitConfiguration{}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: option 'token_endpoint_maximum_body_size' must not be negative but it is configured as '-1'"},
+		{"ShouldRaiseErrorOnNegativeRate", 0, schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: -1}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: token_endpoint_rate_limit: option 'requests_per_second' must not be negative but it is configured as '-1'"},
+		{"ShouldRaiseErrorOnNegativeBurst", 0, schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 1, Burst: -1}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: token_endpoint_rate_limit: option 'burst' must not be negative but it is configured as '-1'"},
+		{"ShouldRaiseErrorOnBurstWithoutRate", 0, schema.OpenIDConnectClientRateLimitConfiguration{Burst: 5}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: token_endpoint_rate_limit: option 'burst' must only be configured when option 'requests_per_second' is configured"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:                   "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey:             "key-material",
+					TokenEndpointMaximumBodySize: tc.size,
+					TokenEndpointRateLimit:       tc.have,
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:     "good_id",
+							Secret: "good_secret",
+							Policy: "two_factor",
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			if tc.err == "" {
+				assert.Len(t, validator.Errors(), 0)
+				assert.Equal(t, tc.expected, config.OIDC.TokenEndpointRateLimit)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+		})
+	}
+}
+
+func TestShouldRaiseErrorOnInvalidOIDCTokenEndpointTrustedProxies(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:                  "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:            "key-material",
+			TokenEndpointTrustedProxies: []string{"10.0.0.0/8", "192.168.1.1", "example.com"},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'token_endpoint_trusted_proxies' must only contain IP addresses or networks in CIDR notation but it contains 'example.com'")
+}
+
+func TestShouldValidateOIDCConsentRateLimit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.OpenIDConnectClientRateLimitConfiguration
+		expected schema.OpenIDConnectClientRateLimitConfiguration
+		err      string
+	}{
+		{"ShouldAllowDisabled", schema.OpenIDConnectClientRateLimitConfiguration{}, schema.OpenIDConnectClientRateLimitConfiguration{}, ""},
+		{"ShouldSetDefaultBurst", schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 1.5}, schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 1.5, Burst: 2}, ""},
+		{"ShouldRaiseErrorOnNegativeRate", schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: -1}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: consent_rate_limit: option 'requests_per_second' must not be negative but it is configured as '-1'"},
+		{"ShouldRaiseErrorOnBurstWithoutRate", schema.OpenIDConnectClientRateLimitConfiguration{Burst: 5}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: consent_rate_limit: option 'burst' must only be configured when option 'requests_per_second' is configured"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey: "key-material",
+					ConsentRateLimit: tc.have,
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:     "good_id",
+							Secret: "good_secret",
+							Policy: "two_factor",
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			if tc.err == "" {
+				assert.Len(t, validator.Errors(), 0)
+				assert.Equal(t, tc.expected, config.OIDC.ConsentRateLimit)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+		})
+	}
+}
+
+func TestShouldValidateOIDCClientTokenEndpointRateLimit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.OpenIDConnectClientRateLimitConfiguration
+		expected schema.OpenIDConnectClientRateLimitConfiguration
+		err      string
+	}{
+		{"ShouldAllowDisabled", schema.OpenIDConnectClientRateLimitConfiguration{}, schema.OpenIDConnectClientRateLimitConfiguration{}, ""},
+		{"ShouldSetDefaultBurst", schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 2.5}, schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 2.5, Burst: 3}, ""},
+		{"ShouldKeepBurst", schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 1, Burst: 10}, schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 1, Burst: 10}, ""},
+		{"ShouldRaiseErrorOnNegativeRate", schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: -1}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: client 'good_id': token_endpoint_rate_limit: option 'requests_per_second' must not be negative but it is configured as '-1'"},
+		{"ShouldRaiseErrorOnNegativeBurst", schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 1, Burst: -1}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: client 'good_id': token_endpoint_rate_limit: option 'burst' must not be negative but it is configured as '-1'"},
+		{"ShouldRaiseErrorOnBurstWithoutRate", schema.OpenIDConnectClientRateLimitConfiguration{Burst: 5}, schema.OpenIDConnectClientRateLimitConfiguration{}, "identity_providers: oidc: client 'good_id': token_endpoint_rate_limit: option 'burst' must only be configured when option 'requests_per_second' is configured"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey: "key-material",
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:                     "good_id",
+							Secret:                 "good_secret",
+							Policy:                 "two_factor",
+							TokenEndpointRateLimit: tc.have,
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			if tc.err == "" {
+				assert.Len(t, validator.Errors(), 0)
+				assert.Equal(t, tc.expected, config.OIDC.Clients[0].TokenEndpointRateLimit)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+		})
+	}
+}
+
+func TestShouldValidateOIDCClientRefreshTokenAbsoluteLifetime(t *testing.T) {
+	testCases := []struct {
+		name string
+		have time.Duration
+		err  string
+	}{
+		{"ShouldAllowDisabled", 0, ""},
+		{"ShouldAllowEqualToLifespan", time.Minute * 90, ""},
+		{"ShouldAllowGreaterThanLifespan", time.Hour * 24, ""},
+		{"ShouldRaiseErrorOnLessThanLifespan", time.Hour, "identity_providers: oidc: client 'good_id': option 'refresh_token_absolute_lifetime' must be greater than or equal to the option 'refresh_token_lifespan' value of '1h30m0s' but it is configured as '1h0m0s'"},
+		{"ShouldRaiseErrorOnNegative", -time.Hour, "identity_providers: oidc: client 'good_id': option 'refresh_token_absolute_lifetime' must be greater than or equal to the option 'refresh_token_lifespan' value of '1h30m0s' but it is configured as '-1h0m0s'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey: "key-material",
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:                           "good_id",
+							Secret:                       "good_secret",
+							Policy:                       "two_factor",
+							RefreshTokenAbsoluteLifetime: tc.have,
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			if tc.err == "" {
+				assert.Len(t, validator.Errors(), 0)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+		})
+	}
+}
+
+func TestShouldValidateOIDCClientLifespans(t *testing.T) {
+	testCases := []struct {
+		name string
+		have schema.OpenIDConnectClientConfiguration
+		errs []string
+	}{
+		{
+			"ShouldAllowUnset",
+			schema.OpenIDConnectClientConfiguration{},
+			nil,
+		},
+		{
+			"ShouldAllowPositive",
+			schema.OpenIDConnectClientConfiguration{
+				AccessTokenLifespan:   time.Minute * 5,
+				AuthorizeCodeLifespan: time.Second * 30,
+				IDTokenLifespan:       time.Minute * 5,
+				RefreshTokenLifespan:  time.Hour * 24 * 30,
+			},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnNegative",
+			schema.OpenIDConnectClientConfiguration{
+				AccessTokenLifespan:   -time.Minute,
+				AuthorizeCodeLifespan: -time.Second,
+				IDTokenLifespan:       -time.Minute,
+				RefreshTokenLifespan:  -time.Hour,
+			},
+			[]string{
+				"identity_providers: oidc: client 'good_id': option 'access_token_lifespan' must be a positive duration but it is configured as '-1m0s'",
+				"identity_providers: oidc: client 'good_id': option 'authorize_code_lifespan' must be a positive duration but it is configured as '-1s'",
+				"identity_providers: oidc: client 'good_id': option 'id_token_lifespan' must be a positive duration but it is configured as '-1m0s'",
+				"identity_providers: oidc: client 'good_id': option 'refresh_token_lifespan' must be a positive duration but it is configured as '-1h0m0s'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := tc.have
+			client.ID = "good_id"
+			client.Secret = "good_secret"
+			client.Policy = "two_factor"
+			client.RedirectURIs = []string{"https://google.com/callback"}
+
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey: "key-material",
+					Clients:          []schema.OpenIDConnectClientConfiguration{client},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+		})
+	}
+}
+
+func TestShouldRaiseErrorOnReservedOIDCClientClaims(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "two_factor",
+					Claims: map[string]string{
+						"tenant": "acme",
+						"sub":    "admin",
+						"groups": "admins",
+					},
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'claims' must not contain the reserved claim 'groups'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'good_id': option 'claims' must not contain the reserved claim 'sub'")
+}
+
+func TestShouldRaiseErrorOnInvalidOIDCClientAllowedNetworks(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "two_factor",
+					AllowedNetworks: []string{
+						"10.0.0.0/8",
+						"192.168.1.1",
+						"192.168.0.0/33",
+						"internal",
+					},
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'allowed_networks' has an invalid value: the network '192.168.0.0/33' must be a valid IP address or CIDR notation")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'good_id': option 'allowed_networks' has an invalid value: the network 'internal' must be a valid IP address or CIDR notation")
+}
+
+func TestShouldValidateOIDCClientSecretRotation(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:              "good_id",
+					Secret:          "good_secret",
+					SecretNext:      "next_secret",
+					SecretExpiresAt: "2999-01-01T00:00:00Z",
+					Policy:          "two_factor",
+					RedirectURIs:    []string{"https://google.com/callback"},
+				},
+				{
+					ID:              "expired_id",
+					Secret:          "expired_secret",
+					SecretExpiresAt: "2020-01-01T00:00:00Z",
+					Policy:          "two_factor",
+					RedirectURIs:    []string{"https://google.com/callback"},
+				},
+				{
+					ID:              "bad_expires_id",
+					Secret:          "good_secret",
+					SecretExpiresAt: "2020-01-01",
+					Policy:          "two_factor",
+					RedirectURIs:    []string{"https://google.com/callback"},
+				},
+				{
+					ID:           "no_secret_id",
+					SecretNext:   "next_secret",
+					Policy:       "two_factor",
+					RedirectURIs: []string{"https://google.com/callback"},
+				},
+				{
+					ID:           "public_id",
+					Public:       true,
+					SecretNext:   "next_secret",
+					Policy:       "two_factor",
+					RedirectURIs: []string{"https://google.com/callback"},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Warnings(), 1)
+	assert.EqualError(t, validator.Warnings()[0], "identity_providers: oidc: client 'expired_id': option 'secret' expired at '2020-01-01T00:00:00Z' and should be rotated using the option 'secret_next'")
+	assert.True(t, errors.As(validator.Warnings()[0], &strictWarning{}))
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'bad_expires_id': option 'secret_expires_at' must be a RFC3339 timestamp such as '2006-01-02T15:04:05Z' but it is configured as '2020-01-01'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'no_secret_id': option 'secret' is required")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: client 'no_secret_id': option 'secret_next' must only be configured when option 'secret' is configured")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: client 'public_id': option 'secret_next' is required to be empty when option 'public' is true")
+}
+
 func TestValidateIdentityProvidersShouldRaiseWarningOnSecurityIssue(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
@@ -338,6 +867,237 @@ func TestValidateIdentityProvidersShouldRaiseErrorsOnInvalidClientTypes(t *testi
 	assert.EqualError(t, validator.Errors()[1], fmt.Sprintf(errFmtOIDCClientRedirectURIPublic, "client-with-bad-redirect-uri", oauth2InstalledApp))
 }
 
+func TestValidateIdentityProvidersShouldEnforceHTTPSRedirectURIs(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:               "hmac1",
+			IssuerPrivateKey:         "key2",
+			EnforceHTTPSRedirectURIs: true,
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "confidential",
+					Secret: "a-secret",
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://app.example.com/callback",
+						"http://localhost:8080/callback",
+						"http://127.0.0.1/callback",
+						"http://[::1]:8080/callback",
+						"http://app.example.com/callback",
+					},
+				},
+				{
+					ID:     "public",
+					Public: true,
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"http://app.example.com/callback",
+						oauth2InstalledApp,
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'confidential': option 'redirect_uris' has an invalid value: redirect uri 'http://app.example.com/callback' must have the scheme 'https' unless it's a loopback address when option 'enforce_https_redirect_uris' is enabled but it has the scheme 'http'")
+
+	validator.Clear()
+
+	config.OIDC.EnforceHTTPSRedirectURIs = false
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestValidateIdentityProvidersShouldWarnLoopbackRedirectURIsWithoutDynamicPort(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "hmac1",
+			IssuerPrivateKey: "key2",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "native",
+					Public: true,
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"http://127.0.0.1/callback",
+						"http://[::1]:8080/callback",
+						"http://localhost/callback",
+						"http://127.0.0.2/callback",
+						"https://localhost/callback",
+						"http://app.example.com/callback",
+					},
+				},
+				{
+					ID:     "confidential",
+					Secret: "a-secret",
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"http://localhost:8080/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 2)
+
+	assert.EqualError(t, validator.Warnings()[0], "identity_providers: oidc: client 'native': option 'redirect_uris' has the redirect uri 'http://localhost/callback' which uses the loopback host 'localhost' but the port of a redirect uri is only matched dynamically for the loopback addresses '127.0.0.1' and '[::1]', native apps should use one of them instead")
+	assert.EqualError(t, validator.Warnings()[1], "identity_providers: oidc: client 'native': option 'redirect_uris' has the redirect uri 'http://127.0.0.2/callback' which uses the loopback host '127.0.0.2' but the port of a redirect uri is only matched dynamically for the loopback addresses '127.0.0.1' and '[::1]', native apps should use one of them instead")
+}
+
+func TestValidateIdentityProvidersShouldDisableImplicitFlow(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:          "hmac1",
+			IssuerPrivateKey:    "key2",
+			DisableImplicitFlow: true,
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "default",
+					Secret: "a-secret",
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://app.example.com/callback",
+					},
+				},
+				{
+					ID:            "implicit",
+					Secret:        "a-secret",
+					Policy:        "two_factor",
+					GrantTypes:    []string{"implicit", "authorization_code"},
+					ResponseTypes: []string{"code", "code id_token", "id_token token"},
+					RedirectURIs: []string{
+						"https://app.example.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'implicit': option 'grant_types' must not contain 'implicit' when option 'disable_implicit_flow' is enabled as it's used by the implicit or hybrid flows")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'implicit': option 'response_types' must not contain 'code id_token' when option 'disable_implicit_flow' is enabled as it's used by the implicit or hybrid flows")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: client 'implicit': option 'response_types' must not contain 'id_token token' when option 'disable_implicit_flow' is enabled as it's used by the implicit or hybrid flows")
+
+	validator.Clear()
+
+	config.OIDC.DisableImplicitFlow = false
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestValidateIdentityProvidersShouldRestrictAllowedResponseTypes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:           "hmac1",
+			IssuerPrivateKey:     "key2",
+			AllowedResponseTypes: []string{"code", "id_token code", "code token token"},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "default",
+					Secret: "a-secret",
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://app.example.com/callback",
+					},
+				},
+				{
+					ID:            "hybrid",
+					Secret:        "a-secret",
+					Policy:        "two_factor",
+					ResponseTypes: []string{"code", "code id_token", "id_token token"},
+					RedirectURIs: []string{
+						"https://app.example.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'allowed_response_types' must only have the values 'code', 'token', 'id_token', 'code token', 'code id_token', 'token id_token', 'code token id_token', 'none' but one option is configured as 'code token token'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'hybrid': option 'response_types' must only have the values allowed by the option 'allowed_response_types' but one option is configured as 'id_token token'")
+}
+
+func TestValidateIdentityProvidersShouldValidateConsentAuditLogPath(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "existing.log"), nil, 0600))
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"ShouldAllowExistingFile", filepath.Join(dir, "existing.log"), ""},
+		{"ShouldAllowMissingFileInExistingDirectory", filepath.Join(dir, "consent.log"), ""},
+		{"ShouldRaiseErrorOnDirectory", dir, fmt.Sprintf("identity_providers: oidc: option 'consent_audit_log_path' refers to '%s' which is a directory but it must be a file", dir)},
+		{"ShouldRaiseErrorOnMissingDirectory", filepath.Join(dir, "missing", "consent.log"), fmt.Sprintf("identity_providers: oidc: option 'consent_audit_log_path' refers to the file '%s' but its directory '%s' does not exist", filepath.Join(dir, "missing", "consent.log"), filepath.Join(dir, "missing"))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:          "hmac1",
+					IssuerPrivateKey:    "key2",
+					ConsentAuditLogPath: tc.path,
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:     "default",
+							Secret: "a-secret",
+							Policy: "two_factor",
+							RedirectURIs: []string{
+								"https://app.example.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+
+			if tc.expected == "" {
+				assert.Len(t, validator.Errors(), 0)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.expected)
+			}
+		})
+	}
+
+	// The validation must not create the file.
+	_, err := os.Stat(filepath.Join(dir, "consent.log"))
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestValidateIdentityProvidersShouldNotRaiseErrorsOnValidPublicClients(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
@@ -483,6 +1243,10 @@ func TestValidateIdentityProvidersShouldSetDefaultValues(t *testing.T) {
 	assert.Equal(t, time.Minute, config.OIDC.AuthorizeCodeLifespan)
 	assert.Equal(t, time.Hour, config.OIDC.IDTokenLifespan)
 	assert.Equal(t, time.Minute*90, config.OIDC.RefreshTokenLifespan)
+	assert.Equal(t, "username", config.OIDC.PreferredUsernameClaim)
+	assert.Equal(t, 20, config.OIDC.MaximumRequestedScopes)
+	assert.Equal(t, 20, config.OIDC.MaximumRequestedAudiences)
+	assert.Equal(t, 1024, config.OIDC.MaximumDebugBodyLength)
 }
 
 // All valid schemes are supported as defined in https://datatracker.ietf.org/doc/html/rfc8252#section-7.1
@@ -501,7 +1265,7 @@ func TestValidateOIDCClientRedirectURIsSupportingPrivateUseURISchemes(t *testing
 	t.Run("public", func(t *testing.T) {
 		validator := schema.NewStructValidator()
 		conf.Public = true
-		validateOIDCClientRedirectURIs(conf, validator)
+		validateOIDCClientRedirectURIs(conf, false, validator)
 
 		assert.Len(t, validator.Warnings(), 0)
 		assert.Len(t, validator.Errors(), 0)
@@ -510,7 +1274,7 @@ func TestValidateOIDCClientRedirectURIsSupportingPrivateUseURISchemes(t *testing
 	t.Run("not public", func(t *testing.T) {
 		validator := schema.NewStructValidator()
 		conf.Public = false
-		validateOIDCClientRedirectURIs(conf, validator)
+		validateOIDCClientRedirectURIs(conf, false, validator)
 
 		assert.Len(t, validator.Warnings(), 0)
 		assert.Len(t, validator.Errors(), 2)
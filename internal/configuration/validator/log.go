@@ -18,6 +18,10 @@ func ValidateLog(config *schema.Configuration, validator *schema.StructValidator
 		config.Log.Format = schema.DefaultLoggingConfiguration.Format
 	}
 
+	if config.Log.RedactedFields == nil {
+		config.Log.RedactedFields = schema.DefaultLoggingConfiguration.RedactedFields
+	}
+
 	if !utils.IsStringInSlice(config.Log.Level, validLoLevels) {
 		validator.Push(fmt.Errorf(errFmtLoggingLevelInvalid, strings.Join(validLoLevels, "', '"), config.Log.Level))
 	}
@@ -24,6 +24,7 @@ func TestShouldSetDefaultLoggingValues(t *testing.T) {
 	assert.Equal(t, "info", config.Log.Level)
 	assert.Equal(t, "text", config.Log.Format)
 	assert.Equal(t, "", config.Log.FilePath)
+	assert.Equal(t, schema.DefaultLoggingConfiguration.RedactedFields, config.Log.RedactedFields)
 }
 
 func TestShouldRaiseErrorOnInvalidLoggingLevel(t *testing.T) {
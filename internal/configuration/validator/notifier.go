@@ -2,9 +2,13 @@ package validator
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/templates"
@@ -22,6 +26,8 @@ func ValidateNotifier(config *schema.NotifierConfiguration, validator *schema.St
 		return
 	}
 
+	validateNotifierPasswordReset(&config.PasswordReset, validator)
+
 	if config.FileSystem != nil {
 		if config.FileSystem.Filename == "" {
 			validator.Push(fmt.Errorf(errFmtNotifierFileSystemFileNameNotConfigured))
@@ -35,6 +41,42 @@ func ValidateNotifier(config *schema.NotifierConfiguration, validator *schema.St
 	validateNotifierTemplates(config, validator)
 }
 
+func validateNotifierPasswordReset(config *schema.NotifierPasswordResetConfiguration, validator *schema.StructValidator) {
+	if config.Retries < 0 || config.Retries > notifierPasswordResetMaximumRetries {
+		validator.Push(fmt.Errorf(errFmtNotifierPasswordResetRetries, notifierPasswordResetMaximumRetries, config.Retries))
+	}
+
+	switch config.OnFailure {
+	case "":
+		config.OnFailure = schema.DefaultNotifierPasswordResetConfiguration.OnFailure
+	case schema.NotifierOnFailureFail, schema.NotifierOnFailureQueue:
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtNotifierPasswordResetOnFailure, strings.Join([]string{schema.NotifierOnFailureFail, schema.NotifierOnFailureQueue}, "', '"), config.OnFailure))
+	}
+
+	switch {
+	case config.RetryBackoff == 0:
+		config.RetryBackoff = schema.DefaultNotifierPasswordResetConfiguration.RetryBackoff
+	case config.RetryBackoff < 0:
+		validator.Push(fmt.Errorf(errFmtNotifierPasswordResetDuration, "retry_backoff", config.RetryBackoff))
+	}
+
+	// The backoff doubles after each retry so the total delay is the backoff multiplied by 2^retries - 1.
+	if config.Retries > 0 && config.Retries <= notifierPasswordResetMaximumRetries && config.RetryBackoff > 0 {
+		if delay := config.RetryBackoff * time.Duration(1<<config.Retries-1); delay > notifierPasswordResetMaximumRetryDelay {
+			validator.Push(fmt.Errorf(errFmtNotifierPasswordResetRetryDelay, notifierPasswordResetMaximumRetryDelay, delay))
+		}
+	}
+
+	switch {
+	case config.QueueInterval == 0:
+		config.QueueInterval = schema.DefaultNotifierPasswordResetConfiguration.QueueInterval
+	case config.QueueInterval < 0:
+		validator.Push(fmt.Errorf(errFmtNotifierPasswordResetDuration, "queue_interval", config.QueueInterval))
+	}
+}
+
 func validateNotifierTemplates(config *schema.NotifierConfiguration, validator *schema.StructValidator) {
 	if config.TemplatePath == "" {
 		return
@@ -79,19 +121,67 @@ func validateNotifierTemplates(config *schema.NotifierConfiguration, validator *
 	} else {
 		validator.PushWarning(fmt.Errorf(errFmtNotifierTemplateLoad, templates.TemplateNameStep2+".txt", err))
 	}
+
+	if !config.Notifications.DeviceChange {
+		return
+	}
+
+	if t, err = template.ParseFiles(filepath.Join(config.TemplatePath, templates.TemplateNameDeviceChange+".txt")); err == nil {
+		templates.PlainTextEmailTemplateDeviceChange = t
+	} else {
+		validator.PushWarning(fmt.Errorf(errFmtNotifierTemplateLoad, templates.TemplateNameDeviceChange+".txt", err))
+	}
 }
 
 func validateSMTPNotifier(config *schema.SMTPNotifierConfiguration, validator *schema.StructValidator) {
+	validateSMTPNotifierOptions(config, "", validator)
+
+	if config.Fallback == nil {
+		return
+	}
+
+	validateSMTPNotifierFallback(config, validator)
+}
+
+// validateSMTPNotifierFallback validates the fallback SMTP server. The options which describe the email rather than the
+// server are inherited from the primary SMTP server when they're not configured.
+func validateSMTPNotifierFallback(config *schema.SMTPNotifierConfiguration, validator *schema.StructValidator) {
+	fallback := config.Fallback
+
+	if fallback.Sender.Address == "" {
+		fallback.Sender = config.Sender
+	}
+
+	if fallback.Subject == "" {
+		fallback.Subject = config.Subject
+	}
+
+	if fallback.Identifier == "" {
+		fallback.Identifier = config.Identifier
+	}
+
+	if fallback.StartupCheckAddress == "" {
+		fallback.StartupCheckAddress = config.StartupCheckAddress
+	}
+
+	if fallback.Timeout == 0 {
+		fallback.Timeout = config.Timeout
+	}
+
+	validateSMTPNotifierOptions(fallback, "fallback.", validator)
+}
+
+func validateSMTPNotifierOptions(config *schema.SMTPNotifierConfiguration, prefix string, validator *schema.StructValidator) {
 	if config.StartupCheckAddress == "" {
 		config.StartupCheckAddress = schema.DefaultSMTPNotifierConfiguration.StartupCheckAddress
 	}
 
 	if config.Host == "" {
-		validator.Push(fmt.Errorf(errFmtNotifierSMTPNotConfigured, "host"))
+		validator.Push(fmt.Errorf(errFmtNotifierSMTPNotConfigured, prefix+"host"))
 	}
 
 	if config.Port == 0 {
-		validator.Push(fmt.Errorf(errFmtNotifierSMTPNotConfigured, "port"))
+		validator.Push(fmt.Errorf(errFmtNotifierSMTPNotConfigured, prefix+"port"))
 	}
 
 	if config.Timeout == 0 {
@@ -99,7 +189,7 @@ func validateSMTPNotifier(config *schema.SMTPNotifierConfiguration, validator *s
 	}
 
 	if config.Sender.Address == "" {
-		validator.Push(fmt.Errorf(errFmtNotifierSMTPNotConfigured, "sender"))
+		validator.Push(fmt.Errorf(errFmtNotifierSMTPNotConfigured, prefix+"sender"))
 	}
 
 	if config.Subject == "" {
@@ -108,13 +198,75 @@ func validateSMTPNotifier(config *schema.SMTPNotifierConfiguration, validator *s
 
 	if config.Identifier == "" {
 		config.Identifier = schema.DefaultSMTPNotifierConfiguration.Identifier
+	} else if !isValidSMTPIdentifier(config.Identifier) {
+		validator.Push(fmt.Errorf(errFmtNotifierSMTPIdentifierInvalid, prefix+"identifier", config.Identifier))
 	}
 
 	if config.TLS == nil {
-		config.TLS = schema.DefaultSMTPNotifierConfiguration.TLS
+		tlsConfig := *schema.DefaultSMTPNotifierConfiguration.TLS
+		config.TLS = &tlsConfig
 	}
 
 	if config.TLS.ServerName == "" {
 		config.TLS.ServerName = config.Host
 	}
+
+	if config.Auth != nil && config.Auth.XOAUTH2 != nil {
+		validateSMTPNotifierXOAUTH2(config, prefix, validator)
+	}
+}
+
+// validateSMTPNotifierXOAUTH2 validates the XOAUTH2 authentication options. When the options required to obtain an
+// access token are missing and a password is configured the XOAUTH2 authentication is disabled with a warning so the
+// notifier falls back to authenticating with the username and password.
+func validateSMTPNotifierXOAUTH2(config *schema.SMTPNotifierConfiguration, prefix string, validator *schema.StructValidator) {
+	xoauth2 := config.Auth.XOAUTH2
+
+	if config.Username == "" {
+		validator.Push(fmt.Errorf(errFmtNotifierSMTPXOAUTH2NotConfigured, prefix+"username"))
+	}
+
+	var missing []string
+
+	if xoauth2.TokenEndpoint == "" {
+		missing = append(missing, prefix+"auth.xoauth2.token_endpoint")
+	} else if u, err := url.Parse(xoauth2.TokenEndpoint); err != nil || u.Scheme != schemeHTTPS || u.Host == "" {
+		validator.Push(fmt.Errorf(errFmtNotifierSMTPXOAUTH2TokenEndpointInvalid, prefix+"auth.xoauth2.token_endpoint", xoauth2.TokenEndpoint))
+	}
+
+	if xoauth2.ClientID == "" {
+		missing = append(missing, prefix+"auth.xoauth2.client_id")
+	}
+
+	if xoauth2.ClientSecret == "" {
+		missing = append(missing, prefix+"auth.xoauth2.client_secret")
+	}
+
+	if len(missing) == 0 {
+		return
+	}
+
+	if config.Password == "" {
+		for _, option := range missing {
+			validator.Push(fmt.Errorf(errFmtNotifierSMTPXOAUTH2NotConfigured, option))
+		}
+
+		return
+	}
+
+	for _, option := range missing {
+		validator.PushWarning(fmt.Errorf(errFmtNotifierSMTPXOAUTH2Fallback, option))
+	}
+
+	config.Auth.XOAUTH2 = nil
+}
+
+// isValidSMTPIdentifier returns true if the identifier is a hostname or an address literal as per RFC5321 section 4.1.3
+// which can be sent with the HELO/EHLO command.
+func isValidSMTPIdentifier(identifier string) bool {
+	if strings.HasPrefix(identifier, "[") && strings.HasSuffix(identifier, "]") {
+		return net.ParseIP(strings.TrimPrefix(identifier[1:len(identifier)-1], "IPv6:")) != nil
+	}
+
+	return len(identifier) <= 253 && reHostname.MatchString(identifier)
 }
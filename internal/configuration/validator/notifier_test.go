@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/mail"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -26,6 +27,7 @@ func (suite *NotifierSuite) SetupTest() {
 		Port:     25,
 	}
 	suite.config.FileSystem = nil
+	suite.config.PasswordReset = schema.NotifierPasswordResetConfiguration{}
 }
 
 /*
@@ -137,6 +139,134 @@ func (suite *NotifierSuite) TestSMTPShouldEnsureSenderIsProvided() {
 /*
 	File Tests.
 */
+func (suite *NotifierSuite) TestSMTPShouldInheritFallbackDefaultsFromPrimary() {
+	suite.config.SMTP.Subject = "[Example] {title}"
+	suite.config.SMTP.Fallback = &schema.SMTPNotifierConfiguration{
+		Host: "backup.example.com",
+		Port: 587,
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal("authelia@example.com", suite.config.SMTP.Fallback.Sender.Address)
+	suite.Assert().Equal("[Example] {title}", suite.config.SMTP.Fallback.Subject)
+	suite.Assert().Equal(schema.DefaultSMTPNotifierConfiguration.Identifier, suite.config.SMTP.Fallback.Identifier)
+	suite.Assert().Equal(schema.DefaultSMTPNotifierConfiguration.Timeout, suite.config.SMTP.Fallback.Timeout)
+
+	suite.Assert().Equal("example.com", suite.config.SMTP.TLS.ServerName)
+	suite.Assert().Equal("backup.example.com", suite.config.SMTP.Fallback.TLS.ServerName)
+	suite.Assert().Equal("TLS1.2", suite.config.SMTP.Fallback.TLS.MinimumVersion)
+}
+
+func (suite *NotifierSuite) TestSMTPShouldEnsureFallbackHostAndPortAreProvided() {
+	suite.config.SMTP.Fallback = &schema.SMTPNotifierConfiguration{}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+
+	errors := suite.validator.Errors()
+
+	suite.Require().Len(errors, 2)
+
+	suite.Assert().EqualError(errors[0], fmt.Sprintf(errFmtNotifierSMTPNotConfigured, "fallback.host"))
+	suite.Assert().EqualError(errors[1], fmt.Sprintf(errFmtNotifierSMTPNotConfigured, "fallback.port"))
+}
+
+func (suite *NotifierSuite) TestSMTPShouldAllowValidIdentifiers() {
+	for _, identifier := range []string{"localhost", "mail.example.com", "authelia-1", "[192.168.1.10]", "[IPv6:2001:db8::1]"} {
+		suite.Run(identifier, func() {
+			suite.SetupTest()
+
+			suite.config.SMTP.Identifier = identifier
+
+			ValidateNotifier(&suite.config, suite.validator)
+
+			suite.Assert().Len(suite.validator.Warnings(), 0)
+			suite.Assert().Len(suite.validator.Errors(), 0)
+
+			suite.Assert().Equal(identifier, suite.config.SMTP.Identifier)
+		})
+	}
+}
+
+func (suite *NotifierSuite) TestSMTPShouldRaiseErrorOnInvalidIdentifier() {
+	suite.config.SMTP.Identifier = "mail server.example.com"
+	suite.config.SMTP.Fallback = &schema.SMTPNotifierConfiguration{
+		Host:       "backup.example.com",
+		Port:       587,
+		Identifier: "-backup.example.com",
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "notifier: smtp: option 'identifier' must be a valid hostname or address literal but it is configured as 'mail server.example.com'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "notifier: smtp: option 'fallback.identifier' must be a valid hostname or address literal but it is configured as '-backup.example.com'")
+}
+
+func (suite *NotifierSuite) TestSMTPShouldValidateXOAUTH2() {
+	suite.config.SMTP.Password = ""
+	suite.config.SMTP.Auth = &schema.SMTPNotifierAuthConfiguration{
+		XOAUTH2: &schema.SMTPNotifierXOAUTH2Configuration{
+			TokenEndpoint: "https://login.microsoftonline.com/example/oauth2/v2.0/token",
+			ClientID:      "authelia",
+			ClientSecret:  "secret",
+			Scopes:        []string{"https://outlook.office365.com/.default"},
+		},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().NotNil(suite.config.SMTP.Auth.XOAUTH2)
+}
+
+func (suite *NotifierSuite) TestSMTPShouldRaiseErrorsOnMissingXOAUTH2Options() {
+	suite.config.SMTP.Username = ""
+	suite.config.SMTP.Password = ""
+	suite.config.SMTP.Auth = &schema.SMTPNotifierAuthConfiguration{
+		XOAUTH2: &schema.SMTPNotifierXOAUTH2Configuration{
+			TokenEndpoint: "http://login.example.com/token",
+		},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "notifier: smtp: option 'username' is required when the 'auth.xoauth2' option is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "notifier: smtp: option 'auth.xoauth2.token_endpoint' must be an absolute URL with the https scheme but it is configured as 'http://login.example.com/token'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "notifier: smtp: option 'auth.xoauth2.client_id' is required when the 'auth.xoauth2' option is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "notifier: smtp: option 'auth.xoauth2.client_secret' is required when the 'auth.xoauth2' option is configured")
+}
+
+func (suite *NotifierSuite) TestSMTPShouldFallBackToPasswordOnMissingXOAUTH2Options() {
+	suite.config.SMTP.Auth = &schema.SMTPNotifierAuthConfiguration{
+		XOAUTH2: &schema.SMTPNotifierXOAUTH2Configuration{
+			ClientID: "authelia",
+		},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+	suite.Require().Len(suite.validator.Warnings(), 2)
+
+	suite.Assert().EqualError(suite.validator.Warnings()[0], "notifier: smtp: option 'auth.xoauth2.token_endpoint' is required when the 'auth.xoauth2' option is configured: falling back to authentication with the 'username' and 'password' options")
+	suite.Assert().EqualError(suite.validator.Warnings()[1], "notifier: smtp: option 'auth.xoauth2.client_secret' is required when the 'auth.xoauth2' option is configured: falling back to authentication with the 'username' and 'password' options")
+
+	suite.Assert().Nil(suite.config.SMTP.Auth.XOAUTH2)
+}
+
 func (suite *NotifierSuite) TestFileShouldEnsureFilenameIsProvided() {
 	suite.config.SMTP = nil
 	suite.config.FileSystem = &schema.FileSystemNotifierConfiguration{
@@ -159,6 +289,64 @@ func (suite *NotifierSuite) TestFileShouldEnsureFilenameIsProvided() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], errFmtNotifierFileSystemFileNameNotConfigured)
 }
 
+func (suite *NotifierSuite) TestPasswordResetShouldSetDefaults() {
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(0, suite.config.PasswordReset.Retries)
+	suite.Assert().Equal(time.Second, suite.config.PasswordReset.RetryBackoff)
+	suite.Assert().Equal(schema.NotifierOnFailureFail, suite.config.PasswordReset.OnFailure)
+	suite.Assert().Equal(time.Second*30, suite.config.PasswordReset.QueueInterval)
+}
+
+func (suite *NotifierSuite) TestPasswordResetShouldRaiseErrorsOnInvalidValues() {
+	suite.config.PasswordReset = schema.NotifierPasswordResetConfiguration{
+		Retries:       -1,
+		RetryBackoff:  -time.Second,
+		OnFailure:     "drop",
+		QueueInterval: -time.Minute,
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "notifier: password_reset: option 'retries' must be between 0 and 3 but it is configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "notifier: password_reset: option 'on_failure' must be one of 'fail', 'queue' but it is configured as 'drop'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "notifier: password_reset: option 'retry_backoff' must be more than 0 but it is configured as '-1s'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "notifier: password_reset: option 'queue_interval' must be more than 0 but it is configured as '-1m0s'")
+}
+
+func (suite *NotifierSuite) TestPasswordResetShouldRaiseErrorsOnExcessiveRetries() {
+	suite.config.PasswordReset = schema.NotifierPasswordResetConfiguration{
+		Retries: 10,
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "notifier: password_reset: option 'retries' must be between 0 and 3 but it is configured as '10'")
+}
+
+func (suite *NotifierSuite) TestPasswordResetShouldRaiseErrorOnExcessiveRetryDelay() {
+	suite.config.PasswordReset = schema.NotifierPasswordResetConfiguration{
+		Retries:      3,
+		RetryBackoff: time.Second * 2,
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "notifier: password_reset: options 'retries' and 'retry_backoff' must not delay the response by more than 10s but they are configured to delay it by up to '14s'")
+}
+
 func TestNotifierSuite(t *testing.T) {
 	suite.Run(t, new(NotifierSuite))
 }
@@ -21,4 +21,10 @@ func ValidateNTP(config *schema.Configuration, validator *schema.StructValidator
 	if config.NTP.MaximumDesync <= 0 {
 		config.NTP.MaximumDesync = schema.DefaultNTPConfiguration.MaximumDesync
 	}
+
+	if config.NTP.Timeout == 0 {
+		config.NTP.Timeout = schema.DefaultNTPConfiguration.Timeout
+	} else if config.NTP.Timeout < 0 {
+		validator.Push(fmt.Errorf(errFmtNTPTimeout, config.NTP.Timeout))
+	}
 }
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,6 +26,7 @@ func TestShouldSetDefaultNTPValues(t *testing.T) {
 	assert.Equal(t, schema.DefaultNTPConfiguration.Address, config.NTP.Address)
 	assert.Equal(t, schema.DefaultNTPConfiguration.Version, config.NTP.Version)
 	assert.Equal(t, schema.DefaultNTPConfiguration.MaximumDesync, config.NTP.MaximumDesync)
+	assert.Equal(t, schema.DefaultNTPConfiguration.Timeout, config.NTP.Timeout)
 	assert.Equal(t, schema.DefaultNTPConfiguration.DisableStartupCheck, config.NTP.DisableStartupCheck)
 }
 
@@ -51,3 +53,15 @@ func TestShouldRaiseErrorOnInvalidNTPVersion(t *testing.T) {
 
 	assert.EqualError(t, validator.Errors()[0], "ntp: option 'version' must be either 3 or 4 but it is configured as '1'")
 }
+
+func TestShouldRaiseErrorOnNegativeNTPTimeout(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultNTPConfig()
+	config.NTP.Timeout = -time.Second
+
+	ValidateNTP(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "ntp: option 'timeout' must be a positive duration but it is configured as '-1s'")
+}
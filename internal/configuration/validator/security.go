@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateSecurity checks the configured secrets are not reused between options, are not too short, and are not well
+// known values. These issues are reported as warnings which are promoted to errors by the strict configuration mode.
+func ValidateSecurity(config *schema.Configuration, validator *schema.StructValidator) {
+	// The length of the storage encryption key is already enforced by the storage validation.
+	secrets := []securitySecret{
+		{"jwt_secret", config.JWTSecret, true},
+		{"storage: option 'encryption_key'", config.Storage.EncryptionKey, false},
+	}
+
+	if config.IdentityProviders.OIDC != nil {
+		secrets = append(secrets, securitySecret{"identity_providers: oidc: option 'hmac_secret'", config.IdentityProviders.OIDC.HMACSecret, true})
+	}
+
+	for i, secret := range secrets {
+		if secret.value == "" {
+			continue
+		}
+
+		switch {
+		case utils.IsStringInSlice(strings.ToLower(secret.value), weakSecrets):
+			validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtSecurityWeakSecret, secret.name)))
+		case secret.checkLength && len(secret.value) < securitySecretMinimumLength:
+			validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtSecurityShortSecret, secret.name, securitySecretMinimumLength)))
+		}
+
+		for _, other := range secrets[i+1:] {
+			if secret.value == other.value {
+				validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtSecuritySecretReused, secret.name, other.name)))
+			}
+		}
+	}
+}
+
+type securitySecret struct {
+	name        string
+	value       string
+	checkLength bool
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotRaiseSecurityIssuesWithUniqueSecrets(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		JWTSecret: "QAWTtYcbqHqkYEj8ZGkyFM8Xn2E8pVqhh",
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "qJ6WvjGhxnQ5C9NBsGnJzSwpYjmwR32V",
+		},
+		IdentityProviders: schema.IdentityProvidersConfiguration{
+			OIDC: &schema.OpenIDConnectConfiguration{
+				HMACSecret: "RKyJ3DXAnTBwHyKbKMn4CpNbpFWSAk4G",
+			},
+		},
+	}
+
+	ValidateSecurity(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+}
+
+func TestShouldWarnOnReusedAndWeakSecrets(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		JWTSecret: "a_very_important_secret",
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "qJ6WvjGhxnQ5C9NBsGnJzSwpYjmwR32V",
+		},
+		IdentityProviders: schema.IdentityProvidersConfiguration{
+			OIDC: &schema.OpenIDConnectConfiguration{
+				HMACSecret: "qJ6WvjGhxnQ5C9NBsGnJzSwpYjmwR32V",
+			},
+		},
+	}
+
+	ValidateSecurity(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 2)
+
+	assert.EqualError(t, validator.Warnings()[0], "security: SECURITY ISSUE - jwt_secret is configured with a well known value which must be changed")
+	assert.EqualError(t, validator.Warnings()[1], "security: SECURITY ISSUE - storage: option 'encryption_key' is configured with the same value as identity_providers: oidc: option 'hmac_secret' but each secret should be unique")
+}
+
+func TestShouldWarnOnShortSecrets(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		JWTSecret: "QAWTtYcbqHqkYEj8",
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "qJ6WvjGhxnQ5C9NB",
+		},
+	}
+
+	ValidateSecurity(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 1)
+
+	assert.EqualError(t, validator.Warnings()[0], "security: SECURITY ISSUE - jwt_secret is configured with a value shorter than 20 characters which is easily guessed")
+}
+
+func TestShouldRaiseErrorsOnReusedSecretsInStrictMode(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		JWTSecret: "qJ6WvjGhxnQ5C9NBsGnJzSwpYjmwR32V",
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "qJ6WvjGhxnQ5C9NBsGnJzSwpYjmwR32V",
+		},
+		ConfigurationValidation: schema.ConfigurationValidationConfiguration{
+			Strict: true,
+		},
+	}
+
+	ValidateSecurity(config, validator)
+	promoteStrictWarnings(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "security: SECURITY ISSUE - jwt_secret is configured with the same value as storage: option 'encryption_key' but each secret should be unique: this warning is reported as an error because the 'configuration.strict' option is enabled")
+}
@@ -2,7 +2,9 @@ package validator
 
 import (
 	"fmt"
+	"net"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -47,4 +49,138 @@ func ValidateServer(config *schema.Configuration, validator *schema.StructValida
 	} else if config.Server.WriteBufferSize < 0 {
 		validator.Push(fmt.Errorf(errFmtServerBufferSize, "write", config.Server.WriteBufferSize))
 	}
+
+	if config.Server.FallbackLocale == "" {
+		config.Server.FallbackLocale = schema.DefaultServerConfiguration.FallbackLocale
+	} else if !reLocale.MatchString(config.Server.FallbackLocale) {
+		validator.Push(fmt.Errorf(errFmtServerFallbackLocale, config.Server.FallbackLocale))
+	}
+
+	validateServerExternalURL(config, validator)
+	validateServerTLSRedirectPort(config, validator)
+	validateServerDebug(config, validator)
+	validateServerHealthCheck(config, validator)
+	validateServerErrorPages(config, validator)
+}
+
+func validateServerExternalURL(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.ExternalURL.String() == "" {
+		return
+	}
+
+	if !config.Server.ExternalURL.IsAbs() || config.Server.ExternalURL.Host == "" ||
+		(config.Server.ExternalURL.Scheme != schemeHTTP && config.Server.ExternalURL.Scheme != schemeHTTPS) {
+		validator.Push(fmt.Errorf(errFmtServerExternalURL, config.Server.ExternalURL.String()))
+
+		return
+	}
+
+	// Normalize the URL so it can be used as the base for other URLs.
+	config.Server.ExternalURL.Path = strings.TrimSuffix(config.Server.ExternalURL.Path, "/")
+	config.Server.ExternalURL.RawQuery, config.Server.ExternalURL.Fragment = "", ""
+
+	if len(config.Session.Domains) != 0 && !isHostInDomains(config.Server.ExternalURL.Hostname(), config.Session.Domains) {
+		validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtServerExternalURLDomain, config.Server.ExternalURL.Hostname(), strings.Join(config.Session.Domains, "', '"))))
+	}
+}
+
+// isHostInDomains returns true if the host is the same as or a subdomain of one of the domains.
+func isHostInDomains(host string, domains []string) bool {
+	host = strings.ToLower(host)
+
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateServerTLSRedirectPort(config *schema.Configuration, validator *schema.StructValidator) {
+	switch {
+	case config.Server.TLS.RedirectPort == 0:
+		return
+	case config.Server.TLS.RedirectPort < 0 || config.Server.TLS.RedirectPort > 65535:
+		validator.Push(fmt.Errorf(errFmtServerTLSRedirectPort, config.Server.TLS.RedirectPort))
+	case config.Server.TLS.RedirectPort == config.Server.Port:
+		validator.Push(fmt.Errorf(errFmtServerTLSRedirectPortInUse, config.Server.Port))
+	case config.Server.TLS.Certificate == "" || config.Server.TLS.Key == "":
+		validator.PushWarning(fmt.Errorf(errFmtServerTLSRedirectPortNoTLS))
+	default:
+		if _, port, err := net.SplitHostPort(config.Server.Debug.Address); err == nil && port == strconv.Itoa(config.Server.TLS.RedirectPort) {
+			validator.Push(fmt.Errorf(errFmtServerTLSRedirectPortDebug, config.Server.Debug.Address))
+		}
+
+		// The redirect location is built from the external URL rather than the Host header of the request which is
+		// controlled by the client.
+		if config.Server.ExternalURL.Scheme != schemeHTTPS || config.Server.ExternalURL.Host == "" {
+			validator.Push(fmt.Errorf(errFmtServerTLSRedirectPortURL, config.Server.ExternalURL.String()))
+		}
+	}
+}
+
+func validateServerDebug(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.Debug.Address == "" {
+		return
+	}
+
+	host, port, err := net.SplitHostPort(config.Server.Debug.Address)
+	if err != nil {
+		validator.Push(fmt.Errorf(errFmtServerDebugAddress, config.Server.Debug.Address, err))
+
+		return
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		validator.Push(fmt.Errorf(errFmtServerDebugAddressPort, config.Server.Debug.Address))
+
+		return
+	}
+
+	if port == strconv.Itoa(config.Server.Port) && (host == "" || host == config.Server.Host || config.Server.Host == schema.DefaultServerConfiguration.Host) {
+		validator.Push(fmt.Errorf(errFmtServerDebugAddressInUse, net.JoinHostPort(config.Server.Host, port)))
+	}
+
+	if !config.Server.EnablePprof && !config.Server.EnableExpvars {
+		validator.PushWarning(fmt.Errorf(errFmtServerDebugAddressNoUse))
+	}
+}
+
+func validateServerHealthCheck(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.HealthCheck.Path == "" {
+		config.Server.HealthCheck.Path = schema.DefaultServerConfiguration.HealthCheck.Path
+
+		return
+	}
+
+	if !strings.HasPrefix(config.Server.HealthCheck.Path, "/") || strings.ContainsAny(config.Server.HealthCheck.Path, " \t\r\n?#{}") {
+		validator.Push(fmt.Errorf(errFmtServerHealthCheckPath, config.Server.HealthCheck.Path))
+
+		return
+	}
+
+	config.Server.HealthCheck.Path = path.Clean(config.Server.HealthCheck.Path)
+}
+
+func validateServerErrorPages(config *schema.Configuration, validator *schema.StructValidator) {
+	for option, path := range map[string]string{
+		"client_error": config.Server.ErrorPages.ClientError,
+		"server_error": config.Server.ErrorPages.ServerError,
+	} {
+		if path == "" {
+			continue
+		}
+
+		exists, err := utils.FileExists(path)
+
+		switch {
+		case err != nil:
+			validator.Push(fmt.Errorf(errFmtServerErrorPagesUnknown, option, path, err))
+		case !exists:
+			validator.Push(fmt.Errorf(errFmtServerErrorPagesNotExist, option, path))
+		}
+	}
 }
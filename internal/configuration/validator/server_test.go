@@ -1,6 +1,10 @@
 package validator
 
 import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +31,8 @@ func TestShouldSetDefaultServerValues(t *testing.T) {
 	assert.Equal(t, schema.DefaultServerConfiguration.Path, config.Server.Path)
 	assert.Equal(t, schema.DefaultServerConfiguration.EnableExpvars, config.Server.EnableExpvars)
 	assert.Equal(t, schema.DefaultServerConfiguration.EnablePprof, config.Server.EnablePprof)
+	assert.Equal(t, schema.DefaultServerConfiguration.HealthCheck.Path, config.Server.HealthCheck.Path)
+	assert.Equal(t, schema.DefaultServerConfiguration.FallbackLocale, config.Server.FallbackLocale)
 }
 
 func TestShouldSetDefaultConfig(t *testing.T) {
@@ -167,3 +173,328 @@ func TestShouldValidateAndUpdatePort(t *testing.T) {
 	require.Len(t, validator.Errors(), 0)
 	assert.Equal(t, 9091, config.Server.Port)
 }
+
+func TestShouldValidateServerTLSRedirectPort(t *testing.T) {
+	testCases := []struct {
+		name         string
+		port         int
+		tls          bool
+		externalURL  string
+		debug        string
+		expectedErr  string
+		expectedWarn string
+	}{
+		{"ShouldAllowDisabled", 0, false, "", "", "", ""},
+		{"ShouldAllowPort", 8080, true, "https://auth.example.com", "", "", ""},
+		{"ShouldAllowPortWithDebugAddress", 8080, true, "https://auth.example.com", "127.0.0.1:9959", "", ""},
+		{"ShouldRaiseErrorOnNegativePort", -1, true, "https://auth.example.com", "", "server: tls: option 'redirect_port' must be between 1 and 65535 but it is configured as '-1'", ""},
+		{"ShouldRaiseErrorOnOutOfRangePort", 70000, true, "https://auth.example.com", "", "server: tls: option 'redirect_port' must be between 1 and 65535 but it is configured as '70000'", ""},
+		{"ShouldRaiseErrorOnMainPort", 9090, true, "https://auth.example.com", "", "server: tls: option 'redirect_port' must not be the same as option 'port' which is configured as '9090'", ""},
+		{"ShouldRaiseErrorOnDebugPort", 8080, true, "https://auth.example.com", "127.0.0.1:8080", "server: tls: option 'redirect_port' must not be the same as the port of option 'address' in section 'debug' which is configured as '127.0.0.1:8080'", ""},
+		{"ShouldRaiseErrorWithoutExternalURL", 8080, true, "", "", "server: tls: option 'redirect_port' requires option 'external_url' to be configured with the 'https' scheme as it's used as the redirect location but it's configured as ''", ""},
+		{"ShouldRaiseErrorOnInsecureExternalURL", 8080, true, "http://auth.example.com", "", "server: tls: option 'redirect_port' requires option 'external_url' to be configured with the 'https' scheme as it's used as the redirect location but it's configured as 'http://auth.example.com'", ""},
+		{"ShouldWarnWithoutTLS", 8080, false, "", "", "", "server: tls: option 'redirect_port' is configured but options 'certificate' and 'key' are not so it has no effect"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultConfig()
+			config.Server.TLS.RedirectPort = tc.port
+			config.Server.Debug.Address = tc.debug
+			config.Server.EnableExpvars = tc.debug != ""
+
+			if tc.externalURL != "" {
+				externalURL, err := url.Parse(tc.externalURL)
+				require.NoError(t, err)
+
+				config.Server.ExternalURL = *externalURL
+			}
+
+			if tc.tls {
+				config.Server.TLS.Certificate = testTLSCert
+				config.Server.TLS.Key = testTLSKey
+			}
+
+			ValidateServer(&config, validator)
+
+			if tc.expectedErr == "" {
+				assert.Len(t, validator.Errors(), 0)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.expectedErr)
+			}
+
+			if tc.expectedWarn == "" {
+				assert.Len(t, validator.Warnings(), 0)
+			} else {
+				require.Len(t, validator.Warnings(), 1)
+				assert.EqualError(t, validator.Warnings()[0], tc.expectedWarn)
+			}
+		})
+	}
+}
+
+func TestShouldValidateServerDebugAddress(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			EnablePprof: true,
+			Debug: schema.ServerDebugConfiguration{
+				Address: "localhost:9092",
+			},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+}
+
+func TestShouldRaiseErrorOnInvalidServerDebugAddress(t *testing.T) {
+	testCases := []struct {
+		name, have, expected string
+	}{
+		{"ShouldRaiseErrorOnMissingPort", "localhost", "server: debug: option 'address' must be a host and port such as 'localhost:9092' but it is configured as 'localhost': address localhost: missing port in address"},
+		{"ShouldRaiseErrorOnInvalidPort", "localhost:abc", "server: debug: option 'address' must have a port between 1 and 65535 but it is configured as 'localhost:abc'"},
+		{"ShouldRaiseErrorOnOutOfRangePort", "localhost:70000", "server: debug: option 'address' must have a port between 1 and 65535 but it is configured as 'localhost:70000'"},
+		{"ShouldRaiseErrorOnMainAddress", ":9091", "server: debug: option 'address' must not be the same as the main server address '0.0.0.0:9091'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					EnableExpvars: true,
+					Debug: schema.ServerDebugConfiguration{
+						Address: tc.have,
+					},
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			require.Len(t, validator.Errors(), 1)
+			assert.Len(t, validator.Warnings(), 0)
+
+			assert.EqualError(t, validator.Errors()[0], tc.expected)
+		})
+	}
+}
+
+func TestShouldWarnWhenServerDebugAddressHasNoEndpoints(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			Debug: schema.ServerDebugConfiguration{
+				Address: "localhost:9092",
+			},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 1)
+
+	assert.EqualError(t, validator.Warnings()[0], "server: debug: option 'address' is configured but neither option 'enable_pprof' or 'enable_expvars' are enabled so it has no effect")
+}
+
+func TestShouldValidateAndNormalizeServerExternalURL(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			ExternalURL: url.URL{Scheme: "https", Host: "auth.example.com", Path: "/authelia/"},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.Equal(t, "https://auth.example.com/authelia", config.Server.ExternalURL.String())
+}
+
+func TestShouldValidateServerExternalURLMatchesSessionDomains(t *testing.T) {
+	testCases := []struct {
+		name    string
+		have    url.URL
+		domains []string
+		warning string
+	}{
+		{"ShouldAllowSameDomain", url.URL{Scheme: "https", Host: "example.com"}, []string{"example.com"}, ""},
+		{"ShouldAllowSubdomain", url.URL{Scheme: "https", Host: "auth.example.com:8443"}, []string{"example.org", "example.com"}, ""},
+		{"ShouldIgnoreCase", url.URL{Scheme: "https", Host: "Auth.Example.com"}, []string{"example.COM"}, ""},
+		{"ShouldWarnOnOtherDomain", url.URL{Scheme: "https", Host: "auth.example.org"}, []string{"example.com"}, "server: option 'external_url' has the host 'auth.example.org' which is not the same as or a subdomain of any of the session domains 'example.com' so the session cookie will never be set"},
+		{"ShouldWarnOnSuffixWithoutDot", url.URL{Scheme: "https", Host: "authexample.com"}, []string{"example.com", "example.net"}, "server: option 'external_url' has the host 'authexample.com' which is not the same as or a subdomain of any of the session domains 'example.com', 'example.net' so the session cookie will never be set"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					ExternalURL: tc.have,
+				},
+				Session: schema.SessionConfiguration{
+					Domains: tc.domains,
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			assert.Len(t, validator.Errors(), 0)
+
+			if tc.warning == "" {
+				assert.Len(t, validator.Warnings(), 0)
+			} else {
+				require.Len(t, validator.Warnings(), 1)
+				assert.EqualError(t, validator.Warnings()[0], tc.warning)
+			}
+		})
+	}
+}
+
+func TestShouldRaiseErrorOnInvalidServerExternalURL(t *testing.T) {
+	testCases := []struct {
+		name string
+		have url.URL
+	}{
+		{"ShouldRaiseErrorOnRelativeURL", url.URL{Path: "/authelia"}},
+		{"ShouldRaiseErrorOnInvalidScheme", url.URL{Scheme: "ftp", Host: "auth.example.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					ExternalURL: tc.have,
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			require.Len(t, validator.Errors(), 1)
+			assert.EqualError(t, validator.Errors()[0], fmt.Sprintf("server: option 'external_url' must be an absolute URL with the 'http' or 'https' scheme but it is configured as '%s'", tc.have.String()))
+		})
+	}
+}
+
+func TestShouldValidateServerFallbackLocale(t *testing.T) {
+	testCases := []struct {
+		name, have, err string
+	}{
+		{"ShouldAllowLanguage", "de", ""},
+		{"ShouldAllowVariant", "en-us", ""},
+		{"ShouldRaiseErrorOnUppercase", "en-US", "server: option 'fallback_locale' must be a locale such as 'en' or 'en-us' but it is configured as 'en-US'"},
+		{"ShouldRaiseErrorOnPath", "../en", "server: option 'fallback_locale' must be a locale such as 'en' or 'en-us' but it is configured as '../en'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					FallbackLocale: tc.have,
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			if tc.err == "" {
+				assert.Len(t, validator.Errors(), 0)
+				assert.Equal(t, tc.have, config.Server.FallbackLocale)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+		})
+	}
+}
+
+func TestShouldValidateServerHealthCheckPath(t *testing.T) {
+	testCases := []struct {
+		name, have, expected, err string
+	}{
+		{"ShouldAllowCustomPath", "/healthz", "/healthz", ""},
+		{"ShouldCleanPath", "/status/health/", "/status/health", ""},
+		{"ShouldRaiseErrorOnRelativePath", "healthz", "", "server: health_check: option 'path' must be an absolute path starting with a forward slash and must not contain whitespace, query, fragment, or parameter characters but it is configured as 'healthz'"},
+		{"ShouldRaiseErrorOnQuery", "/healthz?full=true", "", "server: health_check: option 'path' must be an absolute path starting with a forward slash and must not contain whitespace, query, fragment, or parameter characters but it is configured as '/healthz?full=true'"},
+		{"ShouldRaiseErrorOnRouterParameter", "/{name}", "", "server: health_check: option 'path' must be an absolute path starting with a forward slash and must not contain whitespace, query, fragment, or parameter characters but it is configured as '/{name}'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					HealthCheck: schema.ServerHealthCheckConfiguration{
+						Path: tc.have,
+					},
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			if tc.err == "" {
+				assert.Len(t, validator.Errors(), 0)
+				assert.Equal(t, tc.expected, config.Server.HealthCheck.Path)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+		})
+	}
+}
+
+func TestShouldValidateServerErrorPages(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "client.html")
+
+	require.NoError(t, os.WriteFile(page, []byte("<h1>{{ .StatusCode }} {{ .StatusText }}</h1>"), 0600))
+
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			ErrorPages: schema.ServerErrorPagesConfiguration{
+				ClientError: page,
+			},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldRaiseErrorOnMissingServerErrorPages(t *testing.T) {
+	dir := t.TempDir()
+
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			ErrorPages: schema.ServerErrorPagesConfiguration{
+				ServerError: filepath.Join(dir, "server.html"),
+			},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf("server: error_pages: option 'server_error' must be the path of an existing file but it is configured as '%s'", filepath.Join(dir, "server.html")))
+
+	validator = schema.NewStructValidator()
+	config.Server.ErrorPages.ServerError = dir
+
+	ValidateServer(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf("server: error_pages: option 'server_error' with value '%s' could not be verified due to a file system error: path is a directory", dir))
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -39,25 +40,108 @@ func validateSession(config *schema.SessionConfiguration, validator *schema.Stru
 		config.RememberMeDuration = schema.DefaultSessionConfiguration.RememberMeDuration // 1 month.
 	}
 
-	if config.Domain == "" {
-		validator.Push(fmt.Errorf(errFmtSessionOptionRequired, "domain"))
+	if config.RememberMeRequireTwoFactor && config.RememberMeDuration == schema.RememberMeDisabled {
+		validator.PushWarning(errors.New(errSessionRememberMeRequireTwoFactor))
 	}
 
-	if strings.HasPrefix(config.Domain, "*.") {
-		validator.Push(fmt.Errorf(errFmtSessionDomainMustBeRoot, config.Domain))
+	if config.MaximumLifetime < 0 {
+		validator.Push(fmt.Errorf(errFmtSessionMaximumLifetime, config.MaximumLifetime))
 	}
 
+	validateSessionDomains(config, validator)
+
 	if config.SameSite == "" {
 		config.SameSite = schema.DefaultSessionConfiguration.SameSite
 	} else if !utils.IsStringInSlice(config.SameSite, validSessionSameSiteValues) {
 		validator.Push(fmt.Errorf(errFmtSessionSameSite, strings.Join(validSessionSameSiteValues, "', '"), config.SameSite))
 	}
+
+	if config.SameSiteOIDC != "" && !utils.IsStringInSlice(config.SameSiteOIDC, validSessionSameSiteValues) {
+		validator.Push(fmt.Errorf(errFmtSessionSameSiteOIDC, strings.Join(validSessionSameSiteValues, "', '"), config.SameSiteOIDC))
+	}
+}
+
+// validateSessionDomains validates the session domains and maps the deprecated domain option into the domains option
+// as the primary domain, the primary domain is always available as the domain option after validation.
+func validateSessionDomains(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if strings.HasPrefix(config.Domain, "*.") {
+		validator.Push(fmt.Errorf(errFmtSessionDomainMustBeRoot, config.Domain))
+	}
+
+	for _, domain := range config.Domains {
+		if strings.HasPrefix(domain, "*.") {
+			validator.Push(fmt.Errorf(errFmtSessionDomainsMustBeRoot, domain))
+		}
+	}
+
+	if config.Domain != "" && !utils.IsStringInSliceFold(config.Domain, config.Domains) {
+		config.Domains = append([]string{config.Domain}, config.Domains...)
+	}
+
+	if len(config.Domains) == 0 {
+		validator.Push(fmt.Errorf(errFmtSessionOptionRequired, "domain"))
+
+		return
+	}
+
+	config.Domain = config.Domains[0]
 }
 
 func validateRedisCommon(config *schema.SessionConfiguration, validator *schema.StructValidator) {
 	if config.Secret == "" {
 		validator.Push(fmt.Errorf(errFmtSessionSecretRequired, "redis"))
 	}
+
+	if config.Redis.CredentialsFile != "" {
+		validateRedisCredentialsFile(config, validator)
+	}
+
+	if os.Getenv("ENVIRONMENT") != dev {
+		validateRedisTLS(config, validator)
+	}
+}
+
+// validateRedisTLS reports Redis hosts which are not loopback hosts or unix sockets and are configured without TLS. This
+// is an error when the 'require_tls' option is enabled and a strict warning otherwise.
+func validateRedisTLS(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if config.Redis.TLS != nil {
+		return
+	}
+
+	hosts := []string{config.Redis.Host}
+
+	if config.Redis.HighAvailability != nil {
+		for _, node := range config.Redis.HighAvailability.Nodes {
+			hosts = append(hosts, node.Host)
+		}
+	}
+
+	for _, host := range hosts {
+		if host == "" || strings.HasPrefix(host, "/") || isLoopbackHost(strings.Trim(host, "[]")) {
+			continue
+		}
+
+		if config.Redis.RequireTLS {
+			validator.Push(fmt.Errorf(errFmtSessionRedisTLSRequired, host))
+		} else {
+			validator.PushWarning(newStrictWarning(fmt.Errorf(errFmtSessionRedisTLSNotConfigured, host)))
+		}
+	}
+}
+
+func validateRedisCredentialsFile(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if config.Redis.Username != "" || config.Redis.Password != "" {
+		validator.Push(errors.New(errSessionRedisCredentialsFileWithCredentials))
+	}
+
+	_, err := os.Stat(config.Redis.CredentialsFile)
+
+	switch {
+	case os.IsNotExist(err):
+		validator.Push(fmt.Errorf(errFmtSessionRedisCredentialsFileNotExist, config.Redis.CredentialsFile))
+	case err != nil:
+		validator.Push(fmt.Errorf(errFmtSessionRedisCredentialsFileUnknownError, config.Redis.CredentialsFile, err))
+	}
 }
 
 func validateRedis(config *schema.SessionConfiguration, validator *schema.StructValidator) {
@@ -89,8 +173,11 @@ func validateRedisSentinel(config *schema.SessionConfiguration, validator *schem
 		validator.Push(fmt.Errorf(errFmtSessionRedisPortRange, config.Redis.Port))
 	}
 
-	if config.Redis.Host == "" && len(config.Redis.HighAvailability.Nodes) == 0 {
+	switch {
+	case config.Redis.Host == "" && len(config.Redis.HighAvailability.Nodes) == 0:
 		validator.Push(fmt.Errorf(errFmtSessionRedisHostOrNodesRequired))
+	case config.Redis.Host != "" && len(config.Redis.HighAvailability.Nodes) != 0:
+		validator.Push(fmt.Errorf(errFmtSessionRedisHostAndNodesConfigured, config.Redis.Host))
 	}
 
 	validateRedisCommon(config, validator)
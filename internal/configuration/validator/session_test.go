@@ -2,7 +2,10 @@ package validator
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,6 +52,8 @@ func TestShouldSetDefaultSessionValuesWhenNegative(t *testing.T) {
 }
 
 func TestShouldHandleRedisConfigSuccessfully(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
@@ -72,7 +77,55 @@ func TestShouldHandleRedisConfigSuccessfully(t *testing.T) {
 	assert.Equal(t, 8, config.Redis.MaximumActiveConnections)
 }
 
+func TestShouldValidateRedisCredentialsFile(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "credentials.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte("username: authelia\npassword: secret\n"), 0600))
+
+	testCases := []struct {
+		name     string
+		file     string
+		password string
+		expected []string
+	}{
+		{"ShouldAllowExistingFile", path, "", nil},
+		{"ShouldRaiseErrorWhenFileDoesNotExist", filepath.Join(dir, "missing.yml"), "", []string{
+			fmt.Sprintf("session: redis: option 'credentials_file' refers to the file '%s' which does not exist", filepath.Join(dir, "missing.yml")),
+		}},
+		{"ShouldRaiseErrorWhenConfiguredWithPassword", path, "password", []string{
+			"session: redis: option 'credentials_file' must not be configured with the 'username' or 'password' options",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+			config.Redis = &schema.RedisSessionConfiguration{
+				Host:            "redis.localhost",
+				Port:            6379,
+				Password:        tc.password,
+				CredentialsFile: tc.file,
+			}
+
+			ValidateSession(&config, validator)
+
+			assert.False(t, validator.HasWarnings())
+			require.Len(t, validator.Errors(), len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, validator.Errors()[i], expected)
+			}
+		})
+	}
+}
+
 func TestShouldRaiseErrorWithInvalidRedisPortLow(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
@@ -90,6 +143,8 @@ func TestShouldRaiseErrorWithInvalidRedisPortLow(t *testing.T) {
 }
 
 func TestShouldRaiseErrorWithInvalidRedisPortHigh(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
@@ -107,6 +162,8 @@ func TestShouldRaiseErrorWithInvalidRedisPortHigh(t *testing.T) {
 }
 
 func TestShouldRaiseErrorWhenRedisIsUsedAndSecretNotSet(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 	config.Secret = ""
@@ -130,6 +187,8 @@ func TestShouldRaiseErrorWhenRedisIsUsedAndSecretNotSet(t *testing.T) {
 }
 
 func TestShouldRaiseErrorWhenRedisHasHostnameButNoPort(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
@@ -152,11 +211,12 @@ func TestShouldRaiseErrorWhenRedisHasHostnameButNoPort(t *testing.T) {
 }
 
 func TestShouldRaiseOneErrorWhenRedisHighAvailabilityHasNodesWithNoHost(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
 	config.Redis = &schema.RedisSessionConfiguration{
-		Host: "redis",
 		Port: 6379,
 		HighAvailability: &schema.RedisHighAvailabilityConfiguration{
 			SentinelName:     "authelia-sentinel",
@@ -183,6 +243,8 @@ func TestShouldRaiseOneErrorWhenRedisHighAvailabilityHasNodesWithNoHost(t *testi
 }
 
 func TestShouldRaiseOneErrorWhenRedisHighAvailabilityDoesNotHaveSentinelName(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
@@ -205,11 +267,12 @@ func TestShouldRaiseOneErrorWhenRedisHighAvailabilityDoesNotHaveSentinelName(t *
 }
 
 func TestShouldUpdateDefaultPortWhenRedisSentinelHasNodes(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
 	config.Redis = &schema.RedisSessionConfiguration{
-		Host: "redis",
 		Port: 6379,
 		HighAvailability: &schema.RedisHighAvailabilityConfiguration{
 			SentinelName:     "authelia-sentinel",
@@ -240,6 +303,8 @@ func TestShouldUpdateDefaultPortWhenRedisSentinelHasNodes(t *testing.T) {
 }
 
 func TestShouldRaiseErrorsWhenRedisSentinelOptionsIncorrectlyConfigured(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
@@ -286,6 +351,8 @@ func TestShouldRaiseErrorsWhenRedisSentinelOptionsIncorrectlyConfigured(t *testi
 }
 
 func TestShouldNotRaiseErrorsAndSetDefaultPortWhenRedisSentinelPortBlank(t *testing.T) {
+	t.Setenv("ENVIRONMENT", dev)
+
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
 
@@ -295,14 +362,8 @@ func TestShouldNotRaiseErrorsAndSetDefaultPortWhenRedisSentinelPortBlank(t *test
 		HighAvailability: &schema.RedisHighAvailabilityConfiguration{
 			SentinelName:     "sentinel",
 			SentinelPassword: "abc123",
-			Nodes: []schema.RedisNode{
-				{
-					Host: "node1",
-					Port: 26379,
-				},
-			},
-			RouteByLatency: true,
-			RouteRandomly:  true,
+			RouteByLatency:   true,
+			RouteRandomly:    true,
 		},
 	}
 
@@ -314,6 +375,62 @@ func TestShouldNotRaiseErrorsAndSetDefaultPortWhenRedisSentinelPortBlank(t *test
 	assert.Equal(t, 26379, config.Redis.Port)
 }
 
+func TestShouldValidateRedisSentinelHostAndNodesMutuallyExclusive(t *testing.T) {
+	testCases := []struct {
+		name  string
+		host  string
+		nodes []schema.RedisNode
+		err   string
+	}{
+		{
+			name: "ShouldAllowHostOnly",
+			host: "sentinel",
+		},
+		{
+			name:  "ShouldAllowNodesOnly",
+			nodes: []schema.RedisNode{{Host: "node1"}, {Host: "node2"}},
+		},
+		{
+			name:  "ShouldRaiseErrorWhenBoth",
+			host:  "sentinel",
+			nodes: []schema.RedisNode{{Host: "node1"}},
+			err:   "session: redis: option 'host' must not be configured when the 'high_availability' option 'nodes' is configured but it's configured as 'sentinel'",
+		},
+		{
+			name: "ShouldRaiseErrorWhenNeither",
+			err:  "session: redis: option 'host' or the 'high_availability' option 'nodes' is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ENVIRONMENT", dev)
+
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+
+			config.Redis = &schema.RedisSessionConfiguration{
+				Host: tc.host,
+				HighAvailability: &schema.RedisHighAvailabilityConfiguration{
+					SentinelName: "sentinel",
+					Nodes:        tc.nodes,
+				},
+			}
+
+			ValidateSession(&config, validator)
+
+			assert.False(t, validator.HasWarnings())
+
+			if tc.err == "" {
+				assert.False(t, validator.HasErrors())
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+		})
+	}
+}
+
 func TestShouldRaiseErrorWhenRedisHostAndHighAvailabilityNodesEmpty(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -354,6 +471,92 @@ func TestShouldRaiseErrorsWhenRedisHostNotSet(t *testing.T) {
 	assert.EqualError(t, errors[0], errFmtSessionRedisHostRequired)
 }
 
+func TestShouldValidateRedisTLS(t *testing.T) {
+	testCases := []struct {
+		name        string
+		environment string
+		redis       schema.RedisSessionConfiguration
+		warnings    []string
+		errors      []string
+	}{
+		{
+			name:  "ShouldWarnRemoteHostWithoutTLS",
+			redis: schema.RedisSessionConfiguration{Host: "redis.example.com", Port: 6379},
+			warnings: []string{
+				fmt.Sprintf(errFmtSessionRedisTLSNotConfigured, "redis.example.com"),
+			},
+		},
+		{
+			name:  "ShouldErrorRemoteHostWithoutTLSWhenRequired",
+			redis: schema.RedisSessionConfiguration{Host: "redis.example.com", Port: 6379, RequireTLS: true},
+			errors: []string{
+				fmt.Sprintf(errFmtSessionRedisTLSRequired, "redis.example.com"),
+			},
+		},
+		{
+			name:  "ShouldAllowRemoteHostWithTLSWhenRequired",
+			redis: schema.RedisSessionConfiguration{Host: "redis.example.com", Port: 6379, RequireTLS: true, TLS: &schema.TLSConfig{}},
+		},
+		{
+			name:  "ShouldAllowLoopbackHostWithoutTLS",
+			redis: schema.RedisSessionConfiguration{Host: "127.0.0.1", Port: 6379, RequireTLS: true},
+		},
+		{
+			name:  "ShouldAllowLocalhostWithoutTLS",
+			redis: schema.RedisSessionConfiguration{Host: "localhost", Port: 6379, RequireTLS: true},
+		},
+		{
+			name:  "ShouldAllowIPv6LoopbackHostWithoutTLS",
+			redis: schema.RedisSessionConfiguration{Host: "[::1]", Port: 6379, RequireTLS: true},
+		},
+		{
+			name:  "ShouldAllowUnixSocketWithoutTLS",
+			redis: schema.RedisSessionConfiguration{Host: "/var/run/redis/redis.sock", RequireTLS: true},
+		},
+		{
+			name:        "ShouldAllowRemoteHostWithoutTLSInDevelopment",
+			environment: dev,
+			redis:       schema.RedisSessionConfiguration{Host: "redis.example.com", Port: 6379, RequireTLS: true},
+		},
+		{
+			name: "ShouldErrorRemoteSentinelNodesWithoutTLSWhenRequired",
+			redis: schema.RedisSessionConfiguration{
+				RequireTLS: true,
+				HighAvailability: &schema.RedisHighAvailabilityConfiguration{
+					SentinelName: "sentinel",
+					Nodes:        []schema.RedisNode{{Host: "node1"}, {Host: "::1"}},
+				},
+			},
+			errors: []string{
+				fmt.Sprintf(errFmtSessionRedisTLSRequired, "node1"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ENVIRONMENT", tc.environment)
+
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+			config.Redis = &tc.redis
+
+			ValidateSession(&config, validator)
+
+			require.Len(t, validator.Warnings(), len(tc.warnings))
+			require.Len(t, validator.Errors(), len(tc.errors))
+
+			for i, warning := range tc.warnings {
+				assert.EqualError(t, validator.Warnings()[i], warning)
+			}
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+		})
+	}
+}
+
 func TestShouldRaiseErrorWhenDomainNotSet(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -378,6 +581,43 @@ func TestShouldRaiseErrorWhenDomainIsWildcard(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "session: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '*.example.com'")
 }
 
+func TestShouldMapDomainIntoDomains(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Domains = []string{"example.org"}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, []string{"example.com", "example.org"}, config.Domains)
+	assert.Equal(t, "example.com", config.Domain)
+
+	validator.Clear()
+
+	config.Domain = ""
+	config.Domains = []string{"example.org", "example.com"}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, []string{"example.org", "example.com"}, config.Domains)
+	assert.Equal(t, "example.org", config.Domain)
+}
+
+func TestShouldRaiseErrorWhenDomainsContainsWildcard(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Domain = ""
+	config.Domains = []string{"example.com", "*.example.org"}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: option 'domains' must only contain the domains you wish to protect not wildcard domains but one is configured as '*.example.org'")
+}
+
 func TestShouldRaiseErrorWhenSameSiteSetIncorrectly(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -406,6 +646,18 @@ func TestShouldNotRaiseErrorWhenSameSiteSetCorrectly(t *testing.T) {
 	}
 }
 
+func TestShouldRaiseErrorWhenSameSiteOIDCSetIncorrectly(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.SameSiteOIDC = "Strict"
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: option 'same_site_oidc' must be one of 'none', 'lax', 'strict' but is configured as 'Strict'")
+}
+
 func TestShouldSetDefaultWhenNegativeAndNotOverrideDisabledRememberMe(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -433,3 +685,42 @@ func TestShouldSetDefaultRememberMeDuration(t *testing.T) {
 	assert.False(t, validator.HasErrors())
 	assert.Equal(t, config.RememberMeDuration, schema.DefaultSessionConfiguration.RememberMeDuration)
 }
+
+func TestShouldWarnWhenRememberMeRequireTwoFactorAndRememberMeDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.RememberMeDuration = schema.RememberMeDisabled
+	config.RememberMeRequireTwoFactor = true
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 1)
+
+	assert.EqualError(t, validator.Warnings()[0], "session: option 'remember_me_require_two_factor' has no effect because option 'remember_me_duration' is configured as '-1' (disabled)")
+}
+
+func TestShouldRaiseErrorWhenMaximumLifetimeIsNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.MaximumLifetime = -1 * time.Hour
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "session: option 'maximum_lifetime' must be 0 (disabled) or a positive duration but it is configured as '-1h0m0s'")
+}
+
+func TestShouldNotRaiseErrorWhenMaximumLifetimeIsPositive(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.MaximumLifetime = 12 * time.Hour
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, 12*time.Hour, config.MaximumLifetime)
+}
@@ -47,6 +47,37 @@ func validateSQLConfiguration(config *schema.SQLStorageConfiguration, validator
 	if config.Database == "" {
 		validator.Push(fmt.Errorf(errFmtStorageOptionMustBeProvided, provider, "database"))
 	}
+
+	validateSQLConnectionPoolConfiguration(config, validator, provider)
+}
+
+func validateSQLConnectionPoolConfiguration(config *schema.SQLStorageConfiguration, validator *schema.StructValidator, provider string) {
+	switch {
+	case config.MaxOpenConnections == 0:
+		config.MaxOpenConnections = schema.DefaultSQLStorageConfiguration.MaxOpenConnections
+	case config.MaxOpenConnections < 0:
+		validator.Push(fmt.Errorf(errFmtStorageConnectionPoolOption, provider, "max_open_connections", schema.DefaultSQLStorageConfiguration.MaxOpenConnections, config.MaxOpenConnections))
+	}
+
+	switch {
+	case config.MaxIdleConnections == 0:
+		config.MaxIdleConnections = schema.DefaultSQLStorageConfiguration.MaxIdleConnections
+
+		if config.MaxOpenConnections > 0 && config.MaxIdleConnections > config.MaxOpenConnections {
+			config.MaxIdleConnections = config.MaxOpenConnections
+		}
+	case config.MaxIdleConnections < 0:
+		validator.Push(fmt.Errorf(errFmtStorageConnectionPoolOption, provider, "max_idle_connections", schema.DefaultSQLStorageConfiguration.MaxIdleConnections, config.MaxIdleConnections))
+	case config.MaxOpenConnections > 0 && config.MaxIdleConnections > config.MaxOpenConnections:
+		validator.Push(fmt.Errorf(errFmtStorageMaxIdleConnections, provider, config.MaxOpenConnections, config.MaxIdleConnections))
+	}
+
+	switch {
+	case config.ConnectionMaxLifetime == 0:
+		config.ConnectionMaxLifetime = schema.DefaultSQLStorageConfiguration.ConnectionMaxLifetime
+	case config.ConnectionMaxLifetime < 0:
+		validator.Push(fmt.Errorf(errFmtStorageConnectionPoolOption, provider, "connection_max_lifetime", schema.DefaultSQLStorageConfiguration.ConnectionMaxLifetime, config.ConnectionMaxLifetime))
+	}
 }
 
 func validatePostgreSQLConfiguration(config *schema.PostgreSQLStorageConfiguration, validator *schema.StructValidator) {
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -104,6 +105,68 @@ func (suite *StorageSuite) TestShouldValidatePostgreSQLHostUsernamePasswordAndDa
 	suite.Assert().Len(suite.validator.Errors(), 0)
 }
 
+func (suite *StorageSuite) TestShouldSetSQLConnectionPoolDefaults() {
+	suite.config.MySQL = &schema.MySQLStorageConfiguration{
+		SQLStorageConfiguration: schema.SQLStorageConfiguration{
+			Host:     "localhost",
+			Username: "myuser",
+			Password: "pass",
+			Database: "database",
+		},
+	}
+
+	ValidateStorage(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(10, suite.config.MySQL.MaxOpenConnections)
+	suite.Assert().Equal(2, suite.config.MySQL.MaxIdleConnections)
+	suite.Assert().Equal(time.Hour, suite.config.MySQL.ConnectionMaxLifetime)
+
+	suite.config.MySQL.MaxOpenConnections = 1
+	suite.config.MySQL.MaxIdleConnections = 0
+
+	ValidateStorage(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+	suite.Assert().Equal(1, suite.config.MySQL.MaxIdleConnections)
+}
+
+func (suite *StorageSuite) TestShouldRaiseErrorsOnInvalidSQLConnectionPool() {
+	suite.config.PostgreSQL = &schema.PostgreSQLStorageConfiguration{
+		SQLStorageConfiguration: schema.SQLStorageConfiguration{
+			Host:                  "db1",
+			Username:              "myuser",
+			Password:              "pass",
+			Database:              "database",
+			MaxOpenConnections:    -1,
+			MaxIdleConnections:    -1,
+			ConnectionMaxLifetime: -time.Minute,
+		},
+	}
+
+	ValidateStorage(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 3)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: postgres: option 'max_open_connections' must be more than 0 (the default is '10') but it is configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "storage: postgres: option 'max_idle_connections' must be more than 0 (the default is '2') but it is configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "storage: postgres: option 'connection_max_lifetime' must be more than 0 (the default is '1h0m0s') but it is configured as '-1m0s'")
+
+	suite.validator.Clear()
+
+	suite.config.PostgreSQL.MaxOpenConnections = 5
+	suite.config.PostgreSQL.MaxIdleConnections = 6
+	suite.config.PostgreSQL.ConnectionMaxLifetime = time.Minute
+
+	ValidateStorage(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Errors(), 1)
+	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: postgres: option 'max_idle_connections' must not be more than the 'max_open_connections' value of '5' but it is configured as '6'")
+}
+
 func (suite *StorageSuite) TestShouldValidatePostgresSSLModeAndSchemaDefaults() {
 	suite.config.PostgreSQL = &schema.PostgreSQLStorageConfiguration{
 		SQLStorageConfiguration: schema.SQLStorageConfiguration{
@@ -38,5 +38,7 @@ func ValidateTOTP(config *schema.Configuration, validator *schema.StructValidato
 
 	if config.TOTP.Skew == nil {
 		config.TOTP.Skew = schema.DefaultTOTPConfiguration.Skew
+	} else if *config.TOTP.Skew > 2 {
+		validator.Push(fmt.Errorf(errFmtTOTPInvalidSkew, *config.TOTP.Skew))
 	}
 }
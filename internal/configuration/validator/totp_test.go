@@ -71,3 +71,39 @@ func TestShouldRaiseErrorWhenInvalidTOTPValues(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf(errFmtTOTPInvalidPeriod, 5))
 	assert.EqualError(t, validator.Errors()[1], fmt.Sprintf(errFmtTOTPInvalidDigits, 20))
 }
+
+func TestShouldValidateTOTPSkew(t *testing.T) {
+	testCases := []struct {
+		skew uint
+		err  string
+	}{
+		{0, ""},
+		{1, ""},
+		{2, ""},
+		{3, "totp: option 'skew' must be between 0 and 2 but it is configured as '3'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Skew%d", tc.skew), func(t *testing.T) {
+			skew := tc.skew
+
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				TOTP: schema.TOTPConfiguration{
+					Skew: &skew,
+				},
+			}
+
+			ValidateTOTP(config, validator)
+
+			if tc.err == "" {
+				assert.Len(t, validator.Errors(), 0)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.err)
+			}
+
+			assert.Equal(t, tc.skew, *config.TOTP.Skew)
+		})
+	}
+}
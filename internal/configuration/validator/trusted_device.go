@@ -0,0 +1,20 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateTrustedDevice validates and update trusted device configuration.
+func ValidateTrustedDevice(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.TrustedDevice.Lifespan < 0 {
+		validator.Push(fmt.Errorf(errFmtTrustedDeviceLifespan, config.TrustedDevice.Lifespan))
+	}
+
+	if config.TrustedDevice.CookieName == "" {
+		config.TrustedDevice.CookieName = schema.DefaultTrustedDeviceConfiguration.CookieName
+	} else if config.TrustedDevice.CookieName == config.Session.Name {
+		validator.Push(fmt.Errorf(errFmtTrustedDeviceCookieName, config.TrustedDevice.CookieName))
+	}
+}
@@ -0,0 +1,42 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldSetDefaultTrustedDeviceValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := schema.Configuration{}
+
+	ValidateTrustedDevice(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Duration(0), config.TrustedDevice.Lifespan)
+	assert.Equal(t, schema.DefaultTrustedDeviceConfiguration.CookieName, config.TrustedDevice.CookieName)
+	assert.False(t, config.TrustedDevice.BindToIP)
+}
+
+func TestShouldRaiseErrorOnInvalidTrustedDeviceValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := schema.Configuration{
+		Session: schema.SessionConfiguration{
+			Name: "authelia_session",
+		},
+		TrustedDevice: schema.TrustedDeviceConfiguration{
+			Lifespan:   -time.Hour,
+			CookieName: "authelia_session",
+		},
+	}
+
+	ValidateTrustedDevice(&config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "trusted_device: option 'lifespan' must not be negative but it is configured as '-1h0m0s'")
+	assert.EqualError(t, validator.Errors()[1], "trusted_device: option 'cookie_name' must not be the same as the session name but it is configured as 'authelia_session'")
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -31,4 +34,28 @@ func ValidateWebauthn(config *schema.Configuration, validator *schema.StructVali
 	case !utils.IsStringInSlice(string(config.Webauthn.UserVerification), validWebauthnUserVerificationRequirement):
 		validator.Push(fmt.Errorf(errFmtWebauthnUserVerification, config.Webauthn.UserVerification))
 	}
+
+	validateWebauthnAllowedAAGUIDs(config, validator)
+}
+
+func validateWebauthnAllowedAAGUIDs(config *schema.Configuration, validator *schema.StructValidator) {
+	if len(config.Webauthn.AllowedAAGUIDs) == 0 {
+		return
+	}
+
+	for i, aaguid := range config.Webauthn.AllowedAAGUIDs {
+		id, err := uuid.Parse(aaguid)
+		if err != nil {
+			validator.Push(fmt.Errorf(errFmtWebauthnAllowedAAGUID, aaguid, err))
+
+			continue
+		}
+
+		// Normalize the value so it can be compared with the canonical form of the authenticator AAGUID.
+		config.Webauthn.AllowedAAGUIDs[i] = id.String()
+	}
+
+	if config.Webauthn.ConveyancePreference == protocol.PreferNoAttestation {
+		validator.PushWarning(fmt.Errorf(errFmtWebauthnAllowedAAGUIDNone))
+	}
 }
@@ -96,3 +96,49 @@ func TestWebauthnShouldRaiseErrorsOnInvalidOptions(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "webauthn: option 'attestation_conveyance_preference' must be one of 'none', 'indirect', 'direct' but it is configured as 'no'")
 	assert.EqualError(t, validator.Errors()[1], "webauthn: option 'user_verification' must be one of 'discouraged', 'preferred', 'required' but it is configured as 'yes'")
 }
+
+func TestWebauthnShouldNormalizeAllowedAAGUIDs(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			AllowedAAGUIDs: []string{"CB69481E-8FF7-4039-93EC-0A2729A154A8", "ee882879721c491397753dfcce97072a"},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Equal(t, []string{"cb69481e-8ff7-4039-93ec-0a2729a154a8", "ee882879-721c-4913-9775-3dfcce97072a"}, config.Webauthn.AllowedAAGUIDs)
+}
+
+func TestWebauthnShouldRaiseErrorsOnInvalidAllowedAAGUIDs(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			AllowedAAGUIDs: []string{"cb69481e-8ff7-4039-93ec-0a2729a154a8", "not-a-uuid"},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.Len(t, validator.Warnings(), 0)
+	assert.EqualError(t, validator.Errors()[0], "webauthn: option 'allowed_aaguids' must only contain valid UUIDs but it has the value 'not-a-uuid': invalid UUID length: 10")
+}
+
+func TestWebauthnShouldWarnWhenAllowedAAGUIDsConfiguredWithoutAttestation(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			ConveyancePreference: protocol.PreferNoAttestation,
+			AllowedAAGUIDs:       []string{"cb69481e-8ff7-4039-93ec-0a2729a154a8"},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 1)
+	assert.EqualError(t, validator.Warnings()[0], "webauthn: option 'allowed_aaguids' is configured but option 'attestation_conveyance_preference' is configured as 'none' so authenticators will not provide an AAGUID and registration will always fail")
+}
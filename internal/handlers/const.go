@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"time"
 
 	"github.com/valyala/fasthttp"
@@ -44,9 +45,42 @@ const (
 	messageUnableToRegisterSecurityKey     = "Unable to register your security key."
 	messageUnableToResetPassword           = "Unable to reset your password."
 	messageMFAValidationFailed             = "Authentication failed, please retry later."
+	messageSecurityKeyNotAllowed           = "Your security key is not allowed, please use an approved security key."
 	messagePasswordWeak                    = "Your supplied password does not meet the password policy requirements"
 )
 
+const (
+	deviceChangeActionAdded = "added"
+)
+
+const (
+	healthStatusOK = "OK"
+	healthStatusKO = "KO"
+
+	healthCheckStorage = "storage"
+	healthCheckLDAP    = "ldap"
+
+	// healthReadyCacheDuration is how long the result of the readiness checks is reused.
+	healthReadyCacheDuration = time.Second * 10
+)
+
+// firstFactorQueueTimeout is the maximum amount of time a first factor request waits for the authentication backend
+// when the maximum number of concurrent authentication requests are in flight.
+var firstFactorQueueTimeout = time.Second * 5
+
+// userSessionRecordLastSeenInterval is the minimum amount of time between updates of the last seen time of the session
+// record of a user, this limits the writes to the storage provider made by the verify endpoint.
+var userSessionRecordLastSeenInterval = time.Minute
+
+// verifyLookups limits the authentication backend lookups of the verify endpoint which are in flight when the verify
+// timeout is configured, as a lookup which exceeds the timeout keeps running in the background until the backend
+// responds. When the limit is reached further lookups wait for a slot until their own deadline.
+var verifyLookups = make(chan struct{}, verifyMaxLookups)
+
+const verifyMaxLookups = 100
+
+var errFirstFactorQueueTimeout = errors.New("timed out waiting for the authentication backend as the maximum number of concurrent requests are in flight")
+
 const (
 	logFmtErrParseRequestBody     = "Failed to parse %s request body: %+v"
 	logFmtErrWriteResponseBody    = "Failed to write %s response body for user '%s': %+v"
@@ -55,6 +89,9 @@ const (
 	logFmtErrSessionReset         = "Could not reset session during %s authentication for user '%s': %+v"
 	logFmtErrSessionSave          = "Could not save session with the %s during %s authentication for user '%s': %+v"
 	logFmtErrObtainProfileDetails = "Could not obtain profile details during %s authentication for user '%s': %+v"
+	logFmtErrTrustedDevice        = "Could not trust the device of user '%s': %+v"
+	logFmtErrLastUsed2FAMethod    = "Could not save the last used %s method of user '%s': %+v"
+	logFmtErrSessionRecord        = "Could not %s the session record of user '%s': %+v"
 	logFmtTraceProfileDetails     = "Profile details for user '%s' => groups: %s, emails %s"
 )
 
@@ -80,6 +117,13 @@ const (
 
 	// Note: If you change this const you must also do so in the frontend at web/src/services/Api.ts.
 	pathOpenIDConnectConsent = "/api/oidc/consent"
+
+	oidcFormParameterLoginHint = "login_hint"
+	oidcResponseTypeToken      = "token"
+	oidcResponseTypeIDToken    = "id_token"
+
+	// oidcLoginHintMaximumLength is the maximum length of a login_hint which is used to prefill the username.
+	oidcLoginHintMaximumLength = 256
 )
 
 const (
@@ -25,7 +25,7 @@ func CheckSafeRedirection(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	safe, err := utils.IsRedirectionURISafe(reqBody.URI, ctx.Configuration.Session.Domain)
+	safe, err := utils.IsRedirectionURISafe(reqBody.URI, ctx.GetSessionDomains()...)
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to determine if uri %s is safe to redirect to: %w", reqBody.URI, err), messageOperationFailed)
 		return
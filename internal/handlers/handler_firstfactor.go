@@ -4,15 +4,24 @@ import (
 	"errors"
 	"time"
 
+	"github.com/valyala/fasthttp"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/regulation"
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
-// FirstFactorPost is the handler performing the first factory.
+// FirstFactorPost is the handler performing the first factory. If maxConcurrentRequests is greater than 0 it limits the
+// number of concurrent requests to the authentication backend.
 //nolint:gocyclo // TODO: Consider refactoring time permitting.
-func FirstFactorPost(delayFunc middlewares.TimingAttackDelayFunc) middlewares.RequestHandler {
+func FirstFactorPost(delayFunc middlewares.TimingAttackDelayFunc, maxConcurrentRequests int) middlewares.RequestHandler {
+	var semaphore chan struct{}
+
+	if maxConcurrentRequests > 0 {
+		semaphore = make(chan struct{}, maxConcurrentRequests)
+	}
+
 	return func(ctx *middlewares.AutheliaCtx) {
 		var successful bool
 
@@ -48,7 +57,16 @@ func FirstFactorPost(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 			return
 		}
 
-		userPasswordOk, err := ctx.Providers.UserProvider.CheckUserPassword(bodyJSON.Username, bodyJSON.Password)
+		userPasswordOk, err := firstFactorCheckUserPassword(ctx, semaphore, bodyJSON.Username, bodyJSON.Password)
+		if errors.Is(err, errFirstFactorQueueTimeout) {
+			ctx.Logger.Errorf("Failed to perform %s authentication for user '%s': %+v", regulation.AuthType1FA, bodyJSON.Username, err)
+
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+
 		if err != nil {
 			_ = markAuthenticationAttempt(ctx, false, nil, bodyJSON.Username, regulation.AuthType1FA, err)
 
@@ -95,10 +113,15 @@ func FirstFactorPost(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		// Check if bodyJSON.KeepMeLoggedIn can be deref'd and derive the value based on the configuration and JSON data.
 		keepMeLoggedIn := ctx.Providers.SessionProvider.RememberMe != schema.RememberMeDisabled && bodyJSON.KeepMeLoggedIn != nil && *bodyJSON.KeepMeLoggedIn
 
+		// When remember me requires two factor authentication the request is deferred until the second factor is completed.
+		keepMeLoggedInPending := keepMeLoggedIn && ctx.Configuration.Session.RememberMeRequireTwoFactor
+		if keepMeLoggedInPending {
+			keepMeLoggedIn = false
+		}
+
 		// Set the cookie to expire if remember me is enabled and the user has asked us to.
 		if keepMeLoggedIn {
-			err = ctx.Providers.SessionProvider.UpdateExpiration(ctx.RequestCtx, ctx.Providers.SessionProvider.RememberMe)
-			if err != nil {
+			if err = updateRememberMeExpiration(ctx); err != nil {
 				ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthType1FA, bodyJSON.Username, err)
 
 				respondUnauthorized(ctx, messageAuthenticationFailed)
@@ -120,6 +143,28 @@ func FirstFactorPost(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		ctx.Logger.Tracef(logFmtTraceProfileDetails, bodyJSON.Username, userDetails.Groups, userDetails.Emails)
 
 		userSession.SetOneFactor(ctx.Clock.Now(), userDetails, keepMeLoggedIn)
+		userSession.KeepMeLoggedInPending = keepMeLoggedInPending
+
+		handleUserSessionRecordFirstFactor(ctx, &userSession)
+
+		trusted, err := isTrustedDevice(ctx, userSession.Username)
+		if err != nil {
+			ctx.Logger.Warnf("Could not verify the trusted device of user '%s': %+v", userSession.Username, err)
+		}
+
+		if trusted {
+			ctx.Logger.Debugf("Skipping the second factor for user '%s' as the device is trusted", userSession.Username)
+
+			userSession.SetTwoFactorTrustedDevice(ctx.Clock.Now())
+
+			if err = handleRememberMeSecondFactor(ctx, &userSession); err != nil {
+				ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthType1FA, bodyJSON.Username, err)
+
+				respondUnauthorized(ctx, messageAuthenticationFailed)
+
+				return
+			}
+		}
 
 		if refresh, refreshInterval := getProfileRefreshSettings(ctx.Configuration.AuthenticationBackend); refresh {
 			userSession.RefreshTTL = ctx.Clock.Now().Add(refreshInterval)
@@ -135,10 +180,31 @@ func FirstFactorPost(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 
 		successful = true
 
-		if userSession.OIDCWorkflowSession != nil {
+		switch {
+		case userSession.OIDCWorkflowSession != nil:
 			handleOIDCWorkflowResponse(ctx)
-		} else {
+		case trusted:
+			Handle2FAResponse(ctx, bodyJSON.TargetURL)
+		default:
 			Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
 		}
 	}
 }
+
+// firstFactorCheckUserPassword checks the password of the user with the authentication backend. If the semaphore is not
+// nil it waits for a free slot for at most the queue timeout before giving up.
+func firstFactorCheckUserPassword(ctx *middlewares.AutheliaCtx, semaphore chan struct{}, username, password string) (valid bool, err error) {
+	if semaphore != nil {
+		timer := time.NewTimer(firstFactorQueueTimeout)
+		defer timer.Stop()
+
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+		case <-timer.C:
+			return false, errFirstFactorQueueTimeout
+		}
+	}
+
+	return ctx.Providers.UserProvider.CheckUserPassword(username, password)
+}
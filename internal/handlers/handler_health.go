@@ -1,6 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
@@ -8,3 +15,79 @@ import (
 func HealthGet(ctx *middlewares.AutheliaCtx) {
 	ctx.ReplyOK()
 }
+
+// HealthReadyGet can be used by readiness checks, it checks the storage and LDAP authentication backend connectivity.
+// The result is cached for healthReadyCacheDuration as the endpoint is not authenticated, which prevents it from being
+// used to send a query to the storage and a bind to the LDAP server for every request.
+func HealthReadyGet() middlewares.RequestHandler {
+	var (
+		mutex   sync.Mutex
+		expires time.Time
+		status  int
+		body    []byte
+	)
+
+	return func(ctx *middlewares.AutheliaCtx) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if now := ctx.Clock.Now(); body == nil || !now.Before(expires) {
+			var err error
+
+			if status, body, err = healthReadyCheck(ctx); err != nil {
+				ctx.Error(err, messageOperationFailed)
+
+				return
+			}
+
+			expires = now.Add(healthReadyCacheDuration)
+		}
+
+		ctx.SetStatusCode(status)
+		ctx.SetContentType("application/json")
+		ctx.SetBody(body)
+	}
+}
+
+func healthReadyCheck(ctx *middlewares.AutheliaCtx) (status int, b []byte, err error) {
+	body := healthReadyResponseBody{
+		Status: healthStatusOK,
+		Checks: map[string]string{},
+	}
+
+	if err = ctx.Providers.StorageProvider.Ping(ctx); err != nil {
+		ctx.Logger.Errorf("Readiness check failed for the storage provider: %+v", err)
+
+		body.Checks[healthCheckStorage] = healthStatusKO
+	} else {
+		body.Checks[healthCheckStorage] = healthStatusOK
+	}
+
+	if ctx.Configuration.AuthenticationBackend.LDAP != nil {
+		if provider, ok := ctx.Providers.UserProvider.(*authentication.LDAPUserProvider); ok {
+			if err = provider.CheckConnection(); err != nil {
+				ctx.Logger.Errorf("Readiness check failed for the LDAP authentication backend: %+v", err)
+
+				body.Checks[healthCheckLDAP] = healthStatusKO
+			} else {
+				body.Checks[healthCheckLDAP] = healthStatusOK
+			}
+		}
+	}
+
+	status = fasthttp.StatusOK
+
+	for _, check := range body.Checks {
+		if check != healthStatusOK {
+			body.Status, status = healthStatusKO, fasthttp.StatusServiceUnavailable
+
+			break
+		}
+	}
+
+	if b, err = json.Marshal(body); err != nil {
+		return 0, nil, err
+	}
+
+	return status, b, nil
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestShouldReplyOKWhenDependenciesAreReady(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.StorageMock.EXPECT().Ping(gomock.Eq(mock.Ctx)).Return(nil)
+
+	HealthReadyGet()(mock.Ctx)
+
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+	assert.JSONEq(t, `{"status":"OK","checks":{"storage":"OK"}}`, string(mock.Ctx.Response.Body()))
+}
+
+func TestShouldReplyServiceUnavailableWhenStorageIsNotReady(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.StorageMock.EXPECT().Ping(gomock.Eq(mock.Ctx)).Return(errors.New("connection refused"))
+
+	HealthReadyGet()(mock.Ctx)
+
+	assert.Equal(t, 503, mock.Ctx.Response.StatusCode())
+	assert.JSONEq(t, `{"status":"KO","checks":{"storage":"KO"}}`, string(mock.Ctx.Response.Body()))
+}
+
+func TestShouldCacheReadinessChecks(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Clock = &mock.Clock
+	mock.Clock.Set(time.Unix(1640000000, 0))
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().Ping(gomock.Eq(mock.Ctx)).Return(errors.New("connection refused")),
+		mock.StorageMock.EXPECT().Ping(gomock.Eq(mock.Ctx)).Return(nil),
+	)
+
+	handler := HealthReadyGet()
+
+	handler(mock.Ctx)
+
+	assert.Equal(t, 503, mock.Ctx.Response.StatusCode())
+
+	mock.Ctx.Response.Reset()
+	mock.Clock.Set(time.Unix(1640000000, 0).Add(healthReadyCacheDuration - time.Second))
+
+	handler(mock.Ctx)
+
+	assert.Equal(t, 503, mock.Ctx.Response.StatusCode())
+	assert.JSONEq(t, `{"status":"KO","checks":{"storage":"KO"}}`, string(mock.Ctx.Response.Body()))
+
+	mock.Ctx.Response.Reset()
+	mock.Clock.Set(time.Unix(1640000000, 0).Add(healthReadyCacheDuration))
+
+	handler(mock.Ctx)
+
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+	assert.JSONEq(t, `{"status":"OK","checks":{"storage":"OK"}}`, string(mock.Ctx.Response.Body()))
+}
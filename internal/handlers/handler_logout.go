@@ -26,6 +26,10 @@ func LogoutPost(ctx *middlewares.AutheliaCtx) {
 		ctx.Error(fmt.Errorf("unable to parse body during logout: %s", err), messageOperationFailed)
 	}
 
+	userSession := ctx.GetSession()
+
+	handleUserSessionRecordLogout(ctx, &userSession)
+
 	err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx)
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to destroy session during logout: %s", err), messageOperationFailed)
@@ -33,7 +37,7 @@ func LogoutPost(ctx *middlewares.AutheliaCtx) {
 
 	redirectionURL, err := url.Parse(body.TargetURL)
 	if err == nil {
-		responseBody.SafeTargetURL = utils.IsRedirectionSafe(*redirectionURL, ctx.Configuration.Session.Domain)
+		responseBody.SafeTargetURL = utils.IsRedirectionSafe(*redirectionURL, ctx.GetSessionDomains()...)
 	}
 
 	if body.TargetURL != "" {
@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/ory/fosite"
 
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
@@ -40,6 +42,30 @@ func oidcAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, r *
 
 	ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' is being processed", requester.GetID(), clientID)
 
+	if err = oidcAuthorizationValidateRequestSize(ctx.Configuration.IdentityProviders.OIDC, requester); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), clientID, fosite.ErrorToRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, err)
+
+		return
+	}
+
+	if err = oidcAuthorizationValidateResponseType(ctx.Configuration.IdentityProviders.OIDC, requester); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), clientID, fosite.ErrorToRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, err)
+
+		return
+	}
+
+	if err = oidcAuthorizationValidateImplicitFlow(ctx.Configuration.IdentityProviders.OIDC, requester); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), clientID, fosite.ErrorToRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, err)
+
+		return
+	}
+
 	if client, err = ctx.Providers.OpenIDConnect.Store.GetInternalClient(clientID); err != nil {
 		if errors.Is(err, fosite.ErrNotFound) {
 			ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: client was not found", requester.GetID(), clientID)
@@ -52,6 +78,22 @@ func oidcAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, r *
 		return
 	}
 
+	if err = oidcAuthorizationValidateState(ctx.Configuration.IdentityProviders.OIDC, client, requester); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), clientID, fosite.ErrorToRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, err)
+
+		return
+	}
+
+	if err = oidcAuthorizationValidateNetwork(client, ctx.RemoteIP()); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), clientID, fosite.ErrorToRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, err)
+
+		return
+	}
+
 	if issuer, err = ctx.ExternalRootURL(); err != nil {
 		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred determining issuer: %+v", requester.GetID(), clientID, err)
 
@@ -73,7 +115,11 @@ func oidcAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, r *
 		return
 	}
 
-	extraClaims := oidcGrantRequests(requester, requestedScopes, requestedAudience, &userSession)
+	extraClaims := oidcGrantRequests(requester, userSession.OIDCWorkflowSession.GrantedScopes, requestedAudience, ctx.Configuration.IdentityProviders.OIDC.PreferredUsernameClaim, &userSession)
+
+	for claim, value := range client.Claims {
+		extraClaims[claim] = value
+	}
 
 	workflowCreated := time.Unix(userSession.OIDCWorkflowSession.CreatedTimestamp, 0)
 
@@ -98,13 +144,23 @@ func oidcAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, r *
 	ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' was successfully processed, proceeding to build Authorization Response", requester.GetID(), clientID)
 
 	subject := userSession.Username
-	oidcSession := oidc.NewSessionWithAuthorizeRequest(issuer, ctx.Providers.OpenIDConnect.KeyManager.GetActiveKeyID(),
+	oidcSession := oidc.NewSessionWithAuthorizeRequest(issuer, client.GetSigningKeyID(ctx.Providers.OpenIDConnect.KeyManager.GetActiveKeyID()),
 		subject, userSession.Username, userSession.AuthenticationMethodRefs.MarshalRFC8176(), extraClaims, authTime, workflowCreated, requester)
 
-	ctx.Logger.Tracef("Authorization Request with id '%s' on client with id '%s' creating session for Authorization Response for subject '%s' with username '%s' with claims: %+v",
-		requester.GetID(), oidcSession.ClientID, oidcSession.Subject, oidcSession.Username, oidcSession.Claims)
-	ctx.Logger.Tracef("Authorization Request with id '%s' on client with id '%s' creating session for Authorization Response for subject '%s' with username '%s' with headers: %+v",
-		requester.GetID(), oidcSession.ClientID, oidcSession.Subject, oidcSession.Username, oidcSession.Headers)
+	client.SetSessionLifespan(oidcSession, fosite.IDToken, ctx.Clock.Now())
+
+	if client.RefreshSessionActivity {
+		if oidcSession.SessionID, err = ctx.Providers.SessionProvider.GetSessionID(ctx.RequestCtx); err != nil {
+			ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not determine the session id to refresh the session activity: %+v", requester.GetID(), client.GetID(), err)
+		}
+	}
+
+	maximumDebugBodyLength := ctx.Configuration.IdentityProviders.OIDC.MaximumDebugBodyLength
+
+	ctx.Logger.Tracef("Authorization Request with id '%s' on client with id '%s' creating session for Authorization Response for subject '%s' with username '%s' with claims: %s",
+		requester.GetID(), oidcSession.ClientID, oidcSession.Subject, oidcSession.Username, oidc.DebugBody(oidcSession.Claims.ToMap(), maximumDebugBodyLength))
+	ctx.Logger.Tracef("Authorization Request with id '%s' on client with id '%s' creating session for Authorization Response for subject '%s' with username '%s' with headers: %s",
+		requester.GetID(), oidcSession.ClientID, oidcSession.Subject, oidcSession.Username, oidc.DebugBody(oidcSession.Headers.ToMap(), maximumDebugBodyLength))
 
 	if responder, err = ctx.Providers.OpenIDConnect.Fosite.NewAuthorizeResponse(ctx, requester, oidcSession); err != nil {
 		rfc := fosite.ErrorToRFC6749Error(err)
@@ -119,6 +175,88 @@ func oidcAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, r *
 	ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeResponse(rw, requester, responder)
 }
 
+// oidcAuthorizationValidateResponseType ensures the requested response type is one of the response types allowed
+// provider wide, regardless of the response types allowed for the client.
+func oidcAuthorizationValidateResponseType(config *schema.OpenIDConnectConfiguration, requester fosite.AuthorizeRequester) error {
+	if config == nil || len(config.AllowedResponseTypes) == 0 {
+		return nil
+	}
+
+	for _, responseType := range config.AllowedResponseTypes {
+		if requester.GetResponseTypes().Matches(strings.Fields(responseType)...) {
+			return nil
+		}
+	}
+
+	return fosite.ErrUnsupportedResponseType.WithHintf("The response type '%s' is not allowed.", strings.Join(requester.GetResponseTypes(), " "))
+}
+
+// oidcAuthorizationValidateImplicitFlow rejects authorization requests using the implicit or hybrid flows, i.e. requests
+// which request a token directly from the authorization endpoint, when the implicit flow is disabled.
+func oidcAuthorizationValidateImplicitFlow(config *schema.OpenIDConnectConfiguration, requester fosite.AuthorizeRequester) error {
+	if config == nil || !config.DisableImplicitFlow {
+		return nil
+	}
+
+	if requester.GetResponseTypes().HasOneOf(oidcResponseTypeToken, oidcResponseTypeIDToken) {
+		return fosite.ErrUnsupportedResponseType.WithHint("The implicit flow is disabled.")
+	}
+
+	return nil
+}
+
+// oidcAuthorizationValidateState ensures the state parameter is present and meets the minimum parameter entropy when
+// the client requires it, even if the minimum parameter entropy has been disabled globally.
+func oidcAuthorizationValidateState(config *schema.OpenIDConnectConfiguration, client *oidc.InternalClient, requester fosite.AuthorizeRequester) error {
+	if client == nil || !client.RequireState {
+		return nil
+	}
+
+	minimum := fosite.MinParameterEntropy
+	if config != nil && config.MinimumParameterEntropy > 0 {
+		minimum = config.MinimumParameterEntropy
+	}
+
+	state := requester.GetState()
+
+	switch {
+	case state == "":
+		return fosite.ErrInvalidState.WithHint("The client requires the 'state' parameter but it was not provided.")
+	case len(state) < minimum:
+		return fosite.ErrInvalidState.WithHintf("Request parameter 'state' must be at least be %d characters long to ensure sufficient entropy.", minimum)
+	}
+
+	return nil
+}
+
+// oidcAuthorizationValidateNetwork ensures the remote IP of the request is in one of the networks the client is
+// allowed to be used from.
+func oidcAuthorizationValidateNetwork(client *oidc.InternalClient, ip net.IP) error {
+	if client == nil || client.IsNetworkAllowed(ip) {
+		return nil
+	}
+
+	return fosite.ErrAccessDenied.WithHintf("The client is not allowed to be used from the network of IP '%s'.", ip)
+}
+
+// oidcAuthorizationValidateRequestSize ensures the number of requested scopes and audiences does not exceed the
+// configured maximums before a consent session is created for the request.
+func oidcAuthorizationValidateRequestSize(config *schema.OpenIDConnectConfiguration, requester fosite.AuthorizeRequester) error {
+	if config == nil {
+		return nil
+	}
+
+	if n := len(requester.GetRequestedScopes()); config.MaximumRequestedScopes > 0 && n > config.MaximumRequestedScopes {
+		return fosite.ErrInvalidScope.WithHintf("The request has %d scopes which exceeds the maximum of %d.", n, config.MaximumRequestedScopes)
+	}
+
+	if n := len(requester.GetRequestedAudience()); config.MaximumRequestedAudiences > 0 && n > config.MaximumRequestedAudiences {
+		return fosite.ErrInvalidRequest.WithHintf("The request has %d audiences which exceeds the maximum of %d.", n, config.MaximumRequestedAudiences)
+	}
+
+	return nil
+}
+
 func oidcAuthorizeHandleAuthorizationOrConsentInsufficient(
 	ctx *middlewares.AutheliaCtx, userSession session.UserSession, client *oidc.InternalClient, isAuthInsufficient bool,
 	rw http.ResponseWriter, r *http.Request,
@@ -135,6 +273,7 @@ func oidcAuthorizeHandleAuthorizationOrConsentInsufficient(
 		AuthURI:           redirectURL,
 		TargetURI:         requester.GetRedirectURI().String(),
 		Require2FA:        client.Policy == authorization.TwoFactor,
+		LoginHint:         oidcSanitizeLoginHint(requester.GetRequestForm().Get(oidcFormParameterLoginHint)),
 		CreatedTimestamp:  time.Now().Unix(),
 	}
 
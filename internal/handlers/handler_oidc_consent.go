@@ -2,11 +2,63 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
+// isConsentRegulationEnabled returns true if suspicious consent attempts should be regulated.
+func isConsentRegulationEnabled(ctx *middlewares.AutheliaCtx) bool {
+	return ctx.Configuration.IdentityProviders.OIDC != nil && ctx.Configuration.IdentityProviders.OIDC.EnableConsentRegulation
+}
+
+// isConsentBanned returns true if consent regulation is enabled and the user is currently banned by the regulator.
+func isConsentBanned(ctx *middlewares.AutheliaCtx, userSession session.UserSession) bool {
+	if !isConsentRegulationEnabled(ctx) {
+		return false
+	}
+
+	bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, userSession.Username)
+	if err != nil && errors.Is(err, regulation.ErrUserIsBanned) {
+		ctx.Logger.Errorf("Cannot consent for user %s as they are banned until %s", userSession.Username, bannedUntil)
+
+		return true
+	}
+
+	return false
+}
+
+// isConsentRateLimited returns true and replies with a 429 response if the consent requests of the user for the client
+// of the workflow exceed the consent rate limit.
+func isConsentRateLimited(ctx *middlewares.AutheliaCtx, userSession session.UserSession) bool {
+	limiter := ctx.Providers.OpenIDConnect.ConsentRateLimiter
+	if limiter == nil {
+		return false
+	}
+
+	allowed, retryAfter := limiter.Allow(userSession.Username+"|"+userSession.OIDCWorkflowSession.ClientID, ctx.Clock.Now())
+	if allowed {
+		return false
+	}
+
+	ctx.Logger.Errorf("Consent request for user %s on client with id '%s' was rate limited", userSession.Username, userSession.OIDCWorkflowSession.ClientID)
+
+	ctx.RequestCtx.Error(fasthttp.StatusMessage(fasthttp.StatusTooManyRequests), fasthttp.StatusTooManyRequests)
+	ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+	return true
+}
+
 func oidcConsent(ctx *middlewares.AutheliaCtx) {
 	userSession := ctx.GetSession()
 
@@ -17,6 +69,16 @@ func oidcConsent(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	if isConsentBanned(ctx, userSession) {
+		ctx.ReplyForbidden()
+
+		return
+	}
+
+	if isConsentRateLimited(ctx, userSession) {
+		return
+	}
+
 	clientID := userSession.OIDCWorkflowSession.ClientID
 	client, err := ctx.Providers.OpenIDConnect.Store.GetInternalClient(clientID)
 
@@ -49,6 +111,16 @@ func oidcConsentPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	if isConsentBanned(ctx, userSession) {
+		ctx.ReplyForbidden()
+
+		return
+	}
+
+	if isConsentRateLimited(ctx, userSession) {
+		return
+	}
+
 	client, err := ctx.Providers.OpenIDConnect.Store.GetInternalClient(userSession.OIDCWorkflowSession.ClientID)
 
 	if err != nil {
@@ -83,6 +155,12 @@ func oidcConsentPOST(ctx *middlewares.AutheliaCtx) {
 	if userSession.OIDCWorkflowSession.ClientID != body.ClientID {
 		ctx.Logger.Infof("User %s consented to scopes of another client (%s) than expected (%s). Beware this can be a sign of attack",
 			userSession.Username, body.ClientID, userSession.OIDCWorkflowSession.ClientID)
+
+		if isConsentRegulationEnabled(ctx) {
+			_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeConsent,
+				fmt.Errorf("consent was submitted for client '%s' but the expected client was '%s'", body.ClientID, userSession.OIDCWorkflowSession.ClientID))
+		}
+
 		ctx.ReplyBadRequest()
 
 		return
@@ -91,23 +169,45 @@ func oidcConsentPOST(ctx *middlewares.AutheliaCtx) {
 	var redirectionURL string
 
 	if body.AcceptOrReject == accept {
+		for _, scope := range body.GrantedScopes {
+			if !utils.IsStringInSlice(scope, userSession.OIDCWorkflowSession.RequestedScopes) {
+				ctx.Logger.Infof("User %s tried to grant the scope '%s' to client %s which was not requested",
+					userSession.Username, scope, userSession.OIDCWorkflowSession.ClientID)
+				ctx.ReplyBadRequest()
+
+				return
+			}
+		}
+
 		redirectionURL = userSession.OIDCWorkflowSession.AuthURI
-		userSession.OIDCWorkflowSession.GrantedScopes = userSession.OIDCWorkflowSession.RequestedScopes
-		userSession.OIDCWorkflowSession.GrantedAudience = userSession.OIDCWorkflowSession.RequestedAudience
 
-		if err := ctx.SaveSession(userSession); err != nil {
-			ctx.Error(fmt.Errorf("unable to write session: %v", err), "Operation failed")
-			return
+		if body.GrantedScopes != nil {
+			userSession.OIDCWorkflowSession.GrantedScopes = body.GrantedScopes
+		} else {
+			userSession.OIDCWorkflowSession.GrantedScopes = userSession.OIDCWorkflowSession.RequestedScopes
 		}
-	} else if body.AcceptOrReject == reject {
+
+		userSession.OIDCWorkflowSession.GrantedAudience = userSession.OIDCWorkflowSession.RequestedAudience
+	}
+
+	workflow := userSession.OIDCWorkflowSession
+
+	if body.AcceptOrReject == reject {
 		redirectionURL = fmt.Sprintf("%s?error=access_denied&error_description=%s",
 			userSession.OIDCWorkflowSession.TargetURI, "User has rejected the scopes")
 		userSession.OIDCWorkflowSession = nil
+	}
 
-		if err := ctx.SaveSession(userSession); err != nil {
-			ctx.Error(fmt.Errorf("unable to write session: %v", err), "Operation failed")
-			return
-		}
+	if err := ctx.SaveSession(userSession); err != nil {
+		ctx.Error(fmt.Errorf("unable to write session: %v", err), "Operation failed")
+		return
+	}
+
+	// The decision is only recorded once it has been saved, so the audit log never contains a decision which didn't
+	// take effect.
+	if err = oidcConsentAudit(ctx, userSession.Username, workflow, body.AcceptOrReject); err != nil {
+		ctx.Error(fmt.Errorf("unable to write consent audit log: %w", err), "Operation failed")
+		return
 	}
 
 	response := ConsentPostResponseBody{RedirectURI: redirectionURL}
@@ -116,3 +216,25 @@ func oidcConsentPOST(ctx *middlewares.AutheliaCtx) {
 		ctx.Error(fmt.Errorf("unable to set JSON body in response"), "Operation failed")
 	}
 }
+
+// oidcConsentAudit writes the consent decision to the consent audit log if it's configured.
+func oidcConsentAudit(ctx *middlewares.AutheliaCtx, username string, workflow *model.OIDCWorkflowSession, decision string) (err error) {
+	if ctx.Providers.OpenIDConnect.ConsentAuditLog == nil {
+		return nil
+	}
+
+	record := oidc.ConsentAuditRecord{
+		Time:              ctx.Clock.Now(),
+		Username:          username,
+		ClientID:          workflow.ClientID,
+		Decision:          decision,
+		RequestedScopes:   workflow.RequestedScopes,
+		RequestedAudience: workflow.RequestedAudience,
+	}
+
+	if decision == accept {
+		record.GrantedScopes, record.GrantedAudience = workflow.GrantedScopes, workflow.GrantedAudience
+	}
+
+	return ctx.Providers.OpenIDConnect.ConsentAuditLog.Write(record)
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+func newConsentRegulationMock(t *testing.T) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t)
+	mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{EnableConsentRegulation: true}
+	mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{
+		MaxRetries: 3,
+		FindTime:   time.Minute * 2,
+		BanTime:    time.Minute * 5,
+	}, mock.StorageMock, &mock.Clock)
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.FirstFactorAuthnTimestamp = mock.Clock.Now().Unix()
+	userSession.OIDCWorkflowSession = &model.OIDCWorkflowSession{
+		ClientID:        "test",
+		RequestedScopes: []string{"openid"},
+		AuthURI:         "https://auth.example.com/api/oidc/authorization",
+		TargetURI:       "https://app.example.com/callback",
+	}
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	return mock
+}
+
+func TestShouldMarkFailedAttemptWhenConsentClientMismatch(t *testing.T) {
+	mock := newConsentRegulationMock(t)
+	defer mock.Close()
+
+	mock.StorageMock.EXPECT().
+		LoadAuthenticationLogs(mock.Ctx, testUsername, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]model.AuthenticationAttempt{}, nil)
+
+	mock.StorageMock.EXPECT().
+		AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+			assert.Equal(t, testUsername, attempt.Username)
+			assert.Equal(t, regulation.AuthTypeConsent, attempt.Type)
+			assert.False(t, attempt.Successful)
+
+			return nil
+		})
+
+	mock.Ctx.Request.SetBodyString(`{"client_id":"other","accept_or_reject":"accept"}`)
+
+	oidcConsentPOST(mock.Ctx)
+
+	assert.Equal(t, 400, mock.Ctx.Response.StatusCode())
+}
+
+func TestShouldNotMarkFailedAttemptWhenConsentRegulationDisabled(t *testing.T) {
+	mock := newConsentRegulationMock(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.IdentityProviders.OIDC.EnableConsentRegulation = false
+
+	mock.Ctx.Request.SetBodyString(`{"client_id":"other","accept_or_reject":"accept"}`)
+
+	oidcConsentPOST(mock.Ctx)
+
+	assert.Equal(t, 400, mock.Ctx.Response.StatusCode())
+}
+
+func TestShouldForbidConsentWhenUserIsBanned(t *testing.T) {
+	mock := newConsentRegulationMock(t)
+	defer mock.Close()
+
+	attempts := []model.AuthenticationAttempt{
+		{Successful: false, Time: mock.Clock.Now().Add(-10 * time.Second)},
+		{Successful: false, Time: mock.Clock.Now().Add(-20 * time.Second)},
+		{Successful: false, Time: mock.Clock.Now().Add(-30 * time.Second)},
+	}
+
+	mock.StorageMock.EXPECT().
+		LoadAuthenticationLogs(mock.Ctx, testUsername, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(attempts, nil).
+		Times(2)
+
+	oidcConsent(mock.Ctx)
+
+	assert.Equal(t, 403, mock.Ctx.Response.StatusCode())
+
+	mock.Ctx.Response.Reset()
+	mock.Ctx.Request.SetBodyString(`{"client_id":"test","accept_or_reject":"accept"}`)
+
+	oidcConsentPOST(mock.Ctx)
+
+	assert.Equal(t, 403, mock.Ctx.Response.StatusCode())
+}
+
+func TestShouldWriteConsentDecisionToAuditLog(t *testing.T) {
+	testCases := []struct {
+		decision      string
+		grantedScopes []string
+	}{
+		{accept, []string{"openid"}},
+		{reject, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.decision, func(t *testing.T) {
+			mock := newConsentRegulationMock(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.IdentityProviders.OIDC.EnableConsentRegulation = false
+			mock.Ctx.Clock = &mock.Clock
+
+			path := filepath.Join(t.TempDir(), "consent.log")
+
+			auditLog, err := oidc.NewConsentAuditLog(path)
+			require.NoError(t, err)
+
+			mock.Ctx.Providers.OpenIDConnect.ConsentAuditLog = auditLog
+
+			mock.Ctx.Request.SetBodyString(fmt.Sprintf(`{"client_id":"test","accept_or_reject":"%s"}`, tc.decision))
+
+			oidcConsentPOST(mock.Ctx)
+
+			assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			var record oidc.ConsentAuditRecord
+
+			require.NoError(t, json.Unmarshal(data, &record))
+
+			assert.True(t, mock.Clock.Now().Equal(record.Time))
+			assert.Equal(t, testUsername, record.Username)
+			assert.Equal(t, "test", record.ClientID)
+			assert.Equal(t, tc.decision, record.Decision)
+			assert.Equal(t, []string{"openid"}, record.RequestedScopes)
+			assert.Equal(t, tc.grantedScopes, record.GrantedScopes)
+		})
+	}
+}
+
+func TestShouldGrantOnlyTheConsentedScopes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{"ShouldGrantAllWhenOmitted", `{"client_id":"test","accept_or_reject":"accept"}`, []string{"openid", "profile", "email"}},
+		{"ShouldGrantSubset", `{"client_id":"test","accept_or_reject":"accept","granted_scopes":["openid","email"]}`, []string{"openid", "email"}},
+		{"ShouldGrantNone", `{"client_id":"test","accept_or_reject":"accept","granted_scopes":[]}`, []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newConsentRegulationMock(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.IdentityProviders.OIDC.EnableConsentRegulation = false
+
+			userSession := mock.Ctx.GetSession()
+			userSession.OIDCWorkflowSession.RequestedScopes = []string{"openid", "profile", "email"}
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			oidcConsentPOST(mock.Ctx)
+
+			assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+
+			userSession = mock.Ctx.GetSession()
+			require.NotNil(t, userSession.OIDCWorkflowSession)
+			assert.Equal(t, tc.expected, userSession.OIDCWorkflowSession.GrantedScopes)
+			assert.False(t, isConsentMissing(userSession.OIDCWorkflowSession, []string{"openid", "profile", "email"}, nil))
+		})
+	}
+}
+
+func TestShouldRejectConsentGrantingScopesNotRequested(t *testing.T) {
+	mock := newConsentRegulationMock(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.IdentityProviders.OIDC.EnableConsentRegulation = false
+
+	mock.Ctx.Request.SetBodyString(`{"client_id":"test","accept_or_reject":"accept","granted_scopes":["openid","groups"]}`)
+
+	oidcConsentPOST(mock.Ctx)
+
+	assert.Equal(t, 400, mock.Ctx.Response.StatusCode())
+
+	userSession := mock.Ctx.GetSession()
+	require.NotNil(t, userSession.OIDCWorkflowSession)
+	assert.Nil(t, userSession.OIDCWorkflowSession.GrantedScopes)
+}
+
+func TestShouldRateLimitConsentPerUserAndClient(t *testing.T) {
+	mock := newConsentRegulationMock(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.IdentityProviders.OIDC.EnableConsentRegulation = false
+	mock.Ctx.Providers.OpenIDConnect.ConsentRateLimiter = oidc.NewKeyedRateLimiter(0.5, 2)
+	mock.Ctx.Clock = &mock.Clock
+
+	oidcConsent(mock.Ctx)
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+
+	mock.Ctx.Response.Reset()
+
+	oidcConsent(mock.Ctx)
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+
+	mock.Ctx.Response.Reset()
+	mock.Ctx.Request.SetBodyString(`{"client_id":"test","accept_or_reject":"accept"}`)
+
+	oidcConsentPOST(mock.Ctx)
+	assert.Equal(t, 429, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "2", string(mock.Ctx.Response.Header.Peek("Retry-After")))
+
+	userSession := mock.Ctx.GetSession()
+	userSession.OIDCWorkflowSession.ClientID = "other"
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.Ctx.Response.Reset()
+
+	oidcConsent(mock.Ctx)
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode(), "the requests of the user for another client must not be limited")
+
+	userSession.OIDCWorkflowSession.ClientID = "test"
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.Clock.Set(mock.Clock.Now().Add(time.Second * 2))
+	mock.Ctx.Response.Reset()
+
+	oidcConsent(mock.Ctx)
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/ory/fosite"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func oidcIntrospection(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
@@ -31,5 +33,46 @@ func oidcIntrospection(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req
 
 	ctx.Logger.Tracef("Introspection Request yeilded a %s (active: %t) requested at %s created with request id '%s' on client with id '%s'", responder.GetTokenUse(), responder.IsActive(), requester.GetRequestedAt().String(), requester.GetID(), requester.GetClient().GetID())
 
+	if responder.IsActive() && ctx.Configuration.IdentityProviders.OIDC != nil && ctx.Configuration.IdentityProviders.OIDC.EnforceIntrospectionAudience {
+		clientID := oidcIntrospectionClientID(ctx, req)
+
+		if !isIntrospectionAudienceAllowed(clientID, requester) {
+			ctx.Logger.Errorf("Introspection Request by client with id '%s' for a token created with request id '%s' on client with id '%s' was denied: the client is not in the token audience", clientID, requester.GetID(), requester.GetClient().GetID())
+
+			ctx.Providers.OpenIDConnect.Fosite.WriteIntrospectionResponse(rw, &fosite.IntrospectionResponse{Active: false})
+
+			return
+		}
+	}
+
 	ctx.Providers.OpenIDConnect.Fosite.WriteIntrospectionResponse(rw, responder)
 }
+
+// oidcIntrospectionClientID returns the id of the client performing an introspection request which has already been
+// authenticated, either using the HTTP basic authorization header or a bearer access token.
+func oidcIntrospectionClientID(ctx *middlewares.AutheliaCtx, req *http.Request) (clientID string) {
+	if token := fosite.AccessTokenFromRequest(req); token != "" {
+		_, requester, err := ctx.Providers.OpenIDConnect.Fosite.IntrospectToken(ctx, token, fosite.AccessToken, oidc.NewSession())
+		if err != nil {
+			return ""
+		}
+
+		return requester.GetClient().GetID()
+	}
+
+	if id, _, ok := req.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(id)
+	}
+
+	return clientID
+}
+
+// isIntrospectionAudienceAllowed returns true if the client is the client the token was issued to or is in the granted
+// audience of the token.
+func isIntrospectionAudienceAllowed(clientID string, requester fosite.Requester) bool {
+	if clientID == "" {
+		return false
+	}
+
+	return clientID == requester.GetClient().GetID() || utils.IsStringInSlice(clientID, requester.GetGrantedAudience())
+}
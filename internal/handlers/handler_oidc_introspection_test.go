@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldCheckIntrospectionAudience(t *testing.T) {
+	requester := &fosite.Request{
+		Client:          &fosite.DefaultClient{ID: "app"},
+		GrantedAudience: fosite.Arguments{"app", "api"},
+	}
+
+	assert.True(t, isIntrospectionAudienceAllowed("app", requester))
+	assert.True(t, isIntrospectionAudienceAllowed("api", requester))
+	assert.False(t, isIntrospectionAudienceAllowed("other-api", requester))
+	assert.False(t, isIntrospectionAudienceAllowed("", requester))
+
+	requester.GrantedAudience = nil
+
+	assert.True(t, isIntrospectionAudienceAllowed("app", requester))
+	assert.False(t, isIntrospectionAudienceAllowed("api", requester))
+}
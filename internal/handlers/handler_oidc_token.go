@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/oidc"
@@ -16,12 +21,32 @@ func oidcToken(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.R
 		err       error
 	)
 
+	if limiter := ctx.Providers.OpenIDConnect.TokenEndpointRateLimiter; limiter != nil {
+		remoteIP := ctx.Providers.OpenIDConnect.TokenEndpointRemoteIP(ctx.RequestCtx.RemoteIP(), ctx.Request.Header.Peek(fasthttp.HeaderXForwardedFor))
+
+		if allowed, retryAfter := limiter.Allow(remoteIP.String(), ctx.Clock.Now()); !allowed {
+			ctx.Logger.Errorf("Access Request from '%s' was rate limited", remoteIP)
+
+			rw.Header().Set(fasthttp.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+			ctx.Providers.OpenIDConnect.Fosite.WriteAccessError(rw, nil, oidc.ErrTokenEndpointRateLimited)
+
+			return
+		}
+	}
+
 	oidcSession := oidc.NewSession()
 
 	if requester, err = ctx.Providers.OpenIDConnect.Fosite.NewAccessRequest(ctx, req, oidcSession); err != nil {
-		rfc := fosite.ErrorToRFC6749Error(err)
+		var limited *oidc.RateLimitedError
 
-		ctx.Logger.Errorf("Access Request failed with error: %+v", rfc)
+		if errors.As(err, &limited) {
+			ctx.Logger.Errorf("Access Request with id '%s' on client with id '%s' was rate limited", requester.GetID(), requester.GetClient().GetID())
+
+			rw.Header().Set(fasthttp.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+		} else {
+			ctx.Logger.Errorf("Access Request failed with error: %+v", fosite.ErrorToRFC6749Error(err))
+		}
 
 		ctx.Providers.OpenIDConnect.Fosite.WriteAccessError(rw, requester, err)
 
@@ -30,6 +55,22 @@ func oidcToken(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.R
 
 	client := requester.GetClient()
 
+	if c, ok := client.(*oidc.InternalClient); ok && requester.GetGrantTypes().ExactOne("refresh_token") {
+		// The requested at claim of the session is the time of the original authorization and is retained on every
+		// refresh, so it's used as the original grant time.
+		if session, ok := requester.GetSession().(openid.Session); ok && c.IsRefreshTokenAbsoluteLifetimeExceeded(session.IDTokenClaims().RequestedAt, ctx.Clock.Now()) {
+			ctx.Logger.Errorf("Access Request with id '%s' on client with id '%s' could not be processed: the refresh token has exceeded the absolute lifetime of the client", requester.GetID(), client.GetID())
+
+			ctx.Providers.OpenIDConnect.Fosite.WriteAccessError(rw, requester, fosite.ErrInvalidGrant.WithHint("The refresh token has exceeded the absolute lifetime permitted for this client."))
+
+			return
+		}
+	}
+
+	if c, ok := client.(*oidc.InternalClient); ok {
+		c.SetSessionLifespan(requester.GetSession(), fosite.IDToken, ctx.Clock.Now())
+	}
+
 	ctx.Logger.Debugf("Access Request with id '%s' on client with id '%s' is being processed", requester.GetID(), client.GetID())
 
 	// If this is a client_credentials grant, grant all scopes the client is allowed to perform.
@@ -53,7 +94,12 @@ func oidcToken(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.R
 
 	ctx.Logger.Debugf("Access Request with id '%s' on client with id '%s' has successfully been processed", requester.GetID(), client.GetID())
 
-	ctx.Logger.Tracef("Access Request with id '%s' on client with id '%s' produced the following claims: %+v", requester.GetID(), client.GetID(), responder.ToMap())
+	if c, ok := client.(*oidc.InternalClient); ok {
+		oidcRefreshSessionActivity(ctx, c, requester)
+	}
+
+	ctx.Logger.Tracef("Access Request with id '%s' on client with id '%s' produced the following claims: %s", requester.GetID(), client.GetID(),
+		oidc.DebugBody(responder.ToMap(), ctx.Configuration.IdentityProviders.OIDC.MaximumDebugBodyLength))
 
 	ctx.Providers.OpenIDConnect.Fosite.WriteAccessResponse(rw, requester, responder)
 }
@@ -57,6 +57,8 @@ func oidcUserinfo(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *htt
 		return
 	}
 
+	oidcRefreshSessionActivity(ctx, client, requester)
+
 	claims := requester.GetSession().(*oidc.OpenIDSession).IDTokenClaims().ToMap()
 	delete(claims, "jti")
 	delete(claims, "sid")
@@ -90,7 +92,8 @@ func oidcUserinfo(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *htt
 		keyID, token string
 	)
 
-	ctx.Logger.Tracef("UserInfo Response with id '%s' on client with id '%s' is being sent with the following claims: %+v", requester.GetID(), clientID, claims)
+	ctx.Logger.Tracef("UserInfo Response with id '%s' on client with id '%s' is being sent with the following claims: %s", requester.GetID(), clientID,
+		oidc.DebugBody(claims, ctx.Configuration.IdentityProviders.OIDC.MaximumDebugBodyLength))
 
 	switch client.UserinfoSigningAlgorithm {
 	case "RS256":
@@ -112,7 +115,7 @@ func oidcUserinfo(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *htt
 		}
 
 		headers := &jwt.Headers{
-			Extra: map[string]interface{}{"kid": keyID},
+			Extra: map[string]interface{}{"kid": client.GetSigningKeyID(keyID)},
 		}
 
 		if token, _, err = ctx.Providers.OpenIDConnect.KeyManager.Strategy().Generate(req.Context(), claims, headers); err != nil {
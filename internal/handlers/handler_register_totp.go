@@ -5,6 +5,7 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
@@ -51,6 +52,10 @@ func secondFactorTOTPIdentityFinish(ctx *middlewares.AutheliaCtx, username strin
 		return
 	}
 
+	userSession := ctx.GetSession()
+
+	notifyDeviceChange(ctx, &userSession, regulation.AuthTypeTOTP, deviceChangeActionAdded)
+
 	response := TOTPKeyResponse{
 		OTPAuthURL:   config.URI(),
 		Base32Secret: string(config.Secret),
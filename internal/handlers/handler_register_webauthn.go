@@ -136,6 +136,14 @@ func SecondFactorWebauthnAttestationPOST(ctx *middlewares.AutheliaCtx) {
 
 	device := model.NewWebauthnDeviceFromCredential(w.Config.RPID, userSession.Username, "Primary", credential)
 
+	if !isWebauthnAAGUIDAllowed(ctx.Configuration.Webauthn.AllowedAAGUIDs, device.AAGUID) {
+		ctx.Logger.Errorf("Unable to register %s device for user '%s': the authenticator AAGUID '%s' is not in the list of allowed AAGUIDs", regulation.AuthTypeWebauthn, userSession.Username, device.AAGUID)
+
+		respondUnauthorized(ctx, messageSecurityKeyNotAllowed)
+
+		return
+	}
+
 	if err = ctx.Providers.StorageProvider.SaveWebauthnDevice(ctx, device); err != nil {
 		ctx.Logger.Errorf("Unable to load %s devices for assertion challenge for user '%s': %+v", regulation.AuthTypeWebauthn, userSession.Username, err)
 
@@ -153,6 +161,8 @@ func SecondFactorWebauthnAttestationPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	notifyDeviceChange(ctx, &userSession, regulation.AuthTypeWebauthn, deviceChangeActionAdded)
+
 	ctx.ReplyOK()
 	ctx.SetStatusCode(fasthttp.StatusCreated)
 }
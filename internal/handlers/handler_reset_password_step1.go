@@ -42,6 +42,7 @@ var ResetPasswordIdentityStart = middlewares.IdentityVerificationStart(middlewar
 	TargetEndpoint:        "/reset-password/step2",
 	ActionClaim:           ActionResetPassword,
 	IdentityRetrieverFunc: identityRetrieverFromStorage,
+	RetryNotification:     true,
 }, middlewares.TimingAttackDelay(10, 250, 85, time.Millisecond*500))
 
 func resetPasswordIdentityFinish(ctx *middlewares.AutheliaCtx, username string) {
@@ -61,6 +61,10 @@ func ResetPasswordPost(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	if ctx.Configuration.AuthenticationBackend.PasswordReset.RevokeSessions {
+		resetPasswordRevokeSessions(ctx, username)
+	}
+
 	// Send Notification.
 	userInfo, err := ctx.Providers.UserProvider.GetDetails(username)
 	if err != nil {
@@ -127,3 +131,23 @@ func ResetPasswordPost(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 }
+
+// resetPasswordRevokeSessions revokes the OpenID Connect tokens issued to the user and destroys the current session if
+// it belongs to the user, so the user has to authenticate again with the new password.
+func resetPasswordRevokeSessions(ctx *middlewares.AutheliaCtx, username string) {
+	if ctx.Providers.OpenIDConnect.Store != nil {
+		if err := ctx.Providers.OpenIDConnect.Store.RevokeUserTokens(ctx, username); err != nil {
+			ctx.Logger.Errorf("Unable to revoke the OpenID Connect tokens of user %s after a password reset: %+v", username, err)
+		} else {
+			ctx.Logger.Debugf("Revoked the OpenID Connect tokens of user %s after a password reset", username)
+		}
+	}
+
+	if ctx.GetSession().Username != username {
+		return
+	}
+
+	if err := ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf("Unable to destroy the session of user %s after a password reset: %+v", username, err)
+	}
+}
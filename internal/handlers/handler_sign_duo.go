@@ -255,8 +255,21 @@ func HandleAllow(ctx *middlewares.AutheliaCtx, targetURL string) {
 		return
 	}
 
+	handleUserSessionRecordRegenerated(ctx, &userSession)
+
 	userSession.SetTwoFactorDuo(ctx.Clock.Now())
 
+	if err = handleRememberMeSecondFactor(ctx, &userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthTypeDuo, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	handleTrustedDeviceSecondFactor(ctx, &userSession)
+	handleLastUsed2FAMethod(ctx, userSession.Username, model.SecondFactorMethodDuo)
+
 	err = ctx.SaveSession(userSession)
 	if err != nil {
 		ctx.Logger.Errorf(logFmtErrSessionSave, "authentication time", regulation.AuthTypeTOTP, userSession.Username, err)
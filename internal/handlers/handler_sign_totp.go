@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
 )
 
@@ -58,6 +59,8 @@ func SecondFactorTOTPPost(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	handleUserSessionRecordRegenerated(ctx, &userSession)
+
 	config.UpdateSignInInfo(ctx.Clock.Now())
 
 	if err = ctx.Providers.StorageProvider.UpdateTOTPConfigurationSignIn(ctx, config.ID, config.LastUsedAt); err != nil {
@@ -70,6 +73,17 @@ func SecondFactorTOTPPost(ctx *middlewares.AutheliaCtx) {
 
 	userSession.SetTwoFactorTOTP(ctx.Clock.Now())
 
+	if err = handleRememberMeSecondFactor(ctx, &userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthTypeTOTP, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	handleTrustedDeviceSecondFactor(ctx, &userSession)
+	handleLastUsed2FAMethod(ctx, userSession.Username, model.SecondFactorMethodTOTP)
+
 	if err = ctx.SaveSession(userSession); err != nil {
 		ctx.Logger.Errorf(logFmtErrSessionSave, "authentication time", regulation.AuthTypeTOTP, userSession.Username, err)
 
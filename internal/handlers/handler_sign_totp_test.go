@@ -71,6 +71,78 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToDefaultURL() {
 	})
 }
 
+func (s *HandlerSignTOTPSuite) TestShouldApplyPendingRememberMe() {
+	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.KeepMeLoggedInPending = true
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
+		Return(&config, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any())
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	SecondFactorTOTPPost(s.mock.Ctx)
+
+	userSession = s.mock.Ctx.GetSession()
+	s.Assert().True(userSession.KeepMeLoggedIn)
+	s.Assert().False(userSession.KeepMeLoggedInPending)
+
+	expiration, err := s.mock.Ctx.Providers.SessionProvider.GetExpiration(s.mock.Ctx.RequestCtx)
+	s.Require().NoError(err)
+	s.Assert().Equal(s.mock.Ctx.Providers.SessionProvider.RememberMe, expiration)
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldSaveLastUsed2FAMethod() {
+	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
+		Return(&config, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any())
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+
+	s.mock.StorageMock.
+		EXPECT().
+		SaveLastUsed2FAMethod(s.mock.Ctx, gomock.Eq("john"), gomock.Eq(model.SecondFactorMethodTOTP)).
+		Return(nil)
+
+	s.mock.Ctx.Configuration.RememberLast2FAMethod = true
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	SecondFactorTOTPPost(s.mock.Ctx)
+	s.mock.Assert200OK(s.T(), nil)
+}
+
 func (s *HandlerSignTOTPSuite) TestShouldFailWhenTOTPSignInInfoFailsToUpdate() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
@@ -179,6 +179,8 @@ func SecondFactorWebauthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	handleUserSessionRecordRegenerated(ctx, &userSession)
+
 	if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeWebauthn, nil); err != nil {
 		respondUnauthorized(ctx, messageMFAValidationFailed)
 
@@ -189,6 +191,17 @@ func SecondFactorWebauthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		assertionResponse.Response.AuthenticatorData.Flags.UserPresent(),
 		assertionResponse.Response.AuthenticatorData.Flags.UserVerified())
 
+	if err = handleRememberMeSecondFactor(ctx, &userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthTypeWebauthn, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	handleTrustedDeviceSecondFactor(ctx, &userSession)
+	handleLastUsed2FAMethod(ctx, userSession.Username, model.SecondFactorMethodWebauthn)
+
 	if err = ctx.SaveSession(userSession); err != nil {
 		ctx.Logger.Errorf(logFmtErrSessionSave, "removal of the assertion challenge and authentication time", regulation.AuthTypeWebauthn, userSession.Username, err)
 
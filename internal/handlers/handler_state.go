@@ -13,6 +13,10 @@ func StateGet(ctx *middlewares.AutheliaCtx) {
 		DefaultRedirectionURL: ctx.Configuration.DefaultRedirectionURL,
 	}
 
+	if userSession.Username == "" && userSession.OIDCWorkflowSession != nil {
+		stateResponse.LoginHint = userSession.OIDCWorkflowSession.LoginHint
+	}
+
 	err := ctx.SetJSONBody(stateResponse)
 	if err != nil {
 		ctx.Logger.Errorf("Unable to set state response in body: %s", err)
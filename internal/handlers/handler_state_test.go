@@ -10,6 +10,7 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
 type StateGetSuite struct {
@@ -56,6 +57,26 @@ func (s *StateGetSuite) TestShouldReturnUsernameFromSession() {
 	assert.Equal(s.T(), expectedBody, actualBody)
 }
 
+func (s *StateGetSuite) TestShouldReturnLoginHintFromWorkflowSession() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.OIDCWorkflowSession = &model.OIDCWorkflowSession{LoginHint: "john"}
+	err := s.mock.Ctx.SaveSession(userSession)
+	require.NoError(s.T(), err)
+
+	StateGet(s.mock.Ctx)
+
+	type Response struct {
+		Status string
+		Data   StateResponse
+	}
+
+	actualBody := Response{}
+
+	err = json.Unmarshal(s.mock.Ctx.Response.Body(), &actualBody)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "john", actualBody.Data.LoginHint)
+}
+
 func (s *StateGetSuite) TestShouldReturnAuthenticationLevelFromSession() {
 	userSession := s.mock.Ctx.GetSession()
 	userSession.AuthenticationLevel = authentication.OneFactor
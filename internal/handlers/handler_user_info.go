@@ -46,6 +46,10 @@ func UserInfoPOST(ctx *middlewares.AutheliaCtx) {
 		}
 	}
 
+	if ctx.Configuration.RememberLast2FAMethod {
+		userInfo.SetLastUsed2FAMethod(ctx.AvailableSecondFactorMethods())
+	}
+
 	userInfo.DisplayName = userSession.DisplayName
 
 	err = ctx.SetJSONBody(userInfo)
@@ -64,6 +68,10 @@ func UserInfoGET(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	if ctx.Configuration.RememberLast2FAMethod {
+		userInfo.SetLastUsed2FAMethod(ctx.AvailableSecondFactorMethods())
+	}
+
 	userInfo.DisplayName = userSession.DisplayName
 
 	err = ctx.SetJSONBody(userInfo)
@@ -98,3 +106,15 @@ func MethodPreferencePost(ctx *middlewares.AutheliaCtx) {
 
 	ctx.ReplyOK()
 }
+
+// handleLastUsed2FAMethod records the method the user successfully completed the second factor with so it can be
+// selected by default the next time. Failing to record it is logged but doesn't fail the second factor.
+func handleLastUsed2FAMethod(ctx *middlewares.AutheliaCtx, username, method string) {
+	if !ctx.Configuration.RememberLast2FAMethod {
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.SaveLastUsed2FAMethod(ctx, username, method); err != nil {
+		ctx.Logger.Errorf(logFmtErrLastUsed2FAMethod, method, username, err)
+	}
+}
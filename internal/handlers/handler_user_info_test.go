@@ -156,6 +156,50 @@ func TestUserInfoEndpoint_SetCorrectMethod(t *testing.T) {
 	}
 }
 
+func TestUserInfoEndpoint_ShouldSelectLastUsedMethod(t *testing.T) {
+	testCases := []struct {
+		name     string
+		enabled  bool
+		expected string
+	}{
+		{"ShouldSelectLastUsedWhenEnabled", true, model.SecondFactorMethodWebauthn},
+		{"ShouldSelectPreferredWhenDisabled", false, model.SecondFactorMethodTOTP},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.RememberLast2FAMethod = tc.enabled
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = testUsername
+			userSession.AuthenticationLevel = 1
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			mock.StorageMock.
+				EXPECT().
+				LoadUserInfo(mock.Ctx, gomock.Eq("john")).
+				Return(model.UserInfo{
+					Method:         model.SecondFactorMethodTOTP,
+					LastUsedMethod: model.SecondFactorMethodWebauthn,
+					HasTOTP:        true,
+					HasWebauthn:    true,
+				}, nil)
+
+			UserInfoGET(mock.Ctx)
+
+			actual := model.UserInfo{}
+
+			mock.GetResponseData(t, &actual)
+
+			assert.Equal(t, tc.expected, actual.Method)
+			assert.Equal(t, model.SecondFactorMethodWebauthn, actual.LastUsedMethod)
+		})
+	}
+}
+
 func TestUserInfoEndpoint_SetDefaultMethod(t *testing.T) {
 	expectedResponses := []expectedResponseAlt{
 		{
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
@@ -19,8 +21,14 @@ import (
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-func isURLUnderProtectedDomain(url *url.URL, domain string) bool {
-	return strings.HasSuffix(url.Hostname(), domain)
+func isURLUnderProtectedDomain(url *url.URL, domains ...string) bool {
+	for _, domain := range domains {
+		if strings.HasSuffix(url.Hostname(), domain) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func isSchemeHTTPS(url *url.URL) bool {
@@ -53,20 +61,23 @@ func parseBasicAuth(header []byte, auth string) (username, password string, err
 	return cs[:s], cs[s+1:], nil
 }
 
-// isTargetURLAuthorized check whether the given user is authorized to access the resource.
+// isTargetURLAuthorized check whether the given user is authorized to access the resource. The second factor methods
+// required by the matching rule are checked against the methods the user authenticated with in the user session.
 func isTargetURLAuthorized(authorizer *authorization.Authorizer, targetURL url.URL,
-	username string, userGroups []string, clientIP net.IP, method []byte, authLevel authentication.Level) authorizationMatching {
-	level := authorizer.GetRequiredLevel(
+	username string, userGroups []string, clientIP net.IP, method []byte, now time.Time, authLevel authentication.Level, isBasicAuth bool,
+	userSession session.UserSession) (matching authorizationMatching, methods []string) {
+	level, required := authorizer.GetRequiredLevelAndSecondFactorMethods(
 		authorization.Subject{
-			Username: username,
-			Groups:   userGroups,
-			IP:       clientIP,
+			Username:  username,
+			Groups:    userGroups,
+			IP:        clientIP,
+			BasicAuth: isBasicAuth,
 		},
-		authorization.NewObjectRaw(&targetURL, method))
+		authorization.NewObjectRaw(&targetURL, method, now))
 
 	switch {
 	case level == authorization.Bypass:
-		return Authorized
+		return Authorized, nil
 	case level == authorization.Denied && username != "":
 		// If the user is not anonymous, it means that we went through
 		// all the rules related to that user and knowing who he is we can
@@ -74,25 +85,70 @@ func isTargetURLAuthorized(authorizer *authorization.Authorizer, targetURL url.U
 		// For anonymous users though, we cannot be sure that she
 		// could not be granted the rights to access the resource. Consequently
 		// for anonymous users we send Unauthorized instead of Forbidden.
-		return Forbidden
-	case level == authorization.OneFactor && authLevel >= authentication.OneFactor,
-		level == authorization.TwoFactor && authLevel >= authentication.TwoFactor:
-		return Authorized
+		return Forbidden, nil
+	case level == authorization.OneFactor && authLevel >= authentication.OneFactor:
+		return Authorized, nil
+	case level == authorization.TwoFactor && authLevel >= authentication.TwoFactor:
+		if userSession.HasSecondFactorMethod(required) {
+			return Authorized, nil
+		}
+
+		// The user completed the second factor with a method the rule doesn't accept, the accepted methods are returned
+		// so the portal can challenge the user for one of them.
+		return NotAuthorized, required
 	}
 
-	return NotAuthorized
+	return NotAuthorized, nil
+}
+
+// verifyWithDeadline runs the backend lookup fn and returns the error of the deadline context if it expires before the
+// lookup completes. The number of lookups in flight is limited by verifyLookups so the lookups which keep running after
+// their deadline can't accumulate when the backend is unresponsive.
+func verifyWithDeadline(deadline context.Context, fn func() error) error {
+	if deadline.Done() == nil {
+		return fn()
+	}
+
+	select {
+	case verifyLookups <- struct{}{}:
+	case <-deadline.Done():
+		return deadline.Err()
+	}
+
+	result := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			<-verifyLookups
+		}()
+
+		result <- fn()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-deadline.Done():
+		return deadline.Err()
+	}
 }
 
 // verifyBasicAuth verify that the provided username and password are correct and
 // that the user is authorized to target the resource.
-func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+func verifyBasicAuth(ctx *middlewares.AutheliaCtx, deadline context.Context, header, auth []byte) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
 	username, password, err := parseBasicAuth(header, string(auth))
 
 	if err != nil {
 		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to parse content of %s header: %s", header, err)
 	}
 
-	authenticated, err := ctx.Providers.UserProvider.CheckUserPassword(username, password)
+	var authenticated bool
+
+	err = verifyWithDeadline(deadline, func() (err error) {
+		authenticated, err = ctx.Providers.UserProvider.CheckUserPassword(username, password)
+
+		return err
+	})
 
 	if err != nil {
 		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to check credentials extracted from %s header: %w", header, err)
@@ -104,10 +160,16 @@ func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (usernam
 		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("user %s is not authenticated", username)
 	}
 
-	details, err := ctx.Providers.UserProvider.GetDetails(username)
+	var details *authentication.UserDetails
+
+	err = verifyWithDeadline(deadline, func() (err error) {
+		details, err = ctx.Providers.UserProvider.GetDetails(username)
+
+		return err
+	})
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user %s: %s", username, err)
+		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user %s: %w", username, err)
 	}
 
 	return username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
@@ -128,6 +190,32 @@ func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string
 	}
 }
 
+// setVerifyResponseHeaders sets the configured verify response headers from the User, Groups, Name and Email values.
+func setVerifyResponseHeaders(headers *fasthttp.ResponseHeader, config []schema.VerifyResponseHeaderConfiguration, username, name string, groups, emails []string) {
+	if username == "" {
+		return
+	}
+
+	for _, header := range config {
+		switch header.Source {
+		case schema.VerifyResponseHeaderSourceUsername:
+			headers.Set(header.Name, username)
+		case schema.VerifyResponseHeaderSourceDisplayName:
+			headers.Set(header.Name, name)
+		case schema.VerifyResponseHeaderSourceEmail:
+			if len(emails) != 0 {
+				headers.Set(header.Name, emails[0])
+			} else {
+				headers.Set(header.Name, "")
+			}
+		case schema.VerifyResponseHeaderSourceEmails:
+			headers.Set(header.Name, strings.Join(emails, ","))
+		case schema.VerifyResponseHeaderSourceGroups:
+			headers.Set(header.Name, strings.Join(groups, ","))
+		}
+	}
+}
+
 // hasUserBeenInactiveTooLong checks whether the user has been inactive for too long.
 func hasUserBeenInactiveTooLong(ctx *middlewares.AutheliaCtx) (bool, error) { //nolint:unparam
 	maxInactivityPeriod := int64(ctx.Providers.SessionProvider.Inactivity.Seconds())
@@ -149,7 +237,7 @@ func hasUserBeenInactiveTooLong(ctx *middlewares.AutheliaCtx) (bool, error) { //
 }
 
 // verifySessionCookie verifies if a user is identified by a cookie.
-func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userSession *session.UserSession, refreshProfile bool,
+func verifySessionCookie(ctx *middlewares.AutheliaCtx, deadline context.Context, targetURL *url.URL, userSession *session.UserSession, refreshProfile bool,
 	refreshProfileInterval time.Duration) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
 	// No username in the session means the user is anonymous.
 	isUserAnonymous := userSession.Username == ""
@@ -175,7 +263,7 @@ func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userS
 		}
 	}
 
-	err = verifySessionHasUpToDateProfile(ctx, targetURL, userSession, refreshProfile, refreshProfileInterval)
+	err = verifySessionHasUpToDateProfile(ctx, deadline, targetURL, userSession, refreshProfile, refreshProfileInterval)
 	if err != nil {
 		if err == authentication.ErrUserNotFound {
 			err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx)
@@ -194,7 +282,7 @@ func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userS
 	return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.AuthenticationLevel, nil
 }
 
-func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, isBasicAuth bool, username string, method []byte) {
+func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, isBasicAuth bool, username string, method []byte, methods2FA []string) {
 	var (
 		statusCode            int
 		redirectionURL        string
@@ -237,6 +325,11 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 		default:
 			redirectionURL = fmt.Sprintf("%s?rd=%s&rm=%s", rd, url.QueryEscape(targetURL.String()), rm)
 		}
+
+		// The portal challenges the user for one of the second factor methods accepted by the rule.
+		if len(methods2FA) != 0 {
+			redirectionURL = fmt.Sprintf("%s&methods=%s", redirectionURL, url.QueryEscape(strings.Join(methods2FA, ",")))
+		}
 	}
 
 	switch {
@@ -266,8 +359,15 @@ func updateActivityTimestamp(ctx *middlewares.AutheliaCtx, isBasicAuth bool, use
 	}
 
 	userSession := ctx.GetSession()
+
+	recordChanged := handleUserSessionRecordLastSeen(ctx, &userSession)
+
 	// We don't need to update the activity timestamp when user checked keep me logged in.
 	if userSession.KeepMeLoggedIn {
+		if recordChanged {
+			return ctx.SaveSession(userSession)
+		}
+
 		return nil
 	}
 
@@ -325,7 +425,7 @@ func generateVerifySessionHasUpToDateProfileTraceLogs(ctx *middlewares.AutheliaC
 	}
 }
 
-func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userSession *session.UserSession,
+func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, deadline context.Context, targetURL *url.URL, userSession *session.UserSession,
 	refreshProfile bool, refreshProfileInterval time.Duration) error {
 	// TODO: Add a check for LDAP password changes based on a time format attribute.
 	// See https://www.authelia.com/docs/security/threat-model.html#potential-future-guarantees
@@ -340,7 +440,13 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 	}
 
 	ctx.Logger.Debugf("Checking the authentication backend for an updated profile for user %s", userSession.Username)
-	details, err := ctx.Providers.UserProvider.GetDetails(userSession.Username)
+	var details *authentication.UserDetails
+
+	err := verifyWithDeadline(deadline, func() (err error) {
+		details, err = ctx.Providers.UserProvider.GetDetails(userSession.Username)
+
+		return err
+	})
 	// Only update the session if we could get the new details.
 	if err != nil {
 		return err
@@ -402,7 +508,7 @@ func getProfileRefreshSettings(cfg schema.AuthenticationBackendConfiguration) (r
 	return refresh, refreshInterval
 }
 
-func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile bool, refreshProfileInterval time.Duration) (isBasicAuth bool, username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+func verifyAuth(ctx *middlewares.AutheliaCtx, deadline context.Context, targetURL *url.URL, refreshProfile bool, refreshProfileInterval time.Duration) (isBasicAuth bool, username, name string, groups, emails []string, authLevel authentication.Level, err error) {
 	authHeader := headerProxyAuthorization
 	if bytes.Equal(ctx.QueryArgs().Peek("auth"), []byte("basic")) {
 		authHeader = headerAuthorization
@@ -418,12 +524,12 @@ func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile
 	}
 
 	if isBasicAuth {
-		username, name, groups, emails, authLevel, err = verifyBasicAuth(ctx, authHeader, authValue)
+		username, name, groups, emails, authLevel, err = verifyBasicAuth(ctx, deadline, authHeader, authValue)
 		return
 	}
 
 	userSession := ctx.GetSession()
-	username, name, groups, emails, authLevel, err = verifySessionCookie(ctx, targetURL, &userSession, refreshProfile, refreshProfileInterval)
+	username, name, groups, emails, authLevel, err = verifySessionCookie(ctx, deadline, targetURL, &userSession, refreshProfile, refreshProfileInterval)
 
 	sessionUsername := ctx.Request.Header.PeekBytes(headerSessionUsername)
 	if sessionUsername != nil && !strings.EqualFold(string(sessionUsername), username) {
@@ -463,16 +569,33 @@ func VerifyGet(cfg schema.AuthenticationBackendConfiguration) middlewares.Reques
 			return
 		}
 
-		if !isURLUnderProtectedDomain(targetURL, ctx.Configuration.Session.Domain) {
+		if domains := ctx.GetSessionDomains(); !isURLUnderProtectedDomain(targetURL, domains...) {
 			ctx.Logger.Errorf("Target URL %s is not under the protected domain %s",
-				targetURL.String(), ctx.Configuration.Session.Domain)
+				targetURL.String(), strings.Join(domains, ", "))
 			ctx.ReplyUnauthorized()
 
 			return
 		}
 
+		deadline := context.Background()
+
+		if cfg.VerifyTimeout > 0 {
+			var cancel context.CancelFunc
+
+			deadline, cancel = context.WithTimeout(deadline, cfg.VerifyTimeout)
+
+			defer cancel()
+		}
+
 		method := ctx.XForwardedMethod()
-		isBasicAuth, username, name, groups, emails, authLevel, err := verifyAuth(ctx, targetURL, refreshProfile, refreshProfileInterval)
+		isBasicAuth, username, name, groups, emails, authLevel, err := verifyAuth(ctx, deadline, targetURL, refreshProfile, refreshProfileInterval)
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			ctx.Logger.Errorf("Timed out after %s waiting for the authentication backend when verifying user authorization: %s", cfg.VerifyTimeout, err)
+			ctx.RequestCtx.Error(fasthttp.StatusMessage(fasthttp.StatusServiceUnavailable), fasthttp.StatusServiceUnavailable)
+
+			return
+		}
 
 		if err != nil {
 			ctx.Logger.Errorf("Error caught when verifying user authorization: %s", err)
@@ -482,22 +605,27 @@ func VerifyGet(cfg schema.AuthenticationBackendConfiguration) middlewares.Reques
 				return
 			}
 
-			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
+			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method, nil)
 
 			return
 		}
 
-		authorized := isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
-			groups, ctx.RemoteIP(), method, authLevel)
+		authorized, methods2FA := isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
+			groups, ctx.RemoteIP(), method, ctx.Clock.Now(), authLevel, isBasicAuth, ctx.GetSession())
 
 		switch authorized {
 		case Forbidden:
 			ctx.Logger.Infof("Access to %s is forbidden to user %s", targetURL.String(), username)
 			ctx.ReplyForbidden()
 		case NotAuthorized:
-			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
+			if len(methods2FA) != 0 {
+				ctx.Logger.Infof("Access to %s requires user %s to authenticate with one of the second factor methods %s", targetURL.String(), username, strings.Join(methods2FA, ", "))
+			}
+
+			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method, methods2FA)
 		case Authorized:
 			setForwardedHeaders(&ctx.Response.Header, username, name, groups, emails)
+			setVerifyResponseHeaders(&ctx.Response.Header, cfg.VerifyResponseHeaders, username, name, groups, emails)
 		}
 
 		if err := updateActivityTimestamp(ctx, isBasicAuth, username); err != nil {
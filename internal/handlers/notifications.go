@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// notifyDeviceChange sends a notification to the user informing them a 2FA device was added or removed when the
+// device change notifications are enabled. Failures are logged but never fail the request as the change has already
+// been made.
+func notifyDeviceChange(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, deviceType, action string) {
+	if ctx.Configuration.Notifier == nil || !ctx.Configuration.Notifier.Notifications.DeviceChange {
+		return
+	}
+
+	if len(userSession.Emails) == 0 {
+		ctx.Logger.Errorf("Unable to notify user '%s' that a %s device was %s: user does not have any email address", userSession.Username, deviceType, action)
+
+		return
+	}
+
+	bufText := new(bytes.Buffer)
+	textParams := map[string]interface{}{
+		"displayName": userSession.DisplayName,
+		"deviceType":  deviceType,
+		"action":      action,
+		"time":        ctx.Clock.Now().UTC().Format("2006-01-02 15:04:05 MST"),
+		"remoteIP":    ctx.RemoteIP().String(),
+	}
+
+	if err := templates.PlainTextEmailTemplateDeviceChange.Execute(bufText, textParams); err != nil {
+		ctx.Logger.Errorf("Unable to notify user '%s' that a %s device was %s: %+v", userSession.Username, deviceType, action, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Sending an email to user %s (%s) to inform that a %s device was %s.", userSession.Username, userSession.Emails[0], deviceType, action)
+
+	if err := ctx.Providers.Notifier.Send(userSession.Emails[0], fmt.Sprintf("%s device %s", deviceType, action), bufText.String(), ""); err != nil {
+		ctx.Logger.Errorf("Unable to notify user '%s' that a %s device was %s: %+v", userSession.Username, deviceType, action, err)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func TestShouldNotNotifyDeviceChangeWhenDisabled(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	userSession := session.UserSession{Username: testUsername, Emails: []string{"john@example.com"}}
+
+	notifyDeviceChange(mock.Ctx, &userSession, regulation.AuthTypeTOTP, deviceChangeActionAdded)
+
+	mock.Ctx.Configuration.Notifier = &schema.NotifierConfiguration{}
+
+	notifyDeviceChange(mock.Ctx, &userSession, regulation.AuthTypeTOTP, deviceChangeActionAdded)
+}
+
+func TestShouldNotifyDeviceChange(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Notifier = &schema.NotifierConfiguration{
+		Notifications: schema.NotifierNotificationsConfiguration{DeviceChange: true},
+	}
+
+	mock.Ctx.Clock = &mock.Clock
+
+	userSession := session.UserSession{Username: testUsername, DisplayName: "John Smith", Emails: []string{"john@example.com"}}
+
+	mock.NotifierMock.EXPECT().
+		Send(gomock.Eq("john@example.com"), gomock.Eq("Webauthn device added"), gomock.Any(), gomock.Eq("")).
+		DoAndReturn(func(recipient, subject, body, htmlBody string) error {
+			assert.True(t, strings.Contains(body, "Hi John Smith,"))
+			assert.True(t, strings.Contains(body, "A Webauthn device has been added on your account at "+mock.Clock.Now().UTC().Format("2006-01-02 15:04:05 MST")+"."))
+
+			return nil
+		})
+
+	notifyDeviceChange(mock.Ctx, &userSession, regulation.AuthTypeWebauthn, deviceChangeActionAdded)
+}
+
+func TestShouldNotFailWhenDeviceChangeNotificationFails(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Notifier = &schema.NotifierConfiguration{
+		Notifications: schema.NotifierNotificationsConfiguration{DeviceChange: true},
+	}
+
+	notifyDeviceChange(mock.Ctx, &session.UserSession{Username: testUsername}, regulation.AuthTypeTOTP, deviceChangeActionAdded)
+
+	mock.NotifierMock.EXPECT().
+		Send(gomock.Eq("john@example.com"), gomock.Eq("TOTP device added"), gomock.Any(), gomock.Eq("")).
+		Return(errors.New("failed to send"))
+
+	notifyDeviceChange(mock.Ctx, &session.UserSession{Username: testUsername, Emails: []string{"john@example.com"}}, regulation.AuthTypeTOTP, deviceChangeActionAdded)
+
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/ory/fosite"
 
+	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -10,17 +14,54 @@ import (
 )
 
 // isConsentMissing compares the requestedScopes and requestedAudience to the workflows
-// GrantedScopes and GrantedAudience and returns true if they do not match or the workflow is nil.
+// GrantedScopes and GrantedAudience and returns true if they do not match or the workflow is nil. The scopes are also
+// considered consented to if the user made a decision on exactly the requestedScopes but only granted some of them.
 func isConsentMissing(workflow *model.OIDCWorkflowSession, requestedScopes, requestedAudience []string) (isMissing bool) {
 	if workflow == nil {
 		return true
 	}
 
-	return len(requestedScopes) > 0 && utils.IsStringSlicesDifferent(requestedScopes, workflow.GrantedScopes) ||
+	return len(requestedScopes) > 0 && utils.IsStringSlicesDifferent(requestedScopes, workflow.GrantedScopes) &&
+		(workflow.GrantedScopes == nil || utils.IsStringSlicesDifferent(requestedScopes, workflow.RequestedScopes)) ||
 		len(requestedAudience) > 0 && utils.IsStringSlicesDifferentFold(requestedAudience, workflow.GrantedAudience)
 }
 
-func oidcGrantRequests(ar fosite.AuthorizeRequester, scopes, audiences []string, userSession *session.UserSession) (extraClaims map[string]interface{}) {
+// oidcPreferredUsername returns the value of the preferred_username claim given the configured source attribute. It
+// falls back to the username if the attribute is not available for the user.
+func oidcPreferredUsername(claim string, userSession *session.UserSession) string {
+	switch claim {
+	case "display_name":
+		if userSession.DisplayName != "" {
+			return userSession.DisplayName
+		}
+	case "email":
+		if len(userSession.Emails) != 0 {
+			return userSession.Emails[0]
+		}
+	}
+
+	return userSession.Username
+}
+
+// oidcSanitizeLoginHint returns the login_hint if it's suitable to prefill the username, otherwise it returns an empty
+// string. Hints which are too long or contain control or non-printable characters are discarded.
+func oidcSanitizeLoginHint(hint string) string {
+	hint = strings.TrimSpace(hint)
+
+	if len(hint) > oidcLoginHintMaximumLength {
+		return ""
+	}
+
+	for _, r := range hint {
+		if !unicode.IsPrint(r) {
+			return ""
+		}
+	}
+
+	return hint
+}
+
+func oidcGrantRequests(ar fosite.AuthorizeRequester, scopes, audiences []string, preferredUsernameClaim string, userSession *session.UserSession) (extraClaims map[string]interface{}) {
 	extraClaims = map[string]interface{}{}
 
 	for _, scope := range scopes {
@@ -32,7 +73,7 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, scopes, audiences []string,
 		case oidc.ScopeGroups:
 			extraClaims[oidc.ClaimGroups] = userSession.Groups
 		case oidc.ScopeProfile:
-			extraClaims[oidc.ClaimPreferredUsername] = userSession.Username
+			extraClaims[oidc.ClaimPreferredUsername] = oidcPreferredUsername(preferredUsernameClaim, userSession)
 			extraClaims[oidc.ClaimDisplayName] = userSession.DisplayName
 		case oidc.ScopeEmail:
 			if len(userSession.Emails) != 0 {
@@ -58,3 +99,56 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, scopes, audiences []string,
 
 	return extraClaims
 }
+
+// oidcRefreshSessionActivity refreshes the inactivity timer of the Authelia session when the client is configured to do
+// so. The session of the request is refreshed if it belongs to the user the token was issued to, otherwise the session
+// which authorized the request is refreshed as back-channel requests don't include the session cookie. Sessions which
+// have already been inactive for too long are never refreshed.
+func oidcRefreshSessionActivity(ctx *middlewares.AutheliaCtx, client *oidc.InternalClient, requester fosite.Requester) {
+	if client == nil || !client.RefreshSessionActivity {
+		return
+	}
+
+	oidcSession, ok := requester.GetSession().(*oidc.OpenIDSession)
+	if !ok || oidcSession.Username == "" {
+		return
+	}
+
+	var (
+		userSession = ctx.GetSession()
+		save        = ctx.SaveSession
+		err         error
+	)
+
+	if userSession.Username != oidcSession.Username {
+		if len(oidcSession.SessionID) == 0 {
+			return
+		}
+
+		if userSession, err = ctx.Providers.SessionProvider.GetSessionByID(oidcSession.SessionID); err != nil || userSession.Username != oidcSession.Username {
+			ctx.Logger.Debugf("Request with id '%s' on client with id '%s' did not refresh the session activity as the session which authorized the request no longer exists", requester.GetID(), client.GetID())
+
+			return
+		}
+
+		save = func(userSession session.UserSession) error {
+			return ctx.Providers.SessionProvider.SaveSessionByID(oidcSession.SessionID, userSession)
+		}
+	}
+
+	if userSession.KeepMeLoggedIn {
+		return
+	}
+
+	now := ctx.Clock.Now().Unix()
+
+	if inactivity := int64(ctx.Providers.SessionProvider.Inactivity.Seconds()); inactivity != 0 && now-userSession.LastActivity > inactivity {
+		return
+	}
+
+	userSession.LastActivity = now
+
+	if err = save(userSession); err != nil {
+		ctx.Logger.Errorf("Request with id '%s' on client with id '%s' failed to refresh the session activity: %+v", requester.GetID(), client.GetID(), err)
+	}
+}
@@ -9,9 +9,10 @@ import (
 
 // RegisterOIDC registers the handlers with the fasthttp *router.Router. TODO: Add paths for Flush, Logout.
 func RegisterOIDC(router *router.Router, middleware middlewares.RequestHandlerBridge) {
-	// TODO: Add OPTIONS handler.
 	router.GET(oidc.WellKnownOpenIDConfigurationPath, middleware(middlewares.CORSApplyAutomaticAllowAllPolicy(wellKnownOpenIDConnectConfigurationGET)))
 	router.GET(oidc.WellKnownOAuthAuthorizationServerPath, middleware(middlewares.CORSApplyAutomaticAllowAllPolicy(wellKnownOAuthAuthorizationServerGET)))
+	router.OPTIONS(oidc.WellKnownOpenIDConfigurationPath, middleware(middlewares.CORSHandleAutomaticAllowAllPolicyOPTIONS))
+	router.OPTIONS(oidc.WellKnownOAuthAuthorizationServerPath, middleware(middlewares.CORSHandleAutomaticAllowAllPolicyOPTIONS))
 
 	router.GET(pathOpenIDConnectConsent, middleware(oidcConsent))
 
@@ -1,11 +1,25 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fasthttp/router"
+	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -33,10 +47,28 @@ func TestShouldDetectIfConsentIsMissing(t *testing.T) {
 	requestedScopes = []string{"openid", "profile"}
 	requestedAudience = []string{"https://not.authelia.com"}
 	assert.True(t, isConsentMissing(workflow, requestedScopes, requestedAudience))
+
+	requestedAudience = []string{"https://authelia.com"}
+	workflow.RequestedScopes = []string{"openid", "profile"}
+	workflow.GrantedScopes = []string{"openid"}
+
+	assert.False(t, isConsentMissing(workflow, requestedScopes, requestedAudience))
+
+	requestedScopes = []string{"openid", "profile", "groups"}
+
+	assert.True(t, isConsentMissing(workflow, requestedScopes, requestedAudience))
+}
+
+func TestShouldSanitizeLoginHint(t *testing.T) {
+	assert.Equal(t, "john", oidcSanitizeLoginHint("john"))
+	assert.Equal(t, "john@example.com", oidcSanitizeLoginHint(" john@example.com "))
+	assert.Equal(t, "", oidcSanitizeLoginHint("john\n<script>"))
+	assert.Equal(t, "", oidcSanitizeLoginHint("john\x00"))
+	assert.Equal(t, "", oidcSanitizeLoginHint(strings.Repeat("a", oidcLoginHintMaximumLength+1)))
 }
 
 func TestShouldGrantAppropriateClaimsForScopeProfile(t *testing.T) {
-	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeProfile}, []string{}, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeProfile}, []string{}, "username", &oidcUserSessionJohn)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -48,7 +80,7 @@ func TestShouldGrantAppropriateClaimsForScopeProfile(t *testing.T) {
 }
 
 func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
-	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeGroups}, []string{}, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeGroups}, []string{}, "username", &oidcUserSessionJohn)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -57,7 +89,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "admin")
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "dev")
 
-	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeGroups}, []string{}, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeGroups}, []string{}, "username", &oidcUserSessionFred)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -67,7 +99,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 }
 
 func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
-	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeEmail}, []string{}, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeEmail}, []string{}, "username", &oidcUserSessionJohn)
 
 	assert.Len(t, extraClaims, 3)
 
@@ -81,7 +113,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimEmailVerified)
 	assert.Equal(t, true, extraClaims[oidc.ClaimEmailVerified])
 
-	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeEmail}, []string{}, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeEmail}, []string{}, "username", &oidcUserSessionFred)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -93,7 +125,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 }
 
 func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
-	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeOpenID, oidc.ScopeProfile}, []string{}, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeOpenID, oidc.ScopeProfile}, []string{}, "username", &oidcUserSessionJohn)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -103,7 +135,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimDisplayName)
 	assert.Equal(t, "John Smith", extraClaims[oidc.ClaimDisplayName])
 
-	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeOpenID, oidc.ScopeProfile}, []string{}, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeOpenID, oidc.ScopeProfile}, []string{}, "username", &oidcUserSessionFred)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -114,6 +146,23 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	assert.Equal(t, extraClaims[oidc.ClaimDisplayName], "Fred Smith")
 }
 
+func TestShouldGrantPreferredUsernameFromConfiguredClaim(t *testing.T) {
+	extraClaims := oidcGrantRequests(nil, []string{oidc.ScopeProfile}, []string{}, "email", &oidcUserSessionJohn)
+
+	require.Contains(t, extraClaims, oidc.ClaimPreferredUsername)
+	assert.Equal(t, "j.smith@authelia.com", extraClaims[oidc.ClaimPreferredUsername])
+
+	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeProfile}, []string{}, "display_name", &oidcUserSessionJohn)
+
+	require.Contains(t, extraClaims, oidc.ClaimPreferredUsername)
+	assert.Equal(t, "John Smith", extraClaims[oidc.ClaimPreferredUsername])
+
+	extraClaims = oidcGrantRequests(nil, []string{oidc.ScopeProfile}, []string{}, "email", &session.UserSession{Username: "harry"})
+
+	require.Contains(t, extraClaims, oidc.ClaimPreferredUsername)
+	assert.Equal(t, "harry", extraClaims[oidc.ClaimPreferredUsername])
+}
+
 var (
 	oidcUserSessionJohn = session.UserSession{
 		Username:    "john",
@@ -129,3 +178,370 @@ var (
 		Emails:      []string{"f.smith@authelia.com"},
 	}
 )
+
+func TestShouldRequireAuthenticationOnAuthorizationWhenMaximumLifetimeExceeded(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t)
+	mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.DefaultOpenIDConnectConfiguration
+
+	mock.Ctx.Configuration.Session.MaximumLifetime = time.Hour
+	// Reload the session provider since the configuration is indirect.
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.KeepMeLoggedIn = true
+	userSession.FirstFactorAuthnTimestamp = time.Now().Add(-2 * time.Hour).Unix()
+	userSession.LastActivity = time.Now().Unix()
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+	mock.Ctx.Request.SetRequestURI("/api/oidc/authorization?client_id=test&response_type=code&scope=openid&state=abcdefghijkl&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcallback")
+
+	middlewares.NewHTTPToAutheliaHandlerAdaptor(oidcAuthorization)(mock.Ctx)
+
+	// The user is sent back to the portal to authenticate instead of to the consent page.
+	assert.Equal(t, 302, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "https://auth.example.com", string(mock.Ctx.Response.Header.Peek("Location")))
+
+	newUserSession := mock.Ctx.GetSession()
+	assert.Equal(t, "", newUserSession.Username)
+	assert.Equal(t, authentication.NotAuthenticated, newUserSession.AuthenticationLevel)
+	require.NotNil(t, newUserSession.OIDCWorkflowSession)
+	assert.Equal(t, "test", newUserSession.OIDCWorkflowSession.ClientID)
+}
+
+func TestShouldRejectAuthorizationWithTooManyScopes(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t)
+	mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{MaximumRequestedScopes: 1, MaximumRequestedAudiences: 1}
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+	mock.Ctx.Request.SetRequestURI("/api/oidc/authorization?client_id=test&response_type=code&scope=openid+profile&state=abcdefghijkl&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcallback")
+
+	middlewares.NewHTTPToAutheliaHandlerAdaptor(oidcAuthorization)(mock.Ctx)
+
+	location := string(mock.Ctx.Response.Header.Peek("Location"))
+
+	assert.Contains(t, location, "https://app.example.com/callback?")
+	assert.Contains(t, location, "error=invalid_scope")
+
+	// No consent session is created for the request.
+	assert.Nil(t, mock.Ctx.GetSession().OIDCWorkflowSession)
+}
+
+// newTestOpenIDConnectProvider returns an OpenIDConnectProvider with the one_factor clients 'test' and 'other'.
+func newTestOpenIDConnectProvider(t *testing.T) oidc.OpenIDConnectProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	provider, err := oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		HMACSecret:       "abcdefghijklmnopqrstuvwxyz123456",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:           "test",
+				Secret:       "test-secret",
+				Policy:       "one_factor",
+				Scopes:       []string{oidc.ScopeOpenID, oidc.ScopeProfile},
+				RedirectURIs: []string{"https://app.example.com/callback"},
+			},
+			{
+				ID:           "other",
+				Secret:       "other-secret",
+				Policy:       "one_factor",
+				Scopes:       []string{oidc.ScopeOpenID},
+				RedirectURIs: []string{"https://other.example.com/callback"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestShouldValidateImplicitFlow(t *testing.T) {
+	config := &schema.OpenIDConnectConfiguration{DisableImplicitFlow: true}
+
+	code := &fosite.AuthorizeRequest{ResponseTypes: fosite.Arguments{"code"}}
+	implicit := &fosite.AuthorizeRequest{ResponseTypes: fosite.Arguments{"id_token", "token"}}
+	hybridIDToken := &fosite.AuthorizeRequest{ResponseTypes: fosite.Arguments{"code", "id_token"}}
+	hybridToken := &fosite.AuthorizeRequest{ResponseTypes: fosite.Arguments{"code", "token"}}
+
+	assert.NoError(t, oidcAuthorizationValidateImplicitFlow(config, code))
+	assert.EqualError(t, oidcAuthorizationValidateImplicitFlow(config, implicit), "unsupported_response_type")
+	assert.EqualError(t, oidcAuthorizationValidateImplicitFlow(config, hybridIDToken), "unsupported_response_type")
+	assert.EqualError(t, oidcAuthorizationValidateImplicitFlow(config, hybridToken), "unsupported_response_type")
+
+	config.DisableImplicitFlow = false
+
+	assert.NoError(t, oidcAuthorizationValidateImplicitFlow(config, implicit))
+	assert.NoError(t, oidcAuthorizationValidateImplicitFlow(config, hybridIDToken))
+	assert.NoError(t, oidcAuthorizationValidateImplicitFlow(nil, implicit))
+}
+
+func TestShouldValidateAllowedResponseTypes(t *testing.T) {
+	config := &schema.OpenIDConnectConfiguration{AllowedResponseTypes: []string{"code", "id_token code"}}
+
+	code := &fosite.AuthorizeRequest{ResponseTypes: fosite.Arguments{"code"}}
+	implicit := &fosite.AuthorizeRequest{ResponseTypes: fosite.Arguments{"id_token", "token"}}
+	hybrid := &fosite.AuthorizeRequest{ResponseTypes: fosite.Arguments{"code", "id_token"}}
+
+	assert.NoError(t, oidcAuthorizationValidateResponseType(config, code))
+	assert.NoError(t, oidcAuthorizationValidateResponseType(config, hybrid))
+	assert.EqualError(t, oidcAuthorizationValidateResponseType(config, implicit), "unsupported_response_type")
+
+	config.AllowedResponseTypes = []string{"code"}
+
+	assert.EqualError(t, oidcAuthorizationValidateResponseType(config, hybrid), "unsupported_response_type")
+
+	config.AllowedResponseTypes = nil
+
+	assert.NoError(t, oidcAuthorizationValidateResponseType(config, implicit))
+	assert.NoError(t, oidcAuthorizationValidateResponseType(nil, implicit))
+}
+
+func TestShouldRefreshSessionActivityForConfiguredClient(t *testing.T) {
+	testCases := []struct {
+		name      string
+		refresh   bool
+		username  string
+		keep      bool
+		inactive  bool
+		refreshed bool
+	}{
+		{"ShouldRefresh", true, "john", false, false, true},
+		{"ShouldNotRefreshWhenDisabled", false, "john", false, false, false},
+		{"ShouldNotRefreshDifferentUser", true, "harry", false, false, false},
+		{"ShouldNotRefreshKeepMeLoggedIn", true, "john", true, false, false},
+		{"ShouldNotRefreshInactiveSession", true, "john", false, true, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Clock = &mock.Clock
+
+			mock.Ctx.Configuration.Session.Inactivity = time.Minute
+			mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
+
+			lastActivity := mock.Clock.Now().Add(-time.Second * 30)
+			if tc.inactive {
+				lastActivity = mock.Clock.Now().Add(-time.Minute * 2)
+			}
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = "john"
+			userSession.AuthenticationLevel = authentication.TwoFactor
+			userSession.KeepMeLoggedIn = tc.keep
+			userSession.LastActivity = lastActivity.Unix()
+
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			client := &oidc.InternalClient{ID: "client", RefreshSessionActivity: tc.refresh}
+
+			oidcSession := oidc.NewSession()
+			oidcSession.Username = tc.username
+
+			requester := fosite.NewRequest()
+			requester.Client = client
+			requester.Session = oidcSession
+
+			oidcRefreshSessionActivity(mock.Ctx, client, requester)
+
+			if tc.refreshed {
+				assert.Equal(t, mock.Clock.Now().Unix(), mock.Ctx.GetSession().LastActivity)
+			} else {
+				assert.Equal(t, lastActivity.Unix(), mock.Ctx.GetSession().LastActivity)
+			}
+		})
+	}
+}
+
+func TestShouldRefreshSessionActivityForBackChannelRequests(t *testing.T) {
+	testCases := []struct {
+		name      string
+		username  string
+		keep      bool
+		inactive  bool
+		refreshed bool
+	}{
+		{"ShouldRefresh", "john", false, false, true},
+		{"ShouldNotRefreshDifferentUser", "harry", false, false, false},
+		{"ShouldNotRefreshKeepMeLoggedIn", "john", true, false, false},
+		{"ShouldNotRefreshInactiveSession", "john", false, true, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Clock = &mock.Clock
+
+			mock.Ctx.Configuration.Session.Inactivity = time.Minute
+			mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
+
+			lastActivity := mock.Clock.Now().Add(-time.Second * 30)
+			if tc.inactive {
+				lastActivity = mock.Clock.Now().Add(-time.Minute * 2)
+			}
+
+			// The session which authorized the request belongs to the browser of the user, not the back-channel request.
+			browser := &fasthttp.RequestCtx{}
+
+			userSession, err := mock.Ctx.Providers.SessionProvider.GetSession(browser)
+			require.NoError(t, err)
+
+			userSession.Username = "john"
+			userSession.AuthenticationLevel = authentication.TwoFactor
+			userSession.KeepMeLoggedIn = tc.keep
+			userSession.LastActivity = lastActivity.Unix()
+
+			require.NoError(t, mock.Ctx.Providers.SessionProvider.SaveSession(browser, userSession))
+
+			id, err := mock.Ctx.Providers.SessionProvider.GetSessionID(browser)
+			require.NoError(t, err)
+
+			client := &oidc.InternalClient{ID: "client", RefreshSessionActivity: true}
+
+			oidcSession := oidc.NewSession()
+			oidcSession.Username = tc.username
+			oidcSession.SessionID = id
+
+			requester := fosite.NewRequest()
+			requester.Client = client
+			requester.Session = oidcSession
+
+			oidcRefreshSessionActivity(mock.Ctx, client, requester)
+
+			userSession, err = mock.Ctx.Providers.SessionProvider.GetSessionByID(id)
+			require.NoError(t, err)
+
+			if tc.refreshed {
+				assert.Equal(t, mock.Clock.Now().Unix(), userSession.LastActivity)
+			} else {
+				assert.Equal(t, lastActivity.Unix(), userSession.LastActivity)
+			}
+
+			assert.Equal(t, "", mock.Ctx.GetSession().Username)
+		})
+	}
+}
+
+func TestShouldValidateAllowedNetworks(t *testing.T) {
+	client := oidc.NewClient(schema.OpenIDConnectClientConfiguration{
+		ID:              "client",
+		Policy:          "two_factor",
+		AllowedNetworks: []string{"10.0.0.0/8", "192.168.1.1"},
+	})
+
+	assert.NoError(t, oidcAuthorizationValidateNetwork(client, net.ParseIP("10.10.0.1")))
+	assert.NoError(t, oidcAuthorizationValidateNetwork(client, net.ParseIP("192.168.1.1")))
+	assert.EqualError(t, oidcAuthorizationValidateNetwork(client, net.ParseIP("192.168.1.2")), "access_denied")
+	assert.EqualError(t, oidcAuthorizationValidateNetwork(client, net.ParseIP("172.16.0.1")), "access_denied")
+
+	assert.NoError(t, oidcAuthorizationValidateNetwork(oidc.NewClient(schema.OpenIDConnectClientConfiguration{ID: "open"}), net.ParseIP("172.16.0.1")))
+	assert.NoError(t, oidcAuthorizationValidateNetwork(nil, net.ParseIP("172.16.0.1")))
+}
+
+func TestShouldValidateRequiredState(t *testing.T) {
+	config := &schema.OpenIDConnectConfiguration{MinimumParameterEntropy: -1}
+	client := &oidc.InternalClient{ID: "client"}
+
+	missing := &fosite.AuthorizeRequest{}
+	short := &fosite.AuthorizeRequest{State: "abc"}
+	valid := &fosite.AuthorizeRequest{State: "abcdefghijkl"}
+
+	assert.NoError(t, oidcAuthorizationValidateState(config, client, missing))
+	assert.NoError(t, oidcAuthorizationValidateState(config, nil, missing))
+
+	client.RequireState = true
+
+	assert.EqualError(t, oidcAuthorizationValidateState(config, client, missing), "invalid_state")
+	assert.EqualError(t, oidcAuthorizationValidateState(config, client, short), "invalid_state")
+	assert.NoError(t, oidcAuthorizationValidateState(config, client, valid))
+
+	config.MinimumParameterEntropy = 16
+
+	assert.EqualError(t, oidcAuthorizationValidateState(config, client, valid), "invalid_state")
+}
+
+func TestShouldRejectTokenEndpointRequestsEarly(t *testing.T) {
+	provider := newTestOpenIDConnectProvider(t)
+	provider.TokenEndpointRateLimiter = oidc.NewKeyedRateLimiter(1, 1)
+
+	testCases := []struct {
+		name         string
+		forwardedFor string
+		status       int
+		retryAfter   string
+	}{
+		{"ShouldProcessFirstRequest", "", 400, ""},
+		{"ShouldRateLimitSecondRequest", "", 429, "1"},
+		{"ShouldIgnoreForwardedForFromUntrustedPeer", "192.168.1.10", 429, "1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Clock = &mock.Clock
+			mock.Ctx.Providers.OpenIDConnect = provider
+			mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{}
+
+			mock.Ctx.Request.Header.SetMethod("POST")
+			mock.Ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+			mock.Ctx.Request.SetRequestURI("/api/oidc/token")
+			mock.Ctx.Request.SetBodyString("grant_type=client_credentials")
+
+			if tc.forwardedFor != "" {
+				mock.Ctx.Request.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+
+			middlewares.NewHTTPToAutheliaHandlerAdaptor(oidcToken)(mock.Ctx)
+
+			assert.Equal(t, tc.status, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.retryAfter, string(mock.Ctx.Response.Header.Peek("Retry-After")))
+		})
+	}
+}
+
+func TestShouldHandleOpenIDConnectDiscoveryPreflightRequests(t *testing.T) {
+	for _, path := range []string{oidc.WellKnownOpenIDConfigurationPath, oidc.WellKnownOAuthAuthorizationServerPath} {
+		t.Run(path, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			r := router.New()
+
+			RegisterOIDC(r, func(next middlewares.RequestHandler) fasthttp.RequestHandler {
+				return func(_ *fasthttp.RequestCtx) {
+					next(mock.Ctx)
+				}
+			})
+
+			mock.Ctx.Request.Header.SetMethod(fasthttp.MethodOptions)
+			mock.Ctx.Request.SetRequestURI(path)
+			mock.Ctx.Request.Header.Set(fasthttp.HeaderOrigin, "https://app.example.com")
+			mock.Ctx.Request.Header.Set(fasthttp.HeaderAccessControlRequestMethod, fasthttp.MethodGet)
+
+			r.Handler(mock.Ctx.RequestCtx)
+
+			assert.Equal(t, fasthttp.StatusNoContent, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, "https://app.example.com", string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin)))
+			assert.Equal(t, fasthttp.MethodGet, string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderAccessControlAllowMethods)))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// updateRememberMeExpiration sets the session expiration to the remember me duration. The remember me duration can never
+// extend the session beyond the maximum lifetime.
+func updateRememberMeExpiration(ctx *middlewares.AutheliaCtx) (err error) {
+	expiration := ctx.Providers.SessionProvider.RememberMe
+
+	if maxLifetime := ctx.Providers.SessionProvider.MaximumLifetime; maxLifetime > 0 && maxLifetime < expiration {
+		expiration = maxLifetime
+	}
+
+	return ctx.Providers.SessionProvider.UpdateExpiration(ctx.RequestCtx, expiration)
+}
+
+// handleRememberMeSecondFactor honors a remember me request which was deferred during the first factor because
+// remember me requires two factor authentication. It must be called after the second factor has been completed.
+func handleRememberMeSecondFactor(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) (err error) {
+	if !userSession.KeepMeLoggedInPending {
+		return nil
+	}
+
+	if err = updateRememberMeExpiration(ctx); err != nil {
+		return err
+	}
+
+	userSession.KeepMeLoggedIn, userSession.KeepMeLoggedInPending = true, false
+
+	return nil
+}
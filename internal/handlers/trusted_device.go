@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// handleTrustedDeviceSecondFactor trusts the device of the user for the configured lifespan after a successful second
+// factor. Failing to trust the device is logged but doesn't fail the second factor as the user is still authenticated.
+func handleTrustedDeviceSecondFactor(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) {
+	lifespan := ctx.Configuration.TrustedDevice.Lifespan
+	if lifespan <= 0 {
+		return
+	}
+
+	jti, err := uuid.NewRandom()
+	if err != nil {
+		ctx.Logger.Errorf(logFmtErrTrustedDevice, userSession.Username, err)
+
+		return
+	}
+
+	device := model.NewTrustedDevice(jti, userSession.Username, ctx.RemoteIP(), ctx.Clock.Now(), lifespan)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, device.ToTrustedDeviceClaim()).SignedString([]byte(ctx.Configuration.JWTSecret))
+	if err != nil {
+		ctx.Logger.Errorf(logFmtErrTrustedDevice, userSession.Username, err)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveTrustedDevice(ctx, device); err != nil {
+		ctx.Logger.Errorf(logFmtErrTrustedDevice, userSession.Username, err)
+
+		return
+	}
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(ctx.Configuration.TrustedDevice.CookieName)
+	cookie.SetValue(token)
+	cookie.SetPath("/")
+	cookie.SetExpire(device.ExpiresAt)
+	cookie.SetHTTPOnly(true)
+	cookie.SetSecure(true)
+	cookie.SetSameSite(fasthttp.CookieSameSiteStrictMode)
+
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+// isTrustedDevice returns true if the request has a trusted device cookie for the given username which is valid and
+// hasn't been revoked.
+func isTrustedDevice(ctx *middlewares.AutheliaCtx, username string) (trusted bool, err error) {
+	if ctx.Configuration.TrustedDevice.Lifespan <= 0 {
+		return false, nil
+	}
+
+	value := ctx.Request.Header.Cookie(ctx.Configuration.TrustedDevice.CookieName)
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	claims := &model.TrustedDeviceClaim{}
+
+	if _, err = jwt.ParseWithClaims(string(value), claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return []byte(ctx.Configuration.JWTSecret), nil
+	}); err != nil {
+		return false, fmt.Errorf("the trusted device token is invalid: %w", err)
+	}
+
+	if claims.Username != username {
+		return false, nil
+	}
+
+	device, err := ctx.Providers.StorageProvider.LoadTrustedDevice(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNoTrustedDevice) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if !device.IsTrusted(username, ctx.Clock.Now()) {
+		return false, nil
+	}
+
+	if ctx.Configuration.TrustedDevice.BindToIP && !device.IssuedIP.IP.Equal(ctx.RemoteIP()) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// UserInfoTrustedDevicesRevokePOST revokes all of the trusted devices of the current user.
+func UserInfoTrustedDevicesRevokePOST(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	if err := ctx.Providers.StorageProvider.RevokeTrustedDevices(ctx, userSession.Username); err != nil {
+		ctx.Error(fmt.Errorf("unable to revoke the trusted devices of user '%s': %w", userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	ctx.Response.Header.DelClientCookie(ctx.Configuration.TrustedDevice.CookieName)
+
+	ctx.ReplyOK()
+}
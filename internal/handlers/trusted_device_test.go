@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func setupTrustedDevice(t *testing.T) (mock *mocks.MockAutheliaCtx, device model.TrustedDevice, token string) {
+	mock = mocks.NewMockAutheliaCtx(t)
+
+	mock.Ctx.Configuration.JWTSecret = "abc"
+	mock.Ctx.Configuration.TrustedDevice.Lifespan = time.Hour
+	mock.Ctx.Configuration.TrustedDevice.CookieName = "authelia_trusted_device"
+
+	mock.StorageMock.EXPECT().
+		SaveTrustedDevice(mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, d model.TrustedDevice) error {
+			device = d
+
+			return nil
+		})
+
+	handleTrustedDeviceSecondFactor(mock.Ctx, &session.UserSession{Username: "john"})
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey("authelia_trusted_device")
+
+	require.True(t, mock.Ctx.Response.Header.Cookie(cookie))
+
+	assert.True(t, cookie.HTTPOnly())
+	assert.True(t, cookie.Secure())
+	assert.Equal(t, device.ExpiresAt.Unix(), cookie.Expire().Unix())
+
+	return mock, device, string(cookie.Value())
+}
+
+func TestShouldTrustDeviceAfterSecondFactor(t *testing.T) {
+	mock, device, token := setupTrustedDevice(t)
+	defer mock.Close()
+
+	assert.Equal(t, "john", device.Username)
+	assert.Equal(t, time.Hour, device.ExpiresAt.Sub(device.IssuedAt))
+
+	mock.Ctx.Request.Header.SetCookie("authelia_trusted_device", token)
+
+	mock.StorageMock.EXPECT().
+		LoadTrustedDevice(mock.Ctx, device.JTI.String()).
+		Return(&device, nil)
+
+	trusted, err := isTrustedDevice(mock.Ctx, "john")
+	assert.NoError(t, err)
+	assert.True(t, trusted)
+
+	trusted, err = isTrustedDevice(mock.Ctx, "harry")
+	assert.NoError(t, err)
+	assert.False(t, trusted)
+}
+
+func TestShouldNotTrustDeviceWhenDisabled(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	handleTrustedDeviceSecondFactor(mock.Ctx, &session.UserSession{Username: "john"})
+
+	assert.Len(t, mock.Ctx.Response.Header.PeekCookie("authelia_trusted_device"), 0)
+
+	mock.Ctx.Request.Header.SetCookie("authelia_trusted_device", "abc")
+
+	trusted, err := isTrustedDevice(mock.Ctx, "john")
+	assert.NoError(t, err)
+	assert.False(t, trusted)
+}
+
+func TestShouldNotTrustRevokedOrUnknownDevice(t *testing.T) {
+	mock, device, token := setupTrustedDevice(t)
+	defer mock.Close()
+
+	mock.Ctx.Request.Header.SetCookie("authelia_trusted_device", token)
+
+	revoked := device
+	revokedAt := time.Now()
+	revoked.Revoked = &revokedAt
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().
+			LoadTrustedDevice(mock.Ctx, device.JTI.String()).
+			Return(&revoked, nil),
+		mock.StorageMock.EXPECT().
+			LoadTrustedDevice(mock.Ctx, device.JTI.String()).
+			Return(nil, storage.ErrNoTrustedDevice),
+	)
+
+	trusted, err := isTrustedDevice(mock.Ctx, "john")
+	assert.NoError(t, err)
+	assert.False(t, trusted)
+
+	trusted, err = isTrustedDevice(mock.Ctx, "john")
+	assert.NoError(t, err)
+	assert.False(t, trusted)
+}
+
+func TestShouldNotTrustDeviceFromAnotherIPWhenBoundToIP(t *testing.T) {
+	mock, device, token := setupTrustedDevice(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.TrustedDevice.BindToIP = true
+	mock.Ctx.Request.Header.SetCookie("authelia_trusted_device", token)
+
+	device.IssuedIP = model.NewIP(net.ParseIP("192.168.0.1"))
+
+	mock.StorageMock.EXPECT().
+		LoadTrustedDevice(mock.Ctx, device.JTI.String()).
+		Return(&device, nil)
+
+	trusted, err := isTrustedDevice(mock.Ctx, "john")
+	assert.NoError(t, err)
+	assert.False(t, trusted)
+}
+
+func TestShouldNotTrustDeviceWithInvalidToken(t *testing.T) {
+	mock, _, token := setupTrustedDevice(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.JWTSecret = "def"
+	mock.Ctx.Request.Header.SetCookie("authelia_trusted_device", token)
+
+	trusted, err := isTrustedDevice(mock.Ctx, "john")
+	assert.EqualError(t, err, "the trusted device token is invalid: signature is invalid")
+	assert.False(t, trusted)
+}
+
+func TestShouldRevokeTrustedDevices(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.TrustedDevice.CookieName = "authelia_trusted_device"
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.StorageMock.EXPECT().
+		RevokeTrustedDevices(mock.Ctx, "john").
+		Return(nil)
+
+	UserInfoTrustedDevicesRevokePOST(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Contains(t, string(mock.Ctx.Response.Header.PeekCookie("authelia_trusted_device")), "expires=")
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"io"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 )
@@ -11,6 +12,12 @@ type MethodList = []string
 
 type authorizationMatching int
 
+// healthReadyResponseBody the content returned by the readiness endpoint.
+type healthReadyResponseBody struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
 // configurationBody the content returned by the configuration endpoint.
 type configurationBody struct {
 	AvailableMethods MethodList `json:"available_methods"`
@@ -103,6 +110,7 @@ type StateResponse struct {
 	Username              string               `json:"username"`
 	AuthenticationLevel   authentication.Level `json:"authentication_level"`
 	DefaultRedirectionURL string               `json:"default_redirection_url"`
+	LoginHint             string               `json:"login_hint,omitempty"`
 }
 
 // resetPasswordStep1RequestBody model of the reset password (step1) request body.
@@ -135,3 +143,13 @@ type responseWriter interface {
 	SetContentTypeBytes(contentType []byte)
 	SetBodyStream(bodyStream io.Reader, bodySize int)
 }
+
+// userSessionResponse is a session of the user returned by the user sessions endpoint.
+type userSessionResponse struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+}
@@ -2,8 +2,9 @@ package handlers
 
 // ConsentPostRequestBody schema of the request body of the consent POST endpoint.
 type ConsentPostRequestBody struct {
-	ClientID       string `json:"client_id"`
-	AcceptOrReject string `json:"accept_or_reject"`
+	ClientID       string   `json:"client_id"`
+	AcceptOrReject string   `json:"accept_or_reject"`
+	GrantedScopes  []string `json:"granted_scopes,omitempty"`
 }
 
 // ConsentPostResponseBody schema of the response body of the consent POST endpoint.
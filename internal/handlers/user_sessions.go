@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// handleUserSessionRecordFirstFactor records the session of the user after a successful first factor so it can be
+// listed and revoked by the user. The record of a previous session is replaced as the session ID was regenerated.
+// Failing to record the session is logged but doesn't fail the first factor as the user is still authenticated.
+func handleUserSessionRecordFirstFactor(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) {
+	if userSession.SessionRecordID != "" {
+		if err := ctx.Providers.StorageProvider.DeleteUserSessionRecord(ctx, userSession.SessionRecordID); err != nil {
+			ctx.Logger.Errorf(logFmtErrSessionRecord, "delete", userSession.Username, err)
+		}
+
+		userSession.SessionRecordID, userSession.SessionRecordLastSeen = "", 0
+	}
+
+	publicID, err := uuid.NewRandom()
+	if err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRecord, "save", userSession.Username, err)
+
+		return
+	}
+
+	sessionID, err := ctx.Providers.SessionProvider.GetSessionID(ctx.RequestCtx)
+	if err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRecord, "save", userSession.Username, err)
+
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	record := model.NewUserSessionRecord(publicID, sessionID, userSession.Username, ctx.RemoteIP(), string(ctx.UserAgent()), now)
+
+	if err = ctx.Providers.StorageProvider.SaveUserSessionRecord(ctx, record); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRecord, "save", userSession.Username, err)
+
+		return
+	}
+
+	userSession.SessionRecordID, userSession.SessionRecordLastSeen = publicID.String(), now.Unix()
+}
+
+// handleUserSessionRecordRegenerated updates the session ID of the session record of the user after the session ID was
+// regenerated during the second factor.
+func handleUserSessionRecordRegenerated(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) {
+	if userSession.SessionRecordID == "" {
+		return
+	}
+
+	sessionID, err := ctx.Providers.SessionProvider.GetSessionID(ctx.RequestCtx)
+	if err == nil {
+		err = ctx.Providers.StorageProvider.UpdateUserSessionRecordSessionID(ctx, userSession.SessionRecordID, sessionID)
+	}
+
+	if err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRecord, "update", userSession.Username, err)
+	}
+}
+
+// handleUserSessionRecordLastSeen updates the last seen time of the session record of the user at most once every
+// userSessionRecordLastSeenInterval. It returns true if the user session was changed and needs to be saved.
+func handleUserSessionRecordLastSeen(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) (changed bool) {
+	if userSession.SessionRecordID == "" {
+		return false
+	}
+
+	now := ctx.Clock.Now()
+
+	if now.Unix()-userSession.SessionRecordLastSeen < int64(userSessionRecordLastSeenInterval.Seconds()) {
+		return false
+	}
+
+	if err := ctx.Providers.StorageProvider.UpdateUserSessionRecordLastSeen(ctx, userSession.SessionRecordID, now); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRecord, "update", userSession.Username, err)
+
+		return false
+	}
+
+	userSession.SessionRecordLastSeen = now.Unix()
+
+	return true
+}
+
+// handleUserSessionRecordLogout deletes the session record of the user when the session is destroyed during logout.
+func handleUserSessionRecordLogout(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) {
+	if userSession.SessionRecordID == "" {
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.DeleteUserSessionRecord(ctx, userSession.SessionRecordID); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRecord, "delete", userSession.Username, err)
+	}
+}
+
+// loadUserSessionRecords loads the session records of the user. The records of sessions which no longer exist in the
+// session store, because they expired or were destroyed, are deleted and not returned.
+func loadUserSessionRecords(ctx *middlewares.AutheliaCtx, username string) (records []model.UserSessionRecord, err error) {
+	all, err := ctx.Providers.StorageProvider.LoadUserSessionRecords(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	records = make([]model.UserSessionRecord, 0, len(all))
+
+	var exists bool
+
+	for _, record := range all {
+		exists, err = ctx.Providers.SessionProvider.SessionExists(record.SessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			if err = ctx.Providers.StorageProvider.DeleteUserSessionRecord(ctx, record.PublicID.String()); err != nil {
+				ctx.Logger.Errorf(logFmtErrSessionRecord, "delete", username, err)
+			}
+
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// UserSessionsGET lists the active sessions of the current user.
+func UserSessionsGET(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	records, err := loadUserSessionRecords(ctx, userSession.Username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the sessions of user '%s': %w", userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	sessionID, err := ctx.Providers.SessionProvider.GetSessionID(ctx.RequestCtx)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the sessions of user '%s': %w", userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	sessions := make([]userSessionResponse, len(records))
+
+	for i, record := range records {
+		sessions[i] = userSessionResponse{
+			ID:         record.PublicID.String(),
+			CreatedAt:  record.CreatedAt,
+			LastSeenAt: record.LastSeenAt,
+			IP:         record.IP.IP.String(),
+			UserAgent:  record.UserAgent,
+			Current:    bytes.Equal(record.SessionID, sessionID),
+		}
+	}
+
+	if err = ctx.SetJSONBody(sessions); err != nil {
+		ctx.Logger.Errorf("Unable to set the sessions response in body: %s", err)
+	}
+}
+
+// UserSessionsDELETE revokes the session of the current user with the given public ID.
+func UserSessionsDELETE(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	id, _ := ctx.UserValue("id").(string)
+
+	records, err := loadUserSessionRecords(ctx, userSession.Username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the sessions of user '%s': %w", userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	for _, record := range records {
+		if record.PublicID.String() != id {
+			continue
+		}
+
+		if err = ctx.Providers.SessionProvider.DestroySessionByID(record.SessionID); err != nil {
+			ctx.Error(fmt.Errorf("unable to revoke the session '%s' of user '%s': %w", id, userSession.Username, err), messageOperationFailed)
+
+			return
+		}
+
+		if err = ctx.Providers.StorageProvider.DeleteUserSessionRecord(ctx, id); err != nil {
+			ctx.Error(fmt.Errorf("unable to revoke the session '%s' of user '%s': %w", id, userSession.Username, err), messageOperationFailed)
+
+			return
+		}
+
+		ctx.ReplyOK()
+
+		return
+	}
+
+	ctx.Error(fmt.Errorf("unable to revoke the session '%s' of user '%s': the session does not exist", id, userSession.Username), messageOperationFailed)
+}
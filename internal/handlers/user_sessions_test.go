@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func TestShouldRecordUserSessionAndFollowRegeneration(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Clock = &mock.Clock
+
+	mock.Ctx.Request.Header.SetUserAgent("Mozilla/5.0")
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	sessionID, err := mock.Ctx.Providers.SessionProvider.GetSessionID(mock.Ctx.RequestCtx)
+	require.NoError(t, err)
+
+	var record model.UserSessionRecord
+
+	mock.StorageMock.EXPECT().
+		SaveUserSessionRecord(mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, r model.UserSessionRecord) error {
+			record = r
+
+			return nil
+		})
+
+	handleUserSessionRecordFirstFactor(mock.Ctx, &userSession)
+
+	assert.Equal(t, record.PublicID.String(), userSession.SessionRecordID)
+	assert.Equal(t, mock.Clock.Now().Unix(), userSession.SessionRecordLastSeen)
+	assert.Equal(t, sessionID, record.SessionID)
+	assert.Equal(t, "john", record.Username)
+	assert.Equal(t, "Mozilla/5.0", record.UserAgent)
+
+	require.NoError(t, mock.Ctx.Providers.SessionProvider.RegenerateSession(mock.Ctx.RequestCtx))
+
+	newSessionID, err := mock.Ctx.Providers.SessionProvider.GetSessionID(mock.Ctx.RequestCtx)
+	require.NoError(t, err)
+	require.NotEqual(t, sessionID, newSessionID)
+
+	mock.StorageMock.EXPECT().
+		UpdateUserSessionRecordSessionID(mock.Ctx, userSession.SessionRecordID, newSessionID).
+		Return(nil)
+
+	handleUserSessionRecordRegenerated(mock.Ctx, &userSession)
+}
+
+func TestShouldReplaceUserSessionRecordOnFirstFactor(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	userSession := session.UserSession{Username: "john", SessionRecordID: "b5a1e1e4-8c34-4a4a-9d8e-0a3c7f0c1c2d"}
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().
+			DeleteUserSessionRecord(mock.Ctx, "b5a1e1e4-8c34-4a4a-9d8e-0a3c7f0c1c2d").
+			Return(nil),
+		mock.StorageMock.EXPECT().
+			SaveUserSessionRecord(mock.Ctx, gomock.Any()).
+			Return(nil),
+	)
+
+	handleUserSessionRecordFirstFactor(mock.Ctx, &userSession)
+
+	assert.NotEqual(t, "b5a1e1e4-8c34-4a4a-9d8e-0a3c7f0c1c2d", userSession.SessionRecordID)
+}
+
+func TestShouldUpdateUserSessionRecordLastSeenOncePerInterval(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Clock = &mock.Clock
+
+	userSession := session.UserSession{
+		Username:              "john",
+		SessionRecordID:       "b5a1e1e4-8c34-4a4a-9d8e-0a3c7f0c1c2d",
+		SessionRecordLastSeen: mock.Clock.Now().Unix(),
+	}
+
+	assert.False(t, handleUserSessionRecordLastSeen(mock.Ctx, &userSession))
+
+	mock.Clock.Set(mock.Clock.Now().Add(time.Minute))
+
+	mock.StorageMock.EXPECT().
+		UpdateUserSessionRecordLastSeen(mock.Ctx, "b5a1e1e4-8c34-4a4a-9d8e-0a3c7f0c1c2d", mock.Clock.Now()).
+		Return(nil)
+
+	assert.True(t, handleUserSessionRecordLastSeen(mock.Ctx, &userSession))
+	assert.Equal(t, mock.Clock.Now().Unix(), userSession.SessionRecordLastSeen)
+
+	assert.False(t, handleUserSessionRecordLastSeen(mock.Ctx, &session.UserSession{Username: "john"}))
+}
+
+func TestShouldListUserSessionsAndDeleteStaleRecords(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	sessionID, err := mock.Ctx.Providers.SessionProvider.GetSessionID(mock.Ctx.RequestCtx)
+	require.NoError(t, err)
+
+	current := model.NewUserSessionRecord(uuid.New(), sessionID, "john", mock.Ctx.RemoteIP(), "Mozilla/5.0", mock.Clock.Now())
+	stale := model.NewUserSessionRecord(uuid.New(), []byte("expired"), "john", mock.Ctx.RemoteIP(), "curl/7.83.1", mock.Clock.Now())
+
+	mock.StorageMock.EXPECT().
+		LoadUserSessionRecords(mock.Ctx, "john").
+		Return([]model.UserSessionRecord{current, stale}, nil)
+
+	mock.StorageMock.EXPECT().
+		DeleteUserSessionRecord(mock.Ctx, stale.PublicID.String()).
+		Return(nil)
+
+	UserSessionsGET(mock.Ctx)
+
+	var response struct {
+		Status string                `json:"status"`
+		Data   []userSessionResponse `json:"data"`
+	}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &response))
+
+	assert.Equal(t, "OK", response.Status)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, current.PublicID.String(), response.Data[0].ID)
+	assert.Equal(t, "Mozilla/5.0", response.Data[0].UserAgent)
+	assert.True(t, response.Data[0].Current)
+}
+
+func TestShouldRevokeUserSession(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	other := &fasthttp.RequestCtx{}
+	require.NoError(t, mock.Ctx.Providers.SessionProvider.SaveSession(other, session.UserSession{Username: "john"}))
+
+	otherSessionID, err := mock.Ctx.Providers.SessionProvider.GetSessionID(other)
+	require.NoError(t, err)
+
+	record := model.NewUserSessionRecord(uuid.New(), otherSessionID, "john", mock.Ctx.RemoteIP(), "Mozilla/5.0", mock.Clock.Now())
+
+	mock.StorageMock.EXPECT().
+		LoadUserSessionRecords(mock.Ctx, "john").
+		Return([]model.UserSessionRecord{record}, nil)
+
+	mock.StorageMock.EXPECT().
+		DeleteUserSessionRecord(mock.Ctx, record.PublicID.String()).
+		Return(nil)
+
+	mock.Ctx.SetUserValue("id", record.PublicID.String())
+
+	UserSessionsDELETE(mock.Ctx)
+
+	assert.Equal(t, []byte("{\"status\":\"OK\"}"), mock.Ctx.Response.Body())
+
+	exists, err := mock.Ctx.Providers.SessionProvider.SessionExists(otherSessionID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestShouldNotRevokeUnknownUserSession(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.StorageMock.EXPECT().
+		LoadUserSessionRecords(mock.Ctx, "john").
+		Return(nil, nil)
+
+	mock.Ctx.SetUserValue("id", "b5a1e1e4-8c34-4a4a-9d8e-0a3c7f0c1c2d")
+
+	UserSessionsDELETE(mock.Ctx)
+
+	assert.Equal(t, "unable to revoke the session 'b5a1e1e4-8c34-4a4a-9d8e-0a3c7f0c1c2d' of user 'john': the session does not exist", mock.Hook.LastEntry().Message)
+	assert.Equal(t, []byte("{\"status\":\"KO\",\"message\":\"Operation failed.\"}"), mock.Ctx.Response.Body())
+}
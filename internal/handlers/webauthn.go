@@ -6,10 +6,12 @@ import (
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func getWebAuthnUser(ctx *middlewares.AutheliaCtx, userSession session.UserSession) (user *model.WebauthnUser, err error) {
@@ -61,3 +63,12 @@ func newWebauthn(ctx *middlewares.AutheliaCtx) (w *webauthn.WebAuthn, err error)
 
 	return webauthn.New(config)
 }
+
+// isWebauthnAAGUIDAllowed returns true if the allowed AAGUIDs list is empty or contains the provided AAGUID.
+func isWebauthnAAGUIDAllowed(allowed []string, aaguid uuid.UUID) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	return utils.IsStringInSlice(aaguid.String(), allowed)
+}
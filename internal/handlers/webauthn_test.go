@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -165,3 +166,12 @@ func TestWebauthnNewWebauthnShouldReturnErrWhenWebauthnNotConfigured(t *testing.
 	assert.Nil(t, w)
 	assert.EqualError(t, err, "Configuration error: Missing RPDisplayName")
 }
+
+func TestWebauthnShouldCheckAAGUIDIsAllowed(t *testing.T) {
+	aaguid := uuid.MustParse("cb69481e-8ff7-4039-93ec-0a2729a154a8")
+
+	assert.True(t, isWebauthnAAGUIDAllowed(nil, aaguid))
+	assert.True(t, isWebauthnAAGUIDAllowed([]string{"ee882879-721c-4913-9775-3dfcce97072a", "cb69481e-8ff7-4039-93ec-0a2729a154a8"}, aaguid))
+	assert.False(t, isWebauthnAAGUIDAllowed([]string{"ee882879-721c-4913-9775-3dfcce97072a"}, aaguid))
+	assert.False(t, isWebauthnAAGUIDAllowed([]string{"ee882879-721c-4913-9775-3dfcce97072a"}, uuid.Nil))
+}
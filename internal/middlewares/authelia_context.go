@@ -152,6 +152,16 @@ func (ctx *AutheliaCtx) XForwardedHost() (host []byte) {
 	return host
 }
 
+// GetSessionDomains returns the domains protected by the session, falling back to the session domain when the list of
+// session domains has not been populated.
+func (ctx *AutheliaCtx) GetSessionDomains() (domains []string) {
+	if len(ctx.Configuration.Session.Domains) == 0 {
+		return []string{ctx.Configuration.Session.Domain}
+	}
+
+	return ctx.Configuration.Session.Domains
+}
+
 // XForwardedURI return the content of the X-Forwarded-URI header.
 func (ctx *AutheliaCtx) XForwardedURI() (uri []byte) {
 	uri = ctx.RequestCtx.Request.Header.PeekBytes(headerXForwardedURI)
@@ -172,8 +182,13 @@ func (ctx *AutheliaCtx) BasePath() (base string) {
 	return base
 }
 
-// ExternalRootURL gets the X-Forwarded-Proto, X-Forwarded-Host headers and the BasePath and forms them into a URL.
+// ExternalRootURL gets the X-Forwarded-Proto, X-Forwarded-Host headers and the BasePath and forms them into a URL. If
+// the external URL is configured it's used instead.
 func (ctx *AutheliaCtx) ExternalRootURL() (string, error) {
+	if ctx.Configuration.Server.ExternalURL.String() != "" {
+		return ctx.Configuration.Server.ExternalURL.String(), nil
+	}
+
 	protocol := ctx.XForwardedProto()
 	if protocol == nil {
 		return "", errMissingXForwardedProto
@@ -205,7 +220,8 @@ func (ctx *AutheliaCtx) XOriginalURL() []byte {
 	return ctx.RequestCtx.Request.Header.PeekBytes(headerXOriginalURL)
 }
 
-// GetSession return the user session. Any update will be saved in cache.
+// GetSession return the user session. Any update will be saved in cache. Sessions which have exceeded the maximum
+// lifetime are destroyed and a default session is returned instead.
 func (ctx *AutheliaCtx) GetSession() session.UserSession {
 	userSession, err := ctx.Providers.SessionProvider.GetSession(ctx.RequestCtx)
 	if err != nil {
@@ -213,6 +229,16 @@ func (ctx *AutheliaCtx) GetSession() session.UserSession {
 		return session.NewDefaultUserSession()
 	}
 
+	if userSession.HasExceededMaximumLifetime(ctx.Clock.Now(), ctx.Providers.SessionProvider.MaximumLifetime) {
+		ctx.Logger.Infof("User %s has exceeded the maximum session lifetime, the session will be destroyed", userSession.Username)
+
+		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+			ctx.Logger.Errorf("Unable to destroy user session after exceeding the maximum lifetime: %v", err)
+		}
+
+		return session.NewDefaultUserSession()
+	}
+
 	return userSession
 }
 
@@ -72,6 +72,32 @@ func TestShouldGetOriginalURLFromForwardedHeadersWithURI(t *testing.T) {
 	assert.Equal(t, "Unable to parse URL extracted from X-Original-URL header: parse \"htt-ps//home?-.example.com\": invalid URI for request", err.Error())
 }
 
+func TestShouldGetExternalRootURLFromForwardedHeaders(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "home.example.com")
+
+	externalRootURL, err := mock.Ctx.ExternalRootURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://home.example.com", externalRootURL)
+}
+
+func TestShouldPreferConfiguredExternalURLForExternalRootURL(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Server.ExternalURL = url.URL{Scheme: "https", Host: "auth.example.com", Path: "/authelia"}
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "http")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "internal-proxy.local")
+
+	externalRootURL, err := mock.Ctx.ExternalRootURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com/authelia", externalRootURL)
+}
+
 func TestShouldFallbackToNonXForwardedHeaders(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
@@ -23,12 +23,18 @@ var (
 	headerAccessControlAllowHeaders     = []byte(fasthttp.HeaderAccessControlAllowHeaders)
 	headerAccessControlAllowMethods     = []byte(fasthttp.HeaderAccessControlAllowMethods)
 	headerAccessControlAllowOrigin      = []byte(fasthttp.HeaderAccessControlAllowOrigin)
+	headerAccessControlExposeHeaders    = []byte(fasthttp.HeaderAccessControlExposeHeaders)
 	headerAccessControlMaxAge           = []byte(fasthttp.HeaderAccessControlMaxAge)
 	headerAccessControlRequestHeaders   = []byte(fasthttp.HeaderAccessControlRequestHeaders)
 	headerAccessControlRequestMethod    = []byte(fasthttp.HeaderAccessControlRequestMethod)
 )
 
+const logRedacted = "<redacted>"
+
+const corsDefaultMaximumRequestHeaders = 100
+
 var (
+	headerValueTrue   = []byte("true")
 	headerValueFalse  = []byte("false")
 	headerValueMaxAge = []byte("100")
 	headerValueVary   = []byte("Accept-Encoding, Origin")
@@ -1,53 +1,335 @@
 package middlewares
 
 import (
+	"expvar"
+	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 )
 
-// CORSApplyAutomaticAllowAllPolicy applies a CORS policy that automatically grants all Origins as well
-// as all Request Headers other than Cookie and *. It does not allow credentials, and has a max age of 100. Vary is applied
-// to both Accept-Encoding and Origin. It grants the GET Request Method only.
-func CORSApplyAutomaticAllowAllPolicy(next RequestHandler) RequestHandler {
+// NewCORSMiddleware returns a new CORSMiddleware with the default values. By default it automatically grants all https
+// Origins as well as all Request Headers other than Cookie and *. It does not allow credentials, and has a max age of
+// 100. Vary is applied to both Accept-Encoding and Origin. It grants the requested Request Method.
+func NewCORSMiddleware() (cors *CORSMiddleware) {
+	return &CORSMiddleware{
+		vary:                    headerValueVary,
+		maxAge:                  headerValueMaxAge,
+		maximumRequestHeaders:   corsDefaultMaximumRequestHeaders,
+		automaticRequestHeaders: true,
+		automaticRequestMethods: true,
+	}
+}
+
+// CORSMiddleware is a middleware builder which applies a CORS policy to responses.
+type CORSMiddleware struct {
+	vary   []byte
+	maxAge []byte
+
+	maximumRequestHeaders int
+
+	automaticRequestHeaders bool
+	automaticRequestMethods bool
+
+	credentials     bool
+	insecureOrigins bool
+
+	allowedOrigins           []string
+	allowedOriginDomains     []string
+	allowedRequestHeaders    []string
+	allowedRequestMethods    []string
+	allowedRequestHeadersRaw []byte
+	defaultRequestMethods    []byte
+
+	exposedHeaders []byte
+}
+
+// WithAllowedOrigins restricts the Origins which are granted to the exact provided values. By default all https Origins
+// are granted.
+func (cors *CORSMiddleware) WithAllowedOrigins(origins ...string) *CORSMiddleware {
+	cors.allowedOrigins = origins
+
+	return cors
+}
+
+// WithAllowedOriginsFromDomains restricts the Origins which are granted to those with a host which is one of the
+// provided domains or a subdomain of one of them. It can be combined with WithAllowedOrigins in which case an Origin
+// matching either is granted.
+func (cors *CORSMiddleware) WithAllowedOriginsFromDomains(domains ...string) *CORSMiddleware {
+	cors.allowedOriginDomains = make([]string, len(domains))
+
+	for i, domain := range domains {
+		cors.allowedOriginDomains[i] = strings.ToLower(strings.TrimPrefix(domain, "."))
+	}
+
+	return cors
+}
+
+// WithAllowedRequestHeaders grants the provided Request Headers instead of automatically granting the requested ones.
+func (cors *CORSMiddleware) WithAllowedRequestHeaders(headers ...string) *CORSMiddleware {
+	cors.automaticRequestHeaders = false
+	cors.allowedRequestHeaders = headers
+	cors.allowedRequestHeadersRaw = []byte(strings.Join(headers, ", "))
+
+	return cors
+}
+
+// WithAllowedRequestMethods grants the provided Request Methods instead of automatically granting the requested one.
+func (cors *CORSMiddleware) WithAllowedRequestMethods(methods ...string) *CORSMiddleware {
+	cors.automaticRequestMethods = false
+	cors.allowedRequestMethods = methods
+
+	return cors
+}
+
+// WithDefaultMethods sets the Access-Control-Allow-Methods value used when the Request Method is automatically granted
+// and the request does not have the Access-Control-Request-Method header. By default the header is not set in this
+// case. The requested method is still granted when the request has the header.
+func (cors *CORSMiddleware) WithDefaultMethods(methods ...string) *CORSMiddleware {
+	if len(methods) == 0 {
+		cors.defaultRequestMethods = nil
+	} else {
+		cors.defaultRequestMethods = []byte(strings.Join(methods, ", "))
+	}
+
+	return cors
+}
+
+// WithExposedHeaders sets the Access-Control-Expose-Headers value which allows the provided Response Headers to be read
+// by scripts in the browser. By default the header is not set.
+func (cors *CORSMiddleware) WithExposedHeaders(headers ...string) *CORSMiddleware {
+	if len(headers) == 0 {
+		cors.exposedHeaders = nil
+	} else {
+		cors.exposedHeaders = []byte(strings.Join(headers, ", "))
+	}
+
+	return cors
+}
+
+// WithAllowCredentials sets the Access-Control-Allow-Credentials value.
+func (cors *CORSMiddleware) WithAllowCredentials(allow bool) *CORSMiddleware {
+	cors.credentials = allow
+
+	return cors
+}
+
+// WithAllowInsecureOrigins also processes http Origins when enabled. This is only intended for local development, by
+// default only https Origins are processed.
+func (cors *CORSMiddleware) WithAllowInsecureOrigins(allow bool) *CORSMiddleware {
+	cors.insecureOrigins = allow
+
+	return cors
+}
+
+// WithVary sets the Vary header value.
+func (cors *CORSMiddleware) WithVary(values ...string) *CORSMiddleware {
+	cors.vary = []byte(strings.Join(values, ", "))
+
+	return cors
+}
+
+// WithMaxAge sets the Access-Control-Max-Age value in seconds.
+func (cors *CORSMiddleware) WithMaxAge(age int) *CORSMiddleware {
+	cors.maxAge = []byte(strconv.Itoa(age))
+
+	return cors
+}
+
+// WithMaximumRequestHeaders sets the maximum number of headers processed from the Access-Control-Request-Headers
+// header, any additional headers are ignored. This prevents oversized preflight requests causing excessive work.
+func (cors *CORSMiddleware) WithMaximumRequestHeaders(maximum int) *CORSMiddleware {
+	cors.maximumRequestHeaders = maximum
+
+	return cors
+}
+
+// Middleware applies the CORS policy to the response of the next handler.
+func (cors *CORSMiddleware) Middleware(next RequestHandler) RequestHandler {
 	return func(ctx *AutheliaCtx) {
-		if origin := ctx.Request.Header.PeekBytes(headerOrigin); origin != nil {
-			corsApplyAutomaticAllowAllPolicy(&ctx.Request, &ctx.Response, origin)
-		}
+		cors.handleCORS(ctx)
 
 		next(ctx)
 	}
 }
 
-func corsApplyAutomaticAllowAllPolicy(req *fasthttp.Request, resp *fasthttp.Response, origin []byte) {
-	originURL, err := url.Parse(string(origin))
-	if err != nil || originURL.Scheme != "https" {
+// HandleOPTIONS handles a preflight request by applying the CORS policy and responding with 204 No Content.
+func (cors *CORSMiddleware) HandleOPTIONS(ctx *AutheliaCtx) {
+	corsExpvarPreflightRequests.Add(1)
+
+	cors.handleCORS(ctx)
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+func (cors *CORSMiddleware) handleCORS(ctx *AutheliaCtx) {
+	origin := ctx.Request.Header.PeekBytes(headerOrigin)
+	if origin == nil {
+		return
+	}
+
+	err := cors.apply(&ctx.Request, &ctx.Response, origin)
+	if err == nil {
+		corsExpvarOriginsAllowed.Add(1)
+
 		return
 	}
 
-	resp.Header.SetBytesKV(headerVary, headerValueVary)
+	corsExpvarOriginsRejected.Add(1)
+
+	if ctx.Logger.Logger.IsLevelEnabled(logrus.DebugLevel) {
+		ctx.Logger.Debugf("CORS policy for path '%s' did not grant the request with requested method '%s' and requested headers '%s': %v",
+			ctx.Path(), ctx.Request.Header.PeekBytes(headerAccessControlRequestMethod), ctx.Request.Header.PeekBytes(headerAccessControlRequestHeaders), err)
+	}
+}
+
+// apply applies the CORS policy to the response for the provided Origin, returning an error describing why the Origin
+// was not granted if it wasn't.
+func (cors *CORSMiddleware) apply(req *fasthttp.Request, resp *fasthttp.Response, origin []byte) (err error) {
+	originURL, err := url.Parse(string(origin))
+	if err != nil {
+		return fmt.Errorf("origin '%s' could not be parsed: %w", origin, err)
+	}
+
+	if !cors.isOriginSchemeAllowed(originURL.Scheme) {
+		return fmt.Errorf("origin '%s' has the scheme '%s' which is not allowed", origin, originURL.Scheme)
+	}
+
+	if !cors.isOriginAllowed(origin, originURL) {
+		return fmt.Errorf("origin '%s' is not an allowed origin and is not on an allowed domain", origin)
+	}
+
+	resp.Header.SetBytesKV(headerVary, cors.vary)
 	resp.Header.SetBytesKV(headerAccessControlAllowOrigin, origin)
-	resp.Header.SetBytesKV(headerAccessControlAllowCredentials, headerValueFalse)
-	resp.Header.SetBytesKV(headerAccessControlMaxAge, headerValueMaxAge)
-
-	if headers := req.Header.PeekBytes(headerAccessControlRequestHeaders); headers != nil {
-		requestedHeaders := strings.Split(string(headers), ",")
-		allowHeaders := make([]string, len(requestedHeaders))
-
-		for i, header := range requestedHeaders {
-			headerTrimmed := strings.Trim(header, " ")
-			if !strings.EqualFold("*", headerTrimmed) && !strings.EqualFold("Cookie", headerTrimmed) {
-				allowHeaders[i] = headerTrimmed
-			}
+
+	if cors.credentials {
+		resp.Header.SetBytesKV(headerAccessControlAllowCredentials, headerValueTrue)
+	} else {
+		resp.Header.SetBytesKV(headerAccessControlAllowCredentials, headerValueFalse)
+	}
+
+	resp.Header.SetBytesKV(headerAccessControlMaxAge, cors.maxAge)
+
+	if cors.exposedHeaders != nil {
+		resp.Header.SetBytesKV(headerAccessControlExposeHeaders, cors.exposedHeaders)
+	}
+
+	cors.handleAllowedHeaders(req, resp)
+	cors.handleAllowedMethods(req, resp)
+
+	return nil
+}
+
+func (cors *CORSMiddleware) isOriginAllowed(origin []byte, originURL *url.URL) bool {
+	if cors.allowedOrigins == nil && cors.allowedOriginDomains == nil {
+		return true
+	}
+
+	if isStringInSliceBytes(origin, cors.allowedOrigins) {
+		return true
+	}
+
+	host := strings.ToLower(originURL.Hostname())
+
+	for _, domain := range cors.allowedOriginDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cors *CORSMiddleware) isOriginSchemeAllowed(scheme string) bool {
+	return scheme == "https" || (cors.insecureOrigins && scheme == "http")
+}
+
+func (cors *CORSMiddleware) handleAllowedHeaders(req *fasthttp.Request, resp *fasthttp.Response) {
+	if !cors.automaticRequestHeaders {
+		if len(cors.allowedRequestHeaders) != 0 {
+			resp.Header.SetBytesKV(headerAccessControlAllowHeaders, cors.allowedRequestHeadersRaw)
+		}
+
+		return
+	}
+
+	headers := req.Header.PeekBytes(headerAccessControlRequestHeaders)
+	if headers == nil {
+		return
+	}
+
+	// Only the first headers up to the maximum are split, the remainder is left unprocessed in the final element.
+	requestedHeaders := strings.SplitN(string(headers), ",", cors.maximumRequestHeaders+1)
+	if len(requestedHeaders) > cors.maximumRequestHeaders {
+		requestedHeaders = requestedHeaders[:cors.maximumRequestHeaders]
+	}
+
+	allowHeaders := make([]string, 0, len(requestedHeaders))
+
+	for _, header := range requestedHeaders {
+		headerTrimmed := strings.Trim(header, " ")
+		if headerTrimmed != "" && !strings.EqualFold("*", headerTrimmed) && !strings.EqualFold("Cookie", headerTrimmed) {
+			allowHeaders = append(allowHeaders, headerTrimmed)
 		}
+	}
+
+	if len(allowHeaders) != 0 {
+		resp.Header.SetBytesKV(headerAccessControlAllowHeaders, []byte(strings.Join(allowHeaders, ", ")))
+	}
+}
 
-		if len(allowHeaders) != 0 {
-			resp.Header.SetBytesKV(headerAccessControlAllowHeaders, []byte(strings.Join(allowHeaders, ", ")))
+func (cors *CORSMiddleware) handleAllowedMethods(req *fasthttp.Request, resp *fasthttp.Response) {
+	if !cors.automaticRequestMethods {
+		if len(cors.allowedRequestMethods) != 0 {
+			resp.Header.SetBytesKV(headerAccessControlAllowMethods, []byte(strings.Join(cors.allowedRequestMethods, ", ")))
 		}
+
+		return
 	}
 
 	if requestMethods := req.Header.PeekBytes(headerAccessControlRequestMethod); requestMethods != nil {
 		resp.Header.SetBytesKV(headerAccessControlAllowMethods, requestMethods)
+	} else if cors.defaultRequestMethods != nil {
+		resp.Header.SetBytesKV(headerAccessControlAllowMethods, cors.defaultRequestMethods)
+	}
+}
+
+func isStringInSliceBytes(needle []byte, haystack []string) bool {
+	for _, value := range haystack {
+		if value == string(needle) {
+			return true
+		}
 	}
+
+	return false
+}
+
+// These counters are published on the expvars endpoint when it's enabled. They're registered once at package
+// initialization and expvar.Int is safe for concurrent use.
+var (
+	corsExpvarPreflightRequests = expvar.NewInt("authelia_cors_preflight_requests")
+	corsExpvarOriginsAllowed    = expvar.NewInt("authelia_cors_origins_allowed")
+	corsExpvarOriginsRejected   = expvar.NewInt("authelia_cors_origins_rejected")
+)
+
+var corsAutomaticAllowAllPolicy = NewCORSMiddleware()
+
+// CORSApplyAutomaticAllowAllPolicy applies a CORS policy that automatically grants all Origins as well
+// as all Request Headers other than Cookie and *. It does not allow credentials, and has a max age of 100. Vary is applied
+// to both Accept-Encoding and Origin. It grants the requested Request Method.
+func CORSApplyAutomaticAllowAllPolicy(next RequestHandler) RequestHandler {
+	return corsAutomaticAllowAllPolicy.Middleware(next)
+}
+
+// CORSHandleAutomaticAllowAllPolicyOPTIONS handles a preflight request by applying the same CORS policy as
+// CORSApplyAutomaticAllowAllPolicy and responding with 204 No Content.
+func CORSHandleAutomaticAllowAllPolicyOPTIONS(ctx *AutheliaCtx) {
+	corsAutomaticAllowAllPolicy.HandleOPTIONS(ctx)
+}
+
+func corsApplyAutomaticAllowAllPolicy(req *fasthttp.Request, resp *fasthttp.Response, origin []byte) {
+	_ = corsAutomaticAllowAllPolicy.apply(req, resp, origin)
 }
@@ -1,9 +1,13 @@
 package middlewares
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 )
 
@@ -63,3 +67,236 @@ func Test_CORSApplyAutomaticAllowAllPolicy_ShouldNotModifyFotNonHTTPSRequests(t
 	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlAllowHeaders))
 	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlAllowMethods))
 }
+
+func Test_CORSMiddleware_ShouldLimitRequestHeaders(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.Response{}
+
+	origin := []byte("https://myapp.example.com")
+
+	req.Header.SetBytesK(headerAccessControlRequestHeaders, "X-One, Cookie, X-Two, X-Three, X-Four")
+
+	NewCORSMiddleware().WithMaximumRequestHeaders(3).apply(req, &resp, origin)
+
+	assert.Equal(t, []byte("X-One, X-Two"), resp.Header.PeekBytes(headerAccessControlAllowHeaders))
+
+	resp.Reset()
+
+	req.Header.SetBytesK(headerAccessControlRequestHeaders, strings.Repeat("X-Example,", 1000))
+
+	NewCORSMiddleware().apply(req, &resp, origin)
+
+	assert.Len(t, strings.Split(string(resp.Header.PeekBytes(headerAccessControlAllowHeaders)), ", "), corsDefaultMaximumRequestHeaders)
+}
+
+func Test_CORSMiddleware_ShouldOnlyAllowConfiguredOrigins(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.Response{}
+
+	cors := NewCORSMiddleware().
+		WithAllowedOrigins("https://myapp.example.com").
+		WithAllowedRequestMethods("GET", "POST").
+		WithAllowedRequestHeaders("Authorization").
+		WithAllowCredentials(true).
+		WithMaxAge(600)
+
+	cors.apply(req, &resp, []byte("https://other.example.com"))
+
+	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+
+	cors.apply(req, &resp, []byte("https://myapp.example.com"))
+
+	assert.Equal(t, []byte("https://myapp.example.com"), resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+	assert.Equal(t, headerValueTrue, resp.Header.PeekBytes(headerAccessControlAllowCredentials))
+	assert.Equal(t, []byte("600"), resp.Header.PeekBytes(headerAccessControlMaxAge))
+	assert.Equal(t, []byte("Authorization"), resp.Header.PeekBytes(headerAccessControlAllowHeaders))
+	assert.Equal(t, []byte("GET, POST"), resp.Header.PeekBytes(headerAccessControlAllowMethods))
+}
+
+func Test_CORSMiddleware_ShouldUseDefaultMethodsOnlyWithoutRequestMethod(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.Response{}
+
+	origin := []byte("https://myapp.example.com")
+
+	cors := NewCORSMiddleware().WithDefaultMethods("GET", "OPTIONS")
+
+	require.NoError(t, cors.apply(req, &resp, origin))
+
+	assert.Equal(t, []byte("GET, OPTIONS"), resp.Header.PeekBytes(headerAccessControlAllowMethods))
+
+	resp.Reset()
+
+	req.Header.SetBytesK(headerAccessControlRequestMethod, "POST")
+
+	require.NoError(t, cors.apply(req, &resp, origin))
+
+	assert.Equal(t, []byte("POST"), resp.Header.PeekBytes(headerAccessControlAllowMethods))
+
+	resp.Reset()
+
+	req.Header.DelBytes(headerAccessControlRequestMethod)
+
+	cors.WithDefaultMethods()
+
+	require.NoError(t, cors.apply(req, &resp, origin))
+
+	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlAllowMethods))
+}
+
+func Test_CORSMiddleware_ShouldOnlyExposeHeadersWhenConfigured(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.Response{}
+
+	origin := []byte("https://myapp.example.com")
+
+	cors := NewCORSMiddleware()
+
+	require.NoError(t, cors.apply(req, &resp, origin))
+
+	assert.Equal(t, origin, resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlExposeHeaders))
+
+	resp.Reset()
+
+	cors.WithExposedHeaders("Remote-User", "Remote-Groups")
+
+	require.NoError(t, cors.apply(req, &resp, origin))
+
+	assert.Equal(t, []byte("Remote-User, Remote-Groups"), resp.Header.PeekBytes(headerAccessControlExposeHeaders))
+
+	resp.Reset()
+
+	require.Error(t, cors.apply(req, &resp, []byte("http://myapp.example.com")))
+
+	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlExposeHeaders))
+}
+
+func Test_CORSMiddleware_ShouldOnlyAllowInsecureOriginsWhenEnabled(t *testing.T) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.Response{}
+
+	origin := []byte("http://localhost:3000")
+
+	NewCORSMiddleware().apply(req, &resp, origin)
+
+	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+
+	NewCORSMiddleware().WithAllowInsecureOrigins(true).apply(req, &resp, origin)
+
+	assert.Equal(t, origin, resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+
+	resp.Reset()
+
+	NewCORSMiddleware().WithAllowInsecureOrigins(true).apply(req, &resp, []byte("ftp://localhost"))
+
+	assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+}
+
+func Test_CORSMiddleware_ShouldAllowOriginsFromDomains(t *testing.T) {
+	testCases := []struct {
+		name     string
+		origin   string
+		insecure bool
+		allowed  bool
+	}{
+		{"ShouldAllowDomain", "https://example.com", false, true},
+		{"ShouldAllowSubdomain", "https://app.example.com", false, true},
+		{"ShouldAllowDeepSubdomain", "https://a.b.app.example.com", false, true},
+		{"ShouldAllowSubdomainWithPort", "https://app.example.com:8443", false, true},
+		{"ShouldAllowSubdomainCaseInsensitive", "https://APP.Example.com", false, true},
+		{"ShouldAllowExactOrigin", "https://myapp.example.org", false, true},
+		{"ShouldNotAllowSiblingDomain", "https://badexample.com", false, false},
+		{"ShouldNotAllowSuffixDomain", "https://example.com.evil.net", false, false},
+		{"ShouldNotAllowOtherSubdomainOfExactOrigin", "https://other.example.org", false, false},
+		{"ShouldNotAllowInsecureSubdomain", "http://app.example.com", false, false},
+		{"ShouldAllowInsecureSubdomainWhenEnabled", "http://app.example.com", true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := fasthttp.AcquireRequest()
+			resp := fasthttp.Response{}
+
+			cors := NewCORSMiddleware().
+				WithAllowedOrigins("https://myapp.example.org").
+				WithAllowedOriginsFromDomains(".Example.com").
+				WithAllowInsecureOrigins(tc.insecure)
+
+			cors.apply(req, &resp, []byte(tc.origin))
+
+			if tc.allowed {
+				assert.Equal(t, []byte(tc.origin), resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+			} else {
+				assert.Equal(t, []byte(nil), resp.Header.PeekBytes(headerAccessControlAllowOrigin))
+			}
+		})
+	}
+}
+
+func Test_CORSMiddleware_ShouldLogRejectedOriginsAtDebugLevel(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	ctx := &AutheliaCtx{RequestCtx: &fasthttp.RequestCtx{}, Logger: logrus.NewEntry(logger)}
+
+	ctx.Request.SetRequestURI("/api/oidc/token")
+	ctx.Request.Header.SetBytesKV(headerOrigin, []byte("http://myapp.example.com"))
+	ctx.Request.Header.SetBytesK(headerAccessControlRequestMethod, "POST")
+	ctx.Request.Header.SetBytesK(headerAccessControlRequestHeaders, "Authorization")
+
+	cors := NewCORSMiddleware().WithAllowedOrigins("https://myapp.example.com")
+
+	cors.handleCORS(ctx)
+
+	assert.Len(t, hook.AllEntries(), 0)
+
+	logger.SetLevel(logrus.DebugLevel)
+
+	cors.handleCORS(ctx)
+
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.DebugLevel, hook.LastEntry().Level)
+	assert.Equal(t, "CORS policy for path '/api/oidc/token' did not grant the request with requested method 'POST' and requested headers 'Authorization': origin 'http://myapp.example.com' has the scheme 'http' which is not allowed", hook.LastEntry().Message)
+
+	ctx.Request.Header.SetBytesKV(headerOrigin, []byte("https://other.example.com"))
+
+	cors.handleCORS(ctx)
+
+	require.Len(t, hook.AllEntries(), 2)
+	assert.Equal(t, "CORS policy for path '/api/oidc/token' did not grant the request with requested method 'POST' and requested headers 'Authorization': origin 'https://other.example.com' is not an allowed origin and is not on an allowed domain", hook.LastEntry().Message)
+
+	ctx.Request.Header.SetBytesKV(headerOrigin, []byte("https://myapp.example.com"))
+
+	cors.handleCORS(ctx)
+
+	assert.Len(t, hook.AllEntries(), 2)
+	assert.Equal(t, []byte("https://myapp.example.com"), ctx.Response.Header.PeekBytes(headerAccessControlAllowOrigin))
+}
+
+func Test_CORSMiddleware_ShouldCountPreflightsAndOrigins(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	ctx := &AutheliaCtx{RequestCtx: &fasthttp.RequestCtx{}, Logger: logrus.NewEntry(logger)}
+
+	cors := NewCORSMiddleware().WithAllowedOrigins("https://myapp.example.com")
+
+	preflights, allowed, rejected := corsExpvarPreflightRequests.Value(), corsExpvarOriginsAllowed.Value(), corsExpvarOriginsRejected.Value()
+
+	cors.HandleOPTIONS(ctx)
+
+	assert.Equal(t, preflights+1, corsExpvarPreflightRequests.Value())
+	assert.Equal(t, allowed, corsExpvarOriginsAllowed.Value())
+	assert.Equal(t, rejected, corsExpvarOriginsRejected.Value())
+
+	ctx.Request.Header.SetBytesKV(headerOrigin, []byte("https://myapp.example.com"))
+
+	cors.HandleOPTIONS(ctx)
+
+	ctx.Request.Header.SetBytesKV(headerOrigin, []byte("https://other.example.com"))
+
+	cors.handleCORS(ctx)
+
+	assert.Equal(t, preflights+2, corsExpvarPreflightRequests.Value())
+	assert.Equal(t, allowed+1, corsExpvarOriginsAllowed.Value())
+	assert.Equal(t, rejected+1, corsExpvarOriginsRejected.Value())
+}
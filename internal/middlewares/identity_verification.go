@@ -9,6 +9,7 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/templates"
 )
@@ -110,7 +111,7 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 		ctx.Logger.Debugf("Sending an email to user %s (%s) to confirm identity for registering a device.",
 			identity.Username, identity.Email)
 
-		err = ctx.Providers.Notifier.Send(identity.Email, args.MailTitle, bufText.String(), bufHTML.String())
+		err = identityVerificationSendNotification(ctx, args, verification, identity.Email, bufText.String(), bufHTML.String())
 
 		if err != nil {
 			ctx.Error(err, messageOperationFailed)
@@ -123,6 +124,43 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 	}
 }
 
+// identityVerificationSendNotification sends the identity verification notification. If the args enable it and the
+// notifier password_reset configuration is set to queue a failed notification is immediately queued to be sent by the
+// notification queue worker until the verification expires, otherwise it is retried with an exponential backoff. The
+// notification is queued without retrying so the response time doesn't reveal if the notification could be sent.
+func identityVerificationSendNotification(ctx *AutheliaCtx, args IdentityVerificationStartArgs, verification model.IdentityVerification, recipient, body, htmlBody string) (err error) {
+	if !args.RetryNotification || ctx.Configuration.Notifier == nil {
+		return ctx.Providers.Notifier.Send(recipient, args.MailTitle, body, htmlBody)
+	}
+
+	config := ctx.Configuration.Notifier.PasswordReset
+	backoff := config.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		if err = ctx.Providers.Notifier.Send(recipient, args.MailTitle, body, htmlBody); err == nil {
+			return nil
+		}
+
+		if attempt >= config.Retries || config.OnFailure == schema.NotifierOnFailureQueue {
+			break
+		}
+
+		ctx.Logger.Warnf("Failed to send the identity verification notification to %s, retrying in %s: %v", recipient, backoff, err)
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+	}
+
+	if config.OnFailure != schema.NotifierOnFailureQueue {
+		return err
+	}
+
+	ctx.Logger.Errorf("Failed to send the identity verification notification to %s, queueing it until it expires at %s: %v", recipient, verification.ExpiresAt, err)
+
+	return ctx.Providers.StorageProvider.SaveQueuedNotification(ctx, model.NewQueuedNotification(recipient, args.MailTitle, body, htmlBody, ctx.Clock.Now(), verification.ExpiresAt))
+}
+
 // IdentityVerificationFinish the middleware for finishing the identity validation process.
 func IdentityVerificationFinish(args IdentityVerificationFinishArgs, next func(ctx *AutheliaCtx, username string)) RequestHandler {
 	return func(ctx *AutheliaCtx) {
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -90,6 +91,94 @@ func TestShouldFailSendingAnEmail(t *testing.T) {
 	assert.Equal(t, "no notif", mock.Hook.LastEntry().Message)
 }
 
+func TestShouldRetrySendingAnEmail(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.JWTSecret = testJWTSecret
+	mock.Ctx.Configuration.Notifier = &schema.NotifierConfiguration{
+		PasswordReset: schema.NotifierPasswordResetConfiguration{
+			Retries:      2,
+			RetryBackoff: time.Millisecond,
+			OnFailure:    schema.NotifierOnFailureFail,
+		},
+	}
+	mock.Ctx.Request.Header.Add("X-Forwarded-Proto", "http")
+	mock.Ctx.Request.Header.Add("X-Forwarded-Host", "host")
+
+	mock.StorageMock.EXPECT().
+		SaveIdentityVerification(mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	gomock.InOrder(
+		mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("john@example.com"), gomock.Eq("Title"), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("no notif")).
+			Times(2),
+		mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("john@example.com"), gomock.Eq("Title"), gomock.Any(), gomock.Any()).
+			Return(nil),
+	)
+
+	args := newArgs(defaultRetriever)
+	args.RetryNotification = true
+
+	middlewares.IdentityVerificationStart(args, nil)(mock.Ctx)
+
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "{\"status\":\"OK\"}", string(mock.Ctx.Response.Body()))
+}
+
+func TestShouldQueueEmailWithoutRetrying(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.JWTSecret = testJWTSecret
+	mock.Ctx.Configuration.Notifier = &schema.NotifierConfiguration{
+		PasswordReset: schema.NotifierPasswordResetConfiguration{
+			Retries:      1,
+			RetryBackoff: time.Millisecond,
+			OnFailure:    schema.NotifierOnFailureQueue,
+		},
+	}
+	mock.Ctx.Request.Header.Add("X-Forwarded-Proto", "http")
+	mock.Ctx.Request.Header.Add("X-Forwarded-Host", "host")
+
+	var verification model.IdentityVerification
+
+	mock.StorageMock.EXPECT().
+		SaveIdentityVerification(mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, v model.IdentityVerification) error {
+			verification = v
+
+			return nil
+		})
+
+	mock.NotifierMock.EXPECT().
+		Send(gomock.Eq("john@example.com"), gomock.Eq("Title"), gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("no notif")).
+		Times(1)
+
+	mock.StorageMock.EXPECT().
+		SaveQueuedNotification(mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, notification model.QueuedNotification) error {
+			assert.Equal(t, "john@example.com", notification.Recipient)
+			assert.Equal(t, "Title", notification.Subject)
+			assert.Contains(t, string(notification.Body), "http://host/target?token=")
+			assert.Equal(t, verification.ExpiresAt, notification.ExpiresAt)
+
+			return nil
+		})
+
+	args := newArgs(defaultRetriever)
+	args.RetryNotification = true
+
+	middlewares.IdentityVerificationStart(args, nil)(mock.Ctx)
+
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "{\"status\":\"OK\"}", string(mock.Ctx.Response.Body()))
+}
+
 func TestShouldFailWhenXForwardedHostHeaderIsMissing(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
@@ -1,17 +1,71 @@
 package middlewares
 
 import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-// LogRequestMiddleware logs the query that is being treated.
-func LogRequestMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+// LogRequestMiddleware logs the query that is being treated. The values of the query parameters and headers with a
+// name in the redacted list are replaced so credentials are not written to the logs.
+func LogRequestMiddleware(redacted []string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		autheliaCtx := &AutheliaCtx{RequestCtx: ctx}
 		logger := NewRequestLogger(autheliaCtx)
 
-		logger.Trace("Request hit")
+		if logger.Logger.IsLevelEnabled(logrus.TraceLevel) {
+			logger.WithFields(logrus.Fields{
+				"query":   RedactQueryString(ctx.QueryArgs(), redacted),
+				"headers": redactRequestHeaders(&ctx.Request.Header, redacted),
+			}).Trace("Request hit")
+		}
+
 		next(ctx)
 		logger.Tracef("Replied (status=%d)", ctx.Response.StatusCode())
 	}
 }
+
+// RedactRequestURI returns the request URI of the request with the values of the query parameters with a name in the
+// redacted list replaced.
+func RedactRequestURI(ctx *fasthttp.RequestCtx, redacted []string) string {
+	query := RedactQueryString(ctx.QueryArgs(), redacted)
+
+	if query == "" {
+		return string(ctx.Path())
+	}
+
+	return string(ctx.Path()) + "?" + query
+}
+
+// RedactQueryString returns the query string with the values of the query parameters with a name in the redacted list
+// replaced.
+func RedactQueryString(args *fasthttp.Args, redacted []string) string {
+	values := make([]string, 0, args.Len())
+
+	args.VisitAll(func(key, value []byte) {
+		if utils.IsStringInSliceFold(string(key), redacted) {
+			values = append(values, string(key)+"="+logRedacted)
+		} else {
+			values = append(values, string(key)+"="+string(value))
+		}
+	})
+
+	return strings.Join(values, "&")
+}
+
+func redactRequestHeaders(header *fasthttp.RequestHeader, redacted []string) map[string]string {
+	headers := map[string]string{}
+
+	header.VisitAll(func(key, value []byte) {
+		if utils.IsStringInSliceFold(string(key), redacted) {
+			headers[string(key)] = logRedacted
+		} else {
+			headers[string(key)] = string(value)
+		}
+	})
+
+	return headers
+}
@@ -70,6 +70,9 @@ type IdentityVerificationStartArgs struct {
 
 	// The function for checking the user in the token is valid for the current action.
 	IsTokenUserValidFunc func(ctx *AutheliaCtx, username string) bool
+
+	// Retry the notification and queue it if it still fails according to the notifier password_reset configuration.
+	RetryNotification bool
 }
 
 // IdentityVerificationFinishArgs represent the arguments used to customize the finishing phase
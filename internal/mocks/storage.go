@@ -79,6 +79,20 @@ func (mr *MockStorageMockRecorder) ConsumeIdentityVerification(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeIdentityVerification", reflect.TypeOf((*MockStorage)(nil).ConsumeIdentityVerification), arg0, arg1, arg2)
 }
 
+// DeleteExpiredQueuedNotifications mocks base method.
+func (m *MockStorage) DeleteExpiredQueuedNotifications(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredQueuedNotifications", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredQueuedNotifications indicates an expected call of DeleteExpiredQueuedNotifications.
+func (mr *MockStorageMockRecorder) DeleteExpiredQueuedNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredQueuedNotifications", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredQueuedNotifications), arg0, arg1)
+}
+
 // DeletePreferredDuoDevice mocks base method.
 func (m *MockStorage) DeletePreferredDuoDevice(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -93,6 +107,20 @@ func (mr *MockStorageMockRecorder) DeletePreferredDuoDevice(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).DeletePreferredDuoDevice), arg0, arg1)
 }
 
+// DeleteQueuedNotification mocks base method.
+func (m *MockStorage) DeleteQueuedNotification(arg0 context.Context, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQueuedNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQueuedNotification indicates an expected call of DeleteQueuedNotification.
+func (mr *MockStorageMockRecorder) DeleteQueuedNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQueuedNotification", reflect.TypeOf((*MockStorage)(nil).DeleteQueuedNotification), arg0, arg1)
+}
+
 // DeleteTOTPConfiguration mocks base method.
 func (m *MockStorage) DeleteTOTPConfiguration(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -107,6 +135,20 @@ func (mr *MockStorageMockRecorder) DeleteTOTPConfiguration(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).DeleteTOTPConfiguration), arg0, arg1)
 }
 
+// DeleteUserSessionRecord mocks base method.
+func (m *MockStorage) DeleteUserSessionRecord(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessionRecord", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessionRecord indicates an expected call of DeleteUserSessionRecord.
+func (mr *MockStorageMockRecorder) DeleteUserSessionRecord(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessionRecord", reflect.TypeOf((*MockStorage)(nil).DeleteUserSessionRecord), arg0, arg1)
+}
+
 // FindIdentityVerification mocks base method.
 func (m *MockStorage) FindIdentityVerification(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -167,6 +209,21 @@ func (mr *MockStorageMockRecorder) LoadPreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).LoadPreferredDuoDevice), arg0, arg1)
 }
 
+// LoadQueuedNotifications mocks base method.
+func (m *MockStorage) LoadQueuedNotifications(arg0 context.Context, arg1 time.Time, arg2 int, arg3 int) ([]model.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadQueuedNotifications", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadQueuedNotifications indicates an expected call of LoadQueuedNotifications.
+func (mr *MockStorageMockRecorder) LoadQueuedNotifications(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadQueuedNotifications", reflect.TypeOf((*MockStorage)(nil).LoadQueuedNotifications), arg0, arg1, arg2, arg3)
+}
+
 // LoadTOTPConfiguration mocks base method.
 func (m *MockStorage) LoadTOTPConfiguration(arg0 context.Context, arg1 string) (*model.TOTPConfiguration, error) {
 	m.ctrl.T.Helper()
@@ -197,6 +254,21 @@ func (mr *MockStorageMockRecorder) LoadTOTPConfigurations(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfigurations", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfigurations), arg0, arg1, arg2)
 }
 
+// LoadTrustedDevice mocks base method.
+func (m *MockStorage) LoadTrustedDevice(arg0 context.Context, arg1 string) (*model.TrustedDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTrustedDevice", arg0, arg1)
+	ret0, _ := ret[0].(*model.TrustedDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadTrustedDevice indicates an expected call of LoadTrustedDevice.
+func (mr *MockStorageMockRecorder) LoadTrustedDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTrustedDevice", reflect.TypeOf((*MockStorage)(nil).LoadTrustedDevice), arg0, arg1)
+}
+
 // LoadUserInfo mocks base method.
 func (m *MockStorage) LoadUserInfo(arg0 context.Context, arg1 string) (model.UserInfo, error) {
 	m.ctrl.T.Helper()
@@ -212,6 +284,21 @@ func (mr *MockStorageMockRecorder) LoadUserInfo(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserInfo", reflect.TypeOf((*MockStorage)(nil).LoadUserInfo), arg0, arg1)
 }
 
+// LoadUserSessionRecords mocks base method.
+func (m *MockStorage) LoadUserSessionRecords(arg0 context.Context, arg1 string) ([]model.UserSessionRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserSessionRecords", arg0, arg1)
+	ret0, _ := ret[0].([]model.UserSessionRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserSessionRecords indicates an expected call of LoadUserSessionRecords.
+func (mr *MockStorageMockRecorder) LoadUserSessionRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserSessionRecords", reflect.TypeOf((*MockStorage)(nil).LoadUserSessionRecords), arg0, arg1)
+}
+
 // LoadWebauthnDevices mocks base method.
 func (m *MockStorage) LoadWebauthnDevices(arg0 context.Context, arg1, arg2 int) ([]model.WebauthnDevice, error) {
 	m.ctrl.T.Helper()
@@ -242,6 +329,49 @@ func (mr *MockStorageMockRecorder) LoadWebauthnDevicesByUsername(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadWebauthnDevicesByUsername", reflect.TypeOf((*MockStorage)(nil).LoadWebauthnDevicesByUsername), arg0, arg1)
 }
 
+// LockQueuedNotification mocks base method.
+func (m *MockStorage) LockQueuedNotification(arg0 context.Context, arg1 int, arg2, arg3 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockQueuedNotification", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockQueuedNotification indicates an expected call of LockQueuedNotification.
+func (mr *MockStorageMockRecorder) LockQueuedNotification(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockQueuedNotification", reflect.TypeOf((*MockStorage)(nil).LockQueuedNotification), arg0, arg1, arg2, arg3)
+}
+
+// Ping mocks base method.
+func (m *MockStorage) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockStorageMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStorage)(nil).Ping), arg0)
+}
+
+// RevokeTrustedDevices mocks base method.
+func (m *MockStorage) RevokeTrustedDevices(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTrustedDevices", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeTrustedDevices indicates an expected call of RevokeTrustedDevices.
+func (mr *MockStorageMockRecorder) RevokeTrustedDevices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTrustedDevices", reflect.TypeOf((*MockStorage)(nil).RevokeTrustedDevices), arg0, arg1)
+}
+
 // SaveIdentityVerification mocks base method.
 func (m *MockStorage) SaveIdentityVerification(arg0 context.Context, arg1 model.IdentityVerification) error {
 	m.ctrl.T.Helper()
@@ -256,6 +386,20 @@ func (mr *MockStorageMockRecorder) SaveIdentityVerification(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdentityVerification", reflect.TypeOf((*MockStorage)(nil).SaveIdentityVerification), arg0, arg1)
 }
 
+// SaveLastUsed2FAMethod mocks base method.
+func (m *MockStorage) SaveLastUsed2FAMethod(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLastUsed2FAMethod", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLastUsed2FAMethod indicates an expected call of SaveLastUsed2FAMethod.
+func (mr *MockStorageMockRecorder) SaveLastUsed2FAMethod(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLastUsed2FAMethod", reflect.TypeOf((*MockStorage)(nil).SaveLastUsed2FAMethod), arg0, arg1, arg2)
+}
+
 // SavePreferred2FAMethod mocks base method.
 func (m *MockStorage) SavePreferred2FAMethod(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -284,6 +428,20 @@ func (mr *MockStorageMockRecorder) SavePreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).SavePreferredDuoDevice), arg0, arg1)
 }
 
+// SaveQueuedNotification mocks base method.
+func (m *MockStorage) SaveQueuedNotification(arg0 context.Context, arg1 model.QueuedNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveQueuedNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveQueuedNotification indicates an expected call of SaveQueuedNotification.
+func (mr *MockStorageMockRecorder) SaveQueuedNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveQueuedNotification", reflect.TypeOf((*MockStorage)(nil).SaveQueuedNotification), arg0, arg1)
+}
+
 // SaveTOTPConfiguration mocks base method.
 func (m *MockStorage) SaveTOTPConfiguration(arg0 context.Context, arg1 model.TOTPConfiguration) error {
 	m.ctrl.T.Helper()
@@ -298,6 +456,34 @@ func (mr *MockStorageMockRecorder) SaveTOTPConfiguration(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).SaveTOTPConfiguration), arg0, arg1)
 }
 
+// SaveTrustedDevice mocks base method.
+func (m *MockStorage) SaveTrustedDevice(arg0 context.Context, arg1 model.TrustedDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTrustedDevice", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTrustedDevice indicates an expected call of SaveTrustedDevice.
+func (mr *MockStorageMockRecorder) SaveTrustedDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTrustedDevice", reflect.TypeOf((*MockStorage)(nil).SaveTrustedDevice), arg0, arg1)
+}
+
+// SaveUserSessionRecord mocks base method.
+func (m *MockStorage) SaveUserSessionRecord(arg0 context.Context, arg1 model.UserSessionRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserSessionRecord", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserSessionRecord indicates an expected call of SaveUserSessionRecord.
+func (mr *MockStorageMockRecorder) SaveUserSessionRecord(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSessionRecord", reflect.TypeOf((*MockStorage)(nil).SaveUserSessionRecord), arg0, arg1)
+}
+
 // SaveWebauthnDevice mocks base method.
 func (m *MockStorage) SaveWebauthnDevice(arg0 context.Context, arg1 model.WebauthnDevice) error {
 	m.ctrl.T.Helper()
@@ -458,6 +644,20 @@ func (mr *MockStorageMockRecorder) StartupCheck() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartupCheck", reflect.TypeOf((*MockStorage)(nil).StartupCheck))
 }
 
+// UnlockQueuedNotification mocks base method.
+func (m *MockStorage) UnlockQueuedNotification(arg0 context.Context, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockQueuedNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockQueuedNotification indicates an expected call of UnlockQueuedNotification.
+func (mr *MockStorageMockRecorder) UnlockQueuedNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockQueuedNotification", reflect.TypeOf((*MockStorage)(nil).UnlockQueuedNotification), arg0, arg1)
+}
+
 // UpdateTOTPConfigurationSignIn mocks base method.
 func (m *MockStorage) UpdateTOTPConfigurationSignIn(arg0 context.Context, arg1 int, arg2 *time.Time) error {
 	m.ctrl.T.Helper()
@@ -472,6 +672,34 @@ func (mr *MockStorageMockRecorder) UpdateTOTPConfigurationSignIn(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPConfigurationSignIn", reflect.TypeOf((*MockStorage)(nil).UpdateTOTPConfigurationSignIn), arg0, arg1, arg2)
 }
 
+// UpdateUserSessionRecordLastSeen mocks base method.
+func (m *MockStorage) UpdateUserSessionRecordLastSeen(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserSessionRecordLastSeen", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserSessionRecordLastSeen indicates an expected call of UpdateUserSessionRecordLastSeen.
+func (mr *MockStorageMockRecorder) UpdateUserSessionRecordLastSeen(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSessionRecordLastSeen", reflect.TypeOf((*MockStorage)(nil).UpdateUserSessionRecordLastSeen), arg0, arg1, arg2)
+}
+
+// UpdateUserSessionRecordSessionID mocks base method.
+func (m *MockStorage) UpdateUserSessionRecordSessionID(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserSessionRecordSessionID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserSessionRecordSessionID indicates an expected call of UpdateUserSessionRecordSessionID.
+func (mr *MockStorageMockRecorder) UpdateUserSessionRecordSessionID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSessionRecordSessionID", reflect.TypeOf((*MockStorage)(nil).UpdateUserSessionRecordSessionID), arg0, arg1, arg2)
+}
+
 // UpdateWebauthnDeviceSignIn mocks base method.
 func (m *MockStorage) UpdateWebauthnDeviceSignIn(arg0 context.Context, arg1 int, arg2 string, arg3 *time.Time, arg4 uint32, arg5 bool) error {
 	m.ctrl.T.Helper()
@@ -7,6 +7,10 @@ const (
 	errFmtScanInvalidTypeErr = "cannot scan model type '%T' from type '%T' with value '%v': %w"
 )
 
+const (
+	userSessionUserAgentMaxLength = 512
+)
+
 const (
 	// SecondFactorMethodTOTP method using Time-Based One-Time Password applications like Google Authenticator.
 	SecondFactorMethodTOTP = "totp"
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// NewQueuedNotification creates a new QueuedNotification which is sent until the given expiration.
+func NewQueuedNotification(recipient, subject, body, htmlBody string, now, expiresAt time.Time) (notification QueuedNotification) {
+	return QueuedNotification{
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+		Recipient: recipient,
+		Subject:   subject,
+		Body:      []byte(body),
+		HTMLBody:  []byte(htmlBody),
+	}
+}
+
+// QueuedNotification represents a notification which could not be sent and is queued in the database.
+type QueuedNotification struct {
+	ID        int       `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+	Recipient string    `db:"recipient"`
+	Subject   string    `db:"subject"`
+	Body      []byte    `db:"body"`
+	HTMLBody  []byte    `db:"html_body"`
+}
@@ -10,5 +10,6 @@ type OIDCWorkflowSession struct {
 	TargetURI         string
 	AuthURI           string
 	Require2FA        bool
+	LoginHint         string
 	CreatedTimestamp  int64
 }
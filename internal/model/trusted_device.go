@@ -0,0 +1,57 @@
+package model
+
+import (
+	"net"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// NewTrustedDevice creates a new TrustedDevice from a given username which is trusted for the given lifespan.
+func NewTrustedDevice(jti uuid.UUID, username string, ip net.IP, now time.Time, lifespan time.Duration) (device TrustedDevice) {
+	return TrustedDevice{
+		JTI:       jti,
+		IssuedAt:  now,
+		IssuedIP:  NewIP(ip),
+		ExpiresAt: now.Add(lifespan),
+		Username:  username,
+	}
+}
+
+// TrustedDevice represents a trusted device row in the database.
+type TrustedDevice struct {
+	ID        int        `db:"id"`
+	JTI       uuid.UUID  `db:"jti"`
+	IssuedAt  time.Time  `db:"iat"`
+	IssuedIP  IP         `db:"issued_ip"`
+	ExpiresAt time.Time  `db:"exp"`
+	Username  string     `db:"username"`
+	Revoked   *time.Time `db:"revoked"`
+}
+
+// IsTrusted returns true if the device is trusted for the given username at the given time.
+func (d TrustedDevice) IsTrusted(username string, now time.Time) bool {
+	return d.Revoked == nil && d.Username == username && now.Before(d.ExpiresAt)
+}
+
+// ToTrustedDeviceClaim converts the TrustedDevice into a TrustedDeviceClaim.
+func (d TrustedDevice) ToTrustedDeviceClaim() (claim *TrustedDeviceClaim) {
+	return &TrustedDeviceClaim{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        d.JTI.String(),
+			Issuer:    "Authelia",
+			IssuedAt:  jwt.NewNumericDate(d.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(d.ExpiresAt),
+		},
+		Username: d.Username,
+	}
+}
+
+// TrustedDeviceClaim is the claim stored in the trusted device cookie.
+type TrustedDeviceClaim struct {
+	jwt.RegisteredClaims
+
+	// The user this token has been crafted for.
+	Username string `json:"username"`
+}
@@ -12,6 +12,9 @@ type UserInfo struct {
 	// The preferred 2FA method.
 	Method string `db:"second_factor_method" json:"method" valid:"required"`
 
+	// The last 2FA method the user successfully authenticated with.
+	LastUsedMethod string `db:"last_used_second_factor_method" json:"last_used_method,omitempty"`
+
 	// True if a TOTP device has been registered.
 	HasTOTP bool `db:"has_totp" json:"has_totp" valid:"required"`
 
@@ -56,3 +59,14 @@ func (i *UserInfo) SetDefaultPreferred2FAMethod(methods []string) (changed bool)
 
 	return before != i.Method
 }
+
+// SetLastUsed2FAMethod selects the last used method as the method if it's still available.
+func (i *UserInfo) SetLastUsed2FAMethod(methods []string) (changed bool) {
+	if i.LastUsedMethod == "" || i.LastUsedMethod == i.Method || !utils.IsStringInSlice(i.LastUsedMethod, methods) {
+		return false
+	}
+
+	i.Method = i.LastUsedMethod
+
+	return true
+}
@@ -220,3 +220,25 @@ func TestUserInfo_SetDefaultMethod_ShouldConfigureConfigDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestUserInfo_SetLastUsed2FAMethod(t *testing.T) {
+	testCases := []struct {
+		name             string
+		have             UserInfo
+		availableMethods []string
+		changed          bool
+		expected         string
+	}{
+		{"ShouldSelectLastUsed", UserInfo{Method: SecondFactorMethodTOTP, LastUsedMethod: SecondFactorMethodWebauthn}, []string{SecondFactorMethodTOTP, SecondFactorMethodWebauthn}, true, SecondFactorMethodWebauthn},
+		{"ShouldNotChangeWhenSame", UserInfo{Method: SecondFactorMethodTOTP, LastUsedMethod: SecondFactorMethodTOTP}, []string{SecondFactorMethodTOTP, SecondFactorMethodWebauthn}, false, SecondFactorMethodTOTP},
+		{"ShouldNotSelectUnavailable", UserInfo{Method: SecondFactorMethodTOTP, LastUsedMethod: SecondFactorMethodDuo}, []string{SecondFactorMethodTOTP, SecondFactorMethodWebauthn}, false, SecondFactorMethodTOTP},
+		{"ShouldNotChangeWhenNeverUsed", UserInfo{Method: SecondFactorMethodTOTP}, []string{SecondFactorMethodTOTP, SecondFactorMethodWebauthn}, false, SecondFactorMethodTOTP},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.changed, tc.have.SetLastUsed2FAMethod(tc.availableMethods))
+			assert.Equal(t, tc.expected, tc.have.Method)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewUserSessionRecord creates a new UserSessionRecord for the session with the given id which belongs to the given
+// username. The user agent is truncated to the length of the database column.
+func NewUserSessionRecord(publicID uuid.UUID, sessionID []byte, username string, ip net.IP, userAgent string, now time.Time) (record UserSessionRecord) {
+	if len(userAgent) > userSessionUserAgentMaxLength {
+		userAgent = userAgent[:userSessionUserAgentMaxLength]
+	}
+
+	return UserSessionRecord{
+		PublicID:   publicID,
+		SessionID:  sessionID,
+		Username:   username,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		IP:         NewIP(ip),
+		UserAgent:  userAgent,
+	}
+}
+
+// UserSessionRecord represents a user session row in the database. The public id is the identifier exposed to the user
+// as the session id itself is a credential.
+type UserSessionRecord struct {
+	ID         int       `db:"id"`
+	PublicID   uuid.UUID `db:"public_id"`
+	SessionID  []byte    `db:"session_id"`
+	Username   string    `db:"username"`
+	CreatedAt  time.Time `db:"created_at"`
+	LastSeenAt time.Time `db:"last_seen_at"`
+	IP         IP        `db:"ip"`
+	UserAgent  string    `db:"user_agent"`
+}
@@ -1,9 +1,31 @@
 package notification
 
+import (
+	"time"
+)
+
 const (
 	fileNotifierMode = 0600
 )
 
+const (
+	queueWorkerBatchSize = 100
+
+	// queueWorkerLockDuration is how long a queued notification is locked by the instance sending it, which is long
+	// enough for the instance to send it, and short enough for another instance to send it if the instance stopped.
+	queueWorkerLockDuration = time.Minute * 5
+)
+
 const (
 	rfc5322DateTimeLayout = "Mon, 2 Jan 2006 15:04:05 -0700"
 )
+
+const (
+	smtpAuthMechanismXOAUTH2 = "XOAUTH2"
+
+	// xoauth2TokenExpiryLeeway is the duration before the expiry of an access token when it's no longer reused.
+	xoauth2TokenExpiryLeeway = time.Minute
+
+	// xoauth2TokenDefaultLifetime is the lifetime of an access token when the token endpoint doesn't respond with one.
+	xoauth2TokenDefaultLifetime = time.Hour
+)
@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// QueueStorage is the storage used by the QueueWorker to load, lock, and remove the queued notifications.
+type QueueStorage interface {
+	LoadQueuedNotifications(ctx context.Context, now time.Time, limit, page int) (notifications []model.QueuedNotification, err error)
+	LockQueuedNotification(ctx context.Context, id int, now, until time.Time) (locked bool, err error)
+	UnlockQueuedNotification(ctx context.Context, id int) (err error)
+	DeleteQueuedNotification(ctx context.Context, id int) (err error)
+	DeleteExpiredQueuedNotifications(ctx context.Context, now time.Time) (err error)
+}
+
+// NewQueueWorker creates a QueueWorker which sends the notifications queued in the storage every interval.
+func NewQueueWorker(notifier Notifier, storage QueueStorage, interval time.Duration) *QueueWorker {
+	return &QueueWorker{
+		notifier: notifier,
+		storage:  storage,
+		interval: interval,
+		log:      logging.Logger(),
+	}
+}
+
+// QueueWorker sends the notifications which were queued because the notifier failed when they were first sent.
+type QueueWorker struct {
+	notifier Notifier
+	storage  QueueStorage
+	interval time.Duration
+	log      *logrus.Logger
+}
+
+// Run processes the queue every interval, it never returns.
+func (w *QueueWorker) Run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := w.Process(context.Background(), time.Now()); err != nil {
+			w.log.WithError(err).Error("Failed to process the notification queue")
+		}
+	}
+}
+
+// Process removes the expired notifications from the queue and then attempts to send the queued notifications,
+// removing each one from the queue once it has been sent. Each notification is locked while it's being sent so the
+// instances sharing the storage don't send it more than once.
+func (w *QueueWorker) Process(ctx context.Context, now time.Time) (err error) {
+	if err = w.storage.DeleteExpiredQueuedNotifications(ctx, now); err != nil {
+		return err
+	}
+
+	var (
+		notifications, batch []model.QueuedNotification
+		locked               bool
+	)
+
+	// All pages are loaded before any notification is sent so the notifications which keep failing can't prevent the
+	// ones behind them from being sent.
+	for page := 0; ; page++ {
+		if batch, err = w.storage.LoadQueuedNotifications(ctx, now, queueWorkerBatchSize, page); err != nil {
+			return err
+		}
+
+		notifications = append(notifications, batch...)
+
+		if len(batch) < queueWorkerBatchSize {
+			break
+		}
+	}
+
+	for _, notification := range notifications {
+		if locked, err = w.storage.LockQueuedNotification(ctx, notification.ID, now, now.Add(queueWorkerLockDuration)); err != nil {
+			return err
+		}
+
+		if !locked {
+			w.log.Debugf("Skipping the queued notification with id '%d' as it's being sent by another instance", notification.ID)
+
+			continue
+		}
+
+		if err = w.notifier.Send(notification.Recipient, notification.Subject, string(notification.Body), string(notification.HTMLBody)); err != nil {
+			w.log.WithError(err).Warnf("Failed to send the queued notification with id '%d', it will be retried until it expires at %s", notification.ID, notification.ExpiresAt)
+
+			if err = w.storage.UnlockQueuedNotification(ctx, notification.ID); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = w.storage.DeleteQueuedNotification(ctx, notification.ID); err != nil {
+			return err
+		}
+
+		w.log.Debugf("Sent the queued notification with id '%d'", notification.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+type testQueueStorage struct {
+	notifications []model.QueuedNotification
+	locks         map[int]time.Time
+}
+
+func (s *testQueueStorage) LoadQueuedNotifications(_ context.Context, now time.Time, limit, page int) (notifications []model.QueuedNotification, err error) {
+	for _, notification := range s.notifications {
+		if notification.ExpiresAt.After(now) {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	switch {
+	case limit*page >= len(notifications):
+		return nil, nil
+	case limit*(page+1) >= len(notifications):
+		return notifications[limit*page:], nil
+	default:
+		return notifications[limit*page : limit*(page+1)], nil
+	}
+}
+
+func (s *testQueueStorage) LockQueuedNotification(_ context.Context, id int, now, until time.Time) (locked bool, err error) {
+	if s.locks == nil {
+		s.locks = map[int]time.Time{}
+	}
+
+	if lockedUntil, ok := s.locks[id]; ok && lockedUntil.After(now) {
+		return false, nil
+	}
+
+	s.locks[id] = until
+
+	return true, nil
+}
+
+func (s *testQueueStorage) UnlockQueuedNotification(_ context.Context, id int) (err error) {
+	delete(s.locks, id)
+
+	return nil
+}
+
+func (s *testQueueStorage) DeleteQueuedNotification(_ context.Context, id int) (err error) {
+	delete(s.locks, id)
+
+	for i, notification := range s.notifications {
+		if notification.ID == id {
+			s.notifications = append(s.notifications[:i], s.notifications[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (s *testQueueStorage) DeleteExpiredQueuedNotifications(_ context.Context, now time.Time) (err error) {
+	notifications := s.notifications[:0]
+
+	for _, notification := range s.notifications {
+		if notification.ExpiresAt.After(now) {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	s.notifications = notifications
+
+	return nil
+}
+
+type testQueueNotifier struct {
+	fail       map[string]bool
+	recipients []string
+}
+
+func (n *testQueueNotifier) StartupCheck() (err error) {
+	return nil
+}
+
+func (n *testQueueNotifier) Send(recipient, subject, body, htmlBody string) (err error) {
+	if n.fail[recipient] {
+		return errors.New("connection refused")
+	}
+
+	n.recipients = append(n.recipients, recipient)
+
+	return nil
+}
+
+func TestShouldProcessNotificationQueue(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	storage := &testQueueStorage{
+		notifications: []model.QueuedNotification{
+			{ID: 1, ExpiresAt: now.Add(time.Minute), Recipient: "john@example.com", Subject: "Reset your password"},
+			{ID: 2, ExpiresAt: now.Add(-time.Minute), Recipient: "harry@example.com", Subject: "Reset your password"},
+			{ID: 3, ExpiresAt: now.Add(time.Minute), Recipient: "bob@example.com", Subject: "Reset your password"},
+		},
+	}
+
+	notifier := &testQueueNotifier{fail: map[string]bool{"bob@example.com": true}}
+
+	worker := NewQueueWorker(notifier, storage, time.Minute)
+
+	require.NoError(t, worker.Process(context.Background(), now))
+
+	assert.Equal(t, []string{"john@example.com"}, notifier.recipients)
+	require.Len(t, storage.notifications, 1)
+	assert.Equal(t, 3, storage.notifications[0].ID)
+
+	notifier.fail = nil
+
+	require.NoError(t, worker.Process(context.Background(), now))
+
+	assert.Equal(t, []string{"john@example.com", "bob@example.com"}, notifier.recipients)
+	assert.Len(t, storage.notifications, 0)
+}
+
+func TestShouldProcessNotificationsBehindFailingNotifications(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	storage := &testQueueStorage{}
+	notifier := &testQueueNotifier{fail: map[string]bool{}}
+
+	for i := 1; i <= queueWorkerBatchSize+1; i++ {
+		recipient := fmt.Sprintf("user%d@example.com", i)
+
+		storage.notifications = append(storage.notifications, model.QueuedNotification{ID: i, ExpiresAt: now.Add(time.Minute), Recipient: recipient})
+
+		if i <= queueWorkerBatchSize {
+			notifier.fail[recipient] = true
+		}
+	}
+
+	worker := NewQueueWorker(notifier, storage, time.Minute)
+
+	require.NoError(t, worker.Process(context.Background(), now))
+
+	assert.Equal(t, []string{fmt.Sprintf("user%d@example.com", queueWorkerBatchSize+1)}, notifier.recipients)
+	assert.Len(t, storage.notifications, queueWorkerBatchSize)
+	assert.Len(t, storage.locks, 0)
+}
+
+func TestShouldNotProcessNotificationsLockedByAnotherInstance(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	storage := &testQueueStorage{
+		notifications: []model.QueuedNotification{
+			{ID: 1, ExpiresAt: now.Add(time.Hour), Recipient: "john@example.com", Subject: "Reset your password"},
+			{ID: 2, ExpiresAt: now.Add(time.Hour), Recipient: "bob@example.com", Subject: "Reset your password"},
+		},
+		locks: map[int]time.Time{2: now.Add(time.Minute)},
+	}
+
+	notifier := &testQueueNotifier{}
+
+	worker := NewQueueWorker(notifier, storage, time.Minute)
+
+	require.NoError(t, worker.Process(context.Background(), now))
+
+	assert.Equal(t, []string{"john@example.com"}, notifier.recipients)
+	require.Len(t, storage.notifications, 1)
+	assert.Equal(t, 2, storage.notifications[0].ID)
+
+	require.NoError(t, worker.Process(context.Background(), now.Add(time.Minute)))
+
+	assert.Equal(t, []string{"john@example.com", "bob@example.com"}, notifier.recipients)
+	assert.Len(t, storage.notifications, 0)
+}
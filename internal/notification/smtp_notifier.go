@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -23,6 +24,9 @@ type SMTPNotifier struct {
 	client        *smtp.Client
 	tlsConfig     *tls.Config
 	log           *logrus.Logger
+	tokenSource   *xoauth2TokenSource
+
+	fallback *SMTPNotifier
 }
 
 // NewSMTPNotifier creates a SMTPNotifier using the notifier configuration.
@@ -33,6 +37,14 @@ func NewSMTPNotifier(configuration *schema.SMTPNotifierConfiguration, certPool *
 		log:           logging.Logger(),
 	}
 
+	if configuration.Auth != nil && configuration.Auth.XOAUTH2 != nil {
+		notifier.tokenSource = newXOAUTH2TokenSource(configuration.Auth.XOAUTH2, configuration.Timeout, notifier.tlsConfig)
+	}
+
+	if configuration.Fallback != nil {
+		notifier.fallback = NewSMTPNotifier(configuration.Fallback, certPool)
+	}
+
 	return notifier
 }
 
@@ -67,8 +79,8 @@ func (n *SMTPNotifier) startTLS() error {
 
 // Attempt Authentication.
 func (n *SMTPNotifier) auth() error {
-	// Attempt AUTH if password is specified only.
-	if n.configuration.Password != "" {
+	// Attempt AUTH if password or XOAUTH2 is specified only.
+	if n.configuration.Password != "" || n.tokenSource != nil {
 		_, ok := n.client.TLSConnectionState()
 		if !ok {
 			return errors.New("Notifier SMTP client does not support authentication over plain text and the connection is currently plain text")
@@ -77,29 +89,20 @@ func (n *SMTPNotifier) auth() error {
 		// Check the server supports AUTH, and get the mechanisms.
 		ok, m := n.client.Extension("AUTH")
 		if ok {
-			var auth smtp.Auth
-
 			n.log.Debugf("Notifier SMTP server supports authentication with the following mechanisms: %s", m)
-			mechanisms := strings.Split(m, " ")
 
-			// Adaptively select the AUTH mechanism to use based on what the server advertised.
-			if utils.IsStringInSlice("PLAIN", mechanisms) {
-				auth = smtp.PlainAuth("", n.configuration.Username, n.configuration.Password, n.configuration.Host)
-
-				n.log.Debug("Notifier SMTP client attempting AUTH PLAIN with server")
-			} else if utils.IsStringInSlice("LOGIN", mechanisms) {
-				auth = newLoginAuth(n.configuration.Username, n.configuration.Password, n.configuration.Host)
-
-				n.log.Debug("Notifier SMTP client attempting AUTH LOGIN with server")
+			auth, err := n.selectAuth(strings.Split(m, " "))
+			if err != nil {
+				return err
 			}
 
 			// Throw error since AUTH extension is not supported.
 			if auth == nil {
-				return fmt.Errorf("notifier SMTP server does not advertise a AUTH mechanism that are supported by Authelia (PLAIN or LOGIN are supported, but server advertised %s mechanisms)", m)
+				return fmt.Errorf("notifier SMTP server does not advertise a AUTH mechanism that are supported by Authelia (PLAIN, LOGIN or XOAUTH2 are supported, but server advertised %s mechanisms)", m)
 			}
 
 			// Authenticate.
-			if err := n.client.Auth(auth); err != nil {
+			if err = n.client.Auth(auth); err != nil {
 				return err
 			}
 
@@ -108,7 +111,7 @@ func (n *SMTPNotifier) auth() error {
 			return nil
 		}
 
-		return errors.New("Notifier SMTP server does not advertise the AUTH extension but config requires AUTH (password specified), either disable AUTH, or use an SMTP host that supports AUTH PLAIN or AUTH LOGIN")
+		return errors.New("Notifier SMTP server does not advertise the AUTH extension but config requires AUTH (password or xoauth2 specified), either disable AUTH, or use an SMTP host that supports AUTH PLAIN, AUTH LOGIN or AUTH XOAUTH2")
 	}
 
 	n.log.Debug("Notifier SMTP config has no password specified so authentication is being skipped")
@@ -116,6 +119,47 @@ func (n *SMTPNotifier) auth() error {
 	return nil
 }
 
+// selectAuth adaptively selects the AUTH mechanism to use based on what the server advertised. The XOAUTH2 mechanism
+// is preferred when it's configured, falling back to PLAIN or LOGIN when the server doesn't advertise it and a password
+// is configured.
+func (n *SMTPNotifier) selectAuth(mechanisms []string) (auth smtp.Auth, err error) {
+	if n.tokenSource != nil {
+		if utils.IsStringInSlice(smtpAuthMechanismXOAUTH2, mechanisms) {
+			ctx, cancel := context.WithTimeout(context.Background(), n.configuration.Timeout)
+			defer cancel()
+
+			var token string
+
+			if token, err = n.tokenSource.Token(ctx); err != nil {
+				return nil, fmt.Errorf("notifier SMTP client failed to obtain the access token for AUTH XOAUTH2: %w", err)
+			}
+
+			n.log.Debug("Notifier SMTP client attempting AUTH XOAUTH2 with server")
+
+			return newXOAUTH2Auth(n.configuration.Username, token, n.configuration.Host), nil
+		}
+
+		if n.configuration.Password == "" {
+			return nil, nil
+		}
+
+		n.log.Warn("Notifier SMTP server does not advertise AUTH XOAUTH2 so the client is falling back to authenticating with the password")
+	}
+
+	switch {
+	case utils.IsStringInSlice("PLAIN", mechanisms):
+		n.log.Debug("Notifier SMTP client attempting AUTH PLAIN with server")
+
+		return smtp.PlainAuth("", n.configuration.Username, n.configuration.Password, n.configuration.Host), nil
+	case utils.IsStringInSlice("LOGIN", mechanisms):
+		n.log.Debug("Notifier SMTP client attempting AUTH LOGIN with server")
+
+		return newLoginAuth(n.configuration.Username, n.configuration.Password, n.configuration.Host), nil
+	}
+
+	return nil, nil
+}
+
 func (n *SMTPNotifier) compose(recipient, subject, body, htmlBody string) error {
 	n.log.Debugf("Notifier SMTP client attempting to send email body to %s", recipient)
 
@@ -215,8 +259,29 @@ func (n *SMTPNotifier) cleanup() {
 	}
 }
 
-// StartupCheck implements the startup check provider interface.
+// StartupCheck implements the startup check provider interface. When a fallback SMTP server is configured the check
+// only fails if neither server passes it.
 func (n *SMTPNotifier) StartupCheck() (err error) {
+	if err = n.startupCheck(); err != nil {
+		if n.fallback == nil {
+			return err
+		}
+
+		n.log.Warnf("Notifier SMTP startup check failed for %s:%d, checking the fallback: %v", n.configuration.Host, n.configuration.Port, err)
+
+		return n.fallback.StartupCheck()
+	}
+
+	if n.fallback != nil {
+		if err = n.fallback.StartupCheck(); err != nil {
+			n.log.Warnf("Notifier SMTP startup check failed for the fallback %s:%d: %v", n.fallback.configuration.Host, n.fallback.configuration.Port, err)
+		}
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) startupCheck() (err error) {
 	if err := n.dial(); err != nil {
 		return err
 	}
@@ -246,8 +311,23 @@ func (n *SMTPNotifier) StartupCheck() (err error) {
 	return n.client.Reset()
 }
 
-// Send is used to send an email to a recipient.
-func (n *SMTPNotifier) Send(recipient, title, body, htmlBody string) error {
+// Send is used to send an email to a recipient. When a fallback SMTP server is configured and sending the email fails
+// for any reason, such as a timeout or a permanent error reply, it's sent using the fallback instead.
+func (n *SMTPNotifier) Send(recipient, title, body, htmlBody string) (err error) {
+	if err = n.send(recipient, title, body, htmlBody); err != nil {
+		if n.fallback == nil {
+			return err
+		}
+
+		n.log.Warnf("Notifier SMTP client failed to send email using %s:%d, retrying with the fallback: %v", n.configuration.Host, n.configuration.Port, err)
+
+		return n.fallback.Send(recipient, title, body, htmlBody)
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) send(recipient, title, body, htmlBody string) error {
 	subject := strings.ReplaceAll(n.configuration.Subject, "{title}", title)
 
 	if err := n.dial(); err != nil {
@@ -286,7 +366,7 @@ func (n *SMTPNotifier) Send(recipient, title, body, htmlBody string) error {
 		return err
 	}
 
-	n.log.Debug("Notifier SMTP client successfully sent email")
+	n.log.Debugf("Notifier SMTP client successfully sent email using %s:%d", n.configuration.Host, n.configuration.Port)
 
 	return nil
 }
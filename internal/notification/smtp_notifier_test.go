@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
@@ -47,3 +48,36 @@ func TestShouldConfigureSMTPNotifierWithServerNameOverrideAndDefaultTLS12(t *tes
 	assert.Equal(t, uint16(tls.VersionTLS12), notifier.tlsConfig.MinVersion)
 	assert.False(t, notifier.tlsConfig.InsecureSkipVerify)
 }
+
+func TestShouldConfigureSMTPNotifierFallbackIndependently(t *testing.T) {
+	config := &schema.NotifierConfiguration{
+		DisableStartupCheck: true,
+		SMTP: &schema.SMTPNotifierConfiguration{
+			Host: "smtp.example.com",
+			Port: 25,
+			TLS: &schema.TLSConfig{
+				ServerName: "smtp.example.com",
+			},
+			Fallback: &schema.SMTPNotifierConfiguration{
+				Host: "backup.example.com",
+				Port: 587,
+				TLS: &schema.TLSConfig{
+					ServerName:     "backup.example.com",
+					MinimumVersion: "TLS1.1",
+					SkipVerify:     true,
+				},
+			},
+		},
+	}
+
+	notifier := NewSMTPNotifier(config.SMTP, nil)
+
+	assert.Equal(t, "smtp.example.com", notifier.tlsConfig.ServerName)
+	assert.False(t, notifier.tlsConfig.InsecureSkipVerify)
+
+	require.NotNil(t, notifier.fallback)
+	assert.Nil(t, notifier.fallback.fallback)
+	assert.Equal(t, "backup.example.com", notifier.fallback.tlsConfig.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS11), notifier.fallback.tlsConfig.MinVersion)
+	assert.True(t, notifier.fallback.tlsConfig.InsecureSkipVerify)
+}
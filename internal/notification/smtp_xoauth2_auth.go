@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type xoauth2Auth struct {
+	username string
+	token    string
+	host     string
+}
+
+func newXOAUTH2Auth(username, token, host string) smtp.Auth {
+	return &xoauth2Auth{username, token, host}
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !(server.Name == "localhost" || server.Name == "127.0.0.1" || server.Name == "::1") {
+		return "", nil, errors.New("connection over plain-text")
+	}
+
+	if server.Name != a.host {
+		return "", nil, errors.New("unexpected hostname from server")
+	}
+
+	return smtpAuthMechanismXOAUTH2, []byte(fmt.Sprintf("user=%s\x01auth=Bearer %s\x01\x01", a.username, a.token)), nil
+}
+
+// Next handles the challenge the server sends when it rejects the token, which contains the reason of the failure.
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("server rejected the access token: %s", fromServer)
+}
+
+// xoauth2TokenSource obtains the access tokens for the XOAUTH2 authentication mechanism from the token endpoint with the
+// client credentials grant. The access token is reused until shortly before it expires.
+type xoauth2TokenSource struct {
+	config *schema.SMTPNotifierXOAUTH2Configuration
+	client *http.Client
+
+	mutex   sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newXOAUTH2TokenSource creates a xoauth2TokenSource which trusts the same certificates as the SMTP connection. The
+// server name and the skip verify options of the SMTP connection are specific to the SMTP server so they're not used.
+func newXOAUTH2TokenSource(config *schema.SMTPNotifierXOAUTH2Configuration, timeout time.Duration, tlsConfig *tls.Config) *xoauth2TokenSource {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    tlsConfig.RootCAs,
+		MinVersion: tlsConfig.MinVersion,
+	}
+
+	return &xoauth2TokenSource{
+		config: config,
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+type xoauth2TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Token returns a valid access token, requesting a new one from the token endpoint if necessary.
+func (s *xoauth2TokenSource) Token(ctx context.Context) (token string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	if s.token != "" && now.Before(s.expires) {
+		return s.token, nil
+	}
+
+	form := url.Values{}
+
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", s.config.ClientID)
+	form.Set("client_secret", s.config.ClientSecret)
+
+	if len(s.config.Scopes) != 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create the token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request the access token: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	var response xoauth2TokenResponse
+
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode the token response with status code %d: %w", resp.StatusCode, err)
+	}
+
+	switch {
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("token endpoint responded with status code %d: %s: %s", resp.StatusCode, response.Error, response.ErrorDescription)
+	case response.AccessToken == "":
+		return "", errors.New("token endpoint responded without an access token")
+	case response.TokenType != "" && !strings.EqualFold(response.TokenType, "bearer"):
+		return "", fmt.Errorf("token endpoint responded with the unsupported token type '%s'", response.TokenType)
+	}
+
+	lifetime := time.Duration(response.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = xoauth2TokenDefaultLifetime
+	}
+
+	s.token, s.expires = response.AccessToken, now.Add(lifetime-xoauth2TokenExpiryLeeway)
+
+	return s.token, nil
+}
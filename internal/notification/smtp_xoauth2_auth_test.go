@@ -0,0 +1,160 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestFullXOAUTH2Auth(t *testing.T) {
+	serverInfo := &smtp.ServerInfo{
+		Name: "mail.authelia.com",
+		TLS:  true,
+		Auth: nil,
+	}
+	auth := newXOAUTH2Auth("john@authelia.com", "abc123", "mail.authelia.com")
+
+	proto, toServer, err := auth.Start(serverInfo)
+	assert.Equal(t, "XOAUTH2", proto)
+	assert.Equal(t, []byte("user=john@authelia.com\x01auth=Bearer abc123\x01\x01"), toServer)
+	require.NoError(t, err)
+
+	toServer, err = auth.Next([]byte(nil), false)
+	assert.Equal(t, []byte(nil), toServer)
+	require.NoError(t, err)
+
+	toServer, err = auth.Next([]byte(`{"status":"401","schemes":"bearer","scope":"https://mail.google.com/"}`), true)
+	assert.Equal(t, []byte(nil), toServer)
+	assert.EqualError(t, err, `server rejected the access token: {"status":"401","schemes":"bearer","scope":"https://mail.google.com/"}`)
+}
+
+func TestXOAUTH2AuthTLSNeededForNonLocalhost(t *testing.T) {
+	serverInfo := &smtp.ServerInfo{
+		Name: "mail.authelia.com",
+		TLS:  false,
+		Auth: nil,
+	}
+	auth := newXOAUTH2Auth("john@authelia.com", "abc123", "mail.authelia.com")
+	_, _, err := auth.Start(serverInfo)
+	assert.EqualError(t, err, "connection over plain-text")
+}
+
+func TestShouldRequestAndReuseXOAUTH2AccessToken(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "authelia", r.PostForm.Get("client_id"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		assert.Equal(t, "https://outlook.office365.com/.default offline_access", r.PostForm.Get("scope"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"abc123","token_type":"Bearer","expires_in":3599}`))
+	}))
+	defer server.Close()
+
+	source := newXOAUTH2TokenSource(&schema.SMTPNotifierXOAUTH2Configuration{
+		TokenEndpoint: server.URL,
+		ClientID:      "authelia",
+		ClientSecret:  "secret",
+		Scopes:        []string{"https://outlook.office365.com/.default", "offline_access"},
+	}, time.Second*5, &tls.Config{})
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", token)
+
+	token, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", token)
+	assert.Equal(t, 1, requests)
+}
+
+func TestShouldReturnXOAUTH2TokenEndpointError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"Invalid client secret provided."}`))
+	}))
+	defer server.Close()
+
+	source := newXOAUTH2TokenSource(&schema.SMTPNotifierXOAUTH2Configuration{
+		TokenEndpoint: server.URL,
+		ClientID:      "authelia",
+		ClientSecret:  "bad",
+	}, time.Second*5, &tls.Config{})
+
+	token, err := source.Token(context.Background())
+	assert.Equal(t, "", token)
+	assert.EqualError(t, err, "token endpoint responded with status code 401: invalid_client: Invalid client secret provided.")
+}
+
+func TestShouldReuseXOAUTH2AccessTokenWithoutExpiresIn(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"abc123","token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	source := newXOAUTH2TokenSource(&schema.SMTPNotifierXOAUTH2Configuration{
+		TokenEndpoint: server.URL,
+		ClientID:      "authelia",
+		ClientSecret:  "secret",
+	}, time.Second*5, &tls.Config{})
+
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", token)
+	}
+
+	assert.Equal(t, 1, requests)
+	assert.WithinDuration(t, time.Now().Add(xoauth2TokenDefaultLifetime-xoauth2TokenExpiryLeeway), source.expires, time.Second*5)
+}
+
+func TestShouldTrustXOAUTH2TokenEndpointWithNotifierCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"abc123","token_type":"Bearer","expires_in":3599}`))
+	}))
+	defer server.Close()
+
+	config := &schema.SMTPNotifierXOAUTH2Configuration{
+		TokenEndpoint: server.URL,
+		ClientID:      "authelia",
+		ClientSecret:  "secret",
+	}
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(server.Certificate())
+
+	source := newXOAUTH2TokenSource(config, time.Second*5, &tls.Config{RootCAs: certPool, ServerName: "smtp.example.com"})
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", token)
+
+	source = newXOAUTH2TokenSource(config, time.Second*5, &tls.Config{RootCAs: x509.NewCertPool()})
+
+	token, err = source.Token(context.Background())
+	assert.Equal(t, "", token)
+	assert.ErrorContains(t, err, "failed to request the access token")
+	assert.ErrorContains(t, err, "certificate")
+}
@@ -3,6 +3,7 @@ package ntp
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"net"
 	"time"
 
@@ -20,7 +21,7 @@ func NewProvider(config *schema.NTPConfiguration) *Provider {
 
 // StartupCheck implements the startup check provider interface.
 func (p *Provider) StartupCheck() (err error) {
-	conn, err := net.Dial("udp", p.config.Address)
+	conn, err := net.DialTimeout("udp", p.config.Address, p.config.Timeout)
 	if err != nil {
 		p.log.Warnf("Could not connect to NTP server to validate the system time is properly synchronized: %+v", err)
 
@@ -29,7 +30,7 @@ func (p *Provider) StartupCheck() (err error) {
 
 	defer conn.Close()
 
-	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+	if err := conn.SetDeadline(time.Now().Add(p.config.Timeout)); err != nil {
 		p.log.Warnf("Could not connect to NTP server to validate the system time is properly synchronized: %+v", err)
 
 		return nil
@@ -53,6 +54,14 @@ func (p *Provider) StartupCheck() (err error) {
 	resp := &ntpPacket{}
 
 	if err := binary.Read(conn, binary.BigEndian, resp); err != nil {
+		var netErr net.Error
+
+		// A server which doesn't respond within the timeout is reported as a failure so the disable_failure option
+		// decides if startup is aborted.
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return fmt.Errorf("the NTP server '%s' did not respond within the timeout of %s", p.config.Address, p.config.Timeout)
+		}
+
 		p.log.Warnf("Could not read from the NTP server socket to validate the system time is properly synchronized: %+v", err)
 
 		return nil
@@ -1,10 +1,12 @@
 package ntp
 
 import (
+	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/configuration/validator"
@@ -26,3 +28,28 @@ func TestShouldCheckNTP(t *testing.T) {
 
 	assert.NoError(t, ntp.StartupCheck())
 }
+
+func TestShouldFailNTPCheckOnTimeout(t *testing.T) {
+	// A server which never responds like a network which silently drops the requests.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	config := &schema.Configuration{
+		NTP: schema.NTPConfiguration{
+			Address: conn.LocalAddr().String(),
+			Timeout: time.Millisecond * 100,
+		},
+	}
+
+	sv := schema.NewStructValidator()
+	validator.ValidateNTP(config, sv)
+
+	ntp := NewProvider(&config.NTP)
+
+	start := time.Now()
+
+	assert.EqualError(t, ntp.StartupCheck(), "the NTP server '"+conn.LocalAddr().String()+"' did not respond within the timeout of 100ms")
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
@@ -1,7 +1,11 @@
 package oidc
 
 import (
+	"net"
+	"time"
+
 	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/openid"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
@@ -17,6 +21,8 @@ func NewClient(config schema.OpenIDConnectClientConfiguration) (client *Internal
 		Secret:      []byte(config.Secret),
 		Public:      config.Public,
 
+		RotatedSecrets: secretsToRotatedHashes(config.SecretNext),
+
 		Policy: authorization.PolicyToLevel(config.Policy),
 
 		Audience:      config.Audience,
@@ -27,12 +33,36 @@ func NewClient(config schema.OpenIDConnectClientConfiguration) (client *Internal
 		ResponseModes: []fosite.ResponseModeType{fosite.ResponseModeDefault},
 
 		UserinfoSigningAlgorithm: config.UserinfoSigningAlgorithm,
+		SigningKeyID:             config.SigningKeyID,
+
+		Claims: config.Claims,
+
+		AccessTokenLifespan:   config.AccessTokenLifespan,
+		AuthorizeCodeLifespan: config.AuthorizeCodeLifespan,
+		IDTokenLifespan:       config.IDTokenLifespan,
+		RefreshTokenLifespan:  config.RefreshTokenLifespan,
+
+		RefreshTokenAbsoluteLifetime: config.RefreshTokenAbsoluteLifetime,
+
+		RefreshSessionActivity: config.RefreshSessionActivity,
+
+		RequireState: config.RequireState,
+	}
+
+	for _, network := range config.AllowedNetworks {
+		if cidr, err := authorization.ParseNetwork(network); err == nil {
+			client.AllowedNetworks = append(client.AllowedNetworks, cidr)
+		}
 	}
 
 	for _, mode := range config.ResponseModes {
 		client.ResponseModes = append(client.ResponseModes, fosite.ResponseModeType(mode))
 	}
 
+	if config.TokenEndpointRateLimit.RequestsPerSecond > 0 {
+		client.TokenEndpointRateLimiter = NewRateLimiter(config.TokenEndpointRateLimit.RequestsPerSecond, config.TokenEndpointRateLimit.Burst)
+	}
+
 	return client
 }
 
@@ -41,6 +71,80 @@ func (c InternalClient) IsAuthenticationLevelSufficient(level authentication.Lev
 	return authorization.IsAuthLevelSufficient(level, c.Policy)
 }
 
+// IsRefreshTokenAbsoluteLifetimeExceeded returns true if the client has an absolute refresh token lifetime and the
+// provided time is after the original grant time plus that lifetime.
+func (c InternalClient) IsRefreshTokenAbsoluteLifetimeExceeded(grantedAt, now time.Time) bool {
+	if c.RefreshTokenAbsoluteLifetime <= 0 || grantedAt.IsZero() {
+		return false
+	}
+
+	return now.After(grantedAt.Add(c.RefreshTokenAbsoluteLifetime))
+}
+
+// IsNetworkAllowed returns true if the client has no network restrictions or the provided IP is in one of the
+// networks the client is allowed to be used from.
+func (c InternalClient) IsNetworkAllowed(ip net.IP) bool {
+	if len(c.AllowedNetworks) == 0 {
+		return true
+	}
+
+	for _, network := range c.AllowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetLifespan returns the client specific lifespan for the provided fosite.TokenType or 0 if the client does not
+// have a specific lifespan for it, in which case the global lifespan should be used.
+func (c InternalClient) GetLifespan(tokenType fosite.TokenType) time.Duration {
+	switch tokenType {
+	case fosite.AccessToken:
+		return c.AccessTokenLifespan
+	case fosite.AuthorizeCode:
+		return c.AuthorizeCodeLifespan
+	case fosite.IDToken:
+		return c.IDTokenLifespan
+	case fosite.RefreshToken:
+		return c.RefreshTokenLifespan
+	default:
+		return 0
+	}
+}
+
+// SetSessionLifespan sets the expiration of the provided fosite.TokenType in the session to the client specific
+// lifespan if the client has one. The expiration of the fosite.IDToken is set on the ID Token claims of the session.
+func (c InternalClient) SetSessionLifespan(session fosite.Session, tokenType fosite.TokenType, now time.Time) {
+	lifespan := c.GetLifespan(tokenType)
+	if lifespan <= 0 {
+		return
+	}
+
+	expiresAt := now.UTC().Add(lifespan).Round(time.Second)
+
+	if tokenType == fosite.IDToken {
+		if s, ok := session.(openid.Session); ok {
+			s.IDTokenClaims().ExpiresAt = expiresAt
+		}
+
+		return
+	}
+
+	session.SetExpiresAt(tokenType, expiresAt)
+}
+
+// GetSigningKeyID returns the key id of the key the tokens of this client are signed with, which is the client specific
+// key if it has one otherwise the provided active key id.
+func (c InternalClient) GetSigningKeyID(activeKeyID string) string {
+	if c.SigningKeyID != "" {
+		return c.SigningKeyID
+	}
+
+	return activeKeyID
+}
+
 // GetID returns the ID.
 func (c InternalClient) GetID() string {
 	return c.ID
@@ -66,6 +170,13 @@ func (c InternalClient) GetHashedSecret() []byte {
 	return c.Secret
 }
 
+// GetRotatedHashes returns the RotatedSecrets which are accepted in addition to the Secret while it's being rotated.
+//
+// Implements the fosite.ClientWithSecretRotation.
+func (c InternalClient) GetRotatedHashes() [][]byte {
+	return c.RotatedSecrets
+}
+
 // GetRedirectURIs returns the RedirectURIs.
 func (c InternalClient) GetRedirectURIs() []string {
 	return c.RedirectURIs
@@ -110,3 +221,15 @@ func (c InternalClient) GetAudience() fosite.Arguments {
 func (c InternalClient) GetResponseModes() []fosite.ResponseModeType {
 	return c.ResponseModes
 }
+
+func secretsToRotatedHashes(secrets ...string) (hashes [][]byte) {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		hashes = append(hashes, []byte(secret))
+	}
+
+	return hashes
+}
@@ -2,6 +2,7 @@ package oidc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
@@ -146,6 +147,32 @@ func TestInternalClient_GetHashedSecret(t *testing.T) {
 	assert.Equal(t, []byte("a_bad_secret"), hashedSecret)
 }
 
+func TestInternalClient_GetRotatedHashes(t *testing.T) {
+	c := NewClient(schema.OpenIDConnectClientConfiguration{ID: "myapp", Secret: "a_secret"})
+
+	assert.Nil(t, c.GetRotatedHashes())
+
+	c = NewClient(schema.OpenIDConnectClientConfiguration{ID: "myapp", Secret: "a_secret", SecretNext: "a_next_secret"})
+
+	var client fosite.Client = c
+
+	rotation, ok := client.(fosite.ClientWithSecretRotation)
+	require.True(t, ok)
+
+	assert.Equal(t, []byte("a_secret"), c.GetHashedSecret())
+	assert.Equal(t, [][]byte{[]byte("a_next_secret")}, rotation.GetRotatedHashes())
+}
+
+func TestInternalClient_GetSigningKeyID(t *testing.T) {
+	c := InternalClient{}
+
+	assert.Equal(t, "abc123", c.GetSigningKeyID("abc123"))
+
+	c.SigningKeyID = "legacy"
+
+	assert.Equal(t, "legacy", c.GetSigningKeyID("abc123"))
+}
+
 func TestInternalClient_GetID(t *testing.T) {
 	c := InternalClient{}
 
@@ -213,3 +240,50 @@ func TestInternalClient_IsPublic(t *testing.T) {
 	c.Public = true
 	assert.True(t, c.IsPublic())
 }
+
+func TestInternalClient_IsRefreshTokenAbsoluteLifetimeExceeded(t *testing.T) {
+	c := InternalClient{}
+
+	grantedAt := time.Unix(1000000, 0)
+
+	assert.False(t, c.IsRefreshTokenAbsoluteLifetimeExceeded(grantedAt, grantedAt.Add(time.Hour*24*365)))
+
+	c.RefreshTokenAbsoluteLifetime = time.Hour
+
+	assert.False(t, c.IsRefreshTokenAbsoluteLifetimeExceeded(grantedAt, grantedAt.Add(time.Minute*30)))
+	assert.False(t, c.IsRefreshTokenAbsoluteLifetimeExceeded(grantedAt, grantedAt.Add(time.Hour)))
+	assert.True(t, c.IsRefreshTokenAbsoluteLifetimeExceeded(grantedAt, grantedAt.Add(time.Hour+time.Second)))
+	assert.False(t, c.IsRefreshTokenAbsoluteLifetimeExceeded(time.Time{}, grantedAt.Add(time.Hour*2)))
+}
+
+func TestInternalClient_SetSessionLifespan(t *testing.T) {
+	now := time.Unix(1000000, 0)
+
+	c := InternalClient{}
+	session := NewSession()
+
+	for _, tokenType := range []fosite.TokenType{fosite.AccessToken, fosite.AuthorizeCode, fosite.IDToken, fosite.RefreshToken} {
+		assert.Equal(t, time.Duration(0), c.GetLifespan(tokenType))
+		c.SetSessionLifespan(session, tokenType, now)
+	}
+
+	assert.Len(t, session.ExpiresAt, 0)
+	assert.True(t, session.Claims.ExpiresAt.IsZero())
+
+	c.AccessTokenLifespan = time.Minute * 5
+	c.AuthorizeCodeLifespan = time.Second * 30
+	c.IDTokenLifespan = time.Minute * 10
+	c.RefreshTokenLifespan = time.Hour * 24
+
+	assert.Equal(t, time.Duration(0), c.GetLifespan(fosite.TokenType("unknown")))
+
+	for _, tokenType := range []fosite.TokenType{fosite.AccessToken, fosite.AuthorizeCode, fosite.IDToken, fosite.RefreshToken} {
+		c.SetSessionLifespan(session, tokenType, now)
+	}
+
+	assert.Equal(t, now.Add(time.Minute*5).UTC(), session.GetExpiresAt(fosite.AccessToken))
+	assert.Equal(t, now.Add(time.Second*30).UTC(), session.GetExpiresAt(fosite.AuthorizeCode))
+	assert.Equal(t, now.Add(time.Hour*24).UTC(), session.GetExpiresAt(fosite.RefreshToken))
+	assert.Equal(t, now.Add(time.Minute*10).UTC(), session.Claims.ExpiresAt)
+	assert.True(t, session.GetExpiresAt(fosite.IDToken).IsZero())
+}
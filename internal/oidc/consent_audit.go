@@ -0,0 +1,55 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewConsentAuditLog opens the file at the given path for appending consent decisions, creating it if it doesn't exist.
+func NewConsentAuditLog(path string) (log *ConsentAuditLog, err error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the consent audit log: %w", err)
+	}
+
+	return &ConsentAuditLog{file: file}, nil
+}
+
+// ConsentAuditLog is an append only log of consent decisions, each decision is written as a single line of JSON.
+type ConsentAuditLog struct {
+	mu sync.Mutex
+
+	file *os.File
+}
+
+// ConsentAuditRecord is a consent decision recorded in the ConsentAuditLog.
+type ConsentAuditRecord struct {
+	Time              time.Time `json:"time"`
+	Username          string    `json:"username"`
+	ClientID          string    `json:"client_id"`
+	Decision          string    `json:"decision"`
+	RequestedScopes   []string  `json:"requested_scopes"`
+	RequestedAudience []string  `json:"requested_audience"`
+	GrantedScopes     []string  `json:"granted_scopes"`
+	GrantedAudience   []string  `json:"granted_audience"`
+}
+
+// Write appends the record to the log.
+func (l *ConsentAuditLog) Write(record ConsentAuditRecord) (err error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err = l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to the consent audit log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package oidc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsentAuditLogShouldAppendRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consent.log")
+
+	require.NoError(t, os.WriteFile(path, []byte("existing\n"), 0600))
+
+	log, err := NewConsentAuditLog(path)
+	require.NoError(t, err)
+
+	now := time.Unix(1000000000, 0).UTC()
+
+	require.NoError(t, log.Write(ConsentAuditRecord{Time: now, Username: "john", ClientID: "app", Decision: "accept", GrantedScopes: []string{"openid"}}))
+	require.NoError(t, log.Write(ConsentAuditRecord{Time: now, Username: "harry", ClientID: "app", Decision: "reject"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	require.Len(t, lines, 3)
+	assert.Equal(t, "existing", lines[0])
+	assert.Equal(t, `{"time":"2001-09-09T01:46:40Z","username":"john","client_id":"app","decision":"accept","requested_scopes":null,"requested_audience":null,"granted_scopes":["openid"],"granted_audience":null}`, lines[1])
+	assert.Equal(t, `{"time":"2001-09-09T01:46:40Z","username":"harry","client_id":"app","decision":"reject","requested_scopes":null,"requested_audience":null,"granted_scopes":null,"granted_audience":null}`, lines[2])
+}
+
+func TestConsentAuditLogShouldErrorWhenPathNotWritable(t *testing.T) {
+	_, err := NewConsentAuditLog(filepath.Join(t.TempDir(), "missing", "consent.log"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -125,3 +125,21 @@ const (
 	// RFC8176: https://datatracker.ietf.org/doc/html/rfc8176
 	AMRShortMessageService = "sms"
 )
+
+// Debug message strings.
+const (
+	debugRedacted  = "<redacted>"
+	debugTruncated = "...<truncated>"
+)
+
+// debugSensitiveKeys are keys which are redacted when formatting a body for a debug message.
+var debugSensitiveKeys = []string{
+	"client_secret",
+	"client_assertion",
+	"code",
+	"code_verifier",
+	"token",
+	"access_token",
+	"refresh_token",
+	"id_token",
+}
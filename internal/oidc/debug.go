@@ -0,0 +1,42 @@
+package oidc
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// DebugBody formats a body for a debug message by redacting the values of known sensitive keys and truncating the
+// result to the maximum length. A maximum length of 0 or lower disables truncation.
+func DebugBody(body map[string]interface{}, maximum int) string {
+	value := fmt.Sprintf("%+v", debugRedact(body))
+
+	if maximum > 0 && len(value) > maximum {
+		return value[:maximum] + debugTruncated
+	}
+
+	return value
+}
+
+func debugRedact(body map[string]interface{}) map[string]interface{} {
+	if body == nil {
+		return nil
+	}
+
+	redacted := make(map[string]interface{}, len(body))
+
+	for key, value := range body {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redacted[key] = debugRedact(v)
+		default:
+			if utils.IsStringInSlice(key, debugSensitiveKeys) {
+				redacted[key] = debugRedacted
+			} else {
+				redacted[key] = value
+			}
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,61 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugBody(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		have     map[string]interface{}
+		maximum  int
+		expected string
+	}{
+		{
+			desc:     "ShouldRedactSensitiveKeys",
+			have:     map[string]interface{}{"access_token": "abc", "expires_in": 3600, "id_token": "def", "refresh_token": "ghi"},
+			maximum:  0,
+			expected: "map[access_token:<redacted> expires_in:3600 id_token:<redacted> refresh_token:<redacted>]",
+		},
+		{
+			desc:     "ShouldRedactNestedSensitiveKeys",
+			have:     map[string]interface{}{"ext": map[string]interface{}{"code": "abc", "sub": "john"}},
+			maximum:  0,
+			expected: "map[ext:map[code:<redacted> sub:john]]",
+		},
+		{
+			desc:     "ShouldTruncate",
+			have:     map[string]interface{}{"sub": "john", "token": "abc"},
+			maximum:  12,
+			expected: "map[sub:john...<truncated>",
+		},
+		{
+			desc:     "ShouldNotTruncateShortBody",
+			have:     map[string]interface{}{"sub": "john"},
+			maximum:  100,
+			expected: "map[sub:john]",
+		},
+		{
+			desc:     "ShouldHandleNil",
+			have:     nil,
+			maximum:  100,
+			expected: "map[]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DebugBody(tc.have, tc.maximum))
+		})
+	}
+}
+
+func TestDebugBodyShouldNotModifyOriginal(t *testing.T) {
+	have := map[string]interface{}{"client_secret": "abc"}
+
+	DebugBody(have, 0)
+
+	assert.Equal(t, "abc", have["client_secret"])
+}
@@ -1,5 +1,39 @@
 package oidc
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ory/fosite"
+)
 
 var errPasswordsDoNotMatch = errors.New("the passwords don't match")
+
+// ErrTokenEndpointRateLimited is sent when a client has exceeded the rate limit of the token endpoint.
+var ErrTokenEndpointRateLimited = &fosite.RFC6749Error{
+	ErrorField:       "too_many_requests",
+	DescriptionField: "The client has sent too many requests to the token endpoint. Retry after the period in the Retry-After header.",
+	CodeField:        http.StatusTooManyRequests,
+}
+
+// RateLimitedError is returned by the ClientRateLimitHandler when a client has exceeded the rate limit of the token
+// endpoint. It unwraps to ErrTokenEndpointRateLimited and carries the duration to wait until the next request is allowed.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return ErrTokenEndpointRateLimited.Error()
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrTokenEndpointRateLimited
+}
+
+// ErrTokenEndpointRequestTooLarge is sent when the body of a token endpoint request exceeds the maximum size.
+var ErrTokenEndpointRequestTooLarge = &fosite.RFC6749Error{
+	ErrorField:       "invalid_request",
+	DescriptionField: "The request body exceeds the maximum size permitted by the token endpoint.",
+	CodeField:        http.StatusRequestEntityTooLarge,
+}
@@ -16,7 +16,7 @@ import (
 )
 
 // NewKeyManagerWithConfiguration when provided a schema.OpenIDConnectConfiguration creates a new KeyManager and adds an
-// initial key to the manager.
+// initial key to the manager, followed by any additional keys.
 func NewKeyManagerWithConfiguration(configuration *schema.OpenIDConnectConfiguration) (manager *KeyManager, err error) {
 	manager = NewKeyManager()
 
@@ -25,6 +25,12 @@ func NewKeyManagerWithConfiguration(configuration *schema.OpenIDConnectConfigura
 		return nil, err
 	}
 
+	for _, key := range configuration.IssuerPrivateKeys {
+		if _, _, err = manager.AddPrivateKeyData(key.KeyID, key.Key); err != nil {
+			return nil, fmt.Errorf("error adding the issuer private key with key id '%s': %w", key.KeyID, err)
+		}
+	}
+
 	return manager, nil
 }
 
@@ -133,6 +139,44 @@ func (m *KeyManager) AddActivePrivateKey(key *rsa.PrivateKey) (webKey *jose.JSON
 	return &wk, nil
 }
 
+// AddPrivateKeyData adds a rsa.PrivateKey given the key in the PEM string format with the provided key id.
+func (m *KeyManager) AddPrivateKeyData(keyID, data string) (key *rsa.PrivateKey, webKey *jose.JSONWebKey, err error) {
+	key, err = utils.ParseRsaPrivateKeyFromPemStr(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	webKey, err = m.AddPrivateKey(keyID, key)
+
+	return key, webKey, err
+}
+
+// AddPrivateKey adds a rsa.PrivateKey with the provided key id. Unlike the active key it's only used to sign tokens
+// which explicitly request it with the kid header.
+func (m *KeyManager) AddPrivateKey(keyID string, key *rsa.PrivateKey) (webKey *jose.JSONWebKey, err error) {
+	if m.strategy == nil {
+		return nil, errors.New("an active key must be added before any other key")
+	}
+
+	if _, ok := m.keys[keyID]; ok {
+		return nil, fmt.Errorf("key id %s already exists", keyID)
+	}
+
+	wk := jose.JSONWebKey{
+		Key:       &key.PublicKey,
+		KeyID:     keyID,
+		Algorithm: "RS256",
+		Use:       "sig",
+	}
+
+	m.keySet.Keys = append(m.keySet.Keys, wk)
+	m.keys[keyID] = key
+
+	m.strategy.AddKey(keyID, key)
+
+	return &wk, nil
+}
+
 // NewRS256JWTStrategy returns a new RS256JWTStrategy.
 func NewRS256JWTStrategy(id string, key *rsa.PrivateKey) (strategy *RS256JWTStrategy, err error) {
 	strategy = new(RS256JWTStrategy)
@@ -148,6 +192,7 @@ type RS256JWTStrategy struct {
 	JWTStrategy *jwt.RS256JWTStrategy
 
 	keyID string
+	keys  map[string]*jwt.RS256JWTStrategy
 }
 
 // KeyID returns the key id.
@@ -161,6 +206,31 @@ func (s *RS256JWTStrategy) SetKey(id string, key *rsa.PrivateKey) {
 	s.JWTStrategy.PrivateKey = key
 }
 
+// AddKey adds a key which is used instead of the active key to sign and verify tokens with the kid header of the
+// provided key id.
+func (s *RS256JWTStrategy) AddKey(id string, key *rsa.PrivateKey) {
+	if s.keys == nil {
+		s.keys = map[string]*jwt.RS256JWTStrategy{}
+	}
+
+	s.keys[id] = &jwt.RS256JWTStrategy{PrivateKey: key}
+}
+
+// tokenStrategy returns the underlying fosite RS256JWTStrategy for the key with the kid header of the token.
+func (s *RS256JWTStrategy) tokenStrategy(token string) *jwt.RS256JWTStrategy {
+	if len(s.keys) == 0 {
+		return s.JWTStrategy
+	}
+
+	if jws, err := jose.ParseSigned(token); err == nil && len(jws.Signatures) == 1 {
+		if strategy, ok := s.keys[jws.Signatures[0].Header.KeyID]; ok {
+			return strategy
+		}
+	}
+
+	return s.JWTStrategy
+}
+
 // Hash is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) Hash(ctx context.Context, in []byte) ([]byte, error) {
 	return s.JWTStrategy.Hash(ctx, in)
@@ -176,19 +246,39 @@ func (s *RS256JWTStrategy) GetSignature(ctx context.Context, token string) (stri
 	return s.JWTStrategy.GetSignature(ctx, token)
 }
 
-// Generate is a decorator func for the underlying fosite RS256JWTStrategy.
+// Generate is a decorator func for the underlying fosite RS256JWTStrategy. It signs the token with the additional key
+// requested by the kid header, otherwise with the active key. It always sets the kid header to the key id of the key
+// used to sign the token so clients can select the correct JWKS entry to verify it.
 func (s *RS256JWTStrategy) Generate(ctx context.Context, claims jwt.MapClaims, header jwt.Mapper) (string, string, error) {
-	return s.JWTStrategy.Generate(ctx, claims, header)
+	strategy := s.JWTStrategy
+
+	if header != nil {
+		headers := &jwt.Headers{Extra: header.ToMap()}
+
+		keyID := s.keyID
+
+		if kid, ok := headers.Get("kid").(string); ok {
+			if keyStrategy, ok := s.keys[kid]; ok {
+				keyID, strategy = kid, keyStrategy
+			}
+		}
+
+		headers.Add("kid", keyID)
+
+		header = headers
+	}
+
+	return strategy.Generate(ctx, claims, header)
 }
 
 // Validate is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) Validate(ctx context.Context, token string) (string, error) {
-	return s.JWTStrategy.Validate(ctx, token)
+	return s.tokenStrategy(token).Validate(ctx, token)
 }
 
 // Decode is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) Decode(ctx context.Context, token string) (*jwt.Token, error) {
-	return s.JWTStrategy.Decode(ctx, token)
+	return s.tokenStrategy(token).Decode(ctx, token)
 }
 
 // GetPublicKeyID is a decorator func for the underlying fosite RS256JWTStrategy.
@@ -1,11 +1,15 @@
 package oidc
 
 import (
+	"context"
 	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/ory/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -51,3 +55,95 @@ func TestKeyManager_AddActiveKeyData(t *testing.T) {
 	assert.NotNil(t, keySet)
 	assert.Equal(t, kid, manager.GetActiveKeyID())
 }
+
+func TestRS256JWTStrategy_GenerateShouldSetKeyID(t *testing.T) {
+	manager := NewKeyManager()
+
+	_, _, err := manager.AddActivePrivateKeyData(exampleIssuerPrivateKey)
+	require.NoError(t, err)
+
+	strategy := manager.Strategy()
+
+	testCases := []struct {
+		name   string
+		header *jwt.Headers
+	}{
+		{"ShouldSetKeyIDWhenAbsent", &jwt.Headers{}},
+		{"ShouldOverwriteMismatchedKeyID", &jwt.Headers{Extra: map[string]interface{}{"kid": "abc123"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, _, err := strategy.Generate(context.Background(), jwt.MapClaims{"sub": "john"}, tc.header)
+			require.NoError(t, err)
+
+			decoded, err := strategy.Decode(context.Background(), token)
+			require.NoError(t, err)
+
+			kid, ok := decoded.Header["kid"].(string)
+			require.True(t, ok)
+
+			assert.Equal(t, manager.GetActiveKeyID(), kid)
+
+			keys := manager.GetKeySet().Key(kid)
+			require.Len(t, keys, 1)
+			assert.Equal(t, kid, keys[0].KeyID)
+		})
+	}
+}
+
+func TestRS256JWTStrategy_GenerateShouldUseAdditionalKey(t *testing.T) {
+	manager := NewKeyManager()
+
+	_, _, err := manager.AddActivePrivateKeyData(exampleIssuerPrivateKey)
+	require.NoError(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	wk, err := manager.AddPrivateKey("legacy", key)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", wk.KeyID)
+	assert.Len(t, manager.GetKeySet().Keys, 2)
+
+	_, err = manager.AddPrivateKey("legacy", key)
+	assert.EqualError(t, err, "key id legacy already exists")
+
+	strategy := manager.Strategy()
+
+	testCases := []struct {
+		name     string
+		kid      string
+		expected string
+	}{
+		{"ShouldSignWithAdditionalKey", "legacy", "legacy"},
+		{"ShouldSignWithActiveKey", manager.GetActiveKeyID(), manager.GetActiveKeyID()},
+		{"ShouldSignWithActiveKeyWhenUnknown", "abc123", manager.GetActiveKeyID()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, _, err := strategy.Generate(context.Background(), jwt.MapClaims{"sub": "john"}, &jwt.Headers{Extra: map[string]interface{}{"kid": tc.kid}})
+			require.NoError(t, err)
+
+			decoded, err := strategy.Decode(context.Background(), token)
+			require.NoError(t, err)
+			assert.True(t, decoded.Valid())
+
+			assert.Equal(t, tc.expected, decoded.Header["kid"])
+
+			_, err = strategy.Validate(context.Background(), token)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKeyManager_AddPrivateKeyShouldRequireActiveKey(t *testing.T) {
+	manager := NewKeyManager()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, err = manager.AddPrivateKey("legacy", key)
+	assert.EqualError(t, err, "an active key must be added before any other key")
+}
@@ -2,11 +2,15 @@ package oidc
 
 import (
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/openid"
 	"github.com/ory/herodot"
 
+	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -42,9 +46,24 @@ func NewOpenIDConnectProvider(configuration *schema.OpenIDConnectConfiguration)
 
 	provider.KeyManager = keyManager
 
-	key, err := provider.KeyManager.GetActivePrivateKey()
-	if err != nil {
-		return provider, err
+	if configuration.ConsentAuditLogPath != "" {
+		if provider.ConsentAuditLog, err = NewConsentAuditLog(configuration.ConsentAuditLogPath); err != nil {
+			return provider, err
+		}
+	}
+
+	if configuration.TokenEndpointRateLimit.RequestsPerSecond > 0 {
+		provider.TokenEndpointRateLimiter = NewKeyedRateLimiter(configuration.TokenEndpointRateLimit.RequestsPerSecond, configuration.TokenEndpointRateLimit.Burst)
+	}
+
+	for _, network := range configuration.TokenEndpointTrustedProxies {
+		if cidr, err := authorization.ParseNetwork(network); err == nil {
+			provider.TokenEndpointTrustedProxies = append(provider.TokenEndpointTrustedProxies, cidr)
+		}
+	}
+
+	if configuration.ConsentRateLimit.RequestsPerSecond > 0 {
+		provider.ConsentRateLimiter = NewKeyedRateLimiter(configuration.ConsentRateLimit.RequestsPerSecond, configuration.ConsentRateLimit.Burst)
 	}
 
 	strategy := &compose.CommonStrategy{
@@ -53,10 +72,12 @@ func NewOpenIDConnectProvider(configuration *schema.OpenIDConnectConfiguration)
 			[]byte(utils.HashSHA256FromString(configuration.HMACSecret)),
 			nil,
 		),
-		OpenIDConnectTokenStrategy: compose.NewOpenIDConnectStrategy(
-			composeConfiguration,
-			key,
-		),
+		OpenIDConnectTokenStrategy: &openid.DefaultStrategy{
+			JWTStrategy:         provider.KeyManager.Strategy(),
+			Expiry:              composeConfiguration.GetIDTokenLifespan(),
+			Issuer:              composeConfiguration.IDTokenIssuer,
+			MinParameterEntropy: composeConfiguration.GetMinParameterEntropy(),
+		},
 		JWTStrategy: provider.KeyManager.Strategy(),
 	}
 
@@ -66,6 +87,9 @@ func NewOpenIDConnectProvider(configuration *schema.OpenIDConnectConfiguration)
 		strategy,
 		AutheliaHasher{},
 
+		// The client rate limit must be applied before the grant is handled by any of the other token endpoint handlers.
+		ClientRateLimitFactory,
+
 		/*
 			These are the OAuth2 and OpenIDConnect factories. Order is important (the OAuth2 factories at the top must
 			be before the OpenIDConnect factories) and taken directly from fosite.compose.ComposeAllEnabled. The
@@ -154,6 +178,10 @@ func NewOpenIDConnectProvider(configuration *schema.OpenIDConnectConfiguration)
 		},
 	}
 
+	if len(configuration.AllowedResponseTypes) != 0 {
+		provider.discovery.ResponseTypesSupported = configuration.AllowedResponseTypes
+	}
+
 	if configuration.EnablePKCEPlainChallenge {
 		provider.discovery.CodeChallengeMethodsSupported = append(provider.discovery.CodeChallengeMethodsSupported, "plain")
 	}
@@ -179,6 +207,43 @@ func (p OpenIDConnectProvider) WriteErrorCode(w http.ResponseWriter, r *http.Req
 }
 
 // GetOAuth2WellKnownConfiguration returns the discovery document for the OAuth Configuration.
+// TokenEndpointRemoteIP returns the IP address the token endpoint rate limit is applied to for a request received from
+// the given peer with the given X-Forwarded-For header. The header is only used when the peer is one of the trusted
+// proxies, in which case the right-most address which isn't a trusted proxy is returned as the addresses left of it may
+// have been set by the client.
+func (p OpenIDConnectProvider) TokenEndpointRemoteIP(peer net.IP, forwardedFor []byte) net.IP {
+	if !isIPInNetworks(peer, p.TokenEndpointTrustedProxies) || len(forwardedFor) == 0 {
+		return peer
+	}
+
+	ips := strings.Split(string(forwardedFor), ",")
+
+	for i := len(ips) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(ips[i]))
+		if ip == nil {
+			break
+		}
+
+		peer = ip
+
+		if !isIPInNetworks(ip, p.TokenEndpointTrustedProxies) {
+			break
+		}
+	}
+
+	return peer
+}
+
+func isIPInNetworks(ip net.IP, networks []*net.IPNet) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p OpenIDConnectProvider) GetOAuth2WellKnownConfiguration(issuer string) OAuth2WellKnownConfiguration {
 	options := OAuth2WellKnownConfiguration{
 		CommonDiscoveryOptions: p.discovery.CommonDiscoveryOptions,
@@ -1,6 +1,7 @@
 package oidc
 
 import (
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -251,3 +252,31 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnow
 	assert.Equal(t, "S256", disco.CodeChallengeMethodsSupported[0])
 	assert.Equal(t, "plain", disco.CodeChallengeMethodsSupported[1])
 }
+
+func TestOpenIDConnectProvider_TokenEndpointRemoteIP(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey:            exampleIssuerPrivateKey,
+		HMACSecret:                  "asbdhaaskmdlkamdklasmdlkams",
+		TokenEndpointTrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		peer         string
+		forwardedFor string
+		expected     string
+	}{
+		{"ShouldUsePeerWithoutHeader", "10.0.0.1", "", "10.0.0.1"},
+		{"ShouldIgnoreHeaderFromUntrustedPeer", "203.0.113.1", "198.51.100.1", "203.0.113.1"},
+		{"ShouldUseHeaderFromTrustedPeer", "10.0.0.1", "198.51.100.1", "198.51.100.1"},
+		{"ShouldUseRightMostUntrustedAddress", "10.0.0.1", "198.51.100.99, 198.51.100.1, 192.168.1.1", "198.51.100.1"},
+		{"ShouldStopAtInvalidAddress", "10.0.0.1", "198.51.100.1, invalid, 10.0.0.2", "10.0.0.2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, provider.TokenEndpointRemoteIP(net.ParseIP(tc.peer), []byte(tc.forwardedFor)).String())
+		})
+	}
+}
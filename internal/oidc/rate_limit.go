@@ -0,0 +1,112 @@
+package oidc
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// NewRateLimiter creates a new RateLimiter which allows the given number of requests per second on average with bursts
+// of up to the given number of requests.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// RateLimiter is a token bucket rate limiter.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// Allow returns true if a request is allowed at the given time. If the request is not allowed it returns the duration
+// to wait until the next request is allowed.
+func (l *RateLimiter) Allow(now time.Time) (allowed bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+
+	if l.last.IsZero() || now.After(l.last) {
+		l.last = now
+	}
+
+	if l.tokens >= 1 {
+		l.tokens--
+
+		return true, 0
+	}
+
+	return false, time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// NewKeyedRateLimiter creates a new KeyedRateLimiter which allows each key the given number of requests per second on
+// average with bursts of up to the given number of requests.
+func NewKeyedRateLimiter(requestsPerSecond float64, burst int) *KeyedRateLimiter {
+	return &KeyedRateLimiter{
+		rate:     requestsPerSecond,
+		burst:    burst,
+		limiters: map[string]*RateLimiter{},
+	}
+}
+
+// KeyedRateLimiter is a token bucket rate limiter with a separate bucket for each key such as the remote IP. Buckets
+// which have been refilled are periodically removed so the memory usage is bound by the number of recently active keys.
+type KeyedRateLimiter struct {
+	mu sync.Mutex
+
+	rate  float64
+	burst int
+
+	limiters map[string]*RateLimiter
+	pruned   time.Time
+}
+
+// Allow returns true if a request with the given key is allowed at the given time. If the request is not allowed it
+// returns the duration to wait until the next request with the key is allowed.
+func (l *KeyedRateLimiter) Allow(key string, now time.Time) (allowed bool, retryAfter time.Duration) {
+	l.mu.Lock()
+
+	l.prune(now)
+
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = NewRateLimiter(l.rate, l.burst)
+		l.limiters[key] = limiter
+	}
+
+	l.mu.Unlock()
+
+	return limiter.Allow(now)
+}
+
+// prune removes the buckets which would have been refilled completely, which are equivalent to a new bucket. It only
+// runs once per refill period.
+func (l *KeyedRateLimiter) prune(now time.Time) {
+	period := time.Duration(float64(l.burst) / l.rate * float64(time.Second))
+
+	if now.Sub(l.pruned) < period {
+		return
+	}
+
+	for key, limiter := range l.limiters {
+		limiter.mu.Lock()
+
+		if now.Sub(limiter.last) >= period {
+			delete(l.limiters, key)
+		}
+
+		limiter.mu.Unlock()
+	}
+
+	l.pruned = now
+}
@@ -0,0 +1,81 @@
+package oidc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterShouldAllowBurstThenLimit(t *testing.T) {
+	limiter := NewRateLimiter(2, 3)
+
+	now := time.Unix(1000000, 0)
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := limiter.Allow(now)
+		assert.True(t, allowed)
+	}
+
+	allowed, retryAfter := limiter.Allow(now)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Millisecond*500, retryAfter)
+
+	allowed, _ = limiter.Allow(now.Add(time.Millisecond * 500))
+	assert.True(t, allowed)
+
+	allowed, _ = limiter.Allow(now.Add(time.Millisecond * 600))
+	assert.False(t, allowed)
+
+	// The tokens never exceed the burst.
+	for i := 0; i < 3; i++ {
+		allowed, _ = limiter.Allow(now.Add(time.Hour))
+		assert.True(t, allowed)
+	}
+
+	allowed, _ = limiter.Allow(now.Add(time.Hour))
+	assert.False(t, allowed)
+}
+
+func TestKeyedRateLimiterShouldLimitEachKey(t *testing.T) {
+	limiter := NewKeyedRateLimiter(1, 2)
+
+	now := time.Unix(1000000, 0)
+
+	for i := 0; i < 2; i++ {
+		allowed, _ := limiter.Allow("192.168.1.1", now)
+		assert.True(t, allowed)
+	}
+
+	allowed, retryAfter := limiter.Allow("192.168.1.1", now)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// Other keys have their own bucket.
+	allowed, _ = limiter.Allow("192.168.1.2", now)
+	assert.True(t, allowed)
+
+	assert.Len(t, limiter.limiters, 2)
+
+	allowed, _ = limiter.Allow("192.168.1.1", now.Add(time.Second))
+	assert.True(t, allowed)
+}
+
+func TestKeyedRateLimiterShouldPruneRefilledKeys(t *testing.T) {
+	limiter := NewKeyedRateLimiter(1, 2)
+
+	now := time.Unix(1000000, 0)
+
+	limiter.Allow("192.168.1.1", now)
+	limiter.Allow("192.168.1.2", now.Add(time.Second))
+
+	assert.Len(t, limiter.limiters, 2)
+
+	// Only the first key has been idle for the refill period of 2 seconds.
+	limiter.Allow("192.168.1.3", now.Add(time.Second*2))
+
+	assert.Len(t, limiter.limiters, 2)
+	assert.NotContains(t, limiter.limiters, "192.168.1.1")
+	assert.Contains(t, limiter.limiters, "192.168.1.2")
+	assert.Contains(t, limiter.limiters, "192.168.1.3")
+}
@@ -2,6 +2,8 @@ package oidc
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/ory/fosite"
@@ -32,6 +34,8 @@ func NewOpenIDConnectStore(configuration *schema.OpenIDConnectConfiguration) (st
 	}
 
 	store.clients = make(map[string]*InternalClient)
+	store.userRequests = make(map[string]userRequest)
+	store.userRequestsMutex = &sync.Mutex{}
 
 	for _, client := range configuration.Clients {
 		policy := authorization.PolicyToLevel(client.Policy)
@@ -85,6 +89,103 @@ func (s *OpenIDConnectStore) DeleteOpenIDConnectSession(ctx context.Context, aut
 	return s.memory.DeleteOpenIDConnectSession(ctx, authorizeCode)
 }
 
+// setClientLifespan applies the client specific lifespan of the fosite.TokenType to the session of the request before
+// it's persisted, as fosite only supports global lifespans.
+func (s *OpenIDConnectStore) setClientLifespan(req fosite.Requester, tokenType fosite.TokenType) {
+	if req == nil || req.GetSession() == nil {
+		return
+	}
+
+	if client, ok := req.GetClient().(*InternalClient); ok {
+		client.SetSessionLifespan(req.GetSession(), tokenType, time.Now())
+	}
+}
+
+// EnableUserRequestTracking enables recording the request ID of the tokens issued to each user which is required to
+// revoke all of the tokens of a user with RevokeUserTokens. It's disabled by default as nothing else needs the records.
+func (s *OpenIDConnectStore) EnableUserRequestTracking() {
+	s.userRequestsMutex.Lock()
+	defer s.userRequestsMutex.Unlock()
+
+	s.trackUserRequests = true
+}
+
+// trackUserRequest records the request ID of a token issued to a user so all of the tokens of the user can be revoked,
+// and removes the records of the requests which have expired.
+func (s *OpenIDConnectStore) trackUserRequest(req fosite.Requester, tokenType fosite.TokenType) {
+	if req == nil {
+		return
+	}
+
+	session, ok := req.GetSession().(*OpenIDSession)
+	if !ok || session.Username == "" {
+		return
+	}
+
+	s.userRequestsMutex.Lock()
+	defer s.userRequestsMutex.Unlock()
+
+	if !s.trackUserRequests {
+		return
+	}
+
+	now := time.Now()
+
+	for id, request := range s.userRequests {
+		if !request.expires.IsZero() && request.expires.Before(now) {
+			delete(s.userRequests, id)
+		}
+	}
+
+	expires := session.GetExpiresAt(tokenType)
+
+	// The record is kept until the last token of the request expires, a token which doesn't expire keeps it until the
+	// tokens are revoked.
+	if request, ok := s.userRequests[req.GetID()]; ok && !expires.IsZero() && (request.expires.IsZero() || request.expires.After(expires)) {
+		expires = request.expires
+	}
+
+	s.userRequests[req.GetID()] = userRequest{username: session.Username, expires: expires}
+}
+
+// forgetUserRequest removes the record of a request when its tokens are revoked.
+func (s *OpenIDConnectStore) forgetUserRequest(requestID string) {
+	s.userRequestsMutex.Lock()
+	defer s.userRequestsMutex.Unlock()
+
+	delete(s.userRequests, requestID)
+}
+
+// RevokeUserTokens revokes all of the access and refresh tokens which have been issued to the user. The tokens are only
+// recorded by user when EnableUserRequestTracking has been called.
+func (s *OpenIDConnectStore) RevokeUserTokens(ctx context.Context, username string) (err error) {
+	var requestIDs []string
+
+	s.userRequestsMutex.Lock()
+
+	for id, request := range s.userRequests {
+		if request.username == username {
+			requestIDs = append(requestIDs, id)
+
+			delete(s.userRequests, id)
+		}
+	}
+
+	s.userRequestsMutex.Unlock()
+
+	for _, requestID := range requestIDs {
+		if err = s.memory.RevokeRefreshToken(ctx, requestID); err != nil && !errors.Is(err, fosite.ErrNotFound) {
+			return err
+		}
+
+		if err = s.memory.RevokeAccessToken(ctx, requestID); err != nil && !errors.Is(err, fosite.ErrNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetClient decorates fosite's storage.MemoryStore GetClient method.
 func (s *OpenIDConnectStore) GetClient(_ context.Context, id string) (fosite.Client, error) {
 	return s.GetInternalClient(id)
@@ -102,6 +203,8 @@ func (s *OpenIDConnectStore) SetClientAssertionJWT(ctx context.Context, jti stri
 
 // CreateAuthorizeCodeSession decorates fosite's storage.MemoryStore CreateAuthorizeCodeSession method.
 func (s *OpenIDConnectStore) CreateAuthorizeCodeSession(ctx context.Context, code string, req fosite.Requester) error {
+	s.setClientLifespan(req, fosite.AuthorizeCode)
+
 	return s.memory.CreateAuthorizeCodeSession(ctx, code, req)
 }
 
@@ -132,6 +235,9 @@ func (s *OpenIDConnectStore) DeletePKCERequestSession(ctx context.Context, code
 
 // CreateAccessTokenSession decorates fosite's storage.MemoryStore CreateAccessTokenSession method.
 func (s *OpenIDConnectStore) CreateAccessTokenSession(ctx context.Context, signature string, req fosite.Requester) error {
+	s.setClientLifespan(req, fosite.AccessToken)
+	s.trackUserRequest(req, fosite.AccessToken)
+
 	return s.memory.CreateAccessTokenSession(ctx, signature, req)
 }
 
@@ -147,6 +253,9 @@ func (s *OpenIDConnectStore) DeleteAccessTokenSession(ctx context.Context, signa
 
 // CreateRefreshTokenSession decorates fosite's storage.MemoryStore CreateRefreshTokenSession method.
 func (s *OpenIDConnectStore) CreateRefreshTokenSession(ctx context.Context, signature string, req fosite.Requester) error {
+	s.setClientLifespan(req, fosite.RefreshToken)
+	s.trackUserRequest(req, fosite.RefreshToken)
+
 	return s.memory.CreateRefreshTokenSession(ctx, signature, req)
 }
 
@@ -167,6 +276,8 @@ func (s *OpenIDConnectStore) Authenticate(ctx context.Context, name string, secr
 
 // RevokeRefreshToken decorates fosite's storage.MemoryStore RevokeRefreshToken method.
 func (s *OpenIDConnectStore) RevokeRefreshToken(ctx context.Context, requestID string) error {
+	s.forgetUserRequest(requestID)
+
 	return s.memory.RevokeRefreshToken(ctx, requestID)
 }
 
@@ -177,6 +288,8 @@ func (s OpenIDConnectStore) RevokeRefreshTokenMaybeGracePeriod(ctx context.Conte
 
 // RevokeAccessToken decorates fosite's storage.MemoryStore RevokeAccessToken method.
 func (s *OpenIDConnectStore) RevokeAccessToken(ctx context.Context, requestID string) error {
+	s.forgetUserRequest(requestID)
+
 	return s.memory.RevokeAccessToken(ctx, requestID)
 }
 
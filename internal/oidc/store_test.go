@@ -3,7 +3,9 @@ package oidc
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -112,6 +114,56 @@ func TestOpenIDConnectStore_GetInternalClient_InvalidClient(t *testing.T) {
 	assert.EqualError(t, err, "not_found")
 }
 
+func TestOpenIDConnectStore_GetClientShouldMatchLoopbackRedirectURIWithAnyPort(t *testing.T) {
+	s := NewOpenIDConnectStore(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:     "native",
+				Public: true,
+				Policy: "two_factor",
+				RedirectURIs: []string{
+					"http://127.0.0.1/callback",
+					"http://[::1]/callback",
+					"http://localhost:8080/callback",
+					"https://app.example.com/callback",
+				},
+			},
+		},
+	})
+
+	client, err := s.GetClient(context.Background(), "native")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name, redirectURI string
+		match             bool
+	}{
+		{"ShouldMatchIPv4LoopbackWithPort", "http://127.0.0.1:51004/callback", true},
+		{"ShouldMatchIPv4LoopbackWithoutPort", "http://127.0.0.1/callback", true},
+		{"ShouldMatchIPv6LoopbackWithPort", "http://[::1]:51004/callback", true},
+		{"ShouldMatchLocalhostWithExactPort", "http://localhost:8080/callback", true},
+		{"ShouldNotMatchLocalhostWithOtherPort", "http://localhost:51004/callback", false},
+		{"ShouldNotMatchLoopbackWithOtherPath", "http://127.0.0.1:51004/other", false},
+		{"ShouldNotMatchLoopbackWithHTTPS", "https://127.0.0.1:51004/callback", false},
+		{"ShouldNotMatchOtherLoopbackAddress", "http://127.0.0.2:51004/callback", false},
+		{"ShouldNotMatchNonLoopbackWithOtherPort", "https://app.example.com:8443/callback", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			redirectURI, err := fosite.MatchRedirectURIWithClientRedirectURIs(tc.redirectURI, client)
+
+			if tc.match {
+				require.NoError(t, err)
+				assert.Equal(t, tc.redirectURI, redirectURI.String())
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 func TestOpenIDConnectStore_IsValidClientID(t *testing.T) {
 	s := NewOpenIDConnectStore(&schema.OpenIDConnectConfiguration{
 		IssuerPrivateKey: exampleIssuerPrivateKey,
@@ -132,3 +184,129 @@ func TestOpenIDConnectStore_IsValidClientID(t *testing.T) {
 	assert.True(t, validClient)
 	assert.False(t, invalidClient)
 }
+
+func TestOpenIDConnectStore_CreateAccessTokenSessionShouldApplyClientLifespan(t *testing.T) {
+	s := NewOpenIDConnectStore(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:                  "myclient",
+				Policy:              "one_factor",
+				Secret:              "mysecret",
+				AccessTokenLifespan: time.Minute * 5,
+			},
+		},
+	})
+
+	client, err := s.GetInternalClient("myclient")
+	require.NoError(t, err)
+
+	session := NewSession()
+	session.SetExpiresAt(fosite.AccessToken, time.Now().UTC().Add(time.Hour))
+
+	request := fosite.NewRequest()
+	request.Client = client
+	request.Session = session
+
+	require.NoError(t, s.CreateAccessTokenSession(context.Background(), "signature", request))
+
+	assert.WithinDuration(t, time.Now().UTC().Add(time.Minute*5), session.GetExpiresAt(fosite.AccessToken), time.Second*2)
+}
+
+func TestOpenIDConnectStore_RevokeUserTokens(t *testing.T) {
+	s := NewOpenIDConnectStore(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:     "myclient",
+				Policy: "one_factor",
+				Secret: "mysecret",
+			},
+		},
+	})
+
+	s.EnableUserRequestTracking()
+
+	client, err := s.GetInternalClient("myclient")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for _, username := range []string{"john", "harry"} {
+		session := NewSession()
+		session.Username = username
+
+		request := fosite.NewRequest()
+		request.ID = username + "-request"
+		request.Client = client
+		request.Session = session
+
+		require.NoError(t, s.CreateAccessTokenSession(ctx, username+"-access", request))
+		require.NoError(t, s.CreateRefreshTokenSession(ctx, username+"-refresh", request))
+	}
+
+	require.NoError(t, s.RevokeUserTokens(ctx, "john"))
+
+	_, err = s.GetAccessTokenSession(ctx, "john-access", NewSession())
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	_, err = s.GetRefreshTokenSession(ctx, "john-refresh", NewSession())
+	assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+
+	_, err = s.GetAccessTokenSession(ctx, "harry-access", NewSession())
+	assert.NoError(t, err)
+
+	_, err = s.GetRefreshTokenSession(ctx, "harry-refresh", NewSession())
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.RevokeUserTokens(ctx, "unknown"))
+}
+
+func TestOpenIDConnectStore_ShouldOnlyTrackUnexpiredUserRequestsWhenEnabled(t *testing.T) {
+	s := NewOpenIDConnectStore(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:     "myclient",
+				Policy: "one_factor",
+				Secret: "mysecret",
+			},
+		},
+	})
+
+	client, err := s.GetInternalClient("myclient")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	newRequest := func(id string, expires time.Time) *fosite.Request {
+		session := NewSession()
+		session.Username = "john"
+		session.SetExpiresAt(fosite.AccessToken, expires)
+
+		request := fosite.NewRequest()
+		request.ID = id
+		request.Client = client
+		request.Session = session
+
+		return request
+	}
+
+	s.trackUserRequest(newRequest("untracked", time.Now().Add(time.Hour)), fosite.AccessToken)
+
+	assert.Len(t, s.userRequests, 0)
+
+	s.EnableUserRequestTracking()
+
+	s.trackUserRequest(newRequest("expired", time.Now().Add(-time.Minute)), fosite.AccessToken)
+	s.trackUserRequest(newRequest("revoked", time.Now().Add(time.Hour)), fosite.AccessToken)
+	s.trackUserRequest(newRequest("active", time.Now().Add(time.Hour)), fosite.AccessToken)
+
+	assert.Len(t, s.userRequests, 2)
+	assert.NotContains(t, s.userRequests, "expired")
+
+	require.NoError(t, s.RevokeAccessToken(ctx, "revoked"))
+
+	assert.Len(t, s.userRequests, 1)
+	assert.Contains(t, s.userRequests, "active")
+}
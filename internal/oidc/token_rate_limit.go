@@ -0,0 +1,51 @@
+package oidc
+
+import (
+	"context"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+)
+
+// ClientRateLimitFactory creates the ClientRateLimitHandler. It must be the first token endpoint factory so the rate
+// limit is applied before the other handlers handle the grant.
+func ClientRateLimitFactory(_ *compose.Config, _ interface{}, _ interface{}) interface{} {
+	return &ClientRateLimitHandler{}
+}
+
+// ClientRateLimitHandler is a fosite.TokenEndpointHandler which applies the token endpoint rate limit of the client. It
+// runs after the client is authenticated but before the grant is handled, so a request which is rate limited doesn't
+// consume the authorization code or rotate the refresh token, and the rate limit of a client can't be exhausted by
+// requests with invalid credentials.
+type ClientRateLimitHandler struct{}
+
+// HandleTokenEndpointRequest implements fosite.TokenEndpointHandler. It returns a RateLimitedError when the request is
+// rate limited and fosite.ErrUnknownRequest otherwise so the request is handled by the grant handlers.
+func (h *ClientRateLimitHandler) HandleTokenEndpointRequest(_ context.Context, requester fosite.AccessRequester) error {
+	if client, ok := requester.GetClient().(*InternalClient); ok && client.TokenEndpointRateLimiter != nil {
+		if allowed, retryAfter := client.TokenEndpointRateLimiter.Allow(time.Now()); !allowed {
+			return &RateLimitedError{RetryAfter: retryAfter}
+		}
+	}
+
+	return fosite.ErrUnknownRequest
+}
+
+// PopulateTokenEndpointResponse implements fosite.TokenEndpointHandler.
+func (h *ClientRateLimitHandler) PopulateTokenEndpointResponse(_ context.Context, _ fosite.AccessRequester, _ fosite.AccessResponder) error {
+	return fosite.ErrUnknownRequest
+}
+
+// CanSkipClientAuth implements fosite.TokenEndpointHandler.
+func (h *ClientRateLimitHandler) CanSkipClientAuth(_ fosite.AccessRequester) bool {
+	return false
+}
+
+// CanHandleTokenEndpointRequest implements fosite.TokenEndpointHandler. Only requests with an authenticated client are
+// handled, requests without one are rejected by the grant handlers.
+func (h *ClientRateLimitHandler) CanHandleTokenEndpointRequest(requester fosite.AccessRequester) bool {
+	_, ok := requester.GetClient().(*InternalClient)
+
+	return ok
+}
@@ -0,0 +1,93 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestClientRateLimitHandlerShouldLimitAuthenticatedClients(t *testing.T) {
+	handler := &ClientRateLimitHandler{}
+
+	client := &InternalClient{ID: "test", TokenEndpointRateLimiter: NewRateLimiter(1, 1)}
+
+	requester := fosite.NewAccessRequest(NewSession())
+
+	assert.False(t, handler.CanHandleTokenEndpointRequest(requester))
+
+	requester.Client = client
+
+	assert.True(t, handler.CanHandleTokenEndpointRequest(requester))
+	assert.False(t, handler.CanSkipClientAuth(requester))
+
+	assert.ErrorIs(t, handler.HandleTokenEndpointRequest(context.Background(), requester), fosite.ErrUnknownRequest)
+
+	err := handler.HandleTokenEndpointRequest(context.Background(), requester)
+
+	var limited *RateLimitedError
+
+	require.True(t, errors.As(err, &limited))
+	assert.ErrorIs(t, err, ErrTokenEndpointRateLimited)
+	assert.Greater(t, limited.RetryAfter.Seconds(), 0.0)
+
+	requester.Client = &InternalClient{ID: "unlimited"}
+
+	assert.ErrorIs(t, handler.HandleTokenEndpointRequest(context.Background(), requester), fosite.ErrUnknownRequest)
+}
+
+func TestShouldRateLimitClientBeforeHandlingTheGrant(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:     "a-client",
+				Secret: "a-client-secret",
+				Policy: "one_factor",
+				RedirectURIs: []string{
+					"https://google.com",
+				},
+				TokenEndpointRateLimit: schema.OpenIDConnectClientRateLimitConfiguration{RequestsPerSecond: 0.01, Burst: 1},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	newRequest := func(secret string) *http.Request {
+		form := url.Values{}
+
+		form.Set("grant_type", "authorization_code")
+		form.Set("code", "invalid")
+		form.Set("redirect_uri", "https://google.com")
+
+		req, err := http.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("a-client", secret)
+
+		return req
+	}
+
+	// Requests with invalid credentials are rejected by the client authentication and don't count against the limit.
+	for i := 0; i < 3; i++ {
+		_, err = provider.Fosite.NewAccessRequest(context.Background(), newRequest("bad-secret"), NewSession())
+		assert.ErrorIs(t, err, fosite.ErrInvalidClient)
+	}
+
+	_, err = provider.Fosite.NewAccessRequest(context.Background(), newRequest("a-client-secret"), NewSession())
+	assert.ErrorIs(t, err, fosite.ErrInvalidGrant)
+
+	// The rate limited request is rejected before the authorization code is looked up.
+	_, err = provider.Fosite.NewAccessRequest(context.Background(), newRequest("a-client-secret"), NewSession())
+	assert.ErrorIs(t, err, ErrTokenEndpointRateLimited)
+}
@@ -2,6 +2,8 @@ package oidc
 
 import (
 	"crypto/rsa"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/ory/fosite"
@@ -69,6 +71,12 @@ type OpenIDConnectProvider struct {
 	Store      *OpenIDConnectStore
 	KeyManager *KeyManager
 
+	ConsentAuditLog *ConsentAuditLog
+
+	TokenEndpointRateLimiter    *KeyedRateLimiter
+	TokenEndpointTrustedProxies []*net.IPNet
+	ConsentRateLimiter          *KeyedRateLimiter
+
 	herodot *herodot.JSONWriter
 
 	discovery OpenIDConnectWellKnownConfiguration
@@ -82,6 +90,17 @@ type OpenIDConnectProvider struct {
 type OpenIDConnectStore struct {
 	clients map[string]*InternalClient
 	memory  *storage.MemoryStore
+
+	trackUserRequests bool
+	userRequests      map[string]userRequest
+	userRequestsMutex *sync.Mutex
+}
+
+// userRequest is the user and expiration of the tokens of a request tracked by the OpenIDConnectStore so all of the
+// tokens of a user can be revoked. A zero expiration means the tokens never expire.
+type userRequest struct {
+	username string
+	expires  time.Time
 }
 
 // InternalClient represents the client internally.
@@ -91,6 +110,8 @@ type InternalClient struct {
 	Secret      []byte `json:"client_secret,omitempty"`
 	Public      bool   `json:"public"`
 
+	RotatedSecrets [][]byte `json:"-"`
+
 	Policy authorization.Level `json:"-"`
 
 	Audience      []string                  `json:"audience"`
@@ -101,6 +122,24 @@ type InternalClient struct {
 	ResponseModes []fosite.ResponseModeType `json:"response_modes"`
 
 	UserinfoSigningAlgorithm string `json:"userinfo_signed_response_alg,omitempty"`
+	SigningKeyID             string `json:"-"`
+
+	TokenEndpointRateLimiter *RateLimiter `json:"-"`
+
+	AccessTokenLifespan   time.Duration `json:"-"`
+	AuthorizeCodeLifespan time.Duration `json:"-"`
+	IDTokenLifespan       time.Duration `json:"-"`
+	RefreshTokenLifespan  time.Duration `json:"-"`
+
+	RefreshTokenAbsoluteLifetime time.Duration `json:"-"`
+
+	RefreshSessionActivity bool `json:"-"`
+
+	RequireState bool `json:"-"`
+
+	AllowedNetworks []*net.IPNet `json:"-"`
+
+	Claims map[string]string `json:"-"`
 }
 
 // KeyManager keeps track of all of the active/inactive rsa keys and provides them to services requiring them.
@@ -129,6 +168,34 @@ type OpenIDSession struct {
 
 	Extra    map[string]interface{} `json:"extra"`
 	ClientID string
+
+	// SessionID is the ID of the Authelia session which authorized the request, it's only set for clients which
+	// refresh the session activity so the session can be refreshed by requests which don't include the session cookie.
+	SessionID []byte `json:"-"`
+}
+
+// Clone copies the OpenIDSession. The refresh token flow uses the clone of the session of the original request which
+// would otherwise be the clone of the embedded openid.DefaultSession.
+func (s *OpenIDSession) Clone() fosite.Session {
+	if s == nil {
+		return nil
+	}
+
+	clone := &OpenIDSession{
+		Extra:     make(map[string]interface{}, len(s.Extra)),
+		ClientID:  s.ClientID,
+		SessionID: append([]byte(nil), s.SessionID...),
+	}
+
+	if s.DefaultSession != nil {
+		clone.DefaultSession = s.DefaultSession.Clone().(*openid.DefaultSession)
+	}
+
+	for key, value := range s.Extra {
+		clone.Extra[key] = value
+	}
+
+	return clone
 }
 
 /*
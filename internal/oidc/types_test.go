@@ -85,3 +85,30 @@ func TestNewSessionWithAuthorizeRequest(t *testing.T) {
 	assert.NotNil(t, session.Claims.Extra)
 	assert.Nil(t, session.Claims.AuthenticationMethodsReferences)
 }
+
+func TestShouldCloneSession(t *testing.T) {
+	session := NewSession()
+
+	session.ClientID = "example"
+	session.Username = "john"
+	session.SessionID = []byte("abc123")
+	session.Extra["key"] = "value"
+
+	clone, ok := session.Clone().(*OpenIDSession)
+	require.True(t, ok)
+
+	assert.Equal(t, "example", clone.ClientID)
+	assert.Equal(t, "john", clone.Username)
+	assert.Equal(t, []byte("abc123"), clone.SessionID)
+	assert.Equal(t, "value", clone.Extra["key"])
+
+	clone.SessionID[0] = 'x'
+	clone.Username = "harry"
+
+	assert.Equal(t, []byte("abc123"), session.SessionID)
+	assert.Equal(t, "john", session.Username)
+
+	var nilSession *OpenIDSession
+
+	assert.Nil(t, nilSession.Clone())
+}
@@ -17,4 +17,7 @@ const (
 
 	// AuthTypeDuo is the string representing an auth log for second-factor authentication via DUO.
 	AuthTypeDuo = "Duo"
+
+	// AuthTypeConsent is the string representing an auth log for a suspicious OpenID Connect consent attempt.
+	AuthTypeConsent = "Consent"
 )
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/fs"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
@@ -25,17 +27,11 @@ func newPublicHTMLEmbeddedHandler() fasthttp.RequestHandler {
 	return fasthttpadaptor.NewFastHTTPHandler(http.FileServer(http.FS(embeddedPath)))
 }
 
-func newLocalesEmbeddedHandler() (handler fasthttp.RequestHandler) {
-	var languages []string
-
-	entries, err := locales.ReadDir("locales")
-	if err == nil {
-		for _, entry := range entries {
-			if entry.IsDir() && len(entry.Name()) == 2 {
-				languages = append(languages, entry.Name())
-			}
-		}
-	}
+// newLocalesEmbeddedHandler returns a handler which serves the embedded locales. The supported locales are derived
+// from the embedded locales and the locales in the asset path override, and any request for an unsupported locale is
+// served the fallback locale instead.
+func newLocalesEmbeddedHandler(assetPath, fallback string) (handler fasthttp.RequestHandler) {
+	supported := localesSupported(assetPath)
 
 	return func(ctx *fasthttp.RequestCtx) {
 		var (
@@ -48,21 +44,29 @@ func newLocalesEmbeddedHandler() (handler fasthttp.RequestHandler) {
 
 		if v := ctx.UserValue("variant"); v != nil {
 			variant = v.(string)
-			locale = fmt.Sprintf("%s-%s", language, locale)
+			locale = fmt.Sprintf("%s-%s", language, variant)
 		}
 
-		var data []byte
+		var (
+			data []byte
+			err  error
+		)
 
-		if data, err = locales.ReadFile(fmt.Sprintf("locales/%s/%s.json", locale, namespace)); err != nil {
-			if variant != "" && utils.IsStringInSliceFold(language, languages) {
-				data = []byte("{}")
+		switch {
+		case utils.IsStringInSliceFold(locale, supported):
+			if data, err = localesRead(assetPath, locale, namespace); errors.Is(err, fs.ErrNotExist) {
+				data, err = localesRead(assetPath, fallback, namespace)
 			}
+		case variant != "" && utils.IsStringInSliceFold(language, supported):
+			data = []byte("{}")
+		default:
+			data, err = localesRead(assetPath, fallback, namespace)
+		}
 
-			if len(data) == 0 {
-				hfsHandleErr(ctx, err)
+		if err != nil {
+			hfsHandleErr(ctx, err)
 
-				return
-			}
+			return
 		}
 
 		ctx.SetContentType("application/json")
@@ -70,6 +74,42 @@ func newLocalesEmbeddedHandler() (handler fasthttp.RequestHandler) {
 	}
 }
 
+func localesSupported(assetPath string) (supported []string) {
+	if entries, err := locales.ReadDir("locales"); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				supported = append(supported, entry.Name())
+			}
+		}
+	}
+
+	if assetPath == "" {
+		return supported
+	}
+
+	if entries, err := os.ReadDir(filepath.Join(assetPath, "locales")); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() && !utils.IsStringInSlice(entry.Name(), supported) {
+				supported = append(supported, entry.Name())
+			}
+		}
+	}
+
+	return supported
+}
+
+// localesRead reads the namespace of a locale from the asset path override if it exists there, otherwise from the
+// embedded locales. A locale which only exists in the asset path override may not have every namespace.
+func localesRead(assetPath, locale, namespace string) (data []byte, err error) {
+	if assetPath != "" {
+		if data, err = os.ReadFile(filepath.Join(assetPath, "locales", locale, namespace+".json")); err == nil {
+			return data, nil
+		}
+	}
+
+	return locales.ReadFile(fmt.Sprintf("locales/%s/%s.json", locale, namespace))
+}
+
 func hfsHandleErr(ctx *fasthttp.RequestCtx, err error) {
 	switch {
 	case errors.Is(err, fs.ErrNotExist):
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestShouldServeLocalesWithFallback(t *testing.T) {
+	assetPath := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(assetPath, "locales", "fr"), 0700))
+	require.NoError(t, os.MkdirAll(filepath.Join(assetPath, "locales", "it"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(assetPath, "locales", "fr", "portal.json"), []byte(`{"Sign in":"Se connecter"}`), 0600))
+
+	fallback, err := locales.ReadFile("locales/en/portal.json")
+	require.NoError(t, err)
+
+	embedded, err := locales.ReadFile("locales/de/portal.json")
+	require.NoError(t, err)
+
+	handler := newLocalesEmbeddedHandler(assetPath, "en")
+
+	testCases := []struct {
+		name      string
+		language  string
+		variant   string
+		namespace string
+		expected  []byte
+	}{
+		{"ShouldServeEmbeddedLocale", "de", "", "portal", embedded},
+		{"ShouldServeOverrideOnlyLocale", "fr", "", "portal", []byte(`{"Sign in":"Se connecter"}`)},
+		{"ShouldServeFallbackForOverrideOnlyLocaleMissingNamespace", "it", "", "portal", fallback},
+		{"ShouldServeFallbackForUnsupportedLocale", "xx", "", "portal", fallback},
+		{"ShouldServeEmptyVariantOfSupportedLocale", "fr", "ca", "portal", []byte("{}")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.SetUserValue("language", tc.language)
+			ctx.SetUserValue("namespace", tc.namespace)
+
+			if tc.variant != "" {
+				ctx.SetUserValue("variant", tc.variant)
+			}
+
+			handler(ctx)
+
+			assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+			assert.Equal(t, "application/json", string(ctx.Response.Header.ContentType()))
+			assert.Equal(t, tc.expected, ctx.Response.Body())
+		})
+	}
+}
+
+func TestShouldReturnNotFoundForMissingLocaleNamespace(t *testing.T) {
+	handler := newLocalesEmbeddedHandler("", "en")
+
+	ctx := &fasthttp.RequestCtx{}
+
+	ctx.SetUserValue("language", "de")
+	ctx.SetUserValue("namespace", "unknown")
+
+	handler(ctx)
+
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+}
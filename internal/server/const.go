@@ -52,6 +52,7 @@ X_AUTHELIA_HEALTHCHECK_SCHEME=%s
 X_AUTHELIA_HEALTHCHECK_HOST=%s
 X_AUTHELIA_HEALTHCHECK_PORT=%d
 X_AUTHELIA_HEALTHCHECK_PATH=%s
+X_AUTHELIA_HEALTHCHECK_ENDPOINT=%s
 `
 
 const (
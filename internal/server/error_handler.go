@@ -6,23 +6,26 @@ import (
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
 // Replacement for the default error handler in fasthttp.
-func autheliaErrorHandler(ctx *fasthttp.RequestCtx, err error) {
-	logger := logging.Logger()
+func newAutheliaErrorHandler(pages *errorPages, redacted []string) func(ctx *fasthttp.RequestCtx, err error) {
+	return func(ctx *fasthttp.RequestCtx, err error) {
+		logger := logging.Logger()
 
-	if _, ok := err.(*fasthttp.ErrSmallBuffer); ok {
-		// Note: Getting X-Forwarded-For or Request URI is impossible for ths error.
-		logger.Tracef("Request was too large to handle from client %s. Response Code %d.", ctx.RemoteIP().String(), fasthttp.StatusRequestHeaderFieldsTooLarge)
-		ctx.Error("request header too large", fasthttp.StatusRequestHeaderFieldsTooLarge)
-	} else if netErr, ok := err.(*net.OpError); ok && netErr.Timeout() {
-		// TODO: Add X-Forwarded-For Check here.
-		logger.Tracef("Request timeout occurred while handling from client %s: %s. Response Code %d.", ctx.RemoteIP().String(), ctx.RequestURI(), fasthttp.StatusRequestTimeout)
-		ctx.Error("request timeout", fasthttp.StatusRequestTimeout)
-	} else {
-		// TODO: Add X-Forwarded-For Check here.
-		logger.Tracef("An unknown error occurred while handling a request from client %s: %s. Response Code %d.", ctx.RemoteIP().String(), ctx.RequestURI(), fasthttp.StatusBadRequest)
-		ctx.Error("error when parsing request", fasthttp.StatusBadRequest)
+		if _, ok := err.(*fasthttp.ErrSmallBuffer); ok {
+			// Note: Getting X-Forwarded-For or Request URI is impossible for ths error.
+			logger.Tracef("Request was too large to handle from client %s. Response Code %d.", ctx.RemoteIP().String(), fasthttp.StatusRequestHeaderFieldsTooLarge)
+			pages.Write(ctx, fasthttp.StatusRequestHeaderFieldsTooLarge, "request header too large")
+		} else if netErr, ok := err.(*net.OpError); ok && netErr.Timeout() {
+			// TODO: Add X-Forwarded-For Check here.
+			logger.Tracef("Request timeout occurred while handling from client %s: %s. Response Code %d.", ctx.RemoteIP().String(), middlewares.RedactRequestURI(ctx, redacted), fasthttp.StatusRequestTimeout)
+			pages.Write(ctx, fasthttp.StatusRequestTimeout, "request timeout")
+		} else {
+			// TODO: Add X-Forwarded-For Check here.
+			logger.Tracef("An unknown error occurred while handling a request from client %s: %s. Response Code %d.", ctx.RemoteIP().String(), middlewares.RedactRequestURI(ctx, redacted), fasthttp.StatusBadRequest)
+			pages.Write(ctx, fasthttp.StatusBadRequest, "error when parsing request")
+		}
 	}
 }
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+// errorPages renders the custom error pages for client (4xx) and server (5xx) errors.
+type errorPages struct {
+	client *template.Template
+	server *template.Template
+}
+
+// errorPageData is the data made available to the custom error page templates.
+type errorPageData struct {
+	StatusCode int
+	StatusText string
+	Message    string
+}
+
+// newErrorPages loads the custom error page templates from the configuration.
+func newErrorPages(config schema.ServerErrorPagesConfiguration) (pages *errorPages) {
+	pages = &errorPages{
+		client: loadErrorPageTemplate(config.ClientError),
+		server: loadErrorPageTemplate(config.ServerError),
+	}
+
+	return pages
+}
+
+func loadErrorPageTemplate(path string) (tmpl *template.Template) {
+	if path == "" {
+		return nil
+	}
+
+	logger := logging.Logger()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Fatalf("Unable to read error page %s: %s", path, err)
+	}
+
+	if tmpl, err = template.New(path).Parse(string(data)); err != nil {
+		logger.Fatalf("Unable to parse error page %s template: %s", path, err)
+	}
+
+	return tmpl
+}
+
+// Write writes an error response with the given status code. When the client accepts HTML and a custom error page is
+// configured for the status code class it is rendered, when the client accepts JSON a JSON error response is written,
+// otherwise the plain text message is written.
+func (p *errorPages) Write(ctx *fasthttp.RequestCtx, statusCode int, message string) {
+	if message == "" {
+		message = fmt.Sprintf("%d %s", statusCode, fasthttp.StatusMessage(statusCode))
+	}
+
+	accept := string(ctx.Request.Header.Peek(fasthttp.HeaderAccept))
+
+	switch {
+	case strings.Contains(accept, "text/html") && p.writeHTML(ctx, statusCode, message):
+		return
+	case strings.Contains(accept, "application/json"):
+		body, err := json.Marshal(middlewares.ErrorResponse{Status: "KO", Message: message})
+		if err == nil {
+			ctx.SetStatusCode(statusCode)
+			ctx.SetContentType("application/json; charset=utf-8")
+			ctx.SetBody(body)
+
+			return
+		}
+	}
+
+	ctx.Error(message, statusCode)
+}
+
+func (p *errorPages) writeHTML(ctx *fasthttp.RequestCtx, statusCode int, message string) bool {
+	if p == nil {
+		return false
+	}
+
+	var tmpl *template.Template
+
+	switch {
+	case statusCode >= 400 && statusCode < 500:
+		tmpl = p.client
+	case statusCode >= 500:
+		tmpl = p.server
+	}
+
+	if tmpl == nil {
+		return false
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err := tmpl.Execute(buf, errorPageData{StatusCode: statusCode, StatusText: fasthttp.StatusMessage(statusCode), Message: message}); err != nil {
+		logging.Logger().Errorf("Unable to execute error page template for status code %d: %s", statusCode, err)
+
+		return false
+	}
+
+	ctx.SetStatusCode(statusCode)
+	ctx.SetContentType("text/html; charset=utf-8")
+	ctx.SetBody(buf.Bytes())
+
+	return true
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"github.com/valyala/fasthttp"
+)
+
+// handleMethodNotAllowed writes the 405 Method Not Allowed error while retaining the Allow header populated by the
+// router with the methods registered for the matched path, as writing the error may reset the response headers.
+func handleMethodNotAllowed(pages *errorPages) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		allow := string(ctx.Response.Header.Peek(fasthttp.HeaderAllow))
+
+		pages.Write(ctx, fasthttp.StatusMethodNotAllowed, "")
+
+		if allow != "" {
+			ctx.Response.Header.Set(fasthttp.HeaderAllow, allow)
+		}
+	}
+}
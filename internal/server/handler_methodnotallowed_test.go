@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/fasthttp/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldRetainAllowHeaderOnMethodNotAllowed(t *testing.T) {
+	r := router.New()
+
+	r.GET("/api/example", func(ctx *fasthttp.RequestCtx) {})
+	r.POST("/api/example", func(ctx *fasthttp.RequestCtx) {})
+
+	r.HandleMethodNotAllowed = true
+	r.MethodNotAllowed = handleMethodNotAllowed(newErrorPages(schema.ServerErrorPagesConfiguration{}))
+
+	testCases := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{"ShouldRetainWithPlainText", "", "text/plain; charset=utf-8"},
+		{"ShouldRetainWithJSON", "application/json", "application/json; charset=utf-8"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Request.Header.SetMethod(fasthttp.MethodDelete)
+			ctx.Request.SetRequestURI("/api/example")
+
+			if tc.accept != "" {
+				ctx.Request.Header.Set(fasthttp.HeaderAccept, tc.accept)
+			}
+
+			r.Handler(ctx)
+
+			assert.Equal(t, fasthttp.StatusMethodNotAllowed, ctx.Response.StatusCode())
+			assert.Equal(t, tc.contentType, string(ctx.Response.Header.ContentType()))
+			assert.Equal(t, "GET, OPTIONS, POST", string(ctx.Response.Header.Peek(fasthttp.HeaderAllow)))
+		})
+	}
+}
@@ -4,17 +4,15 @@ import (
 	"strings"
 
 	"github.com/valyala/fasthttp"
-
-	"github.com/authelia/authelia/v4/internal/handlers"
 )
 
-func handleNotFound(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+func handleNotFound(pages *errorPages, next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		path := strings.ToLower(string(ctx.Path()))
 
 		for i := 0; i < len(httpServerDirs); i++ {
 			if path == httpServerDirs[i].name || strings.HasPrefix(path, httpServerDirs[i].prefix) {
-				handlers.SetStatusCodeResponse(ctx, fasthttp.StatusNotFound)
+				pages.Write(ctx, fasthttp.StatusNotFound, "")
 
 				return
 			}
@@ -2,8 +2,10 @@ package server
 
 import (
 	"net"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	duoapi "github.com/duosecurity/duo_api_golang"
@@ -19,7 +21,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
-func registerRoutes(configuration schema.Configuration, providers middlewares.Providers) fasthttp.RequestHandler {
+func registerRoutes(configuration schema.Configuration, providers middlewares.Providers, pages *errorPages) fasthttp.RequestHandler {
 	autheliaMiddleware := middlewares.AutheliaMiddleware(configuration, providers)
 	rememberMe := strconv.FormatBool(configuration.Session.RememberMeDuration != schema.RememberMeDisabled)
 	resetPassword := strconv.FormatBool(!configuration.AuthenticationBackend.DisableResetPassword)
@@ -32,7 +34,7 @@ func registerRoutes(configuration schema.Configuration, providers middlewares.Pr
 	}
 
 	handlerPublicHTML := newPublicHTMLEmbeddedHandler()
-	handlerLocales := newLocalesEmbeddedHandler()
+	handlerLocales := newLocalesEmbeddedHandler(configuration.Server.AssetPath, configuration.Server.FallbackLocale)
 
 	https := configuration.Server.TLS.Key != "" && configuration.Server.TLS.Certificate != ""
 
@@ -62,7 +64,9 @@ func registerRoutes(configuration schema.Configuration, providers middlewares.Pr
 	r.GET("/locales/{language:[a-z]{1,3}}-{variant:[a-z0-9-]+}/{namespace:[a-z]+}.json", middlewares.AssetOverrideMiddleware(configuration.Server.AssetPath, 0, handlerLocales))
 	r.GET("/locales/{language:[a-z]{1,3}}/{namespace:[a-z]+}.json", middlewares.AssetOverrideMiddleware(configuration.Server.AssetPath, 0, handlerLocales))
 
-	r.GET("/api/health", autheliaMiddleware(handlers.HealthGet))
+	r.GET(configuration.Server.HealthCheck.Path, autheliaMiddleware(handlers.HealthGet))
+	r.HEAD(configuration.Server.HealthCheck.Path, autheliaMiddleware(handlers.HealthGet))
+	r.GET(strings.TrimSuffix(configuration.Server.HealthCheck.Path, "/")+"/ready", autheliaMiddleware(handlers.HealthReadyGet()))
 	r.GET("/api/state", autheliaMiddleware(handlers.StateGet))
 
 	r.GET("/api/configuration", autheliaMiddleware(
@@ -75,7 +79,7 @@ func registerRoutes(configuration schema.Configuration, providers middlewares.Pr
 
 	r.POST("/api/checks/safe-redirection", autheliaMiddleware(handlers.CheckSafeRedirection))
 
-	r.POST("/api/firstfactor", autheliaMiddleware(handlers.FirstFactorPost(middlewares.TimingAttackDelay(10, 250, 85, time.Second))))
+	r.POST("/api/firstfactor", autheliaMiddleware(handlers.FirstFactorPost(middlewares.TimingAttackDelay(10, 250, 85, time.Second), configuration.AuthenticationBackend.MaxConcurrentRequests)))
 	r.POST("/api/logout", autheliaMiddleware(handlers.LogoutPost))
 
 	// Only register endpoints if forgot password is not disabled.
@@ -98,6 +102,17 @@ func registerRoutes(configuration schema.Configuration, providers middlewares.Pr
 	r.POST("/api/user/info/2fa_method", autheliaMiddleware(
 		middlewares.RequireFirstFactor(handlers.MethodPreferencePost)))
 
+	if configuration.TrustedDevice.Lifespan > 0 {
+		r.POST("/api/user/info/trusted_devices/revoke", autheliaMiddleware(
+			middlewares.RequireFirstFactor(handlers.UserInfoTrustedDevicesRevokePOST)))
+	}
+
+	// Sessions of the user.
+	r.GET("/api/user/sessions", autheliaMiddleware(
+		middlewares.RequireFirstFactor(handlers.UserSessionsGET)))
+	r.DELETE("/api/user/sessions/{id}", autheliaMiddleware(
+		middlewares.RequireFirstFactor(handlers.UserSessionsDELETE)))
+
 	if !configuration.TOTP.Disable {
 		// TOTP related endpoints.
 		r.GET("/api/user/info/totp", autheliaMiddleware(
@@ -151,6 +166,29 @@ func registerRoutes(configuration schema.Configuration, providers middlewares.Pr
 			middlewares.RequireFirstFactor(handlers.SecondFactorDuoDevicePost)))
 	}
 
+	// The debug routes are only registered on the main router when a dedicated debug listener is not configured.
+	if configuration.Server.Debug.Address == "" {
+		registerDebugRoutes(r, configuration)
+	}
+
+	r.NotFound = handleNotFound(pages, autheliaMiddleware(serveIndexHandler))
+
+	r.HandleMethodNotAllowed = true
+	r.MethodNotAllowed = handleMethodNotAllowed(pages)
+
+	handler := middlewares.LogRequestMiddleware(configuration.Log.RedactedFields, r.Handler)
+	if configuration.Server.Path != "" {
+		handler = middlewares.StripPathMiddleware(configuration.Server.Path, handler)
+	}
+
+	if providers.OpenIDConnect.Fosite != nil {
+		handlers.RegisterOIDC(r, autheliaMiddleware)
+	}
+
+	return handler
+}
+
+func registerDebugRoutes(r *router.Router, configuration schema.Configuration) {
 	if configuration.Server.EnablePprof {
 		r.GET("/debug/pprof/{name?}", pprofhandler.PprofHandler)
 	}
@@ -158,34 +196,97 @@ func registerRoutes(configuration schema.Configuration, providers middlewares.Pr
 	if configuration.Server.EnableExpvars {
 		r.GET("/debug/vars", expvarhandler.ExpvarHandler)
 	}
+}
+
+// startDebugServer starts a separate webserver which only serves the pprof and expvars endpoints when a dedicated
+// debug listener address is configured.
+func startDebugServer(configuration schema.Configuration, pages *errorPages) {
+	if configuration.Server.Debug.Address == "" || (!configuration.Server.EnablePprof && !configuration.Server.EnableExpvars) {
+		return
+	}
 
-	r.NotFound = handleNotFound(autheliaMiddleware(serveIndexHandler))
+	logger := logging.Logger()
 
-	r.HandleMethodNotAllowed = true
-	r.MethodNotAllowed = func(ctx *fasthttp.RequestCtx) {
-		handlers.SetStatusCodeResponse(ctx, fasthttp.StatusMethodNotAllowed)
+	r := router.New()
+
+	registerDebugRoutes(r, configuration)
+
+	server := &fasthttp.Server{
+		ErrorHandler:          newAutheliaErrorHandler(pages, configuration.Log.RedactedFields),
+		Handler:               middlewares.LogRequestMiddleware(configuration.Log.RedactedFields, r.Handler),
+		NoDefaultServerHeader: true,
+		ReadBufferSize:        configuration.Server.ReadBufferSize,
+		WriteBufferSize:       configuration.Server.WriteBufferSize,
 	}
 
-	handler := middlewares.LogRequestMiddleware(r.Handler)
-	if configuration.Server.Path != "" {
-		handler = middlewares.StripPathMiddleware(configuration.Server.Path, handler)
+	listener, err := net.Listen("tcp", configuration.Server.Debug.Address)
+	if err != nil {
+		logger.Fatalf("Error initializing debug listener: %s", err)
 	}
 
-	if providers.OpenIDConnect.Fosite != nil {
-		handlers.RegisterOIDC(r, autheliaMiddleware)
+	logger.Infof("Listening for debug connections on '%s'", configuration.Server.Debug.Address)
+
+	go func() {
+		logger.Fatal(server.Serve(listener))
+	}()
+}
+
+// startTLSRedirectServer starts a separate webserver which redirects all plain HTTP requests to the TLS listener when a
+// redirect port is configured.
+func startTLSRedirectServer(configuration schema.Configuration, pages *errorPages) {
+	if configuration.Server.TLS.RedirectPort == 0 {
+		return
 	}
 
-	return handler
+	logger := logging.Logger()
+
+	server := &fasthttp.Server{
+		ErrorHandler:          newAutheliaErrorHandler(pages, configuration.Log.RedactedFields),
+		Handler:               middlewares.LogRequestMiddleware(configuration.Log.RedactedFields, newTLSRedirectHandler(configuration.Server.ExternalURL)),
+		NoDefaultServerHeader: true,
+		ReadBufferSize:        configuration.Server.ReadBufferSize,
+		WriteBufferSize:       configuration.Server.WriteBufferSize,
+	}
+
+	address := net.JoinHostPort(configuration.Server.Host, strconv.Itoa(configuration.Server.TLS.RedirectPort))
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		logger.Fatalf("Error initializing TLS redirect listener: %s", err)
+	}
+
+	logger.Infof("Listening for non-TLS connections to redirect to TLS on '%s'", address)
+
+	go func() {
+		logger.Fatal(server.Serve(listener))
+	}()
+}
+
+// newTLSRedirectHandler returns a handler which permanently redirects requests to the same path and query on the
+// external URL. The host of the external URL is used instead of the Host header of the request as the latter is
+// controlled by the client which would make the redirect an open redirect.
+func newTLSRedirectHandler(externalURL url.URL) fasthttp.RequestHandler {
+	location := "https://" + externalURL.Host
+
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.Header.Set(fasthttp.HeaderLocation, location+string(ctx.RequestURI()))
+		ctx.SetStatusCode(fasthttp.StatusMovedPermanently)
+	}
 }
 
 // Start Authelia's internal webserver with the given configuration and providers.
 func Start(configuration schema.Configuration, providers middlewares.Providers) {
 	logger := logging.Logger()
 
-	handler := registerRoutes(configuration, providers)
+	pages := newErrorPages(configuration.Server.ErrorPages)
+
+	handler := registerRoutes(configuration, providers, pages)
+
+	startDebugServer(configuration, pages)
 
 	server := &fasthttp.Server{
-		ErrorHandler:          autheliaErrorHandler,
+		ErrorHandler:          newTokenEndpointErrorHandler(configuration, providers.OpenIDConnect, newAutheliaErrorHandler(pages, configuration.Log.RedactedFields)),
+		HeaderReceived:        newTokenEndpointHeaderReceivedHandler(configuration),
 		Handler:               handler,
 		NoDefaultServerHeader: true,
 		ReadBufferSize:        configuration.Server.ReadBufferSize,
@@ -200,7 +301,7 @@ func Start(configuration schema.Configuration, providers middlewares.Providers)
 	}
 
 	if configuration.Server.TLS.Certificate != "" && configuration.Server.TLS.Key != "" {
-		if err = writeHealthCheckEnv(configuration.Server.DisableHealthcheck, "https", configuration.Server.Host, configuration.Server.Path, configuration.Server.Port); err != nil {
+		if err = writeHealthCheckEnv(configuration.Server.DisableHealthcheck, "https", configuration.Server.Host, configuration.Server.Path, configuration.Server.HealthCheck.Path, configuration.Server.Port); err != nil {
 			logger.Fatalf("Could not configure healthcheck: %v", err)
 		}
 
@@ -210,9 +311,11 @@ func Start(configuration schema.Configuration, providers middlewares.Providers)
 			logger.Infof("Listening for TLS connections on '%s' paths '/' and '%s'", address, configuration.Server.Path)
 		}
 
+		startTLSRedirectServer(configuration, pages)
+
 		logger.Fatal(server.ServeTLS(listener, configuration.Server.TLS.Certificate, configuration.Server.TLS.Key))
 	} else {
-		if err = writeHealthCheckEnv(configuration.Server.DisableHealthcheck, "http", configuration.Server.Host, configuration.Server.Path, configuration.Server.Port); err != nil {
+		if err = writeHealthCheckEnv(configuration.Server.DisableHealthcheck, "http", configuration.Server.Host, configuration.Server.Path, configuration.Server.HealthCheck.Path, configuration.Server.Port); err != nil {
 			logger.Fatalf("Could not configure healthcheck: %v", err)
 		}
 
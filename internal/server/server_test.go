@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestShouldRedirectToExternalURLRegardlessOfHost(t *testing.T) {
+	handler := newTLSRedirectHandler(url.URL{Scheme: "https", Host: "auth.example.com:8443", Path: "/authelia"})
+
+	testCases := []struct {
+		name     string
+		host     string
+		uri      string
+		expected string
+	}{
+		{"ShouldRedirectWithPathAndQuery", "auth.example.com", "/authelia/?rd=https%3A%2F%2Fapp.example.com", "https://auth.example.com:8443/authelia/?rd=https%3A%2F%2Fapp.example.com"},
+		{"ShouldIgnoreUntrustedHost", "evil.example.net", "/authelia/", "https://auth.example.com:8443/authelia/"},
+		{"ShouldIgnoreMissingHost", "", "/", "https://auth.example.com:8443/"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Request.SetRequestURI(tc.uri)
+			ctx.Request.Header.SetHost(tc.host)
+
+			handler(ctx)
+
+			assert.Equal(t, fasthttp.StatusMovedPermanently, ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(ctx.Response.Header.Peek(fasthttp.HeaderLocation)))
+		})
+	}
+}
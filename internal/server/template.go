@@ -91,7 +91,7 @@ func ServeTemplatedFile(publicDir, file, assetPath, duoSelfEnrollment, rememberM
 	}
 }
 
-func writeHealthCheckEnv(disabled bool, scheme, host, path string, port int) (err error) {
+func writeHealthCheckEnv(disabled bool, scheme, host, path, endpoint string, port int) (err error) {
 	if disabled {
 		return nil
 	}
@@ -121,7 +121,7 @@ func writeHealthCheckEnv(disabled bool, scheme, host, path string, port int) (er
 		host = "[" + host + "]"
 	}
 
-	_, err = file.WriteString(fmt.Sprintf(healthCheckEnv, scheme, host, port, path))
+	_, err = file.WriteString(fmt.Sprintf(healthCheckEnv, scheme, host, port, path, endpoint))
 
 	return err
 }
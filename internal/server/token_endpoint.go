@@ -0,0 +1,76 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+// newTokenEndpointHeaderReceivedHandler returns the function which configures the maximum body size of a request once
+// its headers have been received. The maximum body size of the token endpoint requests is applied while the body is
+// read so the body of a larger request is never buffered. It returns nil when the limit isn't configured.
+func newTokenEndpointHeaderReceivedHandler(configuration schema.Configuration) func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+	if configuration.IdentityProviders.OIDC == nil || configuration.IdentityProviders.OIDC.TokenEndpointMaximumBodySize == 0 {
+		return nil
+	}
+
+	size := configuration.IdentityProviders.OIDC.TokenEndpointMaximumBodySize
+
+	return func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		if !isTokenEndpointRequestURI(configuration.Server.Path, header.RequestURI()) {
+			return fasthttp.RequestConfig{}
+		}
+
+		return fasthttp.RequestConfig{MaxRequestBodySize: size}
+	}
+}
+
+// newTokenEndpointErrorHandler returns an error handler which rejects the token endpoint requests with a body larger
+// than the maximum size, and passes all other errors to the next error handler. As these requests never reach the token
+// endpoint handler they're counted against the token endpoint rate limit here.
+func newTokenEndpointErrorHandler(configuration schema.Configuration, provider oidc.OpenIDConnectProvider, next func(ctx *fasthttp.RequestCtx, err error)) func(ctx *fasthttp.RequestCtx, err error) {
+	if configuration.IdentityProviders.OIDC == nil || configuration.IdentityProviders.OIDC.TokenEndpointMaximumBodySize == 0 {
+		return next
+	}
+
+	size := configuration.IdentityProviders.OIDC.TokenEndpointMaximumBodySize
+
+	return func(ctx *fasthttp.RequestCtx, err error) {
+		if !errors.Is(err, fasthttp.ErrBodyTooLarge) || !isTokenEndpointRequestURI(configuration.Server.Path, ctx.Request.Header.RequestURI()) {
+			next(ctx, err)
+
+			return
+		}
+
+		remoteIP := provider.TokenEndpointRemoteIP(ctx.RemoteIP(), ctx.Request.Header.Peek(fasthttp.HeaderXForwardedFor))
+
+		if provider.TokenEndpointRateLimiter != nil {
+			provider.TokenEndpointRateLimiter.Allow(remoteIP.String(), time.Now())
+		}
+
+		logging.Logger().Errorf("Access Request from '%s' was rejected: the request body exceeds the maximum size of %d bytes", remoteIP, size)
+
+		body, _ := json.Marshal(oidc.ErrTokenEndpointRequestTooLarge)
+
+		ctx.Response.Header.Set(fasthttp.HeaderCacheControl, "no-store")
+		ctx.Response.Header.Set("Pragma", "no-cache")
+		ctx.SetContentType("application/json;charset=UTF-8")
+		ctx.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+		ctx.SetBody(body)
+	}
+}
+
+func isTokenEndpointRequestURI(path string, uri []byte) bool {
+	if i := bytes.IndexByte(uri, '?'); i != -1 {
+		uri = uri[:i]
+	}
+
+	return string(uri) == oidc.TokenPath || (path != "" && string(uri) == path+oidc.TokenPath)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func TestShouldRejectTokenEndpointRequestsLargerThanMaximumBodySize(t *testing.T) {
+	configuration := schema.Configuration{
+		Server: schema.ServerConfiguration{Path: "/authelia"},
+		IdentityProviders: schema.IdentityProvidersConfiguration{
+			OIDC: &schema.OpenIDConnectConfiguration{TokenEndpointMaximumBodySize: 64},
+		},
+	}
+
+	provider := oidc.OpenIDConnectProvider{TokenEndpointRateLimiter: oidc.NewKeyedRateLimiter(0.01, 2)}
+
+	nextErrors := 0
+
+	server := &fasthttp.Server{
+		ErrorHandler: newTokenEndpointErrorHandler(configuration, provider, func(ctx *fasthttp.RequestCtx, err error) {
+			nextErrors++
+
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		}),
+		HeaderReceived: newTokenEndpointHeaderReceivedHandler(configuration),
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			ctx.SetStatusCode(fasthttp.StatusOK)
+		},
+		MaxRequestBodySize: 128,
+	}
+
+	listener := fasthttputil.NewInmemoryListener()
+	defer listener.Close()
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	testCases := []struct {
+		name     string
+		uri      string
+		size     int
+		expected int
+	}{
+		{"ShouldAllowSmallBody", "/api/oidc/token", 64, fasthttp.StatusOK},
+		{"ShouldRejectLargeBody", "/api/oidc/token", 65, fasthttp.StatusRequestEntityTooLarge},
+		{"ShouldRejectLargeBodyWithPathAndQuery", "/authelia/api/oidc/token?a=b", 65, fasthttp.StatusRequestEntityTooLarge},
+		{"ShouldAllowLargeBodyOnOtherEndpoints", "/api/oidc/revocation", 128, fasthttp.StatusOK},
+		{"ShouldUseNextErrorHandlerForOtherEndpoints", "/api/oidc/revocation", 129, fasthttp.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, err := listener.Dial()
+			require.NoError(t, err)
+
+			defer conn.Close()
+
+			req := fasthttp.AcquireRequest()
+			defer fasthttp.ReleaseRequest(req)
+
+			req.Header.SetMethod(fasthttp.MethodPost)
+			req.SetRequestURI(tc.uri)
+			req.Header.SetHost("auth.example.com")
+			req.SetBodyString(strings.Repeat("a", tc.size))
+
+			require.NoError(t, conn.SetDeadline(time.Now().Add(time.Second*5)))
+
+			_, err = req.WriteTo(conn)
+			require.NoError(t, err)
+
+			resp := fasthttp.AcquireResponse()
+			defer fasthttp.ReleaseResponse(resp)
+
+			require.NoError(t, resp.Read(bufio.NewReader(conn)))
+
+			assert.Equal(t, tc.expected, resp.StatusCode())
+
+			if tc.expected == fasthttp.StatusRequestEntityTooLarge {
+				assert.Contains(t, string(resp.Body()), `"error":"invalid_request"`)
+			}
+		})
+	}
+
+	assert.Equal(t, 1, nextErrors)
+
+	// Both of the rejected token endpoint requests were counted against the rate limit of the remote IP.
+	allowed, _ := provider.TokenEndpointRateLimiter.Allow(net.IPv4zero.String(), time.Now())
+	assert.False(t, allowed)
+}
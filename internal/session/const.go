@@ -1,7 +1,10 @@
 package session
 
 import (
+	"errors"
 	"time"
+
+	"github.com/valyala/fasthttp"
 )
 
 const (
@@ -11,6 +14,19 @@ const (
 	testUsername   = "john"
 )
 
+var (
+	headerXForwardedHost  = []byte(fasthttp.HeaderXForwardedHost)
+	headerXForwardedProto = []byte(fasthttp.HeaderXForwardedProto)
+
+	prefixOIDCPath = []byte("/api/oidc/")
+	protoHTTPS     = []byte("https")
+)
+
+// keepAliveExpiration is the expiration the session holders use for sessions which expire when the browser is closed.
+const keepAliveExpiration = 2 * 24 * time.Hour
+
+var errSessionNotFound = errors.New("session not found")
+
 const (
 	userSessionStorerKey = "UserSession"
 	randomSessionChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
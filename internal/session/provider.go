@@ -1,8 +1,11 @@
 package session
 
 import (
+	"bytes"
 	"crypto/x509"
 	"encoding/json"
+	"net"
+	"strings"
 	"time"
 
 	fasthttpsession "github.com/fasthttp/session/v2"
@@ -16,21 +19,47 @@ import (
 
 // Provider a session provider.
 type Provider struct {
-	sessionHolder *fasthttpsession.Session
-	RememberMe    time.Duration
-	Inactivity    time.Duration
+	sessionHolder      *fasthttpsession.Session
+	sessionHolders     map[string]*fasthttpsession.Session
+	oidcSessionHolder  *fasthttpsession.Session
+	oidcSessionHolders map[string]*fasthttpsession.Session
+	oidcSameSite       fasthttp.CookieSameSite
+	store              fasthttpsession.Provider
+	config             fasthttpsession.Config
+	RememberMe         time.Duration
+	Inactivity         time.Duration
+	MaximumLifetime    time.Duration
 }
 
-// NewProvider instantiate a session provider given a configuration.
+// NewProvider instantiate a session provider given a configuration. A session holder is created for each of the
+// session domains so the cookie is set for the domain matching the request host, the first domain is the default.
+// If the SameSite value is overridden for the OpenID Connect flows another set of session holders is created for them.
 func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *Provider {
 	c := NewProviderConfig(config, certPool)
 
+	// The session holders default to these functions, they're set explicitly so sessions can be read and saved by ID.
+	if c.config.EncodeFunc == nil || c.config.DecodeFunc == nil {
+		c.config.EncodeFunc, c.config.DecodeFunc = fasthttpsession.Base64Encode, fasthttpsession.Base64Decode
+	}
+
 	provider := new(Provider)
+	provider.config = c.config
 	provider.sessionHolder = fasthttpsession.New(c.config)
+	provider.sessionHolders = newSessionHolders(c.config, provider.sessionHolder, config.Domains)
+
+	if c.oidcCookieSameSite != fasthttp.CookieSameSiteDisabled {
+		oidcConfig := c.config
+		oidcConfig.CookieSameSite = c.oidcCookieSameSite
+
+		provider.oidcSameSite = c.oidcCookieSameSite
+		provider.oidcSessionHolder = fasthttpsession.New(oidcConfig)
+		provider.oidcSessionHolders = newSessionHolders(oidcConfig, provider.oidcSessionHolder, config.Domains)
+	}
 
 	logger := logging.Logger()
 
 	provider.Inactivity, provider.RememberMe = config.Inactivity, config.RememberMeDuration
+	provider.MaximumLifetime = config.MaximumLifetime
 
 	var (
 		providerImpl fasthttpsession.Provider
@@ -38,6 +67,11 @@ func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *P
 	)
 
 	switch {
+	case config.Redis != nil && config.Redis.CredentialsFile != "":
+		providerImpl, err = NewRedisCredentialsProvider(c, config.Redis.CredentialsFile)
+		if err != nil {
+			logger.Fatal(err)
+		}
 	case c.redisConfig != nil:
 		providerImpl, err = redis.New(*c.redisConfig)
 		if err != nil {
@@ -55,17 +89,94 @@ func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *P
 		}
 	}
 
-	err = provider.sessionHolder.SetProvider(providerImpl)
-	if err != nil {
-		logger.Fatal(err)
+	provider.store = providerImpl
+
+	for _, holders := range []map[string]*fasthttpsession.Session{provider.sessionHolders, provider.oidcSessionHolders} {
+		for _, holder := range holders {
+			if err = holder.SetProvider(providerImpl); err != nil {
+				logger.Fatal(err)
+			}
+		}
 	}
 
 	return provider
 }
 
+func newSessionHolders(config fasthttpsession.Config, primary *fasthttpsession.Session, domains []string) map[string]*fasthttpsession.Session {
+	holders := map[string]*fasthttpsession.Session{config.Domain: primary}
+
+	for _, domain := range domains {
+		if _, ok := holders[domain]; ok {
+			continue
+		}
+
+		holderConfig := config
+		holderConfig.Domain = domain
+
+		holders[domain] = fasthttpsession.New(holderConfig)
+	}
+
+	return holders
+}
+
+// holder returns the session holder for the session domain which most specifically matches the request host, or the
+// default session holder if none match. The OpenID Connect session holders are used for requests to the OpenID Connect
+// endpoints when the SameSite value is overridden for them.
+func (p *Provider) holder(ctx *fasthttp.RequestCtx) *fasthttpsession.Session {
+	primary, holders := p.sessionHolder, p.sessionHolders
+
+	if p.isOIDCRequest(ctx) {
+		primary, holders = p.oidcSessionHolder, p.oidcSessionHolders
+	}
+
+	if len(holders) <= 1 {
+		return primary
+	}
+
+	host := ctx.Request.Header.PeekBytes(headerXForwardedHost)
+	if host == nil {
+		host = ctx.Host()
+	}
+
+	hostname := strings.ToLower(string(host))
+
+	if h, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = h
+	}
+
+	var (
+		holder = primary
+		match  string
+	)
+
+	for domain, h := range holders {
+		domain = strings.ToLower(domain)
+
+		if len(domain) > len(match) && (hostname == domain || strings.HasSuffix(hostname, "."+domain)) {
+			holder, match = h, domain
+		}
+	}
+
+	return holder
+}
+
+// isOIDCRequest returns true if the OpenID Connect session holders should be used for the request. Browsers reject
+// SameSite None cookies which are not sent over TLS so these requests must be using https for that override to apply.
+func (p *Provider) isOIDCRequest(ctx *fasthttp.RequestCtx) bool {
+	if p.oidcSessionHolder == nil || !bytes.HasPrefix(ctx.Path(), prefixOIDCPath) {
+		return false
+	}
+
+	if p.oidcSameSite != fasthttp.CookieSameSiteNoneMode {
+		return true
+	}
+
+	return ctx.IsTLS() || bytes.Equal(ctx.Request.Header.PeekBytes(headerXForwardedProto), protoHTTPS)
+}
+
 // GetSession return the user session from a request.
 func (p *Provider) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
-	store, err := p.sessionHolder.Get(ctx)
+	store, err := p.holder(ctx).Get(ctx)
 
 	if err != nil {
 		return NewDefaultUserSession(), err
@@ -95,7 +206,7 @@ func (p *Provider) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
 
 // SaveSession save the user session.
 func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession) error {
-	store, err := p.sessionHolder.Get(ctx)
+	store, err := p.holder(ctx).Get(ctx)
 
 	if err != nil {
 		return err
@@ -109,7 +220,7 @@ func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession
 
 	store.Set(userSessionStorerKey, userSessionJSON)
 
-	err = p.sessionHolder.Save(ctx, store)
+	err = p.holder(ctx).Save(ctx, store)
 
 	if err != nil {
 		return err
@@ -120,19 +231,118 @@ func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession
 
 // RegenerateSession regenerate a session ID.
 func (p *Provider) RegenerateSession(ctx *fasthttp.RequestCtx) error {
-	err := p.sessionHolder.Regenerate(ctx)
+	err := p.holder(ctx).Regenerate(ctx)
 
 	return err
 }
 
 // DestroySession destroy a session ID and delete the cookie.
 func (p *Provider) DestroySession(ctx *fasthttp.RequestCtx) error {
-	return p.sessionHolder.Destroy(ctx)
+	return p.holder(ctx).Destroy(ctx)
+}
+
+// GetSessionID returns the session ID of the request.
+func (p *Provider) GetSessionID(ctx *fasthttp.RequestCtx) ([]byte, error) {
+	store, err := p.holder(ctx).Get(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), store.GetSessionID()...), nil
+}
+
+// SessionExists returns true if a session with the given session ID exists in the session store.
+func (p *Provider) SessionExists(id []byte) (bool, error) {
+	data, err := p.store.Get(id)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(data) != 0, nil
+}
+
+// GetSessionByID returns the user session with the given session ID. Unlike GetSession this doesn't create the session
+// if it doesn't exist as the session usually belongs to another request.
+func (p *Provider) GetSessionByID(id []byte) (userSession UserSession, err error) {
+	store, err := p.storeByID(id)
+	if err != nil {
+		return NewDefaultUserSession(), err
+	}
+
+	userSessionJSON, ok := store.Get(userSessionStorerKey).([]byte)
+	if !ok {
+		return NewDefaultUserSession(), errSessionNotFound
+	}
+
+	if err = json.Unmarshal(userSessionJSON, &userSession); err != nil {
+		return NewDefaultUserSession(), err
+	}
+
+	return userSession, nil
+}
+
+// SaveSessionByID save the user session with the given session ID keeping its expiration. Unlike SaveSession this
+// doesn't set the cookie and only saves sessions which already exist as the session usually belongs to another request.
+func (p *Provider) SaveSessionByID(id []byte, userSession UserSession) error {
+	store, err := p.storeByID(id)
+	if err != nil {
+		return err
+	}
+
+	userSessionJSON, err := json.Marshal(userSession)
+	if err != nil {
+		return err
+	}
+
+	store.Set(userSessionStorerKey, userSessionJSON)
+
+	expiration := store.GetExpiration()
+
+	switch {
+	case expiration == 0:
+		expiration = p.config.Expiration
+	case expiration < 0:
+		expiration = keepAliveExpiration
+	}
+
+	data, err := p.config.EncodeFunc(store.GetAll())
+	if err != nil {
+		return err
+	}
+
+	return p.store.Save(id, data, expiration)
+}
+
+func (p *Provider) storeByID(id []byte) (store *fasthttpsession.Store, err error) {
+	data, err := p.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, errSessionNotFound
+	}
+
+	store = fasthttpsession.NewStore()
+
+	if err = p.config.DecodeFunc(store.Ptr(), data); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// DestroySessionByID destroy the session with the given session ID, unlike DestroySession this doesn't delete the
+// cookie as the session usually belongs to another request.
+func (p *Provider) DestroySessionByID(id []byte) error {
+	return p.store.Destroy(id)
 }
 
 // UpdateExpiration update the expiration of the cookie and session.
 func (p *Provider) UpdateExpiration(ctx *fasthttp.RequestCtx, expiration time.Duration) error {
-	store, err := p.sessionHolder.Get(ctx)
+	store, err := p.holder(ctx).Get(ctx)
 
 	if err != nil {
 		return err
@@ -144,12 +354,12 @@ func (p *Provider) UpdateExpiration(ctx *fasthttp.RequestCtx, expiration time.Du
 		return err
 	}
 
-	return p.sessionHolder.Save(ctx, store)
+	return p.holder(ctx).Save(ctx, store)
 }
 
 // GetExpiration get the expiration of the current session.
 func (p *Provider) GetExpiration(ctx *fasthttp.RequestCtx) (time.Duration, error) {
-	store, err := p.sessionHolder.Get(ctx)
+	store, err := p.holder(ctx).Get(ctx)
 
 	if err != nil {
 		return time.Duration(0), err
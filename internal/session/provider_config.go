@@ -39,15 +39,12 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 	c.Domain = config.Domain
 
 	// Set the cookie SameSite option.
-	switch config.SameSite {
-	case "strict":
-		c.CookieSameSite = fasthttp.CookieSameSiteStrictMode
-	case "none":
-		c.CookieSameSite = fasthttp.CookieSameSiteNoneMode
-	case "lax":
-		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
-	default:
-		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
+	c.CookieSameSite = cookieSameSite(config.SameSite)
+
+	// Set the cookie SameSite option used by the OpenID Connect endpoints if it's overridden.
+	oidcCookieSameSite := fasthttp.CookieSameSiteDisabled
+	if config.SameSiteOIDC != "" {
+		oidcCookieSameSite = cookieSameSite(config.SameSiteOIDC)
 	}
 
 	// Only serve the header over HTTPS.
@@ -145,8 +142,20 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 
 	return ProviderConfig{
 		c,
+		oidcCookieSameSite,
 		redisConfig,
 		redisSentinelConfig,
 		providerName,
 	}
 }
+
+func cookieSameSite(value string) fasthttp.CookieSameSite {
+	switch value {
+	case "strict":
+		return fasthttp.CookieSameSiteStrictMode
+	case "none":
+		return fasthttp.CookieSameSiteNoneMode
+	default:
+		return fasthttp.CookieSameSiteLaxMode
+	}
+}
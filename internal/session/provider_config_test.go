@@ -207,6 +207,29 @@ func TestShouldSetCookieSameSite(t *testing.T) {
 	}
 }
 
+func TestShouldSetOIDCCookieSameSite(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.SameSite = "strict"
+
+	configValueExpectedValue := map[string]fasthttp.CookieSameSite{
+		"":       fasthttp.CookieSameSiteDisabled,
+		"lax":    fasthttp.CookieSameSiteLaxMode,
+		"strict": fasthttp.CookieSameSiteStrictMode,
+		"none":   fasthttp.CookieSameSiteNoneMode,
+	}
+
+	for configValue, expectedValue := range configValueExpectedValue {
+		configuration.SameSiteOIDC = configValue
+		providerConfig := NewProviderConfig(configuration, nil)
+
+		assert.Equal(t, fasthttp.CookieSameSiteStrictMode, providerConfig.config.CookieSameSite)
+		assert.Equal(t, expectedValue, providerConfig.oidcCookieSameSite)
+	}
+}
+
 func TestShouldCreateRedisSessionProviderWithUnixSocket(t *testing.T) {
 	configuration := schema.SessionConfiguration{}
 	configuration.Domain = testDomain
@@ -54,6 +54,99 @@ func TestShouldUpdateSession(t *testing.T) {
 	}, session)
 }
 
+func TestShouldSetCookieForTheMatchingSessionDomain(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Domains = []string{testDomain, "example.org", "auth.example.org"}
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil)
+
+	testCases := []struct {
+		host, forwarded, expected string
+	}{
+		{"auth.example.com", "", testDomain},
+		{"auth.example.org:9091", "", "auth.example.org"},
+		{"login.example.org", "", "example.org"},
+		{"auth.example.com", "app.example.org", "example.org"},
+		{"example.net", "", testDomain},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host+tc.forwarded, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetHost(tc.host)
+
+			if tc.forwarded != "" {
+				ctx.Request.Header.Set(fasthttp.HeaderXForwardedHost, tc.forwarded)
+			}
+
+			session, err := provider.GetSession(ctx)
+			require.NoError(t, err)
+
+			session.Username = testUsername
+
+			require.NoError(t, provider.SaveSession(ctx, session))
+
+			cookie := fasthttp.AcquireCookie()
+			defer fasthttp.ReleaseCookie(cookie)
+
+			cookie.SetKey(testName)
+
+			require.True(t, ctx.Response.Header.Cookie(cookie))
+			assert.Equal(t, tc.expected, string(cookie.Domain()))
+		})
+	}
+}
+
+func TestShouldSetCookieSameSiteForTheRequestContext(t *testing.T) {
+	testCases := []struct {
+		name, sameSiteOIDC, path, proto string
+		expected                        fasthttp.CookieSameSite
+	}{
+		{"Portal", "none", "/api/state", "https", fasthttp.CookieSameSiteStrictMode},
+		{"OIDC", "lax", "/api/oidc/authorization", "", fasthttp.CookieSameSiteLaxMode},
+		{"OIDCNotOverridden", "", "/api/oidc/authorization", "https", fasthttp.CookieSameSiteStrictMode},
+		{"OIDCNoneOverTLS", "none", "/api/oidc/authorization", "https", fasthttp.CookieSameSiteNoneMode},
+		{"OIDCNoneWithoutTLS", "none", "/api/oidc/authorization", "http", fasthttp.CookieSameSiteStrictMode},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configuration := schema.SessionConfiguration{}
+			configuration.Domain = testDomain
+			configuration.Name = testName
+			configuration.Expiration = testExpiration
+			configuration.SameSite = "strict"
+			configuration.SameSiteOIDC = tc.sameSiteOIDC
+
+			provider := NewProvider(configuration, nil)
+
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetHost("auth." + testDomain)
+			ctx.Request.SetRequestURI(tc.path)
+
+			if tc.proto != "" {
+				ctx.Request.Header.Set(fasthttp.HeaderXForwardedProto, tc.proto)
+			}
+
+			session, err := provider.GetSession(ctx)
+			require.NoError(t, err)
+
+			require.NoError(t, provider.SaveSession(ctx, session))
+
+			cookie := fasthttp.AcquireCookie()
+			defer fasthttp.ReleaseCookie(cookie)
+
+			cookie.SetKey(testName)
+
+			require.True(t, ctx.Response.Header.Cookie(cookie))
+			assert.Equal(t, tc.expected, cookie.SameSite())
+		})
+	}
+}
+
 func TestShouldSetSessionAuthenticationLevels(t *testing.T) {
 	ctx := &fasthttp.RequestCtx{}
 	configuration := schema.SessionConfiguration{}
@@ -320,3 +413,106 @@ func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	assert.Equal(t, "", newUserSession.Username)
 	assert.Equal(t, authentication.NotAuthenticated, newUserSession.AuthenticationLevel)
 }
+
+func TestShouldDestroySessionByID(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil)
+	session, err := provider.GetSession(ctx)
+	require.NoError(t, err)
+
+	session.Username = testUsername
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	id, err := provider.GetSessionID(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	exists, err := provider.SessionExists(id)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, provider.RegenerateSession(ctx))
+
+	newID, err := provider.GetSessionID(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, newID)
+
+	exists, err = provider.SessionExists(id)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, provider.DestroySessionByID(newID))
+
+	exists, err = provider.SessionExists(newID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestShouldGetAndSaveSessionByID(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil)
+	session, err := provider.GetSession(ctx)
+	require.NoError(t, err)
+
+	session.Username = testUsername
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	id, err := provider.GetSessionID(ctx)
+	require.NoError(t, err)
+
+	session, err = provider.GetSessionByID(id)
+	require.NoError(t, err)
+	assert.Equal(t, testUsername, session.Username)
+
+	session.LastActivity = 1000
+
+	require.NoError(t, provider.SaveSessionByID(id, session))
+
+	session, err = provider.GetSession(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testUsername, session.Username)
+	assert.Equal(t, int64(1000), session.LastActivity)
+
+	require.NoError(t, provider.DestroySessionByID(id))
+
+	_, err = provider.GetSessionByID(id)
+	assert.EqualError(t, err, "session not found")
+
+	assert.EqualError(t, provider.SaveSessionByID(id, session), "session not found")
+
+	exists, err := provider.SessionExists(id)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestShouldDetermineIfSessionExceededMaximumLifetime(t *testing.T) {
+	now := time.Now()
+
+	userSession := NewDefaultUserSession()
+
+	// Anonymous sessions never expire due to the maximum lifetime.
+	assert.False(t, userSession.HasExceededMaximumLifetime(now, time.Hour))
+
+	userSession.SetOneFactor(now.Add(-2*time.Hour), &authentication.UserDetails{Username: testUsername}, true)
+
+	assert.False(t, userSession.HasExceededMaximumLifetime(now, 0))
+	assert.False(t, userSession.HasExceededMaximumLifetime(now, time.Hour*3))
+	assert.True(t, userSession.HasExceededMaximumLifetime(now, time.Hour))
+
+	// Sessions without a known authentication time are always considered expired.
+	userSession.FirstFactorAuthnTimestamp = 0
+
+	assert.True(t, userSession.HasExceededMaximumLifetime(now, time.Hour*3))
+}
@@ -0,0 +1,279 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// NewRedisCredentialsProvider creates a redis session provider which loads the redis credentials from a file and
+// reloads them when the file changes or the process receives a SIGHUP.
+func NewRedisCredentialsProvider(config ProviderConfig, path string) (provider *RedisCredentialsProvider, err error) {
+	provider = &RedisCredentialsProvider{
+		path: path,
+		log:  logging.Logger(),
+	}
+
+	switch {
+	case config.redisConfig != nil:
+		redis.SetLogger(config.redisConfig.Logger)
+
+		provider.keyPrefix = config.redisConfig.KeyPrefix
+		provider.newClient = func(credentials redisCredentials) redis.UniversalClient {
+			return redis.NewClient(&redis.Options{
+				Network:      config.redisConfig.Network,
+				Addr:         config.redisConfig.Addr,
+				Username:     credentials.Username,
+				Password:     credentials.Password,
+				DB:           config.redisConfig.DB,
+				PoolSize:     config.redisConfig.PoolSize,
+				MinIdleConns: config.redisConfig.MinIdleConns,
+				IdleTimeout:  config.redisConfig.IdleTimeout,
+				TLSConfig:    config.redisConfig.TLSConfig,
+			})
+		}
+	case config.redisSentinelConfig != nil:
+		redis.SetLogger(config.redisSentinelConfig.Logger)
+
+		provider.keyPrefix = config.redisSentinelConfig.KeyPrefix
+		provider.newClient = func(credentials redisCredentials) redis.UniversalClient {
+			return redis.NewFailoverClusterClient(&redis.FailoverOptions{
+				MasterName:       config.redisSentinelConfig.MasterName,
+				SentinelAddrs:    config.redisSentinelConfig.SentinelAddrs,
+				SentinelUsername: config.redisSentinelConfig.SentinelUsername,
+				SentinelPassword: config.redisSentinelConfig.SentinelPassword,
+				RouteByLatency:   config.redisSentinelConfig.RouteByLatency,
+				RouteRandomly:    config.redisSentinelConfig.RouteRandomly,
+				Username:         credentials.Username,
+				Password:         credentials.Password,
+				DB:               config.redisSentinelConfig.DB,
+				PoolSize:         config.redisSentinelConfig.PoolSize,
+				MinIdleConns:     config.redisSentinelConfig.MinIdleConns,
+				IdleTimeout:      config.redisSentinelConfig.IdleTimeout,
+				TLSConfig:        config.redisSentinelConfig.TLSConfig,
+			})
+		}
+	default:
+		return nil, errors.New("the redis credentials file can only be used with the redis provider")
+	}
+
+	if err = provider.Reload(); err != nil {
+		return nil, err
+	}
+
+	go provider.watch()
+
+	return provider, nil
+}
+
+// RedisCredentialsProvider is a redis session provider which allows the redis credentials to be rotated without a
+// restart. The sessions are stored with the same keys as the fasthttp/session redis provider.
+type RedisCredentialsProvider struct {
+	mu     sync.RWMutex
+	reload sync.Mutex
+
+	db          redis.UniversalClient
+	credentials redisCredentials
+	modTime     time.Time
+
+	keyPrefix string
+	path      string
+	newClient func(credentials redisCredentials) redis.UniversalClient
+
+	log *logrus.Logger
+}
+
+type redisCredentials struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+func loadRedisCredentials(path string) (credentials redisCredentials, modTime time.Time, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return credentials, modTime, fmt.Errorf("failed to read the redis credentials file: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return credentials, modTime, fmt.Errorf("failed to read the redis credentials file: %w", err)
+	}
+
+	if err = yaml.Unmarshal(data, &credentials); err != nil {
+		return credentials, modTime, fmt.Errorf("failed to parse the redis credentials file: %w", err)
+	}
+
+	return credentials, info.ModTime(), nil
+}
+
+// Reload loads the credentials file and if the credentials have changed connects a new client with them. The previous
+// client is only closed once no session operations are using it, if the new client fails to connect it's discarded and
+// the previous client continues to be used.
+func (p *RedisCredentialsProvider) Reload() (err error) {
+	p.reload.Lock()
+	defer p.reload.Unlock()
+
+	credentials, modTime, err := loadRedisCredentials(p.path)
+	if err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	unchanged := p.db != nil && credentials == p.credentials
+	p.mu.RUnlock()
+
+	if unchanged {
+		p.mu.Lock()
+		p.modTime = modTime
+		p.mu.Unlock()
+
+		return nil
+	}
+
+	db := p.newClient(credentials)
+
+	if err = db.Ping(context.Background()).Err(); err != nil {
+		_ = db.Close()
+
+		return fmt.Errorf("failed to connect to redis with the credentials from the redis credentials file: %w", err)
+	}
+
+	p.mu.Lock()
+	previous := p.db
+	p.db, p.credentials, p.modTime = db, credentials, modTime
+	p.mu.Unlock()
+
+	if previous != nil {
+		p.log.Info("Reloaded the redis session provider credentials")
+
+		return previous.Close()
+	}
+
+	return nil
+}
+
+func (p *RedisCredentialsProvider) watch() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hangup:
+		case <-ticker.C:
+			if !p.changed() {
+				continue
+			}
+		}
+
+		if err := p.Reload(); err != nil {
+			p.log.WithError(err).Error("Failed to reload the redis session provider credentials")
+		}
+	}
+}
+
+func (p *RedisCredentialsProvider) changed() bool {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return !info.ModTime().Equal(p.modTime)
+}
+
+func (p *RedisCredentialsProvider) key(id []byte) string {
+	return p.keyPrefix + ":" + string(id)
+}
+
+// Get returns the data of the given session id.
+func (p *RedisCredentialsProvider) Get(id []byte) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	reply, err := p.db.Get(context.Background(), p.key(id)).Bytes()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Save saves the session data and expiration from the given session id.
+func (p *RedisCredentialsProvider) Save(id, data []byte, expiration time.Duration) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.db.Set(context.Background(), p.key(id), data, expiration).Err()
+}
+
+// Regenerate updates the session id and expiration with the new session id of the given current session id.
+func (p *RedisCredentialsProvider) Regenerate(id, newID []byte, expiration time.Duration) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	key, newKey := p.key(id), p.key(newID)
+
+	exists, err := p.db.Exists(context.Background(), key).Result()
+	if err != nil {
+		return err
+	}
+
+	if exists > 0 {
+		if err = p.db.Rename(context.Background(), key, newKey).Err(); err != nil {
+			return err
+		}
+
+		if err = p.db.Expire(context.Background(), newKey, expiration).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Destroy destroys the session from the given id.
+func (p *RedisCredentialsProvider) Destroy(id []byte) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.db.Del(context.Background(), p.key(id)).Err()
+}
+
+// Count returns the total of stored sessions.
+func (p *RedisCredentialsProvider) Count() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	reply, err := p.db.Keys(context.Background(), p.key([]byte("*"))).Result()
+	if err != nil {
+		return 0
+	}
+
+	return len(reply)
+}
+
+// NeedGC indicates if the GC needs to be run.
+func (p *RedisCredentialsProvider) NeedGC() bool {
+	return false
+}
+
+// GC destroys the expired sessions.
+func (p *RedisCredentialsProvider) GC() error {
+	return nil
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+func TestShouldLoadRedisCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte("username: authelia\npassword: secret\n"), 0600))
+
+	credentials, modTime, err := loadRedisCredentials(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, redisCredentials{Username: "authelia", Password: "secret"}, credentials)
+	assert.False(t, modTime.IsZero())
+
+	_, _, err = loadRedisCredentials(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestShouldNotReplaceRedisClientWhenCredentialsUnchangedOrInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte("username: authelia\npassword: secret\n"), 0600))
+
+	db := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+	defer db.Close()
+
+	calls := 0
+
+	provider := &RedisCredentialsProvider{
+		db:          db,
+		credentials: redisCredentials{Username: "authelia", Password: "secret"},
+		path:        path,
+		log:         logging.Logger(),
+		newClient: func(credentials redisCredentials) redis.UniversalClient {
+			calls++
+
+			return redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", Username: credentials.Username, Password: credentials.Password, MaxRetries: -1})
+		},
+	}
+
+	require.NoError(t, provider.Reload())
+	assert.Equal(t, 0, calls)
+	assert.False(t, provider.changed())
+
+	require.NoError(t, os.WriteFile(path, []byte("username: authelia\npassword: rotated\n"), 0600))
+
+	err := provider.Reload()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to redis with the credentials from the redis credentials file: ")
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, db, provider.db)
+	assert.Equal(t, "secret", provider.credentials.Password)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/fasthttp/session/v2/providers/redis"
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/logging"
@@ -18,6 +19,7 @@ import (
 // ProviderConfig is the configuration used to create the session provider.
 type ProviderConfig struct {
 	config              session.Config
+	oidcCookieSameSite  fasthttp.CookieSameSite
 	redisConfig         *redis.Config
 	redisSentinelConfig *redis.FailoverConfig
 	providerName        string
@@ -35,6 +37,9 @@ type UserSession struct {
 	AuthenticationLevel authentication.Level
 	LastActivity        int64
 
+	// KeepMeLoggedInPending is true when the user asked to be remembered but remember me requires the second factor.
+	KeepMeLoggedInPending bool
+
 	FirstFactorAuthnTimestamp  int64
 	SecondFactorAuthnTimestamp int64
 
@@ -51,6 +56,11 @@ type UserSession struct {
 	PasswordResetUsername *string
 
 	RefreshTTL time.Time
+
+	// SessionRecordID is the public ID of the record of this session in the storage provider which is used to list and
+	// revoke the sessions of a user. SessionRecordLastSeen is the last time that record was updated.
+	SessionRecordID       string
+	SessionRecordLastSeen int64
 }
 
 // Identity identity of the user who is being verified.
@@ -60,6 +60,26 @@ func (s *UserSession) SetTwoFactorWebauthn(now time.Time, userPresence, userVeri
 	s.Webauthn = nil
 }
 
+// SetTwoFactorTrustedDevice sets the factor to 2FA as the user authenticated from a device which completed the second
+// factor recently. No second factor AMR's are set as no second factor was used during this authentication.
+func (s *UserSession) SetTwoFactorTrustedDevice(now time.Time) {
+	s.setTwoFactor(now)
+}
+
+// HasExceededMaximumLifetime returns true if the user authenticated longer ago than the maximum lifetime. Sessions
+// belonging to a user without a first factor timestamp are considered expired as their age cannot be determined.
+func (s UserSession) HasExceededMaximumLifetime(now time.Time, maximumLifetime time.Duration) bool {
+	if maximumLifetime <= 0 || s.Username == "" {
+		return false
+	}
+
+	if s.FirstFactorAuthnTimestamp == 0 {
+		return true
+	}
+
+	return now.Sub(time.Unix(s.FirstFactorAuthnTimestamp, 0)) > maximumLifetime
+}
+
 // AuthenticatedTime returns the unix timestamp this session authenticated successfully at the given level.
 func (s UserSession) AuthenticatedTime(level authorization.Level) (authenticatedTime time.Time, err error) {
 	switch level {
@@ -71,3 +91,31 @@ func (s UserSession) AuthenticatedTime(level authorization.Level) (authenticated
 		return time.Unix(0, 0), errors.New("invalid authorization level")
 	}
 }
+
+// HasSecondFactorMethod returns true if the user authenticated with one of the given second factor methods. An empty
+// list of methods accepts any second factor. A trusted device doesn't satisfy a restricted list as no second factor
+// method was used during the authentication.
+func (s UserSession) HasSecondFactorMethod(methods []string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+
+	for _, method := range methods {
+		switch method {
+		case authorization.SecondFactorMethodTOTP:
+			if s.AuthenticationMethodRefs.TOTP {
+				return true
+			}
+		case authorization.SecondFactorMethodWebauthn:
+			if s.AuthenticationMethodRefs.Webauthn {
+				return true
+			}
+		case authorization.SecondFactorMethodDuo:
+			if s.AuthenticationMethodRefs.Duo {
+				return true
+			}
+		}
+	}
+
+	return false
+}
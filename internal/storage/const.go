@@ -7,6 +7,9 @@ import (
 const (
 	tableUserPreferences      = "user_preferences"
 	tableIdentityVerification = "identity_verification"
+	tableTrustedDevices       = "trusted_devices"
+	tableNotificationQueue    = "notification_queue"
+	tableUserSessions         = "user_sessions"
 	tableTOTPConfigurations   = "totp_configurations"
 	tableWebauthnDevices      = "webauthn_devices"
 	tableDuoDevices           = "duo_devices"
@@ -56,7 +59,7 @@ const (
 
 const (
 	// This is the latest schema version for the purpose of tests.
-	testLatestVersion = 3
+	testLatestVersion = 8
 )
 
 const (
@@ -14,6 +14,9 @@ var (
 	// ErrNoWebauthnDevice error thrown when no Webauthn device handle has been found in DB.
 	ErrNoWebauthnDevice = errors.New("no Webauthn device found")
 
+	// ErrNoTrustedDevice error thrown when no trusted device has been found in DB.
+	ErrNoTrustedDevice = errors.New("no trusted device found")
+
 	// ErrNoDuoDevice error thrown when no Duo device and method has been found in DB.
 	ErrNoDuoDevice = errors.New("no Duo device and method saved")
 
@@ -29,6 +32,10 @@ var (
 	// ErrNoMigrationsFound is returned when no migrations were found.
 	ErrNoMigrationsFound = errors.New("no schema migrations found")
 
+	// ErrSchemaMigrateDryRun is returned when schema migrations are available but they were only logged because the
+	// dry run mode is enabled.
+	ErrSchemaMigrateDryRun = errors.New("schema migrations were not applied because the dry run mode is enabled")
+
 	// ErrSchemaEncryptionVersionUnsupported is returned when the schema is checked if the encryption key is valid for
 	// the database but the schema doesn't support encryption.
 	ErrSchemaEncryptionVersionUnsupported = errors.New("schema version doesn't support encryption")
@@ -57,5 +64,6 @@ const (
 const (
 	logFmtMigrationFromTo   = "Storage schema migration from %s to %s is being attempted"
 	logFmtMigrationComplete = "Storage schema migration from %s to %s is complete"
+	logFmtMigrationDryRun   = "Storage schema migration %d (%s) from version %d to %d would execute the following SQL:\n%s"
 	logFmtErrClosingConn    = "Error occurred closing SQL connection: %v"
 )
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS trusted_devices (
+    id SERIAL,
+    jti CHAR(36),
+    iat TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    issued_ip VARCHAR(39) NOT NULL,
+    exp TIMESTAMP WITH TIME ZONE NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    revoked TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (jti)
+);
+
+CREATE INDEX trusted_devices_username_idx ON trusted_devices (username);
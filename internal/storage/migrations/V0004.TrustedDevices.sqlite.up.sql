@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS trusted_devices (
+    id INTEGER,
+    jti VARCHAR(36),
+    iat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    issued_ip VARCHAR(39) NOT NULL,
+    exp TIMESTAMP NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    revoked TIMESTAMP NULL DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (jti)
+);
+
+CREATE INDEX trusted_devices_username_idx ON trusted_devices (username);
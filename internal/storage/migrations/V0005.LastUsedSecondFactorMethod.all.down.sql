@@ -0,0 +1 @@
+ALTER TABLE user_preferences DROP COLUMN last_used_second_factor_method;
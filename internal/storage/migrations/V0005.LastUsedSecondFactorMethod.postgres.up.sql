@@ -0,0 +1 @@
+ALTER TABLE user_preferences ADD COLUMN last_used_second_factor_method VARCHAR(11) NULL DEFAULT NULL;
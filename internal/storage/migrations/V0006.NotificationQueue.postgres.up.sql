@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id SERIAL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body BYTEA NOT NULL,
+    html_body BYTEA NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX notification_queue_expires_at_idx ON notification_queue (expires_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body BLOB NOT NULL,
+    html_body BLOB NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX notification_queue_expires_at_idx ON notification_queue (expires_at);
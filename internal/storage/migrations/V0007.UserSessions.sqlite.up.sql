@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id INTEGER,
+    public_id VARCHAR(36) NOT NULL,
+    session_id BLOB NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ip VARCHAR(39) NOT NULL,
+    user_agent VARCHAR(512) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (public_id)
+);
+
+CREATE INDEX user_sessions_username_idx ON user_sessions (username);
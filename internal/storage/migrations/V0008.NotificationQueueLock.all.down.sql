@@ -0,0 +1 @@
+ALTER TABLE notification_queue DROP COLUMN locked_until;
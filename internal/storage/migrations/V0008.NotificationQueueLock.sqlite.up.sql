@@ -0,0 +1 @@
+ALTER TABLE notification_queue ADD COLUMN locked_until TIMESTAMP NULL DEFAULT NULL;
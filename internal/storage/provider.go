@@ -15,12 +15,30 @@ type Provider interface {
 
 	SavePreferred2FAMethod(ctx context.Context, username string, method string) (err error)
 	LoadPreferred2FAMethod(ctx context.Context, username string) (method string, err error)
+	SaveLastUsed2FAMethod(ctx context.Context, username string, method string) (err error)
 	LoadUserInfo(ctx context.Context, username string) (info model.UserInfo, err error)
 
 	SaveIdentityVerification(ctx context.Context, verification model.IdentityVerification) (err error)
 	ConsumeIdentityVerification(ctx context.Context, jti string, ip model.NullIP) (err error)
 	FindIdentityVerification(ctx context.Context, jti string) (found bool, err error)
 
+	SaveTrustedDevice(ctx context.Context, device model.TrustedDevice) (err error)
+	LoadTrustedDevice(ctx context.Context, jti string) (device *model.TrustedDevice, err error)
+	RevokeTrustedDevices(ctx context.Context, username string) (err error)
+
+	SaveQueuedNotification(ctx context.Context, notification model.QueuedNotification) (err error)
+	LoadQueuedNotifications(ctx context.Context, now time.Time, limit, page int) (notifications []model.QueuedNotification, err error)
+	LockQueuedNotification(ctx context.Context, id int, now, until time.Time) (locked bool, err error)
+	UnlockQueuedNotification(ctx context.Context, id int) (err error)
+	DeleteQueuedNotification(ctx context.Context, id int) (err error)
+	DeleteExpiredQueuedNotifications(ctx context.Context, now time.Time) (err error)
+
+	SaveUserSessionRecord(ctx context.Context, record model.UserSessionRecord) (err error)
+	LoadUserSessionRecords(ctx context.Context, username string) (records []model.UserSessionRecord, err error)
+	UpdateUserSessionRecordSessionID(ctx context.Context, publicID string, sessionID []byte) (err error)
+	UpdateUserSessionRecordLastSeen(ctx context.Context, publicID string, lastSeenAt time.Time) (err error)
+	DeleteUserSessionRecord(ctx context.Context, publicID string) (err error)
+
 	SaveTOTPConfiguration(ctx context.Context, config model.TOTPConfiguration) (err error)
 	UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time) (err error)
 	DeleteTOTPConfiguration(ctx context.Context, username string) (err error)
@@ -48,6 +66,8 @@ type Provider interface {
 	SchemaEncryptionChangeKey(ctx context.Context, encryptionKey string) (err error)
 	SchemaEncryptionCheckKey(ctx context.Context, verbose bool) (err error)
 
+	Ping(ctx context.Context) (err error)
+
 	Close() (err error)
 }
 
@@ -36,6 +36,25 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlConsumeIdentityVerification: fmt.Sprintf(queryFmtConsumeIdentityVerification, tableIdentityVerification),
 		sqlSelectIdentityVerification:  fmt.Sprintf(queryFmtSelectIdentityVerification, tableIdentityVerification),
 
+		sqlInsertTrustedDevice:  fmt.Sprintf(queryFmtInsertTrustedDevice, tableTrustedDevices),
+		sqlSelectTrustedDevice:  fmt.Sprintf(queryFmtSelectTrustedDevice, tableTrustedDevices),
+		sqlRevokeTrustedDevices: fmt.Sprintf(queryFmtRevokeTrustedDevices, tableTrustedDevices),
+
+		sqlInsertQueuedNotification:         fmt.Sprintf(queryFmtInsertQueuedNotification, tableNotificationQueue),
+		sqlSelectQueuedNotifications:        fmt.Sprintf(queryFmtSelectQueuedNotifications, tableNotificationQueue),
+		sqlUpdateQueuedNotificationBodies:   fmt.Sprintf(queryFmtUpdateQueuedNotificationBodies, tableNotificationQueue),
+		sqlUpdateQueuedNotificationLock:     fmt.Sprintf(queryFmtUpdateQueuedNotificationLock, tableNotificationQueue),
+		sqlUpdateQueuedNotificationUnlock:   fmt.Sprintf(queryFmtUpdateQueuedNotificationUnlock, tableNotificationQueue),
+		sqlDeleteQueuedNotification:         fmt.Sprintf(queryFmtDeleteQueuedNotification, tableNotificationQueue),
+		sqlDeleteExpiredQueuedNotifications: fmt.Sprintf(queryFmtDeleteExpiredQueuedNotifications, tableNotificationQueue),
+
+		sqlInsertUserSessionRecord:            fmt.Sprintf(queryFmtInsertUserSessionRecord, tableUserSessions),
+		sqlSelectUserSessionRecordsByUsername: fmt.Sprintf(queryFmtSelectUserSessionRecordsByUsername, tableUserSessions),
+		sqlSelectUserSessionRecords:           fmt.Sprintf(queryFmtSelectUserSessionRecords, tableUserSessions),
+		sqlUpdateUserSessionRecordSessionID:   fmt.Sprintf(queryFmtUpdateUserSessionRecordSessionID, tableUserSessions),
+		sqlUpdateUserSessionRecordLastSeen:    fmt.Sprintf(queryFmtUpdateUserSessionRecordLastSeen, tableUserSessions),
+		sqlDeleteUserSessionRecord:            fmt.Sprintf(queryFmtDeleteUserSessionRecord, tableUserSessions),
+
 		sqlUpsertTOTPConfig:  fmt.Sprintf(queryFmtUpsertTOTPConfiguration, tableTOTPConfigurations),
 		sqlDeleteTOTPConfig:  fmt.Sprintf(queryFmtDeleteTOTPConfiguration, tableTOTPConfigurations),
 		sqlSelectTOTPConfig:  fmt.Sprintf(queryFmtSelectTOTPConfiguration, tableTOTPConfigurations),
@@ -61,6 +80,7 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 
 		sqlUpsertPreferred2FAMethod: fmt.Sprintf(queryFmtUpsertPreferred2FAMethod, tableUserPreferences),
 		sqlSelectPreferred2FAMethod: fmt.Sprintf(queryFmtSelectPreferred2FAMethod, tableUserPreferences),
+		sqlUpsertLastUsed2FAMethod:  fmt.Sprintf(queryFmtUpsertLastUsed2FAMethod, tableUserPreferences),
 		sqlSelectUserInfo:           fmt.Sprintf(queryFmtSelectUserInfo, tableTOTPConfigurations, tableWebauthnDevices, tableDuoDevices, tableUserPreferences),
 
 		sqlInsertMigration:       fmt.Sprintf(queryFmtInsertMigration, tableMigrations),
@@ -97,6 +117,28 @@ type SQLProvider struct {
 	sqlConsumeIdentityVerification string
 	sqlSelectIdentityVerification  string
 
+	// Table: trusted_devices.
+	sqlInsertTrustedDevice  string
+	sqlSelectTrustedDevice  string
+	sqlRevokeTrustedDevices string
+
+	// Table: notification_queue.
+	sqlInsertQueuedNotification         string
+	sqlSelectQueuedNotifications        string
+	sqlUpdateQueuedNotificationBodies   string
+	sqlUpdateQueuedNotificationLock     string
+	sqlUpdateQueuedNotificationUnlock   string
+	sqlDeleteQueuedNotification         string
+	sqlDeleteExpiredQueuedNotifications string
+
+	// Table: user_sessions.
+	sqlInsertUserSessionRecord            string
+	sqlSelectUserSessionRecordsByUsername string
+	sqlSelectUserSessionRecords           string
+	sqlUpdateUserSessionRecordSessionID   string
+	sqlUpdateUserSessionRecordLastSeen    string
+	sqlDeleteUserSessionRecord            string
+
 	// Table: totp_configurations.
 	sqlUpsertTOTPConfig  string
 	sqlDeleteTOTPConfig  string
@@ -126,6 +168,7 @@ type SQLProvider struct {
 	// Table: user_preferences.
 	sqlUpsertPreferred2FAMethod string
 	sqlSelectPreferred2FAMethod string
+	sqlUpsertLastUsed2FAMethod  string
 	sqlSelectUserInfo           string
 
 	// Table: migrations.
@@ -147,6 +190,26 @@ func (p *SQLProvider) Close() (err error) {
 	return p.db.Close()
 }
 
+// Ping checks the underlying database connection is usable.
+func (p *SQLProvider) Ping(ctx context.Context) (err error) {
+	if _, err = p.db.ExecContext(ctx, queryPing); err != nil {
+		return fmt.Errorf("error pinging database: %w", err)
+	}
+
+	return nil
+}
+
+// setConnectionPool applies the connection pool configuration to the database handle.
+func (p *SQLProvider) setConnectionPool(config schema.SQLStorageConfiguration) {
+	if p.db == nil {
+		return
+	}
+
+	p.db.SetMaxOpenConns(config.MaxOpenConnections)
+	p.db.SetMaxIdleConns(config.MaxIdleConnections)
+	p.db.SetConnMaxLifetime(config.ConnectionMaxLifetime)
+}
+
 // StartupCheck implements the provider startup check interface.
 func (p *SQLProvider) StartupCheck() (err error) {
 	if p.errOpen != nil {
@@ -210,6 +273,15 @@ func (p *SQLProvider) LoadPreferred2FAMethod(ctx context.Context, username strin
 	}
 }
 
+// SaveLastUsed2FAMethod save the last successfully used method for 2FA to the database.
+func (p *SQLProvider) SaveLastUsed2FAMethod(ctx context.Context, username string, method string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertLastUsed2FAMethod, username, method, method); err != nil {
+		return fmt.Errorf("error upserting last used two factor method for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
 // LoadUserInfo loads the model.UserInfo from the database.
 func (p *SQLProvider) LoadUserInfo(ctx context.Context, username string) (info model.UserInfo, err error) {
 	err = p.db.GetContext(ctx, &info, p.sqlSelectUserInfo, username, username, username, username)
@@ -263,6 +335,234 @@ func (p *SQLProvider) FindIdentityVerification(ctx context.Context, jti string)
 	}
 }
 
+// SaveTrustedDevice saves a trusted device record to the database.
+func (p *SQLProvider) SaveTrustedDevice(ctx context.Context, device model.TrustedDevice) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertTrustedDevice,
+		device.JTI, device.IssuedAt, device.IssuedIP, device.ExpiresAt, device.Username); err != nil {
+		return fmt.Errorf("error inserting trusted device for user '%s' with uuid '%s': %w", device.Username, device.JTI, err)
+	}
+
+	return nil
+}
+
+// LoadTrustedDevice loads a trusted device record from the database given the jti.
+func (p *SQLProvider) LoadTrustedDevice(ctx context.Context, jti string) (device *model.TrustedDevice, err error) {
+	device = &model.TrustedDevice{}
+
+	if err = p.db.GetContext(ctx, device, p.sqlSelectTrustedDevice, jti); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoTrustedDevice
+		}
+
+		return nil, fmt.Errorf("error selecting trusted device with uuid '%s': %w", jti, err)
+	}
+
+	return device, nil
+}
+
+// RevokeTrustedDevices revokes all of the trusted devices of a given user in the database.
+func (p *SQLProvider) RevokeTrustedDevices(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlRevokeTrustedDevices, username); err != nil {
+		return fmt.Errorf("error revoking trusted devices for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// SaveQueuedNotification saves a notification which could not be sent to the database, encrypting the bodies as they
+// may contain identity verification links.
+func (p *SQLProvider) SaveQueuedNotification(ctx context.Context, notification model.QueuedNotification) (err error) {
+	if notification.Body, err = p.encrypt(notification.Body); err != nil {
+		return fmt.Errorf("error encrypting the queued notification body for recipient '%s': %w", notification.Recipient, err)
+	}
+
+	if notification.HTMLBody, err = p.encrypt(notification.HTMLBody); err != nil {
+		return fmt.Errorf("error encrypting the queued notification html body for recipient '%s': %w", notification.Recipient, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertQueuedNotification,
+		notification.CreatedAt, notification.ExpiresAt, notification.Recipient, notification.Subject, notification.Body, notification.HTMLBody); err != nil {
+		return fmt.Errorf("error inserting queued notification for recipient '%s': %w", notification.Recipient, err)
+	}
+
+	return nil
+}
+
+// LoadQueuedNotifications loads a set of the queued notifications which have not expired at the given time from the
+// database.
+func (p *SQLProvider) LoadQueuedNotifications(ctx context.Context, now time.Time, limit, page int) (notifications []model.QueuedNotification, err error) {
+	notifications = make([]model.QueuedNotification, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &notifications, p.sqlSelectQueuedNotifications, now, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting queued notifications: %w", err)
+	}
+
+	for i, notification := range notifications {
+		if notifications[i].Body, err = p.decrypt(notification.Body); err != nil {
+			return nil, fmt.Errorf("error decrypting the queued notification body with id '%d': %w", notification.ID, err)
+		}
+
+		if notifications[i].HTMLBody, err = p.decrypt(notification.HTMLBody); err != nil {
+			return nil, fmt.Errorf("error decrypting the queued notification html body with id '%d': %w", notification.ID, err)
+		}
+	}
+
+	return notifications, nil
+}
+
+func (p *SQLProvider) updateQueuedNotificationBodies(ctx context.Context, notification model.QueuedNotification) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateQueuedNotificationBodies, notification.Body, notification.HTMLBody, notification.ID); err != nil {
+		return fmt.Errorf("error updating queued notification bodies with id '%d': %w", notification.ID, err)
+	}
+
+	return nil
+}
+
+// LockQueuedNotification locks a queued notification until the given time so other instances don't send it at the same
+// time. It returns false if the notification is already locked by another instance.
+func (p *SQLProvider) LockQueuedNotification(ctx context.Context, id int, now, until time.Time) (locked bool, err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateQueuedNotificationLock, until, id, now); err != nil {
+		return false, fmt.Errorf("error locking queued notification with id '%d': %w", id, err)
+	}
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return false, fmt.Errorf("error locking queued notification with id '%d': %w", id, err)
+	}
+
+	return affected == 1, nil
+}
+
+// UnlockQueuedNotification unlocks a queued notification given the id so it can be sent again.
+func (p *SQLProvider) UnlockQueuedNotification(ctx context.Context, id int) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateQueuedNotificationUnlock, id); err != nil {
+		return fmt.Errorf("error unlocking queued notification with id '%d': %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteQueuedNotification deletes a queued notification from the database given the id.
+func (p *SQLProvider) DeleteQueuedNotification(ctx context.Context, id int) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteQueuedNotification, id); err != nil {
+		return fmt.Errorf("error deleting queued notification with id '%d': %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredQueuedNotifications deletes the queued notifications which have expired at the given time from the
+// database.
+func (p *SQLProvider) DeleteExpiredQueuedNotifications(ctx context.Context, now time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteExpiredQueuedNotifications, now); err != nil {
+		return fmt.Errorf("error deleting expired queued notifications: %w", err)
+	}
+
+	return nil
+}
+
+// SaveUserSessionRecord saves a record of a session of a user to the database, encrypting the session id as it's
+// a credential for the session.
+func (p *SQLProvider) SaveUserSessionRecord(ctx context.Context, record model.UserSessionRecord) (err error) {
+	if record.SessionID, err = p.encrypt(record.SessionID); err != nil {
+		return fmt.Errorf("error encrypting the session id of the session record for user '%s' with public id '%s': %w", record.Username, record.PublicID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserSessionRecord,
+		record.PublicID, record.SessionID, record.Username, record.CreatedAt, record.LastSeenAt, record.IP, record.UserAgent); err != nil {
+		return fmt.Errorf("error inserting session record for user '%s' with public id '%s': %w", record.Username, record.PublicID, err)
+	}
+
+	return nil
+}
+
+// LoadUserSessionRecords loads the records of the sessions of a given user from the database.
+func (p *SQLProvider) LoadUserSessionRecords(ctx context.Context, username string) (records []model.UserSessionRecord, err error) {
+	if err = p.db.SelectContext(ctx, &records, p.sqlSelectUserSessionRecordsByUsername, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting session records for user '%s': %w", username, err)
+	}
+
+	for i, record := range records {
+		if records[i].SessionID, err = p.decrypt(record.SessionID); err != nil {
+			return nil, fmt.Errorf("error decrypting the session id of the session record with public id '%s': %w", record.PublicID, err)
+		}
+	}
+
+	return records, nil
+}
+
+func (p *SQLProvider) loadUserSessionRecords(ctx context.Context, limit, page int) (records []model.UserSessionRecord, err error) {
+	records = make([]model.UserSessionRecord, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &records, p.sqlSelectUserSessionRecords, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting session records: %w", err)
+	}
+
+	for i, record := range records {
+		if records[i].SessionID, err = p.decrypt(record.SessionID); err != nil {
+			return nil, fmt.Errorf("error decrypting the session id of the session record with public id '%s': %w", record.PublicID, err)
+		}
+	}
+
+	return records, nil
+}
+
+// UpdateUserSessionRecordSessionID updates the session id of a session record in the database given the public id,
+// this is necessary every time the session id is regenerated.
+func (p *SQLProvider) UpdateUserSessionRecordSessionID(ctx context.Context, publicID string, sessionID []byte) (err error) {
+	if sessionID, err = p.encrypt(sessionID); err != nil {
+		return fmt.Errorf("error encrypting the session id of the session record with public id '%s': %w", publicID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateUserSessionRecordSessionID, sessionID, publicID); err != nil {
+		return fmt.Errorf("error updating the session id of the session record with public id '%s': %w", publicID, err)
+	}
+
+	return nil
+}
+
+func (p *SQLProvider) updateUserSessionRecordSessionID(ctx context.Context, record model.UserSessionRecord) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateUserSessionRecordSessionID, record.SessionID, record.PublicID); err != nil {
+		return fmt.Errorf("error updating the session id of the session record with public id '%s': %w", record.PublicID, err)
+	}
+
+	return nil
+}
+
+// UpdateUserSessionRecordLastSeen updates the time a session was last seen in the database given the public id.
+func (p *SQLProvider) UpdateUserSessionRecordLastSeen(ctx context.Context, publicID string, lastSeenAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateUserSessionRecordLastSeen, lastSeenAt, publicID); err != nil {
+		return fmt.Errorf("error updating the last seen time of the session record with public id '%s': %w", publicID, err)
+	}
+
+	return nil
+}
+
+// DeleteUserSessionRecord deletes a session record from the database given the public id.
+func (p *SQLProvider) DeleteUserSessionRecord(ctx context.Context, publicID string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteUserSessionRecord, publicID); err != nil {
+		return fmt.Errorf("error deleting the session record with public id '%s': %w", publicID, err)
+	}
+
+	return nil
+}
+
 // SaveTOTPConfiguration save a TOTP configuration of a given user in the database.
 func (p *SQLProvider) SaveTOTPConfiguration(ctx context.Context, config model.TOTPConfiguration) (err error) {
 	if config.Secret, err = p.encrypt(config.Secret); err != nil {
@@ -20,11 +20,14 @@ func NewMySQLProvider(config *schema.Configuration) (provider *MySQLProvider) {
 		SQLProvider: NewSQLProvider(config, providerMySQL, providerMySQL, dataSourceNameMySQL(*config.Storage.MySQL)),
 	}
 
+	provider.setConnectionPool(config.Storage.MySQL.SQLStorageConfiguration)
+
 	// All providers have differing SELECT existing table statements.
 	provider.sqlSelectExistingTables = queryMySQLSelectExistingTables
 
 	// Specific alterations to this provider.
 	provider.sqlFmtRenameTable = queryFmtMySQLRenameTable
+	provider.sqlUpsertLastUsed2FAMethod = fmt.Sprintf(queryFmtMySQLUpsertLastUsed2FAMethod, tableUserPreferences)
 
 	return provider
 }
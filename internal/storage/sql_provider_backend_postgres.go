@@ -21,6 +21,8 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 		SQLProvider: NewSQLProvider(config, providerPostgres, "pgx", dataSourceNamePostgreSQL(*config.Storage.PostgreSQL)),
 	}
 
+	provider.setConnectionPool(config.Storage.PostgreSQL.SQLStorageConfiguration)
+
 	// All providers have differing SELECT existing table statements.
 	provider.sqlSelectExistingTables = queryPostgreSelectExistingTables
 
@@ -35,10 +37,27 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 	// PostgreSQL requires rebinding of any query that contains a '?' placeholder to use the '$#' notation placeholders.
 	provider.sqlFmtRenameTable = provider.db.Rebind(provider.sqlFmtRenameTable)
 	provider.sqlSelectPreferred2FAMethod = provider.db.Rebind(provider.sqlSelectPreferred2FAMethod)
+	provider.sqlUpsertLastUsed2FAMethod = provider.db.Rebind(provider.sqlUpsertLastUsed2FAMethod)
 	provider.sqlSelectUserInfo = provider.db.Rebind(provider.sqlSelectUserInfo)
 	provider.sqlSelectIdentityVerification = provider.db.Rebind(provider.sqlSelectIdentityVerification)
 	provider.sqlInsertIdentityVerification = provider.db.Rebind(provider.sqlInsertIdentityVerification)
 	provider.sqlConsumeIdentityVerification = provider.db.Rebind(provider.sqlConsumeIdentityVerification)
+	provider.sqlInsertTrustedDevice = provider.db.Rebind(provider.sqlInsertTrustedDevice)
+	provider.sqlSelectTrustedDevice = provider.db.Rebind(provider.sqlSelectTrustedDevice)
+	provider.sqlRevokeTrustedDevices = provider.db.Rebind(provider.sqlRevokeTrustedDevices)
+	provider.sqlInsertQueuedNotification = provider.db.Rebind(provider.sqlInsertQueuedNotification)
+	provider.sqlSelectQueuedNotifications = provider.db.Rebind(provider.sqlSelectQueuedNotifications)
+	provider.sqlUpdateQueuedNotificationBodies = provider.db.Rebind(provider.sqlUpdateQueuedNotificationBodies)
+	provider.sqlUpdateQueuedNotificationLock = provider.db.Rebind(provider.sqlUpdateQueuedNotificationLock)
+	provider.sqlUpdateQueuedNotificationUnlock = provider.db.Rebind(provider.sqlUpdateQueuedNotificationUnlock)
+	provider.sqlDeleteQueuedNotification = provider.db.Rebind(provider.sqlDeleteQueuedNotification)
+	provider.sqlDeleteExpiredQueuedNotifications = provider.db.Rebind(provider.sqlDeleteExpiredQueuedNotifications)
+	provider.sqlInsertUserSessionRecord = provider.db.Rebind(provider.sqlInsertUserSessionRecord)
+	provider.sqlSelectUserSessionRecordsByUsername = provider.db.Rebind(provider.sqlSelectUserSessionRecordsByUsername)
+	provider.sqlSelectUserSessionRecords = provider.db.Rebind(provider.sqlSelectUserSessionRecords)
+	provider.sqlUpdateUserSessionRecordSessionID = provider.db.Rebind(provider.sqlUpdateUserSessionRecordSessionID)
+	provider.sqlUpdateUserSessionRecordLastSeen = provider.db.Rebind(provider.sqlUpdateUserSessionRecordLastSeen)
+	provider.sqlDeleteUserSessionRecord = provider.db.Rebind(provider.sqlDeleteUserSessionRecord)
 	provider.sqlSelectTOTPConfig = provider.db.Rebind(provider.sqlSelectTOTPConfig)
 	provider.sqlUpdateTOTPConfigRecordSignIn = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignIn)
 	provider.sqlUpdateTOTPConfigRecordSignInByUsername = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignInByUsername)
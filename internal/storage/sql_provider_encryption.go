@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -30,6 +31,14 @@ func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, encryptionK
 		return err
 	}
 
+	if err = p.schemaEncryptionChangeKeyNotificationQueue(ctx, tx, key); err != nil {
+		return err
+	}
+
+	if err = p.schemaEncryptionChangeKeyUserSessions(ctx, tx, key); err != nil {
+		return err
+	}
+
 	if err = p.setNewEncryptionCheckValue(ctx, &key, tx); err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
@@ -117,6 +126,86 @@ func (p *SQLProvider) schemaEncryptionChangeKeyWebauthn(ctx context.Context, tx
 	return nil
 }
 
+func (p *SQLProvider) schemaEncryptionChangeKeyNotificationQueue(ctx context.Context, tx *sqlx.Tx, key [32]byte) (err error) {
+	var notifications []model.QueuedNotification
+
+	for page := 0; true; page++ {
+		if notifications, err = p.LoadQueuedNotifications(ctx, time.Time{}, 10, page); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+			}
+
+			return fmt.Errorf("rollback due to error: %w", err)
+		}
+
+		for _, notification := range notifications {
+			if notification.Body, err = utils.Encrypt(notification.Body, &key); err == nil {
+				notification.HTMLBody, err = utils.Encrypt(notification.HTMLBody, &key)
+			}
+
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+				}
+
+				return fmt.Errorf("rollback due to error: %w", err)
+			}
+
+			if err = p.updateQueuedNotificationBodies(ctx, notification); err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+				}
+
+				return fmt.Errorf("rollback due to error: %w", err)
+			}
+		}
+
+		if len(notifications) != 10 {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (p *SQLProvider) schemaEncryptionChangeKeyUserSessions(ctx context.Context, tx *sqlx.Tx, key [32]byte) (err error) {
+	var records []model.UserSessionRecord
+
+	for page := 0; true; page++ {
+		if records, err = p.loadUserSessionRecords(ctx, 10, page); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+			}
+
+			return fmt.Errorf("rollback due to error: %w", err)
+		}
+
+		for _, record := range records {
+			if record.SessionID, err = utils.Encrypt(record.SessionID, &key); err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+				}
+
+				return fmt.Errorf("rollback due to error: %w", err)
+			}
+
+			if err = p.updateUserSessionRecordSessionID(ctx, record); err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+				}
+
+				return fmt.Errorf("rollback due to error: %w", err)
+			}
+		}
+
+		if len(records) != 10 {
+			break
+		}
+	}
+
+	return nil
+}
+
 // SchemaEncryptionCheckKey checks the encryption key configured is valid for the database.
 func (p *SQLProvider) SchemaEncryptionCheckKey(ctx context.Context, verbose bool) (err error) {
 	version, err := p.SchemaVersion(ctx)
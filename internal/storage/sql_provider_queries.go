@@ -1,5 +1,9 @@
 package storage
 
+const (
+	queryPing = "SELECT 1;"
+)
+
 const (
 	queryFmtSelectMigrations = `
 		SELECT id, applied, version_before, version_after, application_version
@@ -35,7 +39,7 @@ const (
 
 const (
 	queryFmtSelectUserInfo = `
-		SELECT second_factor_method, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_totp, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_webauthn, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_duo
+		SELECT second_factor_method, COALESCE(last_used_second_factor_method, '') AS last_used_second_factor_method, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_totp, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_webauthn, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_duo
 		FROM %s
 		WHERE username = ?;`
 
@@ -53,6 +57,17 @@ const (
 		VALUES ($1, $2)
 			ON CONFLICT (username)
 			DO UPDATE SET second_factor_method = $2;`
+
+	queryFmtUpsertLastUsed2FAMethod = `
+		INSERT INTO %s (username, second_factor_method, last_used_second_factor_method)
+		VALUES (?, ?, ?)
+			ON CONFLICT (username)
+			DO UPDATE SET last_used_second_factor_method = excluded.last_used_second_factor_method;`
+
+	queryFmtMySQLUpsertLastUsed2FAMethod = `
+		INSERT INTO %s (username, second_factor_method, last_used_second_factor_method)
+		VALUES (?, ?, ?)
+			ON DUPLICATE KEY UPDATE last_used_second_factor_method = VALUES(last_used_second_factor_method);`
 )
 
 const (
@@ -71,6 +86,92 @@ const (
 		WHERE jti = ?;`
 )
 
+const (
+	queryFmtSelectTrustedDevice = `
+		SELECT id, jti, iat, issued_ip, exp, username, revoked
+		FROM %s
+		WHERE jti = ?;`
+
+	queryFmtInsertTrustedDevice = `
+		INSERT INTO %s (jti, iat, issued_ip, exp, username)
+		VALUES (?, ?, ?, ?, ?);`
+
+	queryFmtRevokeTrustedDevices = `
+		UPDATE %s
+		SET revoked = CURRENT_TIMESTAMP
+		WHERE username = ? AND revoked IS NULL;`
+)
+
+const (
+	queryFmtInsertQueuedNotification = `
+		INSERT INTO %s (created_at, expires_at, recipient, subject, body, html_body)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectQueuedNotifications = `
+		SELECT id, created_at, expires_at, recipient, subject, body, html_body
+		FROM %s
+		WHERE expires_at > ?
+		ORDER BY id
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtUpdateQueuedNotificationBodies = `
+		UPDATE %s
+		SET body = ?, html_body = ?
+		WHERE id = ?;`
+
+	queryFmtUpdateQueuedNotificationLock = `
+		UPDATE %s
+		SET locked_until = ?
+		WHERE id = ? AND (locked_until IS NULL OR locked_until <= ?);`
+
+	queryFmtUpdateQueuedNotificationUnlock = `
+		UPDATE %s
+		SET locked_until = NULL
+		WHERE id = ?;`
+
+	queryFmtDeleteQueuedNotification = `
+		DELETE FROM %s
+		WHERE id = ?;`
+
+	queryFmtDeleteExpiredQueuedNotifications = `
+		DELETE FROM %s
+		WHERE expires_at <= ?;`
+)
+
+const (
+	queryFmtInsertUserSessionRecord = `
+		INSERT INTO %s (public_id, session_id, username, created_at, last_seen_at, ip, user_agent)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectUserSessionRecordsByUsername = `
+		SELECT id, public_id, session_id, username, created_at, last_seen_at, ip, user_agent
+		FROM %s
+		WHERE username = ?
+		ORDER BY created_at;`
+
+	queryFmtSelectUserSessionRecords = `
+		SELECT id, public_id, session_id, username, created_at, last_seen_at, ip, user_agent
+		FROM %s
+		ORDER BY id
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtUpdateUserSessionRecordSessionID = `
+		UPDATE %s
+		SET session_id = ?
+		WHERE public_id = ?;`
+
+	queryFmtUpdateUserSessionRecordLastSeen = `
+		UPDATE %s
+		SET last_seen_at = ?
+		WHERE public_id = ?;`
+
+	queryFmtDeleteUserSessionRecord = `
+		DELETE FROM %s
+		WHERE public_id = ?;`
+)
+
 const (
 	queryFmtSelectTOTPConfiguration = `
 		SELECT id, username, issuer, algorithm, digits, period, secret
@@ -144,6 +144,10 @@ func (p *SQLProvider) schemaMigrate(ctx context.Context, prior, target int) (err
 		return ErrNoMigrationsFound
 	}
 
+	if p.config.Storage.Migrate.DryRun {
+		return p.schemaMigrateDryRun(prior, target, migrations)
+	}
+
 	switch {
 	case prior == -1:
 		p.log.Infof(logFmtMigrationFromTo, "pre1", strconv.Itoa(migrations[len(migrations)-1].After()))
@@ -195,6 +199,27 @@ func (p *SQLProvider) schemaMigrate(ctx context.Context, prior, target int) (err
 	return nil
 }
 
+// schemaMigrateDryRun logs the SQL of the migrations instead of applying them.
+func (p *SQLProvider) schemaMigrateDryRun(prior, target int, migrations []model.SchemaMigration) (err error) {
+	if prior == -1 {
+		p.log.Infof("Storage schema migration from pre1 to 1 would be performed before the following migrations")
+	}
+
+	for _, migration := range migrations {
+		if prior == -1 && migration.Version == 1 {
+			continue
+		}
+
+		p.log.Infof(logFmtMigrationDryRun, migration.Version, migration.Name, migration.Before(), migration.After(), migration.Query)
+	}
+
+	if target == -1 {
+		p.log.Infof("Storage schema migration from 1 to pre1 would be performed after the above migrations")
+	}
+
+	return ErrSchemaMigrateDryRun
+}
+
 func (p *SQLProvider) schemaMigrateRollback(ctx context.Context, prior, after int, migrateErr error) (err error) {
 	migrations, err := loadMigrations(p.name, after, prior)
 	if err != nil {
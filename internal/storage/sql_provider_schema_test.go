@@ -1,10 +1,15 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
 
 func TestShouldReturnErrOnTargetSameAsCurrent(t *testing.T) {
@@ -132,3 +137,37 @@ func TestSchemaVersionToString(t *testing.T) {
 	assert.Equal(t, "1", SchemaVersionToString(1))
 	assert.Equal(t, "2", SchemaVersionToString(2))
 }
+
+func TestShouldNotApplyMigrationsInDryRunMode(t *testing.T) {
+	config := &schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+			EncryptionKey: "a_not_so_secure_encryption_key",
+			Migrate:       schema.StorageMigrateConfiguration{DryRun: true},
+		},
+	}
+
+	provider := NewSQLiteProvider(config)
+
+	defer func() {
+		assert.NoError(t, provider.Close())
+	}()
+
+	ctx := context.Background()
+
+	assert.ErrorIs(t, provider.SchemaMigrate(ctx, true, SchemaLatest), ErrSchemaMigrateDryRun)
+	assert.EqualError(t, provider.StartupCheck(), "error during schema migrate: schema migrations were not applied because the dry run mode is enabled")
+
+	tables, err := provider.SchemaTables(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tables, 0)
+
+	config.Storage.Migrate.DryRun = false
+
+	require.NoError(t, provider.SchemaMigrate(ctx, true, SchemaLatest))
+
+	tables, err = provider.SchemaTables(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, tables, tableTrustedDevices)
+	assert.Contains(t, tables, tableUserSessions)
+}
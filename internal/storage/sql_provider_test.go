@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldApplyConnectionPoolConfiguration(t *testing.T) {
+	sqlConfig := schema.SQLStorageConfiguration{
+		Host:                  "localhost",
+		Database:              "authelia",
+		Username:              "authelia",
+		Password:              "password",
+		Timeout:               time.Second,
+		MaxOpenConnections:    7,
+		MaxIdleConnections:    3,
+		ConnectionMaxLifetime: time.Minute,
+	}
+
+	mysql := NewMySQLProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{MySQL: &schema.MySQLStorageConfiguration{SQLStorageConfiguration: sqlConfig}},
+	})
+
+	assert.Equal(t, 7, mysql.db.Stats().MaxOpenConnections)
+
+	postgres := NewPostgreSQLProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{PostgreSQL: &schema.PostgreSQLStorageConfiguration{SQLStorageConfiguration: sqlConfig}},
+	})
+
+	assert.Equal(t, 7, postgres.db.Stats().MaxOpenConnections)
+}
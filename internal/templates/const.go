@@ -4,4 +4,6 @@ package templates
 const (
 	TemplateNameStep1 = "PasswordResetStep1"
 	TemplateNameStep2 = "PasswordResetStep2"
+
+	TemplateNameDeviceChange = "DeviceChange"
 )
@@ -0,0 +1,29 @@
+package templates
+
+import (
+	"text/template"
+)
+
+// PlainTextEmailTemplateDeviceChange the template of email that the user will receive when a 2FA device is added or
+// removed.
+var PlainTextEmailTemplateDeviceChange *template.Template
+
+func init() {
+	t, err := template.New("text_email_template").Parse(emailPlainTextContentDeviceChange)
+	if err != nil {
+		panic(err)
+	}
+
+	PlainTextEmailTemplateDeviceChange = t
+}
+
+const emailPlainTextContentDeviceChange = `
+Hi {{.displayName}},
+
+A {{.deviceType}} device has been {{.action}} on your account at {{.time}}.
+If you did not initiate the process your credentials might have been compromised. You should reset your password and contact an administrator.
+
+This email was generated by a user with the IP {{.remoteIP}}.
+
+Please contact an administrator if you did not initiate the process.
+`
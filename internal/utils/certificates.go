@@ -27,8 +27,9 @@ func NewTLSConfig(config *schema.TLSConfig, defaultMinVersion uint16, certPool *
 	}
 }
 
-// NewX509CertPool generates a x509.CertPool from the system PKI and the directory specified.
-func NewX509CertPool(directory string) (certPool *x509.CertPool, warnings []error, errors []error) {
+// NewX509CertPool generates a x509.CertPool from the system PKI, the directory specified, and the trusted certificate
+// authority bundle specified.
+func NewX509CertPool(directory, trustedCA string) (certPool *x509.CertPool, warnings []error, errors []error) {
 	certPool, err := x509.SystemCertPool()
 	if err != nil {
 		warnings = append(warnings, fmt.Errorf("could not load system certificate pool which may result in untrusted certificate issues: %v", err))
@@ -65,6 +66,17 @@ func NewX509CertPool(directory string) (certPool *x509.CertPool, warnings []erro
 
 	logger.Tracef("Finished scan of directory %s for certificates", directory)
 
+	if trustedCA != "" {
+		logger.Tracef("Adding the trusted certificate authority bundle %s to the pool", trustedCA)
+
+		certBytes, err := os.ReadFile(trustedCA)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("could not read trusted certificate authority bundle %v", err))
+		} else if ok := certPool.AppendCertsFromPEM(certBytes); !ok {
+			errors = append(errors, fmt.Errorf("could not import trusted certificate authority bundle %s", trustedCA))
+		}
+	}
+
 	return certPool, warnings, errors
 }
 
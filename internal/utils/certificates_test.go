@@ -77,7 +77,7 @@ func TestShouldReturnZeroAndErrorOnInvalidTLSVersions(t *testing.T) {
 }
 
 func TestShouldReturnErrWhenX509DirectoryNotExist(t *testing.T) {
-	pool, warnings, errors := NewX509CertPool("/tmp/asdfzyxabc123/not/a/real/dir")
+	pool, warnings, errors := NewX509CertPool("/tmp/asdfzyxabc123/not/a/real/dir", "")
 	assert.NotNil(t, pool)
 
 	if runtime.GOOS == windows {
@@ -97,7 +97,7 @@ func TestShouldReturnErrWhenX509DirectoryNotExist(t *testing.T) {
 }
 
 func TestShouldNotReturnErrWhenX509DirectoryExist(t *testing.T) {
-	pool, warnings, errors := NewX509CertPool("/tmp")
+	pool, warnings, errors := NewX509CertPool("/tmp", "")
 	assert.NotNil(t, pool)
 
 	if runtime.GOOS == windows {
@@ -111,7 +111,7 @@ func TestShouldNotReturnErrWhenX509DirectoryExist(t *testing.T) {
 }
 
 func TestShouldReadCertsFromDirectoryButNotKeys(t *testing.T) {
-	pool, warnings, errors := NewX509CertPool("../suites/common/ssl/")
+	pool, warnings, errors := NewX509CertPool("../suites/common/ssl/", "")
 	assert.NotNil(t, pool)
 	require.Len(t, errors, 1)
 
@@ -124,3 +124,15 @@ func TestShouldReadCertsFromDirectoryButNotKeys(t *testing.T) {
 
 	assert.EqualError(t, errors[0], "could not import certificate key.pem")
 }
+
+func TestShouldReadTrustedCA(t *testing.T) {
+	pool, _, errors := NewX509CertPool("", "../suites/common/ssl/cert.pem")
+	assert.NotNil(t, pool)
+	assert.Len(t, errors, 0)
+
+	pool, _, errors = NewX509CertPool("", "../suites/common/ssl/key.pem")
+	assert.NotNil(t, pool)
+	require.Len(t, errors, 1)
+
+	assert.EqualError(t, errors[0], "could not import trusted certificate authority bundle ../suites/common/ssl/key.pem")
+}
@@ -6,26 +6,29 @@ import (
 	"strings"
 )
 
-// IsRedirectionSafe determines whether the URL is safe to be redirected to.
-func IsRedirectionSafe(url url.URL, protectedDomain string) bool {
+// IsRedirectionSafe determines whether the URL is safe to be redirected to, it must be under one of the protected
+// domains.
+func IsRedirectionSafe(url url.URL, protectedDomains ...string) bool {
 	if url.Scheme != "https" {
 		return false
 	}
 
-	if !strings.HasSuffix(url.Hostname(), protectedDomain) {
-		return false
+	for _, protectedDomain := range protectedDomains {
+		if strings.HasSuffix(url.Hostname(), protectedDomain) {
+			return true
+		}
 	}
 
-	return true
+	return false
 }
 
 // IsRedirectionURISafe determines whether the URI is safe to be redirected to.
-func IsRedirectionURISafe(uri, protectedDomain string) (bool, error) {
+func IsRedirectionURISafe(uri string, protectedDomains ...string) (bool, error) {
 	targetURL, err := url.ParseRequestURI(uri)
 
 	if err != nil {
 		return false, fmt.Errorf("Unable to parse redirection URI %s: %w", uri, err)
 	}
 
-	return targetURL != nil && IsRedirectionSafe(*targetURL, protectedDomain), nil
+	return targetURL != nil && IsRedirectionSafe(*targetURL, protectedDomains...), nil
 }
@@ -24,6 +24,14 @@ func TestIsRedirectionSafe_ShouldReturnFalseOnBadDomain(t *testing.T) {
 	assert.False(t, isURLSafe("https://secure.example.co", "example.com"))
 }
 
+func TestIsRedirectionSafe_ShouldReturnTrueOnAnyProtectedDomain(t *testing.T) {
+	url, _ := url.ParseRequestURI("https://secure.example.org")
+
+	assert.True(t, IsRedirectionSafe(*url, "example.com", "example.org"))
+	assert.False(t, IsRedirectionSafe(*url, "example.com", "example.net"))
+	assert.False(t, IsRedirectionSafe(*url))
+}
+
 func TestIsRedirectionURISafe_CannotParseURI(t *testing.T) {
 	_, err := IsRedirectionURISafe("http//invalid", "example.com")
 	assert.EqualError(t, err, "Unable to parse redirection URI http//invalid: parse \"http//invalid\": invalid URI for request")
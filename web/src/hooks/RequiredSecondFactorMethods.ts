@@ -0,0 +1,58 @@
+import { useMemo } from "react";
+
+import queryString from "query-string";
+import { useLocation } from "react-router-dom";
+
+import { SecondFactorMethod } from "@models/Methods";
+
+// useRequiredSecondFactorMethods returns the second factor methods accepted by the access control rule of the
+// redirection URL when the user authenticated with another method.
+export function useRequiredSecondFactorMethods() {
+    const location = useLocation();
+    const queryParams = queryString.parse(location.search);
+    const methods = queryParams && "methods" in queryParams ? (queryParams["methods"] as string) : undefined;
+
+    return useMemo(() => {
+        if (!methods) {
+            return undefined;
+        }
+
+        const required = methods
+            .split(",")
+            .map(toSecondFactorMethod)
+            .filter((method): method is SecondFactorMethod => method !== undefined);
+
+        return required.length === 0 ? undefined : required;
+    }, [methods]);
+}
+
+function toSecondFactorMethod(method: string): SecondFactorMethod | undefined {
+    switch (method) {
+        case "totp":
+            return SecondFactorMethod.TOTP;
+        case "webauthn":
+            return SecondFactorMethod.Webauthn;
+        case "duo":
+            return SecondFactorMethod.MobilePush;
+    }
+
+    return undefined;
+}
+
+// toRequiredSecondFactorMethodsParam returns the value of the methods query parameter for the given methods.
+export function toRequiredSecondFactorMethodsParam(methods: SecondFactorMethod[]) {
+    return methods
+        .map((method) => {
+            switch (method) {
+                case SecondFactorMethod.TOTP:
+                    return "totp";
+                case SecondFactorMethod.Webauthn:
+                    return "webauthn";
+                case SecondFactorMethod.MobilePush:
+                    return "duo";
+            }
+
+            return "";
+        })
+        .join(",");
+}
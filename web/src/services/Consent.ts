@@ -4,6 +4,7 @@ import { Post, Get } from "@services/Client";
 interface ConsentPostRequestBody {
     client_id: string;
     accept_or_reject: "accept" | "reject";
+    granted_scopes?: string[];
 }
 
 interface ConsentPostResponseBody {
@@ -10,6 +10,7 @@ export enum AuthenticationLevel {
 export interface AutheliaState {
     username: string;
     authentication_level: AuthenticationLevel;
+    login_hint?: string;
 }
 
 export async function getState(): Promise<AutheliaState> {
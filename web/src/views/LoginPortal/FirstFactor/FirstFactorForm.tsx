@@ -20,6 +20,8 @@ export interface Props {
     resetPassword: boolean;
     resetPasswordCustomURL: string;
 
+    loginHint?: string;
+
     onAuthenticationStart: () => void;
     onAuthenticationFailure: () => void;
     onAuthenticationSuccess: (redirectURL: string | undefined) => void;
@@ -32,7 +34,7 @@ const FirstFactorForm = function (props: Props) {
     const requestMethod = useRequestMethod();
 
     const [rememberMe, setRememberMe] = useState(false);
-    const [username, setUsername] = useState("");
+    const [username, setUsername] = useState(props.loginHint ?? "");
     const [usernameError, setUsernameError] = useState(false);
     const [password, setPassword] = useState("");
     const [passwordError, setPasswordError] = useState(false);
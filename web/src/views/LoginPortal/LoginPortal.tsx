@@ -15,6 +15,7 @@ import { useNotifications } from "@hooks/NotificationsContext";
 import { useRedirectionURL } from "@hooks/RedirectionURL";
 import { useRedirector } from "@hooks/Redirector";
 import { useRequestMethod } from "@hooks/RequestMethod";
+import { toRequiredSecondFactorMethodsParam, useRequiredSecondFactorMethods } from "@hooks/RequiredSecondFactorMethods";
 import { useAutheliaState } from "@hooks/State";
 import { useUserInfoPOST } from "@hooks/UserInfo";
 import { SecondFactorMethod } from "@models/Methods";
@@ -41,6 +42,7 @@ const LoginPortal = function (props: Props) {
     const location = useLocation();
     const redirectionURL = useRedirectionURL();
     const requestMethod = useRequestMethod();
+    const requiredMethods = useRequiredSecondFactorMethods();
     const { createErrorNotification } = useNotifications();
     const [firstFactorDisabled, setFirstFactorDisabled] = useState(true);
     const redirector = useRedirector();
@@ -51,6 +53,13 @@ const LoginPortal = function (props: Props) {
 
     const redirect = useCallback((url: string) => navigate(url), [navigate]);
 
+    // A user who completed the second factor with a method the access control rule doesn't accept is challenged again
+    // for one of the accepted methods.
+    const authenticationLevel =
+        state && requiredMethods && state.authentication_level === AuthenticationLevel.TwoFactor
+            ? AuthenticationLevel.OneFactor
+            : state?.authentication_level;
+
     // Fetch the state when portal is mounted.
     useEffect(() => {
         fetchState();
@@ -95,7 +104,7 @@ const LoginPortal = function (props: Props) {
     // Redirect to the correct stage if not enough authenticated
     useEffect(() => {
         (async function () {
-            if (!state) {
+            if (!state || authenticationLevel === undefined) {
                 return;
             }
 
@@ -103,8 +112,8 @@ const LoginPortal = function (props: Props) {
                 redirectionURL &&
                 ((configuration &&
                     configuration.available_methods.size === 0 &&
-                    state.authentication_level >= AuthenticationLevel.OneFactor) ||
-                    state.authentication_level === AuthenticationLevel.TwoFactor)
+                    authenticationLevel >= AuthenticationLevel.OneFactor) ||
+                    authenticationLevel === AuthenticationLevel.TwoFactor)
             ) {
                 try {
                     const res = await checkSafeRedirection(redirectionURL);
@@ -120,19 +129,26 @@ const LoginPortal = function (props: Props) {
             }
 
             const redirectionSuffix = redirectionURL
-                ? `?rd=${encodeURIComponent(redirectionURL)}${requestMethod ? `&rm=${requestMethod}` : ""}`
+                ? `?rd=${encodeURIComponent(redirectionURL)}${requestMethod ? `&rm=${requestMethod}` : ""}${
+                      requiredMethods ? `&methods=${toRequiredSecondFactorMethodsParam(requiredMethods)}` : ""
+                  }`
                 : "";
 
-            if (state.authentication_level === AuthenticationLevel.Unauthenticated) {
+            if (authenticationLevel === AuthenticationLevel.Unauthenticated) {
                 setFirstFactorDisabled(false);
                 redirect(`${IndexRoute}${redirectionSuffix}`);
-            } else if (state.authentication_level >= AuthenticationLevel.OneFactor && userInfo && configuration) {
+            } else if (authenticationLevel >= AuthenticationLevel.OneFactor && userInfo && configuration) {
+                const method =
+                    requiredMethods && !requiredMethods.includes(userInfo.method)
+                        ? requiredMethods[0]
+                        : userInfo.method;
+
                 if (configuration.available_methods.size === 0) {
                     redirect(AuthenticatedRoute);
                 } else {
-                    if (userInfo.method === SecondFactorMethod.Webauthn) {
+                    if (method === SecondFactorMethod.Webauthn) {
                         redirect(`${SecondFactorRoute}${SecondFactorWebauthnSubRoute}${redirectionSuffix}`);
-                    } else if (userInfo.method === SecondFactorMethod.MobilePush) {
+                    } else if (method === SecondFactorMethod.MobilePush) {
                         redirect(`${SecondFactorRoute}${SecondFactorPushSubRoute}${redirectionSuffix}`);
                     } else {
                         redirect(`${SecondFactorRoute}${SecondFactorTOTPSubRoute}${redirectionSuffix}`);
@@ -142,8 +158,10 @@ const LoginPortal = function (props: Props) {
         })();
     }, [
         state,
+        authenticationLevel,
         redirectionURL,
         requestMethod,
+        requiredMethods,
         redirect,
         userInfo,
         setFirstFactorDisabled,
@@ -178,6 +196,7 @@ const LoginPortal = function (props: Props) {
                             rememberMe={props.rememberMe}
                             resetPassword={props.resetPassword}
                             resetPasswordCustomURL={props.resetPasswordCustomURL}
+                            loginHint={state?.login_hint}
                             onAuthenticationStart={() => setFirstFactorDisabled(true)}
                             onAuthenticationFailure={() => setFirstFactorDisabled(false)}
                             onAuthenticationSuccess={handleAuthSuccess}
@@ -188,9 +207,9 @@ const LoginPortal = function (props: Props) {
             <Route
                 path={`${SecondFactorRoute}*`}
                 element={
-                    state && userInfo && configuration ? (
+                    authenticationLevel !== undefined && userInfo && configuration ? (
                         <SecondFactorForm
-                            authenticationLevel={state.authentication_level}
+                            authenticationLevel={authenticationLevel}
                             userInfo={userInfo}
                             configuration={configuration}
                             duoSelfEnrollment={props.duoSelfEnrollment}